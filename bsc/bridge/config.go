@@ -0,0 +1,61 @@
+package bridge
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// maxCommitteeSize is the maximum number of attesters, bounded by the width of
+// the signer bitmap passed to the mint contract.
+const maxCommitteeSize = 256
+
+// Config contains the settings of the bridge relayer.
+type Config struct {
+	RemoteURL     string               // RPC endpoint of the remote chain
+	Contract      common.Address       // Contract on the remote chain emitting the bridged events
+	Events        []common.Hash        // Signatures of the bridged events, any event of the contract if empty
+	Confirmations uint64               // Number of blocks an event must be buried under before being attested
+	StartBlock    uint64               // Remote block to start watching from unless resuming, the confirmed head if zero
+	Attesters     []types.BLSPublicKey // BLS vote keys of the validators attesting the events
+	Threshold     int                  // Number of attestations needed to mint
+	MintContract  common.Address       // Contract on the local chain minting the attested events
+	SubmitKeyFile string               // Key paying for the mint transactions, no submissions if empty
+	PollInterval  time.Duration        // Interval of polling the remote chain
+}
+
+// DefaultConfig contains the default settings of the bridge relayer.
+var DefaultConfig = Config{
+	Confirmations: 12,
+	PollInterval:  15 * time.Second,
+}
+
+// sanitize checks the config for consistency and fills in the defaults.
+func (c *Config) sanitize() error {
+	if c.Contract == (common.Address{}) {
+		return errors.New("no remote contract configured")
+	}
+	if c.MintContract == (common.Address{}) {
+		return errors.New("no mint contract configured")
+	}
+	if len(c.Attesters) == 0 || len(c.Attesters) > maxCommitteeSize {
+		return fmt.Errorf("invalid number of attesters %d", len(c.Attesters))
+	}
+	seen := make(map[types.BLSPublicKey]struct{})
+	for _, attester := range c.Attesters {
+		if _, ok := seen[attester]; ok {
+			return fmt.Errorf("duplicate attester %x", attester)
+		}
+		seen[attester] = struct{}{}
+	}
+	if c.Threshold <= 0 || c.Threshold > len(c.Attesters) {
+		return fmt.Errorf("invalid threshold %d of %d attesters", c.Threshold, len(c.Attesters))
+	}
+	if c.PollInterval <= 0 {
+		c.PollInterval = DefaultConfig.PollInterval
+	}
+	return nil
+}
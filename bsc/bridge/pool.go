@@ -0,0 +1,198 @@
+package bridge
+
+import (
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// maxPendingPerAttester is the maximum number of events collecting
+	// attestations a single committee member can have attested, bounding the
+	// events kept pending by any one of them.
+	maxPendingPerAttester = 1024
+
+	// pendingLifetime is the time an event collects attestations for without
+	// reaching the threshold before it is dropped. Attesters announce their
+	// attestations of unrelayed events again regularly, reviving it.
+	pendingLifetime = time.Hour
+
+	// expiryInterval is the minimum interval between two sweeps of the expired
+	// events.
+	expiryInterval = time.Minute
+
+	// maxRelayedDigests is the number of relayed events whose late attestations
+	// are still recognised and dropped.
+	maxRelayedDigests = 4096
+)
+
+// pendingDigest is an event collecting attestations.
+type pendingDigest struct {
+	atts    []*types.BridgeAttestation // Attestations indexed by member
+	created time.Time                  // Time the first attestation was pooled
+}
+
+// Pool collects the attestations of the bridge committee, grouped by the digest
+// of the attested event. Only valid attestations of committee members enter
+// the pool, every one of them is announced once to be gossiped to the peers.
+// Events not relayed within pendingLifetime are dropped, and each member can
+// only have attested a limited number of the pending ones.
+type Pool struct {
+	committee map[types.BLSPublicKey]int // Committee member index by vote key
+
+	pending  map[common.Hash]*pendingDigest      // Events collecting attestations by digest
+	attested []int                               // Number of pending events attested, by member
+	relayed  lru.BasicLRU[common.Hash, struct{}] // Digests of the events already relayed
+	expired  time.Time                           // Time of the last sweep of the expired events
+	mu       sync.RWMutex
+
+	feed  event.Feed
+	scope event.SubscriptionScope
+}
+
+// NewPool creates an attestation pool for the given committee.
+func NewPool(committee []types.BLSPublicKey) *Pool {
+	p := &Pool{
+		committee: make(map[types.BLSPublicKey]int, len(committee)),
+		pending:   make(map[common.Hash]*pendingDigest),
+		attested:  make([]int, len(committee)),
+		relayed:   lru.NewBasicLRU[common.Hash, struct{}](maxRelayedDigests),
+		expired:   time.Now(),
+	}
+	for i, member := range committee {
+		p.committee[member] = i
+	}
+	return p
+}
+
+// PutAttestation adds an attestation to the pool if it is valid and not yet
+// known, announcing it to the subscribers.
+func (p *Pool) PutAttestation(att *types.BridgeAttestation) {
+	p.put(att)
+}
+
+// put adds an attestation to the pool if it is valid and not yet known,
+// announcing it to the subscribers. It returns whether it was added.
+func (p *Pool) put(att *types.BridgeAttestation) bool {
+	index, ok := p.committee[att.VoteAddress]
+	if !ok {
+		log.Debug("Dropping bridge attestation of unknown attester", "digest", att.Digest, "attester", common.Bytes2Hex(att.VoteAddress[:]))
+		return false
+	}
+	p.mu.RLock()
+	pending := p.pending[att.Digest]
+	known := (pending != nil && pending.atts[index] != nil) || p.relayed.Contains(att.Digest)
+	full := p.attested[index] >= maxPendingPerAttester
+	p.mu.RUnlock()
+
+	if known {
+		return false
+	}
+	if full {
+		log.Debug("Dropping bridge attestation, too many pending events of attester", "digest", att.Digest, "attester", common.Bytes2Hex(att.VoteAddress[:]))
+		return false
+	}
+	if err := att.Verify(); err != nil {
+		log.Debug("Dropping invalid bridge attestation", "digest", att.Digest, "err", err)
+		return false
+	}
+	p.mu.Lock()
+	if now := time.Now(); now.Sub(p.expired) >= expiryInterval {
+		p.expire(now)
+	}
+	if p.relayed.Contains(att.Digest) || p.attested[index] >= maxPendingPerAttester {
+		p.mu.Unlock()
+		return false
+	}
+	pending = p.pending[att.Digest]
+	if pending == nil {
+		pending = &pendingDigest{atts: make([]*types.BridgeAttestation, len(p.committee)), created: time.Now()}
+		p.pending[att.Digest] = pending
+	}
+	if pending.atts[index] != nil {
+		p.mu.Unlock()
+		return false
+	}
+	pending.atts[index] = att
+	p.attested[index]++
+	p.mu.Unlock()
+
+	p.feed.Send(core.NewBridgeAttestationEvent{Attestation: att})
+	return true
+}
+
+// expire drops the events collecting attestations for longer than the pending
+// lifetime. It expects the lock to be held.
+func (p *Pool) expire(now time.Time) {
+	for digest, pending := range p.pending {
+		if now.Sub(pending.created) >= pendingLifetime {
+			log.Debug("Dropping expired bridge event", "digest", digest)
+			p.drop(digest)
+		}
+	}
+	p.expired = now
+}
+
+// drop removes the attestations of the given event. It expects the lock to be
+// held.
+func (p *Pool) drop(digest common.Hash) {
+	pending := p.pending[digest]
+	if pending == nil {
+		return
+	}
+	for i, att := range pending.atts {
+		if att != nil {
+			p.attested[i]--
+		}
+	}
+	delete(p.pending, digest)
+}
+
+// Attestations returns the attestations of the event with the given digest,
+// indexed by committee member. Members which did not attest are nil.
+func (p *Pool) Attestations(digest common.Hash) []*types.BridgeAttestation {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	pending := p.pending[digest]
+	if pending == nil {
+		return nil
+	}
+	return append([]*types.BridgeAttestation(nil), pending.atts...)
+}
+
+// markRelayed drops the attestations of a relayed event, as well as any
+// arriving later.
+func (p *Pool) markRelayed(digest common.Hash) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	p.drop(digest)
+	p.relayed.Add(digest, struct{}{})
+}
+
+// announce announces a pooled attestation again, to reach peers which missed
+// it or connected since. The attestation is pooled again if its event expired
+// meanwhile.
+func (p *Pool) announce(att *types.BridgeAttestation) {
+	if !p.put(att) {
+		p.feed.Send(core.NewBridgeAttestationEvent{Attestation: att})
+	}
+}
+
+// SubscribeNewAttestationEvent registers a subscription of NewBridgeAttestationEvent
+// and starts sending event to the given channel.
+func (p *Pool) SubscribeNewAttestationEvent(ch chan<- core.NewBridgeAttestationEvent) event.Subscription {
+	return p.scope.Track(p.feed.Subscribe(ch))
+}
+
+// Close terminates all the subscriptions of the pool.
+func (p *Pool) Close() {
+	p.scope.Close()
+}
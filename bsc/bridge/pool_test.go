@@ -0,0 +1,97 @@
+package bridge
+
+import (
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+	blscommon "github.com/prysmaticlabs/prysm/v5/crypto/bls/common"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+func newTestPool(t *testing.T, size int) (*Pool, []blscommon.SecretKey) {
+	var (
+		keys      []blscommon.SecretKey
+		committee []types.BLSPublicKey
+	)
+	for i := 0; i < size; i++ {
+		key, err := bls.RandKey()
+		if err != nil {
+			t.Fatalf("failed to create BLS key: %v", err)
+		}
+		keys = append(keys, key)
+		committee = append(committee, types.BLSPublicKey(key.PublicKey().Marshal()))
+	}
+	return NewPool(committee), keys
+}
+
+func signAttestation(key blscommon.SecretKey, digest common.Hash) *types.BridgeAttestation {
+	att := &types.BridgeAttestation{Digest: digest}
+	copy(att.VoteAddress[:], key.PublicKey().Marshal())
+	hash := att.SigningHash()
+	copy(att.Signature[:], key.Sign(hash[:]).Marshal())
+	return att
+}
+
+// Tests that events not relayed within their lifetime are dropped along with
+// their attestations, and that announcing an own attestation again revives it.
+func TestPoolExpiry(t *testing.T) {
+	pool, keys := newTestPool(t, 2)
+
+	own := signAttestation(keys[0], common.Hash{0x01})
+	pool.PutAttestation(own)
+	pool.PutAttestation(signAttestation(keys[1], common.Hash{0x01}))
+	pool.PutAttestation(signAttestation(keys[1], common.Hash{0x02}))
+
+	pool.mu.Lock()
+	pool.pending[common.Hash{0x02}].created = time.Now().Add(-pendingLifetime)
+	pool.expire(time.Now())
+	pool.mu.Unlock()
+
+	if atts := pool.Attestations(common.Hash{0x02}); atts != nil {
+		t.Fatalf("expired event still pending: %v", atts)
+	}
+	if atts := pool.Attestations(common.Hash{0x01}); len(atts) != 2 || atts[0] == nil || atts[1] == nil {
+		t.Fatalf("live event attestations mismatch: %v", atts)
+	}
+	if pool.attested[0] != 1 || pool.attested[1] != 1 {
+		t.Fatalf("attested counts mismatch: %v", pool.attested)
+	}
+	pool.mu.Lock()
+	pool.expire(time.Now().Add(pendingLifetime))
+	pool.mu.Unlock()
+
+	if len(pool.pending) != 0 || pool.attested[0] != 0 || pool.attested[1] != 0 {
+		t.Fatalf("events left after expiry: %d pending, attested %v", len(pool.pending), pool.attested)
+	}
+	pool.announce(own)
+	if atts := pool.Attestations(common.Hash{0x01}); len(atts) != 2 || atts[0] == nil || atts[1] != nil {
+		t.Fatalf("announced attestation not pooled again: %v", atts)
+	}
+}
+
+// Tests that a committee member cannot keep more than a limited number of
+// events pending, while the others still can.
+func TestPoolAttesterLimit(t *testing.T) {
+	pool, keys := newTestPool(t, 2)
+
+	pool.attested[0] = maxPendingPerAttester - 1
+	pool.PutAttestation(signAttestation(keys[0], common.Hash{0x01}))
+	pool.PutAttestation(signAttestation(keys[0], common.Hash{0x02}))
+	pool.PutAttestation(signAttestation(keys[1], common.Hash{0x02}))
+
+	if atts := pool.Attestations(common.Hash{0x01}); len(atts) != 2 || atts[0] == nil {
+		t.Fatalf("attestation within the limit dropped: %v", atts)
+	}
+	if atts := pool.Attestations(common.Hash{0x02}); len(atts) != 2 || atts[0] != nil || atts[1] == nil {
+		t.Fatalf("attestations beyond the limit mismatch: %v", atts)
+	}
+	// Relaying an event frees the slots of its attesters
+	pool.markRelayed(common.Hash{0x01})
+	pool.PutAttestation(signAttestation(keys[0], common.Hash{0x02}))
+	if atts := pool.Attestations(common.Hash{0x02}); atts[0] == nil {
+		t.Fatal("attestation dropped after freeing a slot")
+	}
+}
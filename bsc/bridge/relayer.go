@@ -0,0 +1,526 @@
+// Package bridge implements a relayer of the events of a remote chain, which are
+// attested by the validators with their BLS vote keys and minted on the local
+// chain once enough of them agree.
+//
+// Every relayer watches the remote chain on its own and attests the configured
+// events once they are buried under enough blocks. The attestations are gossiped
+// over the `bsc` protocol, and once the threshold is reached, the relayers with
+// a submission key aggregate the signatures and submit the mint transaction.
+// Multiple relayers may submit the same event, so the mint contract has to
+// verify the aggregate signature over keccak256("BSC_BRIDGE_ATTESTATION" ||
+// digest) of the event and ignore events it has already minted.
+//
+// The remote block to resume watching from is persisted, so that events
+// confirmed while the relayer is down are attested once it is back.
+package bridge
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"encoding/binary"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vote"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/node"
+)
+
+const (
+	// maxLogRange is the maximum number of remote blocks filtered at once.
+	maxLogRange = 1000
+
+	// rpcTimeout is the timeout of the requests to either chain.
+	rpcTimeout = 10 * time.Second
+
+	// reannounceInterval is the interval of announcing the own attestations of
+	// the events which did not reach the threshold yet.
+	reannounceInterval = time.Minute
+
+	// attestationChanSize is the size of channel listening to NewBridgeAttestationEvent.
+	attestationChanSize = 256
+)
+
+// cursorPrefix + remote contract -> remote block to resume watching from
+var cursorPrefix = []byte("bridge-cursor-")
+
+// mintABI is the interface of the mint contract on the local chain.
+const mintABI = `[{"type":"function","name":"mint","stateMutability":"nonpayable","inputs":[{"name":"event","type":"bytes"},{"name":"signers","type":"uint256"},{"name":"signature","type":"bytes"}],"outputs":[]}]`
+
+var (
+	mintMethod  abi.ABI
+	eventLayout abi.Arguments
+)
+
+func init() {
+	var err error
+	if mintMethod, err = abi.JSON(strings.NewReader(mintABI)); err != nil {
+		panic(err)
+	}
+	for _, typ := range []string{"uint256", "address", "bytes32", "uint256", "uint256", "bytes32[]", "bytes"} {
+		t, err := abi.NewType(typ, "", nil)
+		if err != nil {
+			panic(err)
+		}
+		eventLayout = append(eventLayout, abi.Argument{Type: t})
+	}
+}
+
+// RemoteChain is the access to the watched chain needed by the relayer.
+type RemoteChain interface {
+	ethereum.BlockNumberReader
+	ethereum.LogFilterer
+	ethereum.ChainIDReader
+}
+
+// LocalChain is the access to the local chain needed to submit mint transactions.
+type LocalChain interface {
+	ethereum.PendingStateReader
+	ethereum.GasPricer
+	ethereum.GasEstimator
+	ethereum.TransactionSender
+	ethereum.ChainIDReader
+}
+
+// Signer signs attestations with a validator's BLS vote key.
+type Signer interface {
+	SignBridgeAttestation(att *types.BridgeAttestation) error
+}
+
+// Event is an event of the remote chain attested by the relayers.
+type Event struct {
+	ChainID     *big.Int
+	Source      common.Address
+	TxHash      common.Hash
+	LogIndex    uint
+	BlockNumber uint64
+	Topics      []common.Hash
+	Data        []byte
+}
+
+// newEvent creates the event of a remote log.
+func newEvent(chainID *big.Int, l *types.Log) *Event {
+	return &Event{
+		ChainID:     chainID,
+		Source:      l.Address,
+		TxHash:      l.TxHash,
+		LogIndex:    l.Index,
+		BlockNumber: l.BlockNumber,
+		Topics:      l.Topics,
+		Data:        l.Data,
+	}
+}
+
+// Encode returns the ABI encoding of the event passed to the mint contract:
+//
+//	abi.encode(uint256 chainId, address source, bytes32 txHash, uint256 logIndex,
+//	           uint256 blockNumber, bytes32[] topics, bytes data)
+func (e *Event) Encode() []byte {
+	topics := make([][32]byte, len(e.Topics))
+	for i, topic := range e.Topics {
+		topics[i] = topic
+	}
+	blob, err := eventLayout.Pack(e.ChainID, e.Source, [32]byte(e.TxHash), new(big.Int).SetUint64(uint64(e.LogIndex)), new(big.Int).SetUint64(e.BlockNumber), topics, e.Data)
+	if err != nil {
+		panic(err) // all values match the layout
+	}
+	return blob
+}
+
+// Digest returns the digest of the event signed by the attesters, which is the
+// keccak256 hash of its encoding.
+func (e *Event) Digest() common.Hash {
+	return crypto.Keccak256Hash(e.Encode())
+}
+
+// pendingEvent is an attested event waiting for the threshold to be reached.
+type pendingEvent struct {
+	event     *Event
+	own       *types.BridgeAttestation
+	announced time.Time
+}
+
+// Relayer watches the remote chain for the configured events, attests them and
+// mints the ones attested by enough validators. It implements node.Lifecycle.
+type Relayer struct {
+	config *Config
+	remote RemoteChain
+	local  LocalChain
+	signer Signer
+	key    *ecdsa.PrivateKey // Key paying for the mint transactions, nil if not submitting
+	pool   *Pool
+	db     ethdb.KeyValueStore // Database persisting the watch cursor
+	closer func()              // Closes the connections opened by the relayer
+
+	chainID *big.Int                      // Chain ID of the remote chain, fetched once reachable
+	next    uint64                        // Next remote block to filter
+	saved   uint64                        // Cursor last persisted, the block to resume from
+	started bool                          // Whether the first remote block is decided
+	pending map[common.Hash]*pendingEvent // Attested events by digest
+
+	attCh chan core.NewBridgeAttestationEvent
+	sub   event.Subscription
+	quit  chan struct{}
+	wg    sync.WaitGroup
+}
+
+// New creates a relayer attesting with the BLS vote key of the node and
+// registers it on the given node.
+func New(stack *node.Node, config *Config) (*Relayer, error) {
+	conf := stack.Config()
+	signer, err := vote.NewVoteSigner(stack.ResolvePath(conf.BLSPasswordFile), stack.ResolvePath(conf.BLSWalletDir))
+	if err != nil {
+		return nil, fmt.Errorf("failed to load BLS vote key: %v", err)
+	}
+	var key *ecdsa.PrivateKey
+	if config.SubmitKeyFile != "" {
+		if key, err = crypto.LoadECDSA(config.SubmitKeyFile); err != nil {
+			return nil, fmt.Errorf("failed to load submission key: %v", err)
+		}
+	}
+	if !isAttester(config.Attesters, signer.PubKey) {
+		return nil, fmt.Errorf("BLS vote key %x is not an attester", signer.PubKey)
+	}
+	db, err := stack.OpenDatabase("bridge", 16, 16, "eth/db/bridge/", false)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open bridge database: %v", err)
+	}
+	remote, err := ethclient.Dial(config.RemoteURL)
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to connect to the remote chain: %v", err)
+	}
+	local := ethclient.NewClient(stack.Attach())
+
+	r, err := NewRelayer(config, db, remote, local, signer, key)
+	if err != nil {
+		remote.Close()
+		local.Close()
+		db.Close()
+		return nil, err
+	}
+	r.closer = func() {
+		remote.Close()
+		local.Close()
+		db.Close()
+	}
+	stack.RegisterLifecycle(r)
+	return r, nil
+}
+
+// NewRelayer creates a relayer between the given chains, persisting its
+// progress in db. If key is nil, the relayer only attests events and leaves
+// minting to the others.
+func NewRelayer(config *Config, db ethdb.KeyValueStore, remote RemoteChain, local LocalChain, signer Signer, key *ecdsa.PrivateKey) (*Relayer, error) {
+	conf := *config
+	if err := conf.sanitize(); err != nil {
+		return nil, err
+	}
+	return &Relayer{
+		config:  &conf,
+		remote:  remote,
+		local:   local,
+		signer:  signer,
+		key:     key,
+		db:      db,
+		pool:    NewPool(conf.Attesters),
+		pending: make(map[common.Hash]*pendingEvent),
+		quit:    make(chan struct{}),
+	}, nil
+}
+
+// Pool returns the attestation pool of the relayer, whose attestations are
+// to be exchanged with the other relayers.
+func (r *Relayer) Pool() *Pool {
+	return r.pool
+}
+
+// Start implements node.Lifecycle, starting to watch the remote chain.
+func (r *Relayer) Start() error {
+	r.attCh = make(chan core.NewBridgeAttestationEvent, attestationChanSize)
+	r.sub = r.pool.SubscribeNewAttestationEvent(r.attCh)
+
+	r.wg.Add(1)
+	go r.loop()
+
+	log.Info("Bridge relayer started", "contract", r.config.Contract, "mint", r.config.MintContract, "threshold", r.config.Threshold, "attesters", len(r.config.Attesters), "submit", r.key != nil)
+	return nil
+}
+
+// Stop implements node.Lifecycle, terminating the relayer.
+func (r *Relayer) Stop() error {
+	close(r.quit)
+	r.wg.Wait()
+	r.sub.Unsubscribe()
+	r.pool.Close()
+	if r.closer != nil {
+		r.closer()
+	}
+	log.Info("Bridge relayer stopped")
+	return nil
+}
+
+// loop polls the remote chain and relays the events reaching the threshold.
+func (r *Relayer) loop() {
+	defer r.wg.Done()
+
+	ticker := time.NewTicker(r.config.PollInterval)
+	defer ticker.Stop()
+
+	r.poll()
+	for {
+		select {
+		case <-ticker.C:
+			r.poll()
+
+		case ev := <-r.attCh:
+			r.relay(ev.Attestation.Digest)
+
+		case <-r.sub.Err():
+			return
+
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// poll attests the newly confirmed events of the remote chain and retries the
+// pending ones.
+func (r *Relayer) poll() {
+	if err := r.watch(); err != nil {
+		log.Warn("Failed to watch the remote chain", "err", err)
+	}
+	for digest, p := range r.pending {
+		if time.Since(p.announced) > reannounceInterval {
+			r.pool.announce(p.own)
+			p.announced = time.Now()
+		}
+		r.relay(digest)
+	}
+	r.checkpoint()
+}
+
+// watch filters the configured events up to the confirmed head of the remote
+// chain and attests them.
+func (r *Relayer) watch() error {
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+
+	if r.chainID == nil {
+		chainID, err := r.remote.ChainID(ctx)
+		if err != nil {
+			return err
+		}
+		r.chainID = chainID
+	}
+	head, err := r.remote.BlockNumber(ctx)
+	if err != nil {
+		return err
+	}
+	if head < r.config.Confirmations {
+		return nil
+	}
+	confirmed := head - r.config.Confirmations
+	if !r.started {
+		next, ok, err := r.readCursor()
+		if err != nil {
+			return err
+		}
+		switch {
+		case ok:
+			r.next, r.saved = next, next
+		case r.config.StartBlock != 0:
+			r.next = r.config.StartBlock
+		default:
+			r.next = confirmed
+		}
+		r.started = true
+	}
+	var topics [][]common.Hash
+	if len(r.config.Events) > 0 {
+		topics = [][]common.Hash{r.config.Events}
+	}
+	for r.next <= confirmed {
+		to := min(confirmed, r.next+maxLogRange-1)
+		logs, err := r.remote.FilterLogs(ctx, ethereum.FilterQuery{
+			FromBlock: new(big.Int).SetUint64(r.next),
+			ToBlock:   new(big.Int).SetUint64(to),
+			Addresses: []common.Address{r.config.Contract},
+			Topics:    topics,
+		})
+		if err != nil {
+			return err
+		}
+		for i := range logs {
+			if !logs[i].Removed {
+				if err := r.attest(newEvent(r.chainID, &logs[i])); err != nil {
+					return err
+				}
+			}
+		}
+		r.next = to + 1
+	}
+	return nil
+}
+
+// cursorKey returns the database key of the remote block to resume watching
+// the configured contract from.
+func (r *Relayer) cursorKey() []byte {
+	return append(append([]byte{}, cursorPrefix...), r.config.Contract.Bytes()...)
+}
+
+// readCursor returns the persisted remote block to resume watching from.
+func (r *Relayer) readCursor() (uint64, bool, error) {
+	if ok, err := r.db.Has(r.cursorKey()); !ok || err != nil {
+		return 0, false, err
+	}
+	blob, err := r.db.Get(r.cursorKey())
+	if err != nil {
+		return 0, false, err
+	}
+	if len(blob) != 8 {
+		return 0, false, fmt.Errorf("invalid bridge cursor %x", blob)
+	}
+	return binary.BigEndian.Uint64(blob), true, nil
+}
+
+// checkpoint persists the remote block to resume watching from after a restart,
+// which is the oldest one with an event still pending, so that it is attested
+// again.
+func (r *Relayer) checkpoint() {
+	if !r.started {
+		return
+	}
+	cursor := r.next
+	for _, p := range r.pending {
+		cursor = min(cursor, p.event.BlockNumber)
+	}
+	if cursor == r.saved {
+		return
+	}
+	if err := r.db.Put(r.cursorKey(), binary.BigEndian.AppendUint64(nil, cursor)); err != nil {
+		log.Warn("Failed to persist bridge cursor", "number", cursor, "err", err)
+		return
+	}
+	r.saved = cursor
+}
+
+// attest signs the event and adds the attestation to the pool to be gossiped.
+func (r *Relayer) attest(ev *Event) error {
+	digest := ev.Digest()
+	if _, ok := r.pending[digest]; ok {
+		return nil
+	}
+	att := &types.BridgeAttestation{Digest: digest}
+	if err := r.signer.SignBridgeAttestation(att); err != nil {
+		return fmt.Errorf("failed to sign attestation: %v", err)
+	}
+	r.pending[digest] = &pendingEvent{event: ev, own: att, announced: time.Now()}
+	r.pool.PutAttestation(att)
+
+	log.Info("Attested bridge event", "tx", ev.TxHash, "index", ev.LogIndex, "number", ev.BlockNumber, "digest", digest)
+	r.relay(digest)
+	return nil
+}
+
+// relay submits the mint transaction of an attested event once it reached the
+// threshold.
+func (r *Relayer) relay(digest common.Hash) {
+	p := r.pending[digest]
+	if p == nil {
+		return
+	}
+	var (
+		signers = new(big.Int)
+		sigs    [][]byte
+	)
+	for i, att := range r.pool.Attestations(digest) {
+		if att != nil {
+			signers.SetBit(signers, i, 1)
+			sigs = append(sigs, att.Signature[:])
+		}
+	}
+	if len(sigs) < r.config.Threshold {
+		return
+	}
+	if r.key != nil {
+		if err := r.submit(p.event, signers, sigs); err != nil {
+			log.Warn("Failed to submit bridge mint", "tx", p.event.TxHash, "index", p.event.LogIndex, "err", err)
+			return
+		}
+	}
+	delete(r.pending, digest)
+	r.pool.markRelayed(digest)
+}
+
+// submit aggregates the signatures of the event and sends the mint transaction.
+func (r *Relayer) submit(ev *Event, signers *big.Int, sigs [][]byte) error {
+	signatures, err := bls.MultipleSignaturesFromBytes(sigs)
+	if err != nil {
+		return err
+	}
+	input, err := mintMethod.Pack("mint", ev.Encode(), signers, bls.AggregateSignatures(signatures).Marshal())
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), rpcTimeout)
+	defer cancel()
+
+	from := crypto.PubkeyToAddress(r.key.PublicKey)
+	chainID, err := r.local.ChainID(ctx)
+	if err != nil {
+		return err
+	}
+	nonce, err := r.local.PendingNonceAt(ctx, from)
+	if err != nil {
+		return err
+	}
+	gasPrice, err := r.local.SuggestGasPrice(ctx)
+	if err != nil {
+		return err
+	}
+	gas, err := r.local.EstimateGas(ctx, ethereum.CallMsg{From: from, To: &r.config.MintContract, Data: input})
+	if err != nil {
+		return err
+	}
+	tx, err := types.SignNewTx(r.key, types.LatestSignerForChainID(chainID), &types.LegacyTx{
+		Nonce:    nonce,
+		To:       &r.config.MintContract,
+		Gas:      gas,
+		GasPrice: gasPrice,
+		Data:     input,
+	})
+	if err != nil {
+		return err
+	}
+	if err := r.local.SendTransaction(ctx, tx); err != nil {
+		return err
+	}
+	log.Info("Submitted bridge mint", "tx", ev.TxHash, "index", ev.LogIndex, "signers", len(sigs), "hash", tx.Hash())
+	return nil
+}
+
+// isAttester reports whether key is one of the attesters.
+func isAttester(attesters []types.BLSPublicKey, key [48]byte) bool {
+	for _, attester := range attesters {
+		if attester == key {
+			return true
+		}
+	}
+	return false
+}
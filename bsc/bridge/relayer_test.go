@@ -0,0 +1,327 @@
+package bridge_test
+
+import (
+	"bytes"
+	"context"
+	"math/big"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls/common"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/bridge"
+	ecommon "github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient/simulated"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// testSigner signs attestations with a plain BLS key.
+type testSigner struct {
+	key common.SecretKey
+}
+
+func (s *testSigner) SignBridgeAttestation(att *types.BridgeAttestation) error {
+	copy(att.VoteAddress[:], s.key.PublicKey().Marshal())
+	hash := att.SigningHash()
+	copy(att.Signature[:], s.key.Sign(hash[:]).Marshal())
+	return nil
+}
+
+func newTestCommittee(t *testing.T, size int) ([]*testSigner, []types.BLSPublicKey) {
+	var (
+		signers   []*testSigner
+		attesters []types.BLSPublicKey
+	)
+	for i := 0; i < size; i++ {
+		key, err := bls.RandKey()
+		if err != nil {
+			t.Fatalf("failed to create BLS key: %v", err)
+		}
+		signers = append(signers, &testSigner{key})
+		attesters = append(attesters, types.BLSPublicKey(key.PublicKey().Marshal()))
+	}
+	return signers, attesters
+}
+
+// gossip forwards the attestations entering any of the pools to all others,
+// standing in for the `bsc` protocol.
+func gossip(t *testing.T, pools []*bridge.Pool) {
+	for _, pool := range pools {
+		ch := make(chan core.NewBridgeAttestationEvent, 64)
+		sub := pool.SubscribeNewAttestationEvent(ch)
+		t.Cleanup(sub.Unsubscribe)
+
+		go func(src *bridge.Pool) {
+			for {
+				select {
+				case ev := <-ch:
+					for _, dst := range pools {
+						if dst != src {
+							dst.PutAttestation(ev.Attestation)
+						}
+					}
+				case <-sub.Err():
+					return
+				}
+			}
+		}(pool)
+	}
+}
+
+// Tests that deposits on a remote chain are attested by the relayers once
+// confirmed and minted on the local chain once the threshold is reached.
+func TestRelayer(t *testing.T) {
+	var (
+		depositKey, _   = crypto.GenerateKey()
+		depositor       = crypto.PubkeyToAddress(depositKey.PublicKey)
+		submitKey, _    = crypto.GenerateKey()
+		submitter       = crypto.PubkeyToAddress(submitKey.PublicKey)
+		vault           = ecommon.HexToAddress("0xa017")
+		mint            = ecommon.HexToAddress("0x3141")
+		depositTopic    = crypto.Keccak256Hash([]byte("Deposit(bytes)"))
+		signers, commit = newTestCommittee(t, 7)
+	)
+	// The remote vault logs the call data as a deposit:
+	// calldatacopy(0, 0, calldatasize()); log1(0, calldatasize(), topic)
+	code := append(append([]byte{0x36, 0x5f, 0x5f, 0x37, 0x7f}, depositTopic[:]...), 0x36, 0x5f, 0xa1, 0x00)
+	remote := simulated.NewBackend(types.GenesisAlloc{
+		depositor: {Balance: big.NewInt(params.Ether)},
+		vault:     {Code: code, Balance: new(big.Int)},
+	})
+	defer remote.Close()
+	local := simulated.NewBackend(types.GenesisAlloc{
+		submitter: {Balance: big.NewInt(params.Ether)},
+	})
+	defer local.Close()
+
+	// Run five of the seven attesters, one of them submitting
+	config := &bridge.Config{
+		Contract:      vault,
+		Events:        []ecommon.Hash{depositTopic},
+		Confirmations: 2,
+		StartBlock:    1,
+		Attesters:     commit,
+		Threshold:     5,
+		MintContract:  mint,
+		PollInterval:  10 * time.Millisecond,
+	}
+	var pools []*bridge.Pool
+	for i := 0; i < 5; i++ {
+		key := submitKey
+		if i != 0 {
+			key = nil
+		}
+		relayer, err := bridge.NewRelayer(config, rawdb.NewMemoryDatabase(), remote.Client(), local.Client(), signers[i], key)
+		if err != nil {
+			t.Fatalf("failed to create relayer: %v", err)
+		}
+		pools = append(pools, relayer.Pool())
+		if err := relayer.Start(); err != nil {
+			t.Fatalf("failed to start relayer: %v", err)
+		}
+		defer relayer.Stop()
+	}
+	gossip(t, pools)
+
+	// Deposit on the remote chain
+	var (
+		ctx     = context.Background()
+		payload = []byte("deposit 1 coin")
+		signer  = types.LatestSignerForChainID(params.AllDevChainProtocolChanges.ChainID)
+	)
+	gasPrice, _ := remote.Client().SuggestGasPrice(ctx)
+	tx := types.MustSignNewTx(depositKey, signer, &types.LegacyTx{To: &vault, Gas: 100000, GasPrice: gasPrice, Data: payload})
+	if err := remote.Client().SendTransaction(ctx, tx); err != nil {
+		t.Fatalf("failed to send deposit: %v", err)
+	}
+	remote.Commit()
+
+	// Nothing may be minted before the deposit is confirmed
+	time.Sleep(100 * time.Millisecond)
+	if nonce, _ := local.Client().PendingNonceAt(ctx, submitter); nonce != 0 {
+		t.Fatal("unconfirmed deposit minted")
+	}
+	remote.Commit()
+	remote.Commit()
+
+	deadline := time.Now().Add(10 * time.Second)
+	for {
+		if nonce, _ := local.Client().PendingNonceAt(ctx, submitter); nonce == 1 {
+			break
+		}
+		if time.Now().After(deadline) {
+			t.Fatal("deposit not minted")
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	local.Commit()
+	block, err := local.Client().BlockByNumber(ctx, nil)
+	if err != nil || len(block.Transactions()) != 1 {
+		t.Fatalf("failed to retrieve the mint transaction: %v", err)
+	}
+	mintTx := block.Transactions()[0]
+	if *mintTx.To() != mint {
+		t.Fatalf("mint sent to %v, want %v", mintTx.To(), mint)
+	}
+
+	// Check the minted event and its aggregate signature
+	mintABI, _ := abi.JSON(strings.NewReader(`[{"type":"function","name":"mint","inputs":[{"name":"event","type":"bytes"},{"name":"signers","type":"uint256"},{"name":"signature","type":"bytes"}]}]`))
+	args, err := mintABI.Methods["mint"].Inputs.Unpack(mintTx.Data()[4:])
+	if err != nil {
+		t.Fatalf("failed to unpack mint: %v", err)
+	}
+	var (
+		encoded = args[0].([]byte)
+		bitmap  = args[1].(*big.Int)
+		aggSig  = args[2].([]byte)
+	)
+	want := &bridge.Event{
+		ChainID:     params.AllDevChainProtocolChanges.ChainID,
+		Source:      vault,
+		TxHash:      tx.Hash(),
+		BlockNumber: 1,
+		Topics:      []ecommon.Hash{depositTopic},
+		Data:        payload,
+	}
+	if !bytes.Equal(encoded, want.Encode()) {
+		t.Fatalf("minted event mismatch:\nhave %x\nwant %x", encoded, want.Encode())
+	}
+	if bitmap.Uint64() != 0x1f {
+		t.Fatalf("signers mismatch: have %b, want %b", bitmap, 0x1f)
+	}
+	sig, err := bls.SignatureFromBytes(aggSig)
+	if err != nil {
+		t.Fatalf("invalid aggregate signature: %v", err)
+	}
+	var pubkeys []common.PublicKey
+	for _, s := range signers[:5] {
+		pubkeys = append(pubkeys, s.key.PublicKey())
+	}
+	signed := (&types.BridgeAttestation{Digest: want.Digest()}).SigningHash()
+	if !sig.FastAggregateVerify(pubkeys, signed) {
+		t.Fatal("aggregate signature verification failed")
+	}
+}
+
+// Tests that a restarted relayer resumes watching from where it stopped, and
+// attests the events confirmed in between.
+func TestRelayerResume(t *testing.T) {
+	var (
+		depositKey, _   = crypto.GenerateKey()
+		depositor       = crypto.PubkeyToAddress(depositKey.PublicKey)
+		vault           = ecommon.HexToAddress("0xa017")
+		depositTopic    = crypto.Keccak256Hash([]byte("Deposit(bytes)"))
+		signers, commit = newTestCommittee(t, 1)
+		db              = rawdb.NewMemoryDatabase()
+	)
+	// log1(0, 0, topic)
+	code := append(append([]byte{0x7f}, depositTopic[:]...), 0x5f, 0x5f, 0xa1, 0x00)
+	remote := simulated.NewBackend(types.GenesisAlloc{
+		depositor: {Balance: big.NewInt(params.Ether)},
+		vault:     {Code: code, Balance: new(big.Int)},
+	})
+	defer remote.Close()
+	local := simulated.NewBackend(types.GenesisAlloc{})
+	defer local.Close()
+
+	config := &bridge.Config{
+		Contract:      vault,
+		Confirmations: 2,
+		Attesters:     commit,
+		Threshold:     1,
+		MintContract:  ecommon.HexToAddress("0x3141"),
+		PollInterval:  10 * time.Millisecond,
+	}
+	start := func() (*bridge.Relayer, chan core.NewBridgeAttestationEvent) {
+		relayer, err := bridge.NewRelayer(config, db, remote.Client(), local.Client(), signers[0], nil)
+		if err != nil {
+			t.Fatalf("failed to create relayer: %v", err)
+		}
+		ch := make(chan core.NewBridgeAttestationEvent, 4)
+		relayer.Pool().SubscribeNewAttestationEvent(ch)
+		if err := relayer.Start(); err != nil {
+			t.Fatalf("failed to start relayer: %v", err)
+		}
+		return relayer, ch
+	}
+	for i := 0; i < 3; i++ {
+		remote.Commit()
+	}
+	relayer, _ := start()
+	time.Sleep(100 * time.Millisecond)
+	relayer.Stop()
+
+	// Deposit while the relayer is down and bury it below the confirmed head
+	ctx := context.Background()
+	gasPrice, _ := remote.Client().SuggestGasPrice(ctx)
+	signer := types.LatestSignerForChainID(params.AllDevChainProtocolChanges.ChainID)
+	tx := types.MustSignNewTx(depositKey, signer, &types.LegacyTx{To: &vault, Gas: 100000, GasPrice: gasPrice})
+	if err := remote.Client().SendTransaction(ctx, tx); err != nil {
+		t.Fatalf("failed to send deposit: %v", err)
+	}
+	for i := 0; i < 5; i++ {
+		remote.Commit()
+	}
+	relayer, ch := start()
+	defer relayer.Stop()
+
+	select {
+	case ev := <-ch:
+		want := &bridge.Event{
+			ChainID:     params.AllDevChainProtocolChanges.ChainID,
+			Source:      vault,
+			TxHash:      tx.Hash(),
+			BlockNumber: 4,
+			Topics:      []ecommon.Hash{depositTopic},
+			Data:        []byte{},
+		}
+		if ev.Attestation.Digest != want.Digest() {
+			t.Fatalf("attested digest mismatch: have %x, want %x", ev.Attestation.Digest, want.Digest())
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("deposit confirmed during the downtime not attested")
+	}
+}
+
+// Tests that the pool only accepts valid attestations of committee members.
+func TestPool(t *testing.T) {
+	var (
+		signers, committee = newTestCommittee(t, 2)
+		outsider, _        = newTestCommittee(t, 1)
+		pool               = bridge.NewPool(committee)
+		digest             = ecommon.Hash{0x01}
+	)
+	ch := make(chan core.NewBridgeAttestationEvent, 4)
+	sub := pool.SubscribeNewAttestationEvent(ch)
+	defer sub.Unsubscribe()
+
+	put := func(s *testSigner, modify func(*types.BridgeAttestation)) {
+		att := &types.BridgeAttestation{Digest: digest}
+		s.SignBridgeAttestation(att)
+		modify(att)
+		pool.PutAttestation(att)
+	}
+	put(signers[0], func(*types.BridgeAttestation) {})
+	put(signers[0], func(*types.BridgeAttestation) {})                                      // duplicate
+	put(outsider[0], func(*types.BridgeAttestation) {})                                     // unknown attester
+	put(signers[1], func(att *types.BridgeAttestation) { att.Digest = ecommon.Hash{0x02} }) // wrong digest
+	put(signers[1], func(att *types.BridgeAttestation) { att.Signature[10] ^= 1 })          // corrupt signature
+	put(signers[1], func(att *types.BridgeAttestation) {                                    // digest signed without the domain
+		copy(att.Signature[:], signers[1].key.Sign(digest[:]).Marshal())
+	})
+
+	if len(ch) != 1 {
+		t.Fatalf("announced attestations mismatch: have %d, want 1", len(ch))
+	}
+	atts := pool.Attestations(digest)
+	if len(atts) != 2 || atts[0] == nil || atts[1] != nil {
+		t.Fatalf("pooled attestations mismatch: %v", atts)
+	}
+}
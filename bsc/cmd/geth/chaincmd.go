@@ -195,7 +195,9 @@ be gzipped.`,
 		Flags:     slices.Concat([]cli.Flag{utils.TxLookupLimitFlag, utils.TransactionHistoryFlag}, utils.DatabaseFlags, utils.NetworkFlags),
 		Description: `
 The import-history command will import blocks and their corresponding receipts
-from Era archives.
+from Era archives. Archives of Parlia chains are verified against their
+accumulator root, and their headers by replaying the Parlia header verification
+from the local genesis.
 `,
 	}
 	exportHistoryCommand = &cli.Command{
@@ -206,7 +208,9 @@ from Era archives.
 		Flags:     utils.DatabaseFlags,
 		Description: `
 The export-history command will export blocks and their corresponding receipts
-into Era archives. Eras are typically packaged in steps of 8192 blocks.
+into Era archives. Eras are typically packaged in steps of 8192 blocks. Parlia
+chains are exported with the vote attestations instead of the total difficulty,
+and a checksum manifest for download-era is written next to the archives.
 `,
 	}
 	importPreimagesCommand = &cli.Command{
@@ -279,6 +283,7 @@ helps reduce storage requirements for nodes that don't need full historical data
 				eraEpochFlag,
 				eraAllFlag,
 				eraServerFlag,
+				eraChecksumsFlag,
 			},
 		),
 	}
@@ -301,6 +306,10 @@ var (
 		Name:  "server",
 		Usage: "era1 server URL",
 	}
+	eraChecksumsFlag = &cli.StringFlag{
+		Name:  "checksums",
+		Usage: "Checksum manifest of the era1 files, as written by export-history (required for networks without built-in checksums)",
+	}
 )
 
 const (
@@ -930,7 +939,7 @@ func importHistory(ctx *cli.Context) error {
 	if utils.IsNetworkPreset(ctx) {
 		switch {
 		case ctx.Bool(utils.BSCMainnetFlag.Name):
-			network = "mainnet"
+			network = "bsc"
 		case ctx.Bool(utils.ChapelFlag.Name):
 			network = "chapel"
 		}
@@ -1212,6 +1221,10 @@ func downloadEra(ctx *cli.Context) error {
 	var network = "mainnet"
 	if utils.IsNetworkPreset(ctx) {
 		switch {
+		case ctx.IsSet(eraChecksumsFlag.Name) && ctx.Bool(utils.BSCMainnetFlag.Name):
+			network = "bsc"
+		case ctx.IsSet(eraChecksumsFlag.Name) && ctx.Bool(utils.ChapelFlag.Name):
+			network = "chapel"
 		default:
 			return errors.New("unsupported network, no known era1 checksums")
 		}
@@ -1232,7 +1245,19 @@ func downloadEra(ctx *cli.Context) error {
 		return fmt.Errorf("need --%s flag to download", eraServerFlag.Name)
 	}
 
-	l, err := eradl.New(baseURL, network)
+	var (
+		l   *eradl.Loader
+		err error
+	)
+	if ctx.IsSet(eraChecksumsFlag.Name) {
+		checksums, rerr := os.ReadFile(ctx.String(eraChecksumsFlag.Name))
+		if rerr != nil {
+			return fmt.Errorf("unable to read checksums: %w", rerr)
+		}
+		l, err = eradl.NewWithChecksums(baseURL, network, checksums)
+	} else {
+		l, err = eradl.New(baseURL, network)
+	}
 	if err != nil {
 		return err
 	}
@@ -335,6 +335,13 @@ func makeFullNode(ctx *cli.Context) (*node.Node, ethapi.Backend) {
 	if cfg.Ethstats.URL != "" {
 		utils.RegisterEthStatsService(stack, backend, cfg.Ethstats.URL)
 	}
+	// Add the bridge relayer if requested, or forward the attestations of the
+	// committee without one.
+	if ctx.IsSet(utils.BridgeRemoteURLFlag.Name) && eth != nil {
+		utils.RegisterBridgeRelayerService(stack, eth, utils.MakeBridgeConfig(ctx))
+	} else if ctx.IsSet(utils.BridgeAttestersFlag.Name) && eth != nil {
+		eth.SetBridgeCommittee(utils.MakeBridgeAttesters(ctx))
+	}
 
 	if ctx.IsSet(utils.DeveloperFlag.Name) {
 		// Start dev mode.
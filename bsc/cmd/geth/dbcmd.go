@@ -39,6 +39,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/blobstore"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/trie"
@@ -57,6 +58,10 @@ var (
 		Name:  "remove.chain",
 		Usage: "If set, selects the state data for removal",
 	}
+	migrateRestoreFlag = &cli.BoolFlag{
+		Name:  "restore",
+		Usage: "If set, moves the offloaded ancient data files back to the local disk",
+	}
 
 	removedbCommand = &cli.Command{
 		Action:    removeDB,
@@ -94,6 +99,7 @@ Remove blockchain and state databases`,
 			dbDeleteTrieStateCmd,
 			ancientInspectCmd,
 			incrInspectCmd,
+			dbMigrateAncientsCmd,
 		},
 	}
 	dbInspectCmd = &cli.Command{
@@ -276,6 +282,18 @@ of ancientStore, will also displays the reserved number of blocks in ancientStor
 		}, utils.NetworkFlags, utils.DatabaseFlags),
 		Description: "This command queries the history of the account or storage slot within the specified block range",
 	}
+	dbMigrateAncientsCmd = &cli.Command{
+		Action: migrateAncients,
+		Name:   "migrate-ancients",
+		Usage:  "Move the completed ancient data files to the cold store",
+		Flags: slices.Concat([]cli.Flag{
+			migrateRestoreFlag,
+		}, utils.NetworkFlags, utils.DatabaseFlags),
+		Description: `This command moves the completed data files of the existing chain freezer
+tables to the blob store given by --datadir.ancient.coldstore, from where they are
+fetched back on demand. With --restore, the offloaded files are moved back to the
+local disk instead. The node must not be running.`,
+	}
 	incrInspectCmd = &cli.Command{
 		Action:      inspectIncrSnapshot,
 		Name:        "inspect-incr-snapshot",
@@ -511,6 +529,27 @@ func inspect(ctx *cli.Context) error {
 	return rawdb.InspectDatabase(db, prefix, start)
 }
 
+func migrateAncients(ctx *cli.Context) error {
+	location := ctx.String(utils.AncientColdStoreFlag.Name)
+	if location == "" {
+		return fmt.Errorf("missing --%s", utils.AncientColdStoreFlag.Name)
+	}
+	stack, _ := makeConfigNode(ctx)
+	ancient := stack.ResolveAncient("chaindata", ctx.String(utils.AncientFlag.Name))
+	stack.Close()
+
+	store, err := blobstore.Open(location)
+	if err != nil {
+		return err
+	}
+	start := time.Now()
+	if err := rawdb.MigrateAncients(ancient, store, ctx.Bool(migrateRestoreFlag.Name)); err != nil {
+		return err
+	}
+	log.Info("Migrated ancient data files", "restore", ctx.Bool(migrateRestoreFlag.Name), "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
 func ancientInspect(ctx *cli.Context) error {
 	stack, _ := makeConfigNode(ctx)
 	defer stack.Close()
@@ -101,6 +101,8 @@ var (
 		utils.TxPoolOverflowPoolSlotsFlag,
 		utils.TxPoolLifetimeFlag,
 		utils.TxPoolReannounceTimeFlag,
+		utils.TxPoolBundlesFlag,
+		utils.TxPoolMaxBundlesFlag,
 		utils.MinerTxGasLimitFlag,
 		utils.BlobPoolDataDirFlag,
 		utils.BlobPoolDataCapFlag,
@@ -114,11 +116,20 @@ var (
 		utils.TxLookupLimitFlag, // deprecated
 		utils.TransactionHistoryFlag,
 		utils.BlockHistoryFlag,
-		// utils.ChainHistoryFlag, // disabled in bsc
+		utils.ChainHistoryFlag,
+		utils.HistoryRecentFlag,
 		utils.LogHistoryFlag,
 		utils.LogNoHistoryFlag,
+		utils.LogNativeTransfersFlag,
+		utils.NativeTransfersFlag,
+		utils.AddressIndexFlag,
+		utils.AddressIndexInternalFlag,
 		utils.LogExportCheckpointsFlag,
 		utils.StateHistoryFlag,
+		utils.StateHistoryServeFlag,
+		utils.StatePruneIOBudgetFlag,
+		utils.StatePruneBloomSizeFlag,
+		utils.StatePruneIntervalFlag,
 		utils.PathDBSyncFlag,
 		utils.JournalFileFlag,
 		utils.LightKDFFlag,
@@ -184,10 +195,22 @@ var (
 		utils.EnableDoubleSignMonitorFlag,
 		utils.VotingEnabledFlag,
 		utils.DisableVoteAttestationFlag,
+		utils.PriceOracleURLFlag,
+		utils.PriceOracleFieldFlag,
 		utils.EnableMaliciousVoteMonitorFlag,
 		utils.BLSPasswordFileFlag,
 		utils.BLSWalletDirFlag,
 		utils.VoteJournalDirFlag,
+		utils.BridgeRemoteURLFlag,
+		utils.BridgeContractFlag,
+		utils.BridgeEventsFlag,
+		utils.BridgeConfirmationsFlag,
+		utils.BridgeStartBlockFlag,
+		utils.BridgeAttestersFlag,
+		utils.BridgeThresholdFlag,
+		utils.BridgeMintContractFlag,
+		utils.BridgeSubmitKeyFlag,
+		utils.BridgePollIntervalFlag,
 		utils.LogDebugFlag,
 		utils.LogBacktraceAtFlag,
 		utils.BlobExtraReserveFlag,
@@ -199,6 +222,13 @@ var (
 		utils.IncrSnapshotKeptBlocksFlag,
 		utils.UseRemoteIncrSnapshotFlag,
 		utils.RemoteIncrSnapshotURLFlag,
+		utils.ServeIncrSnapshotFlag,
+		utils.IncrSnapshotServePathFlag,
+		utils.IncrSnapshotServeKeptFilesFlag,
+		utils.ServeReplicasFlag,
+		utils.ReplicaPublishIntervalFlag,
+		utils.ReplicaFlag,
+		utils.ReplicaSourceFlag,
 		// utils.BeaconApiFlag,
 		// utils.BeaconApiHeaderFlag,
 		// utils.BeaconThresholdFlag,
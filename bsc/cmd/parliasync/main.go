@@ -0,0 +1,257 @@
+// parliasync follows the headers of a Parlia chain over RPC, verifies them with
+// the Parlia light client and reports every newly finalized head.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math/big"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/parlia"
+	"github.com/ethereum/go-ethereum/consensus/parlia/lightclient"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/urfave/cli/v2"
+)
+
+// maxReorgDepth is the maximum number of headers walked back to reconnect a
+// header to the verified chain after the node reorganised.
+const maxReorgDepth = 64
+
+var (
+	app *cli.App
+
+	nodeFlag = &cli.StringFlag{
+		Name:     "node",
+		Usage:    "rpc endpoint of the node to follow, http,https,ws,wss,ipc are supported",
+		Required: true,
+	}
+	networkFlag = &cli.StringFlag{
+		Name:  "network",
+		Usage: "built-in network to verify: bsc, chapel or rialto",
+		Value: "bsc",
+	}
+	chainConfigFlag = &cli.StringFlag{
+		Name:  "chainconfig",
+		Usage: "chain config json file, overrides --network",
+	}
+	checkpointFlag = &cli.StringFlag{
+		Name:     "checkpoint",
+		Usage:    "hash of the trusted checkpoint block, or \"genesis\"",
+		Required: true,
+	}
+	intervalFlag = &cli.DurationFlag{
+		Name:  "interval",
+		Usage: "polling interval for new headers",
+		Value: time.Second,
+	}
+	retainFlag = &cli.Uint64Flag{
+		Name:  "retain",
+		Usage: "number of recent headers kept in memory",
+		Value: 2048,
+	}
+)
+
+func init() {
+	app = flags.NewApp("a Parlia finality light client following a node over RPC")
+	app.Name = "parliasync"
+	app.Flags = []cli.Flag{
+		nodeFlag,
+		networkFlag,
+		chainConfigFlag,
+		checkpointFlag,
+		intervalFlag,
+		retainFlag,
+	}
+	app.Action = run
+}
+
+func main() {
+	log.SetDefault(log.NewLogger(log.NewTerminalHandlerWithLevel(os.Stderr, log.LevelInfo, true)))
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// finalizedEvent is the json line printed for every newly finalized head.
+type finalizedEvent struct {
+	Number uint64      `json:"number"`
+	Hash   common.Hash `json:"hash"`
+	Head   uint64      `json:"head"`
+}
+
+func run(ctx *cli.Context) error {
+	config, err := loadChainConfig(ctx)
+	if err != nil {
+		return err
+	}
+	client, err := ethclient.DialContext(ctx.Context, ctx.String(nodeFlag.Name))
+	if err != nil {
+		return fmt.Errorf("failed to connect to node: %w", err)
+	}
+	defer client.Close()
+
+	checkpoint, err := fetchCheckpoint(ctx.Context, client, ctx.String(checkpointFlag.Name))
+	if err != nil {
+		return err
+	}
+	lc, err := lightclient.New(config, checkpoint, &lightclient.Config{Retain: ctx.Uint64(retainFlag.Name)})
+	if err != nil {
+		return err
+	}
+	trusted := checkpoint.Headers[len(checkpoint.Headers)-1]
+	log.Info("Light client started", "checkpoint", trusted.Number, "hash", trusted.Hash())
+
+	var (
+		out       = json.NewEncoder(os.Stdout)
+		finalized = lc.Finalized()
+		ticker    = time.NewTicker(ctx.Duration(intervalFlag.Name))
+	)
+	defer ticker.Stop()
+	for {
+		if err := follow(ctx.Context, client, lc); err != nil {
+			log.Warn("Failed to follow node", "err", err)
+		}
+		if fin := lc.Finalized(); fin != nil && (finalized == nil || fin.Hash() != finalized.Hash()) {
+			finalized = fin
+			log.Info("Verified finalized head", "number", fin.Number, "hash", fin.Hash(), "head", lc.Head().Number)
+			out.Encode(&finalizedEvent{Number: fin.Number.Uint64(), Hash: fin.Hash(), Head: lc.Head().Number.Uint64()})
+		}
+		select {
+		case <-ctx.Context.Done():
+			return nil
+		case <-ticker.C:
+		}
+	}
+}
+
+// follow fetches and verifies all headers between the light client head and
+// the head of the node.
+func follow(ctx context.Context, client *ethclient.Client, lc *lightclient.Client) error {
+	latest, err := client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return err
+	}
+	for number := lc.Head().Number.Uint64() + 1; number <= latest.Number.Uint64(); number++ {
+		header, err := client.HeaderByNumber(ctx, new(big.Int).SetUint64(number))
+		if err != nil {
+			return err
+		}
+		branch, err := connect(ctx, client, lc, header)
+		if err != nil {
+			return err
+		}
+		if n, err := lc.InsertHeaders(branch); err != nil {
+			return fmt.Errorf("header %d rejected: %w", branch[n].Number, err)
+		}
+	}
+	return nil
+}
+
+// connect returns header preceded by any ancestors missing from the light
+// client, which is the case when the node switched to another branch.
+func connect(ctx context.Context, client *ethclient.Client, lc *lightclient.Client, header *types.Header) ([]*types.Header, error) {
+	branch := []*types.Header{header}
+	for lc.Header(header.ParentHash) == nil {
+		if len(branch) > maxReorgDepth {
+			return nil, fmt.Errorf("header %d does not connect within %d blocks", branch[0].Number, maxReorgDepth)
+		}
+		parent, err := client.HeaderByHash(ctx, header.ParentHash)
+		if err != nil {
+			return nil, err
+		}
+		header = parent
+		branch = append(branch, header)
+	}
+	for i := 0; i < len(branch)/2; i++ {
+		branch[i], branch[len(branch)-1-i] = branch[len(branch)-1-i], branch[i]
+	}
+	return branch, nil
+}
+
+// fetchCheckpoint assembles the trusted checkpoint from the node. The caller
+// vouches for the checkpoint hash, everything fetched is checked against it.
+func fetchCheckpoint(ctx context.Context, client *ethclient.Client, checkpoint string) (*lightclient.Checkpoint, error) {
+	if checkpoint == "genesis" {
+		genesis, err := client.HeaderByNumber(ctx, common.Big0)
+		if err != nil {
+			return nil, err
+		}
+		return &lightclient.Checkpoint{Headers: []*types.Header{genesis}}, nil
+	}
+	var hash common.Hash
+	if err := hash.UnmarshalText([]byte(checkpoint)); err != nil {
+		return nil, fmt.Errorf("invalid checkpoint hash %q: %w", checkpoint, err)
+	}
+	trusted, err := client.HeaderByHash(ctx, hash)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch checkpoint header: %w", err)
+	}
+	if trusted.Hash() != hash {
+		return nil, errors.New("checkpoint header hash mismatch")
+	}
+	var snap parlia.Snapshot
+	if err := client.Client().CallContext(ctx, &snap, "parlia_getSnapshotAtHash", hash); err != nil {
+		return nil, fmt.Errorf("failed to fetch checkpoint snapshot: %w", err)
+	}
+	// Headers shortly after an epoch block are still sealed by the previous
+	// validator set, reach back to the epoch block so the switch can be applied.
+	if snap.EpochLength == 0 || snap.TurnLength == 0 {
+		return nil, errors.New("checkpoint snapshot lacks epoch parameters")
+	}
+	depth := uint64(lightclient.CheckpointDepth)
+	if offset := trusted.Number.Uint64() % snap.EpochLength; offset < uint64(len(snap.Validators))*uint64(snap.TurnLength) {
+		depth = max(depth, offset+1)
+	}
+	headers := []*types.Header{trusted}
+	for uint64(len(headers)) < depth && headers[0].Number.Uint64() > 0 {
+		parent, err := client.HeaderByHash(ctx, headers[0].ParentHash)
+		if err != nil {
+			return nil, err
+		}
+		headers = append([]*types.Header{parent}, headers...)
+	}
+	snaps := []*parlia.Snapshot{&snap}
+	for i := len(headers) - 2; i >= len(headers)-lightclient.CheckpointDepth; i-- {
+		snap := new(parlia.Snapshot)
+		if err := client.Client().CallContext(ctx, snap, "parlia_getSnapshotAtHash", headers[i].Hash()); err != nil {
+			return nil, fmt.Errorf("failed to fetch checkpoint snapshot: %w", err)
+		}
+		snaps = append([]*parlia.Snapshot{snap}, snaps...)
+	}
+	return &lightclient.Checkpoint{Headers: headers, Snapshots: snaps}, nil
+}
+
+func loadChainConfig(ctx *cli.Context) (*params.ChainConfig, error) {
+	if path := ctx.String(chainConfigFlag.Name); path != "" {
+		blob, err := os.ReadFile(path)
+		if err != nil {
+			return nil, err
+		}
+		config := new(params.ChainConfig)
+		if err := json.Unmarshal(blob, config); err != nil {
+			return nil, fmt.Errorf("invalid chain config: %w", err)
+		}
+		return config, nil
+	}
+	switch network := strings.ToLower(ctx.String(networkFlag.Name)); network {
+	case "bsc", "mainnet":
+		return params.BSCChainConfig, nil
+	case "chapel", "testnet":
+		return params.ChapelChainConfig, nil
+	case "rialto":
+		return params.RialtoChainConfig, nil
+	default:
+		return nil, fmt.Errorf("unknown network %q", network)
+	}
+}
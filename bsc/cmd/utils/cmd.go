@@ -35,6 +35,7 @@ import (
 	"time"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/parlia"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state/snapshot"
@@ -48,6 +49,7 @@ import (
 	"github.com/ethereum/go-ethereum/node"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
 	"github.com/ethereum/go-ethereum/triedb"
 	"github.com/urfave/cli/v2"
 )
@@ -248,7 +250,8 @@ func readList(filename string) ([]string, error) {
 
 // ImportHistory imports Era1 files containing historical block information,
 // starting from genesis. The assumption is held that the provided chain
-// segment in Era1 file should all be canonical and verified.
+// segment in Era1 file should all be canonical and verified. Parlia eras are
+// verified against their accumulator root and the Parlia header verification.
 func ImportHistory(chain *core.BlockChain, dir string, network string) error {
 	if chain.CurrentSnapBlock().Number.BitLen() != 0 {
 		return errors.New("history import only supported when starting from genesis")
@@ -294,6 +297,19 @@ func ImportHistory(chain *core.BlockChain, dir string, network string) error {
 			if err != nil {
 				return fmt.Errorf("error opening era: %w", err)
 			}
+			if e.IsParlia() {
+				n, err := importParliaEra(chain, e, filename)
+				if err != nil {
+					return fmt.Errorf("error importing %s: %w", filename, err)
+				}
+				imported += n
+				if time.Since(reported) >= 8*time.Second {
+					log.Info("Importing Era files", "head", e.Start()+e.Count()-1, "imported", imported, "elapsed", common.PrettyDuration(time.Since(start)))
+					imported = 0
+					reported = time.Now()
+				}
+				return nil
+			}
 			it, err := era.NewIterator(e)
 			if err != nil {
 				return fmt.Errorf("error making era reader: %w", err)
@@ -333,6 +349,118 @@ func ImportHistory(chain *core.BlockChain, dir string, network string) error {
 	return nil
 }
 
+// importParliaEra imports the blocks of a Parlia era. The archive is checked
+// against its accumulator root, and the bodies and receipts against the
+// headers. The headers themselves are verified by the Parlia engine upon
+// insertion, replaying the validator set transitions from the local genesis,
+// which the vote attestations of the archive are then checked against.
+func importParliaEra(chain *core.BlockChain, e *era.Era, filename string) (int, error) {
+	engine, ok := chain.Engine().(*parlia.Parlia)
+	if !ok {
+		return 0, errors.New("parlia era requires the parlia engine")
+	}
+	it, err := era.NewRawIterator(e)
+	if err != nil {
+		return 0, fmt.Errorf("error making era reader: %w", err)
+	}
+	var (
+		records      []era.ParliaRecord
+		blocks       []*types.Block
+		receipts     []types.Receipts
+		attestations [][]byte
+	)
+	for it.Next() {
+		if err := it.Error(); err != nil {
+			return 0, fmt.Errorf("error reading block %d: %w", it.Number(), err)
+		}
+		var (
+			header                                          types.Header
+			body                                            types.Body
+			blockReceipts                                   types.Receipts
+			rawHeader, rawBody, rawReceipts, rawAttestation []byte
+		)
+		for _, item := range []struct {
+			r   io.Reader
+			out *[]byte
+		}{{it.Header, &rawHeader}, {it.Body, &rawBody}, {it.Receipts, &rawReceipts}, {it.VoteAttestation, &rawAttestation}} {
+			if *item.out, err = io.ReadAll(item.r); err != nil {
+				return 0, fmt.Errorf("error reading block %d: %w", it.Number(), err)
+			}
+		}
+		if err := rlp.DecodeBytes(rawHeader, &header); err != nil {
+			return 0, fmt.Errorf("error decoding header %d: %w", it.Number(), err)
+		}
+		if err := rlp.DecodeBytes(rawBody, &body); err != nil {
+			return 0, fmt.Errorf("error decoding body %d: %w", it.Number(), err)
+		}
+		if err := rlp.DecodeBytes(rawReceipts, &blockReceipts); err != nil {
+			return 0, fmt.Errorf("error decoding receipts %d: %w", it.Number(), err)
+		}
+		block := types.NewBlockWithHeader(&header).WithBody(body)
+		records = append(records, era.NewParliaRecord(block.Hash(), rawBody, rawReceipts, rawAttestation))
+
+		if hash := types.DeriveSha(block.Transactions(), trie.NewStackTrie(nil)); hash != header.TxHash {
+			return 0, fmt.Errorf("transaction root mismatch %d: have %x, want %x", it.Number(), hash, header.TxHash)
+		}
+		if hash := types.CalcUncleHash(block.Uncles()); hash != header.UncleHash {
+			return 0, fmt.Errorf("uncle hash mismatch %d: have %x, want %x", it.Number(), hash, header.UncleHash)
+		}
+		if hash := types.DeriveSha(blockReceipts, trie.NewStackTrie(nil)); hash != header.ReceiptHash {
+			return 0, fmt.Errorf("receipt root mismatch %d: have %x, want %x", it.Number(), hash, header.ReceiptHash)
+		}
+		if block.NumberU64() == 0 {
+			if block.Hash() != chain.Genesis().Hash() {
+				return 0, fmt.Errorf("genesis mismatch: have %x, want %x", block.Hash(), chain.Genesis().Hash())
+			}
+			continue
+		}
+		blocks = append(blocks, block)
+		receipts = append(receipts, blockReceipts)
+		attestations = append(attestations, rawAttestation)
+	}
+	if err := it.Error(); err != nil {
+		return 0, fmt.Errorf("error reading era: %w", err)
+	}
+	// Check the archive against its root, both as stored and as named.
+	root, err := era.ComputeParliaAccumulator(records)
+	if err != nil {
+		return 0, fmt.Errorf("error calculating accumulator root: %w", err)
+	}
+	want, err := e.Accumulator()
+	if err != nil {
+		return 0, fmt.Errorf("error reading accumulator: %w", err)
+	}
+	if root != want {
+		return 0, fmt.Errorf("accumulator mismatch: have %x, want %x", root, want)
+	}
+	if !strings.HasSuffix(filename, root.Hex()[2:10]+".era1") {
+		return 0, fmt.Errorf("filename does not match accumulator %x", root)
+	}
+	if len(blocks) == 0 {
+		return 0, nil
+	}
+	if _, err := chain.InsertReceiptChain(blocks, types.EncodeBlockReceiptLists(receipts), math.MaxUint64); err != nil {
+		return 0, fmt.Errorf("error inserting blocks: %w", err)
+	}
+	// The headers are verified now, so are the attestations carried in them.
+	for i, block := range blocks {
+		attestation, err := engine.GetVoteAttestation(chain, block.Header())
+		if err != nil {
+			return 0, fmt.Errorf("error reading vote attestation %d: %w", block.NumberU64(), err)
+		}
+		var enc []byte
+		if attestation != nil {
+			if enc, err = rlp.EncodeToBytes(attestation); err != nil {
+				return 0, err
+			}
+		}
+		if !bytes.Equal(enc, attestations[i]) {
+			return 0, fmt.Errorf("vote attestation mismatch %d", block.NumberU64())
+		}
+	}
+	return len(blocks), nil
+}
+
 func missingBlocks(chain *core.BlockChain, blocks []*types.Block) []*types.Block {
 	head := chain.CurrentBlock()
 	for i, block := range blocks {
@@ -403,7 +531,10 @@ func ExportAppendChain(blockchain *core.BlockChain, fn string, first uint64, las
 }
 
 // ExportHistory exports blockchain history into the specified directory,
-// following the Era format.
+// following the Era format. Chains running Parlia are exported in the Parlia
+// variant of the format. Next to the checksums.txt list of the files, a
+// checksums_<network>.txt manifest is written for serving the files to
+// download-era.
 func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) error {
 	log.Info("Exporting blockchain history", "dir", dir)
 	if head := bc.CurrentBlock().Number.Uint64(); head < last {
@@ -414,6 +545,14 @@ func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) er
 	if name, ok := params.NetworkNames[bc.Config().ChainID.String()]; ok {
 		network = name
 	}
+	var engine *parlia.Parlia
+	if bc.Config().Parlia != nil {
+		p, ok := bc.Engine().(*parlia.Parlia)
+		if !ok {
+			return errors.New("parlia chain exported without the parlia engine")
+		}
+		engine = p
+	}
 	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
 		return fmt.Errorf("error creating output directory: %w", err)
 	}
@@ -423,6 +562,7 @@ func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) er
 		h         = sha256.New()
 		buf       = bytes.NewBuffer(nil)
 		checksums []string
+		manifest  []string
 	)
 	for i := first; i <= last; i += step {
 		err := func() error {
@@ -434,6 +574,9 @@ func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) er
 			defer f.Close()
 
 			w := era.NewBuilder(f)
+			if engine != nil {
+				w = era.NewParliaBuilder(f)
+			}
 			for j := uint64(0); j < step && j <= last-i; j++ {
 				var (
 					n     = i + j
@@ -446,6 +589,16 @@ func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) er
 				if receipts == nil {
 					return fmt.Errorf("export failed on #%d: receipts not found", n)
 				}
+				if engine != nil {
+					attestation, err := engine.GetVoteAttestation(bc, block.Header())
+					if err != nil {
+						return fmt.Errorf("export failed on #%d: %w", n, err)
+					}
+					if err := w.AddParlia(block, receipts, attestation); err != nil {
+						return err
+					}
+					continue
+				}
 				td := bc.GetTd(block.Hash(), block.NumberU64())
 				if td == nil {
 					return fmt.Errorf("export failed on #%d: total difficulty not found", n)
@@ -459,7 +612,8 @@ func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) er
 				return fmt.Errorf("export failed to finalize %d: %w", step/i, err)
 			}
 			// Set correct filename with root.
-			os.Rename(filename, filepath.Join(dir, era.Filename(network, int(i/step), root)))
+			name := era.Filename(network, int(i/step), root)
+			os.Rename(filename, filepath.Join(dir, name))
 
 			// Compute checksum of entire Era1.
 			if _, err := f.Seek(0, io.SeekStart); err != nil {
@@ -468,7 +622,9 @@ func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) er
 			if _, err := io.Copy(h, f); err != nil {
 				return fmt.Errorf("unable to calculate checksum: %w", err)
 			}
-			checksums = append(checksums, common.BytesToHash(h.Sum(buf.Bytes()[:])).Hex())
+			checksum := common.BytesToHash(h.Sum(buf.Bytes()[:]))
+			checksums = append(checksums, checksum.Hex())
+			manifest = append(manifest, fmt.Sprintf("%x  %s", checksum, name))
 			h.Reset()
 			buf.Reset()
 			return nil
@@ -483,6 +639,7 @@ func ExportHistory(bc *core.BlockChain, dir string, first, last, step uint64) er
 	}
 
 	os.WriteFile(filepath.Join(dir, "checksums.txt"), []byte(strings.Join(checksums, "\n")), os.ModePerm)
+	os.WriteFile(filepath.Join(dir, fmt.Sprintf("checksums_%s.txt", network)), []byte(strings.Join(manifest, "\n")+"\n"), os.ModePerm)
 
 	log.Info("Exported blockchain to", "dir", dir)
 
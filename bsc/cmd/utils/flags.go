@@ -39,13 +39,17 @@ import (
 	"github.com/ethereum/go-ethereum/accounts/keystore"
 	"github.com/ethereum/go-ethereum/beacon/fakebeacon"
 	bparams "github.com/ethereum/go-ethereum/beacon/params"
+	"github.com/ethereum/go-ethereum/bridge"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/fdlimit"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/opcodeCompiler/compiler"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
+	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
@@ -139,6 +143,17 @@ var (
 		Usage:    "Root directory for era1 history (default = inside ancient/chain)",
 		Category: flags.EthCategory,
 	}
+	AncientColdStoreFlag = &cli.StringFlag{
+		Name:     "datadir.ancient.coldstore",
+		Usage:    "Blob store to offload the completed ancient data files to (directory, file:///path or s3://bucket/prefix?endpoint=url&region=name)",
+		Category: flags.EthCategory,
+	}
+	AncientColdCacheFlag = &cli.IntFlag{
+		Name:     "datadir.ancient.coldcache",
+		Usage:    "Megabytes of offloaded ancient data files cached locally once fetched",
+		Value:    ethconfig.Defaults.DatabaseColdCache,
+		Category: flags.EthCategory,
+	}
 	MinFreeDiskSpaceFlag = &flags.DirectoryFlag{
 		Name:     "datadir.minfreedisk",
 		Usage:    "Minimum free disk space in MB, once reached triggers auto shut down (default = --cache.gc converted to MB, 0 = disabled)",
@@ -409,6 +424,28 @@ var (
 		Value:    ethconfig.Defaults.StateHistory,
 		Category: flags.StateCategory,
 	}
+	StateHistoryServeFlag = &cli.BoolFlag{
+		Name:     "history.state.serve",
+		Usage:    "Serve historical states and proofs within the retained state history, only relevant in state.scheme=path",
+		Category: flags.StateCategory,
+	}
+	StatePruneIOBudgetFlag = &cli.Uint64Flag{
+		Name:     "state.prune.iobudget",
+		Usage:    "Disk bandwidth in MB/s the online state pruning may use (0 = unthrottled), start it with admin_startPrune",
+		Value:    ethconfig.Defaults.StatePruneIOBudget,
+		Category: flags.StateCategory,
+	}
+	StatePruneBloomSizeFlag = &cli.Uint64Flag{
+		Name:     "state.prune.bloomsize",
+		Usage:    "Megabytes of memory allocated to the bloom filter of the online state pruning, only relevant in state.scheme=hash",
+		Value:    ethconfig.Defaults.StatePruneBloomSize,
+		Category: flags.StateCategory,
+	}
+	StatePruneIntervalFlag = &cli.DurationFlag{
+		Name:     "state.prune.interval",
+		Usage:    "Interval between the automatic online state prunings (0 = on demand only)",
+		Category: flags.StateCategory,
+	}
 	TransactionHistoryFlag = &cli.Uint64Flag{
 		Name:     "history.transactions",
 		Usage:    "Number of recent blocks to maintain transactions index for (default = about one year, 0 = entire chain)",
@@ -423,10 +460,16 @@ var (
 	}
 	ChainHistoryFlag = &cli.StringFlag{
 		Name:     "history.chain",
-		Usage:    `Blockchain history retention ("all" or "postmerge")`,
+		Usage:    `Blockchain history retention ("all", "postmerge" or "recent")`,
 		Value:    ethconfig.Defaults.HistoryMode.String(),
 		Category: flags.StateCategory,
 	}
+	HistoryRecentFlag = &cli.Uint64Flag{
+		Name:     "history.recent",
+		Usage:    "Number of recent blocks whose bodies and receipts are kept with --history.chain=recent, headers are always kept",
+		Value:    ethconfig.Defaults.HistoryRecent,
+		Category: flags.StateCategory,
+	}
 	LogHistoryFlag = &cli.Uint64Flag{
 		Name:     "history.logs",
 		Usage:    "Number of recent blocks to maintain log search index for (default = about one year, 0 = entire chain)",
@@ -438,6 +481,26 @@ var (
 		Usage:    "Do not maintain log search index",
 		Category: flags.StateCategory,
 	}
+	LogNativeTransfersFlag = &cli.BoolFlag{
+		Name:     "history.logs.nativetransfers",
+		Usage:    "Index the native transfers and the internal calls moving value as synthetic logs, speeding up eth_getNativeTransferLogs within the indexed range (records the native transfers)",
+		Category: flags.StateCategory,
+	}
+	NativeTransfersFlag = &cli.BoolFlag{
+		Name:     "history.nativetransfers",
+		Usage:    "Record the native transfers, mints, burns, fees and rewards of imported blocks, retrieve them with eth_getNativeTransfers",
+		Category: flags.StateCategory,
+	}
+	AddressIndexFlag = &cli.BoolFlag{
+		Name:     "history.address",
+		Usage:    "Index the transactions by the addresses involved within the transaction history, retrieve them with eth_getTransactionsByAddress",
+		Category: flags.StateCategory,
+	}
+	AddressIndexInternalFlag = &cli.BoolFlag{
+		Name:     "history.address.internal",
+		Usage:    "Index the native transfers of internal calls by address as well, recording the native transfers of imported blocks",
+		Category: flags.StateCategory,
+	}
 	// Deprecated Jan 2025
 	LogExportCheckpointsFlag = &cli.StringFlag{
 		Name:     "history.logs.export",
@@ -579,6 +642,17 @@ var (
 		Value:    ethconfig.Defaults.TxPool.ReannounceTime,
 		Category: flags.TxPoolCategory,
 	}
+	TxPoolBundlesFlag = &cli.BoolFlag{
+		Name:     "txpool.bundles",
+		Usage:    "Accept transaction bundles over RPC and merge them into locally built blocks",
+		Category: flags.TxPoolCategory,
+	}
+	TxPoolMaxBundlesFlag = &cli.IntFlag{
+		Name:     "txpool.maxbundles",
+		Usage:    "Maximum number of bundles kept in the bundle pool",
+		Value:    ethconfig.Defaults.BundlePool.MaxBundles,
+		Category: flags.TxPoolCategory,
+	}
 	MinerTxGasLimitFlag = &cli.Uint64Flag{
 		Name:     "miner.txgaslimit",
 		Usage:    fmt.Sprintf("Maximum gas allowed per transaction (default = 0, disabled; min = %d)", params.MaxTxGas),
@@ -1289,6 +1363,18 @@ Please note that --` + MetricsHTTPFlag.Name + ` must be set to start the server.
 		Category: flags.FastFinalityCategory,
 	}
 
+	PriceOracleURLFlag = &cli.StringFlag{
+		Name:     "oracle.url",
+		Usage:    "URL of the JSON price source reported to the price oracle when voting",
+		Category: flags.FastFinalityCategory,
+	}
+	PriceOracleFieldFlag = &cli.StringFlag{
+		Name:     "oracle.field",
+		Usage:    "Field of the price in the JSON price source, nested fields separated by dots",
+		Value:    "price",
+		Category: flags.FastFinalityCategory,
+	}
+
 	EnableMaliciousVoteMonitorFlag = &cli.BoolFlag{
 		Name:     "monitor.maliciousvote",
 		Usage:    "Enable malicious vote monitor to check whether any validator violates the voting rules of fast finality",
@@ -1313,6 +1399,60 @@ Please note that --` + MetricsHTTPFlag.Name + ` must be set to start the server.
 		Category: flags.FastFinalityCategory,
 	}
 
+	// Bridge relayer settings
+	BridgeRemoteURLFlag = &cli.StringFlag{
+		Name:     "bridge.remote",
+		Usage:    "RPC endpoint of the remote chain watched by the bridge relayer (enables the relayer)",
+		Category: flags.BridgeCategory,
+	}
+	BridgeContractFlag = &cli.StringFlag{
+		Name:     "bridge.contract",
+		Usage:    "Contract on the remote chain emitting the bridged events",
+		Category: flags.BridgeCategory,
+	}
+	BridgeEventsFlag = &cli.StringFlag{
+		Name:     "bridge.events",
+		Usage:    "Comma separated signature hashes of the bridged events (default = all events of the contract)",
+		Category: flags.BridgeCategory,
+	}
+	BridgeConfirmationsFlag = &cli.Uint64Flag{
+		Name:     "bridge.confirmations",
+		Usage:    "Number of remote blocks an event must be buried under before being attested",
+		Value:    bridge.DefaultConfig.Confirmations,
+		Category: flags.BridgeCategory,
+	}
+	BridgeStartBlockFlag = &cli.Uint64Flag{
+		Name:     "bridge.startblock",
+		Usage:    "Remote block to start watching from, unless resuming (default = the confirmed head)",
+		Category: flags.BridgeCategory,
+	}
+	BridgeAttestersFlag = &cli.StringFlag{
+		Name:     "bridge.attesters",
+		Usage:    "Comma separated BLS vote keys of the validators attesting the bridged events, whose attestations are forwarded to the peers if no relayer is running",
+		Category: flags.BridgeCategory,
+	}
+	BridgeThresholdFlag = &cli.IntFlag{
+		Name:     "bridge.threshold",
+		Usage:    "Number of attestations needed to mint a bridged event",
+		Category: flags.BridgeCategory,
+	}
+	BridgeMintContractFlag = &cli.StringFlag{
+		Name:     "bridge.mint",
+		Usage:    "Contract on the local chain minting the attested events",
+		Category: flags.BridgeCategory,
+	}
+	BridgeSubmitKeyFlag = &cli.StringFlag{
+		Name:     "bridge.submitkey",
+		Usage:    "Private key file of the account submitting the mint transactions (default = attest only)",
+		Category: flags.BridgeCategory,
+	}
+	BridgePollIntervalFlag = &cli.DurationFlag{
+		Name:     "bridge.pollinterval",
+		Usage:    "Interval of polling the remote chain for new events",
+		Value:    bridge.DefaultConfig.PollInterval,
+		Category: flags.BridgeCategory,
+	}
+
 	// Blob setting
 	BlobExtraReserveFlag = &cli.Uint64Flag{
 		Name:     "blob.extra-reserve",
@@ -1383,6 +1523,48 @@ Please note that --` + MetricsHTTPFlag.Name + ` must be set to start the server.
 		Value:    "",
 		Category: flags.StateCategory,
 	}
+	ServeIncrSnapshotFlag = &cli.BoolFlag{
+		Name:     "incr.serve",
+		Usage:    "Serve incremental snapshots at /incr/ of the HTTP-RPC server, to be used as remote url by other nodes",
+		Value:    false,
+		Category: flags.StateCategory,
+	}
+	IncrSnapshotServePathFlag = &flags.DirectoryFlag{
+		Name:     "incr.serve.datadir",
+		Usage:    "Data directory for storing the served incremental snapshot files (default = inside the datadir)",
+		Value:    "",
+		Category: flags.StateCategory,
+	}
+	IncrSnapshotServeKeptFilesFlag = &cli.Uint64Flag{
+		Name:     "incr.serve.kept-files",
+		Usage:    "Set how many of the newest incremental snapshot files are served, older ones are pruned",
+		Value:    core.DefaultIncrServeKeptFiles,
+		Category: flags.StateCategory,
+	}
+	ServeReplicasFlag = &cli.BoolFlag{
+		Name:     "replica.serve",
+		Usage:    "Publish snapshots of the chain database on new heads, with the in-memory state layers, to be followed by read replicas (path-based scheme and pebble only)",
+		Value:    false,
+		Category: flags.StateCategory,
+	}
+	ReplicaPublishIntervalFlag = &cli.DurationFlag{
+		Name:     "replica.interval",
+		Usage:    "Minimum interval between two database snapshots published for the read replicas",
+		Value:    ethconfig.Defaults.ReplicaPublishInterval,
+		Category: flags.StateCategory,
+	}
+	ReplicaFlag = &flags.DirectoryFlag{
+		Name:     "replica",
+		Usage:    "Run as a read replica of the snapshots published by a writer node serving replicas (its replicas directory, on the same file system as the datadir)",
+		Value:    "",
+		Category: flags.StateCategory,
+	}
+	ReplicaSourceFlag = &cli.StringFlag{
+		Name:     "replica.source",
+		Usage:    "WebSocket endpoint of the writer node, announcing the new heads to the read replica",
+		Value:    "",
+		Category: flags.StateCategory,
+	}
 )
 
 var (
@@ -1397,6 +1579,8 @@ var (
 	DatabaseFlags = []cli.Flag{
 		DataDirFlag,
 		AncientFlag,
+		AncientColdStoreFlag,
+		AncientColdCacheFlag,
 		EraFlag,
 		RemoteDBFlag,
 		DBEngineFlag,
@@ -1997,6 +2181,15 @@ func setTxPool(ctx *cli.Context, cfg *legacypool.Config) {
 	}
 }
 
+func setBundlePool(ctx *cli.Context, cfg *bundlepool.Config) {
+	if ctx.IsSet(TxPoolBundlesFlag.Name) {
+		cfg.Enabled = ctx.Bool(TxPoolBundlesFlag.Name)
+	}
+	if ctx.IsSet(TxPoolMaxBundlesFlag.Name) {
+		cfg.MaxBundles = ctx.Int(TxPoolMaxBundlesFlag.Name)
+	}
+}
+
 func setBlobPool(ctx *cli.Context, cfg *blobpool.Config) {
 	if ctx.IsSet(BlobPoolDataDirFlag.Name) {
 		cfg.Datadir = ctx.String(BlobPoolDataDirFlag.Name)
@@ -2038,6 +2231,10 @@ func setMiner(ctx *cli.Context, cfg *minerconfig.Config) {
 	if ctx.Bool(DisableVoteAttestationFlag.Name) {
 		cfg.DisableVoteAttestation = true
 	}
+	if ctx.IsSet(PriceOracleURLFlag.Name) {
+		cfg.PriceOracleURL = ctx.String(PriceOracleURLFlag.Name)
+		cfg.PriceOracleField = ctx.String(PriceOracleFieldFlag.Name)
+	}
 	if ctx.IsSet(MinerTxGasLimitFlag.Name) {
 		limit := ctx.Uint64(MinerTxGasLimitFlag.Name)
 		if limit != 0 && limit < params.MaxTxGas {
@@ -2080,12 +2277,17 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	// Avoid conflicting network flags, don't allow network id override on preset networks
 	flags.CheckExclusive(ctx, BSCMainnetFlag, DeveloperFlag, NetworkIdFlag, OverrideGenesisFlag)
 	flags.CheckExclusive(ctx, DeveloperFlag, ExternalSignerFlag) // Can't use both ephemeral unlocked and external signer
+	flags.CheckExclusive(ctx, ReplicaFlag, ServeReplicasFlag)
+	flags.CheckExclusive(ctx, ReplicaFlag, MiningEnabledFlag)
+	flags.CheckExclusive(ctx, ReplicaFlag, EnableIncrSnapshotFlag)
+	flags.CheckExclusive(ctx, StateHistoryServeFlag, EnableIncrSnapshotFlag)
 
 	// Set configurations from CLI flags
 	setEtherbase(ctx, cfg)
 	setGPO(ctx, &cfg.GPO)
 	setTxPool(ctx, &cfg.TxPool)
 	setBlobPool(ctx, &cfg.BlobPool)
+	setBundlePool(ctx, &cfg.BundlePool)
 	setMiner(ctx, &cfg.Miner)
 	setRequiredBlocks(ctx, cfg)
 
@@ -2129,6 +2331,13 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 			Fatalf("--%s: %v", ChainHistoryFlag.Name, err)
 		}
 	}
+	if ctx.IsSet(HistoryRecentFlag.Name) {
+		cfg.HistoryRecent = ctx.Uint64(HistoryRecentFlag.Name)
+		if cfg.HistoryRecent < params.FullImmutabilityThreshold {
+			log.Warn("The number of recent history blocks is too small, that it will force to", "fullImmutabilityThreshold", params.FullImmutabilityThreshold)
+			cfg.HistoryRecent = params.FullImmutabilityThreshold
+		}
+	}
 
 	if ctx.IsSet(NetworkIdFlag.Name) {
 		cfg.NetworkId = ctx.Uint64(NetworkIdFlag.Name)
@@ -2147,6 +2356,12 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(EraFlag.Name) {
 		cfg.DatabaseEra = ctx.String(EraFlag.Name)
 	}
+	if ctx.IsSet(AncientColdStoreFlag.Name) {
+		cfg.DatabaseColdStore = ctx.String(AncientColdStoreFlag.Name)
+	}
+	if ctx.IsSet(AncientColdCacheFlag.Name) {
+		cfg.DatabaseColdCache = ctx.Int(AncientColdCacheFlag.Name)
+	}
 
 	if gcmode := ctx.String(GCModeFlag.Name); gcmode != "full" && gcmode != "archive" {
 		Fatalf("--%s must be either 'full' or 'archive'", GCModeFlag.Name)
@@ -2178,6 +2393,18 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(StateHistoryFlag.Name) {
 		cfg.StateHistory = ctx.Uint64(StateHistoryFlag.Name)
 	}
+	if ctx.IsSet(StateHistoryServeFlag.Name) {
+		cfg.HistoricalState = ctx.Bool(StateHistoryServeFlag.Name)
+	}
+	if ctx.IsSet(StatePruneIOBudgetFlag.Name) {
+		cfg.StatePruneIOBudget = ctx.Uint64(StatePruneIOBudgetFlag.Name)
+	}
+	if ctx.IsSet(StatePruneBloomSizeFlag.Name) {
+		cfg.StatePruneBloomSize = ctx.Uint64(StatePruneBloomSizeFlag.Name)
+	}
+	if ctx.IsSet(StatePruneIntervalFlag.Name) {
+		cfg.StatePruneInterval = ctx.Duration(StatePruneIntervalFlag.Name)
+	}
 	scheme, err := ParseCLIAndConfigStateScheme(ctx.String(StateSchemeFlag.Name), cfg.StateScheme)
 	if err != nil {
 		Fatalf("%v", err)
@@ -2218,6 +2445,18 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 	if ctx.IsSet(LogNoHistoryFlag.Name) {
 		cfg.LogNoHistory = true
 	}
+	if ctx.IsSet(LogNativeTransfersFlag.Name) {
+		cfg.LogNativeTransfers = ctx.Bool(LogNativeTransfersFlag.Name)
+	}
+	if ctx.IsSet(NativeTransfersFlag.Name) {
+		cfg.NativeTransfers = ctx.Bool(NativeTransfersFlag.Name)
+	}
+	if ctx.IsSet(AddressIndexFlag.Name) {
+		cfg.AddressIndex = ctx.Bool(AddressIndexFlag.Name)
+	}
+	if ctx.IsSet(AddressIndexInternalFlag.Name) {
+		cfg.AddressIndexInternal = ctx.Bool(AddressIndexInternalFlag.Name)
+	}
 	if ctx.IsSet(LogExportCheckpointsFlag.Name) {
 		cfg.LogExportCheckpoints = ctx.String(LogExportCheckpointsFlag.Name)
 		log.Warn("Flag --history.logs.export is deprecated, checkpoint file is auto-enabled at datadir/geth/filtermap_checkpoints.json")
@@ -2506,6 +2745,37 @@ func SetEthConfig(ctx *cli.Context, stack *node.Node, cfg *ethconfig.Config) {
 			cfg.IncrSnapshotKeptBlocks = ctx.Uint64(IncrSnapshotKeptBlocksFlag.Name)
 		}
 	}
+
+	// serve incremental snapshots config
+	if ctx.IsSet(ServeIncrSnapshotFlag.Name) {
+		cfg.ServeIncrSnapshots = true
+		if ctx.IsSet(IncrSnapshotPathFlag.Name) {
+			cfg.IncrSnapshotPath = ctx.String(IncrSnapshotPathFlag.Name)
+		}
+		if ctx.IsSet(IncrSnapshotServePathFlag.Name) {
+			cfg.IncrSnapshotServePath = ctx.String(IncrSnapshotServePathFlag.Name)
+		}
+		cfg.IncrSnapshotServeKeptFiles = ctx.Uint64(IncrSnapshotServeKeptFilesFlag.Name)
+	}
+
+	// read replica config
+	if ctx.IsSet(ServeReplicasFlag.Name) {
+		cfg.ServeReplicas = ctx.Bool(ServeReplicasFlag.Name)
+	}
+	if ctx.IsSet(ReplicaPublishIntervalFlag.Name) {
+		cfg.ReplicaPublishInterval = ctx.Duration(ReplicaPublishIntervalFlag.Name)
+	}
+	if ctx.IsSet(ReplicaFlag.Name) {
+		path, err := filepath.Abs(ctx.String(ReplicaFlag.Name))
+		if err != nil {
+			Fatalf("Invalid replica chain database path: %v", err)
+		}
+		cfg.Replica = path
+		cfg.ReplicaSource = ctx.String(ReplicaSourceFlag.Name)
+		if cfg.ReplicaSource == "" {
+			Fatalf("--%s is required for read replicas", ReplicaSourceFlag.Name)
+		}
+	}
 }
 
 // SetDNSDiscoveryDefaults configures DNS discovery with the given URL if
@@ -2540,6 +2810,59 @@ func RegisterEthStatsService(stack *node.Node, backend ethapi.Backend, url strin
 	}
 }
 
+// MakeBridgeConfig creates the bridge relayer config from the command line flags.
+func MakeBridgeConfig(ctx *cli.Context) *bridge.Config {
+	cfg := bridge.DefaultConfig
+	cfg.RemoteURL = ctx.String(BridgeRemoteURLFlag.Name)
+	cfg.Contract = mustParseAddress(BridgeContractFlag.Name, ctx.String(BridgeContractFlag.Name))
+	cfg.MintContract = mustParseAddress(BridgeMintContractFlag.Name, ctx.String(BridgeMintContractFlag.Name))
+	for _, event := range SplitAndTrim(ctx.String(BridgeEventsFlag.Name)) {
+		topic, err := hexutil.Decode(event)
+		if err != nil || len(topic) != common.HashLength {
+			Fatalf("Invalid event signature hash in --%s: %s", BridgeEventsFlag.Name, event)
+		}
+		cfg.Events = append(cfg.Events, common.BytesToHash(topic))
+	}
+	cfg.Attesters = MakeBridgeAttesters(ctx)
+	cfg.Confirmations = ctx.Uint64(BridgeConfirmationsFlag.Name)
+	cfg.StartBlock = ctx.Uint64(BridgeStartBlockFlag.Name)
+	cfg.Threshold = ctx.Int(BridgeThresholdFlag.Name)
+	cfg.SubmitKeyFile = ctx.String(BridgeSubmitKeyFlag.Name)
+	cfg.PollInterval = ctx.Duration(BridgePollIntervalFlag.Name)
+	return &cfg
+}
+
+// MakeBridgeAttesters parses the BLS vote keys of the bridge committee from the
+// command line flags.
+func MakeBridgeAttesters(ctx *cli.Context) []types.BLSPublicKey {
+	var attesters []types.BLSPublicKey
+	for _, attester := range SplitAndTrim(ctx.String(BridgeAttestersFlag.Name)) {
+		key, err := hexutil.Decode(attester)
+		if err != nil || len(key) != types.BLSPublicKeyLength {
+			Fatalf("Invalid BLS vote key in --%s: %s", BridgeAttestersFlag.Name, attester)
+		}
+		attesters = append(attesters, types.BLSPublicKey(key))
+	}
+	return attesters
+}
+
+func mustParseAddress(flag string, address string) common.Address {
+	if !common.IsHexAddress(address) {
+		Fatalf("Invalid address in --%s: %q", flag, address)
+	}
+	return common.HexToAddress(address)
+}
+
+// RegisterBridgeRelayerService configures the bridge relayer and adds it to the
+// given node, exchanging its attestations over the `bsc` protocol.
+func RegisterBridgeRelayerService(stack *node.Node, backend *eth.Ethereum, cfg *bridge.Config) {
+	relayer, err := bridge.New(stack, cfg)
+	if err != nil {
+		Fatalf("Failed to register the bridge relayer service: %v", err)
+	}
+	backend.SetBridgePool(relayer.Pool())
+}
+
 // RegisterGraphQLService adds the GraphQL API to the node.
 func RegisterGraphQLService(stack *node.Node, backend ethapi.Backend, filterSystem *filters.FilterSystem, cfg *node.Config) {
 	err := graphql.New(stack, backend, filterSystem, cfg.GraphQLCors, cfg.GraphQLVirtualHosts)
@@ -2777,6 +3100,8 @@ func MakeChainDatabase(ctx *cli.Context, stack *node.Node, readonly bool) ethdb.
 			AncientsDirectory: ctx.String(AncientFlag.Name),
 			MetricsNamespace:  "eth/db/chaindata/",
 			EraDirectory:      ctx.String(EraFlag.Name),
+			ColdStore:         ctx.String(AncientColdStoreFlag.Name),
+			ColdCache:         ctx.Int(AncientColdCacheFlag.Name),
 		}
 		chainDb, err = stack.OpenDatabaseWithOptions("chaindata", options)
 		// set the separate state database
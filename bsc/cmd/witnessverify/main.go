@@ -0,0 +1,298 @@
+// witnessverify re-executes the blocks of a Parlia chain from their execution
+// witnesses alone and checks the resulting state and receipt roots, without
+// keeping any state of its own.
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/parlia"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/stateless"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/internal/flags"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/urfave/cli/v2"
+)
+
+const (
+	// finalityRewardInterval is the block interval at which Parlia distributes
+	// the finality rewards, weighing the vote attestations of the preceding
+	// blocks.
+	finalityRewardInterval = 200
+
+	// finalityRewardAncestors is the number of ancestors supplied to the engine
+	// on finality reward blocks, covering the attested blocks and the targets
+	// of their attestations.
+	finalityRewardAncestors = 256
+)
+
+var (
+	app *cli.App
+
+	nodeFlag = &cli.StringFlag{
+		Name:     "node",
+		Usage:    "rpc endpoint of the node serving blocks and witnesses, http,https,ws,wss,ipc are supported",
+		Required: true,
+	}
+	networkFlag = &cli.StringFlag{
+		Name:  "network",
+		Usage: "built-in network to verify: bsc, chapel or rialto",
+		Value: "bsc",
+	}
+	chainConfigFlag = &cli.StringFlag{
+		Name:  "chainconfig",
+		Usage: "chain config json file, overrides --network",
+	}
+	fromFlag = &cli.Uint64Flag{
+		Name:     "from",
+		Usage:    "number of the first block to verify",
+		Required: true,
+	}
+	toFlag = &cli.Uint64Flag{
+		Name:  "to",
+		Usage: "number of the last block to verify, defaults to --from",
+	}
+)
+
+func init() {
+	app = flags.NewApp("a stateless verifier re-executing blocks from their witnesses")
+	app.Name = "witnessverify"
+	app.Flags = []cli.Flag{
+		nodeFlag,
+		networkFlag,
+		chainConfigFlag,
+		fromFlag,
+		toFlag,
+	}
+	app.Action = run
+}
+
+func main() {
+	log.SetDefault(log.NewLogger(log.NewTerminalHandlerWithLevel(os.Stderr, log.LevelInfo, true)))
+	if err := app.Run(os.Args); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+// verifiedEvent is the json line printed for every verified block.
+type verifiedEvent struct {
+	Number      uint64      `json:"number"`
+	Hash        common.Hash `json:"hash"`
+	StateRoot   common.Hash `json:"stateRoot"`
+	ReceiptRoot common.Hash `json:"receiptRoot"`
+	Txs         int         `json:"txs"`
+}
+
+// verifier re-executes blocks statelessly with a Parlia engine fed with the
+// snapshots of the node.
+type verifier struct {
+	config  *params.ChainConfig
+	client  *ethclient.Client
+	engine  *parlia.Parlia
+	genesis *types.Header
+	known   map[common.Hash]struct{} // Hashes of the snapshots already imported
+}
+
+func run(ctx *cli.Context) error {
+	config, genesisHash, err := loadChainConfig(ctx)
+	if err != nil {
+		return err
+	}
+	if config.Parlia == nil {
+		return errors.New("witnessverify requires a parlia chain config")
+	}
+	from := ctx.Uint64(fromFlag.Name)
+	to := ctx.Uint64(toFlag.Name)
+	if !ctx.IsSet(toFlag.Name) {
+		to = from
+	}
+	if from == 0 || to < from {
+		return fmt.Errorf("invalid block range %d-%d", from, to)
+	}
+	client, err := ethclient.DialContext(ctx.Context, ctx.String(nodeFlag.Name))
+	if err != nil {
+		return fmt.Errorf("failed to connect to node: %w", err)
+	}
+	defer client.Close()
+
+	genesis, err := client.HeaderByNumber(ctx.Context, common.Big0)
+	if err != nil {
+		return fmt.Errorf("failed to fetch genesis header: %w", err)
+	}
+	if genesisHash != (common.Hash{}) && genesis.Hash() != genesisHash {
+		return fmt.Errorf("genesis mismatch: node %x, network %x", genesis.Hash(), genesisHash)
+	}
+	v := &verifier{
+		config:  config,
+		client:  client,
+		engine:  parlia.New(config, rawdb.NewMemoryDatabase(), nil, genesis.Hash()),
+		genesis: genesis,
+		known:   make(map[common.Hash]struct{}),
+	}
+	var (
+		out    = json.NewEncoder(os.Stdout)
+		parent common.Hash
+	)
+	for number := from; number <= to; number++ {
+		block, event, err := v.verify(ctx.Context, number)
+		if err != nil {
+			return fmt.Errorf("block %d: %w", number, err)
+		}
+		if number > from && block.ParentHash() != parent {
+			return fmt.Errorf("block %d does not extend block %d, the node reorganised", number, number-1)
+		}
+		parent = block.Hash()
+
+		log.Info("Verified block", "number", number, "hash", block.Hash(), "txs", event.Txs)
+		out.Encode(event)
+	}
+	return nil
+}
+
+// verify fetches the block with the given number and its witness, executes it
+// statelessly and checks the computed roots against the ones in the header.
+func (v *verifier) verify(ctx context.Context, number uint64) (*types.Block, *verifiedEvent, error) {
+	var blob hexutil.Bytes
+	if err := v.client.Client().CallContext(ctx, &blob, "debug_getRawBlock", hexutil.Uint64(number)); err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch block: %w", err)
+	}
+	block := new(types.Block)
+	if err := rlp.DecodeBytes(blob, block); err != nil {
+		return nil, nil, fmt.Errorf("invalid block: %w", err)
+	}
+	if block.NumberU64() != number {
+		return nil, nil, fmt.Errorf("node returned block %d", block.NumberU64())
+	}
+	var ext stateless.ExtWitness
+	if err := v.client.Client().CallContext(ctx, &ext, "debug_executionWitnessByHash", block.Hash()); err != nil {
+		return nil, nil, fmt.Errorf("failed to fetch witness: %w", err)
+	}
+	witness := ext.ToWitness()
+
+	// The parent header carries the pre-state root, it has to be the actual
+	// parent of the block or the execution proves nothing.
+	if len(witness.Headers) == 0 || witness.Headers[0].Hash() != block.ParentHash() {
+		return nil, nil, errors.New("witness does not start at the parent header")
+	}
+	if err := v.importSnapshot(ctx, witness.Headers[0]); err != nil {
+		return nil, nil, err
+	}
+	if number%finalityRewardInterval == 0 {
+		if err := v.addAncestors(ctx, witness); err != nil {
+			return nil, nil, err
+		}
+	}
+	// Remove the computed fields from the block to force their recalculation
+	context := block.Header()
+	context.Root = common.Hash{}
+	context.ReceiptHash = common.Hash{}
+	task := types.NewBlockWithHeader(context).WithBody(*block.Body())
+
+	stateRoot, receiptRoot, err := core.ExecuteStatelessWithEngine(v.config, vm.Config{}, v.engine, v.genesis, task, witness)
+	if err != nil {
+		return nil, nil, fmt.Errorf("stateless execution failed: %w", err)
+	}
+	if stateRoot != block.Root() {
+		return nil, nil, fmt.Errorf("state root mismatch (computed: %x header: %x)", stateRoot, block.Root())
+	}
+	if receiptRoot != block.ReceiptHash() {
+		return nil, nil, fmt.Errorf("receipt root mismatch (computed: %x header: %x)", receiptRoot, block.ReceiptHash())
+	}
+	return block, &verifiedEvent{
+		Number:      number,
+		Hash:        block.Hash(),
+		StateRoot:   stateRoot,
+		ReceiptRoot: receiptRoot,
+		Txs:         len(block.Transactions()),
+	}, nil
+}
+
+// addAncestors extends the witness with the ancestors weighed by the finality
+// reward distribution, along with their snapshots. The ancestors are linked to
+// the parent by hash, so the node can't substitute them.
+func (v *verifier) addAncestors(ctx context.Context, witness *stateless.Witness) error {
+	present := make(map[common.Hash]struct{}, len(witness.Headers))
+	for _, header := range witness.Headers {
+		present[header.Hash()] = struct{}{}
+	}
+	header := witness.Headers[0]
+	for i := 0; i < finalityRewardAncestors && header.Number.Uint64() > 0; i++ {
+		parent, err := v.client.HeaderByHash(ctx, header.ParentHash)
+		if err != nil {
+			return fmt.Errorf("failed to fetch ancestor %d: %w", header.Number.Uint64()-1, err)
+		}
+		if parent.Hash() != header.ParentHash {
+			return fmt.Errorf("ancestor %d hash mismatch", header.Number.Uint64()-1)
+		}
+		if _, ok := present[parent.Hash()]; !ok {
+			witness.Headers = append(witness.Headers, parent)
+			present[parent.Hash()] = struct{}{}
+		}
+		if err := v.importSnapshot(ctx, parent); err != nil {
+			return err
+		}
+		header = parent
+	}
+	return nil
+}
+
+// importSnapshot fetches the Parlia snapshot at the given header from the node
+// and installs it into the engine, unless already done.
+func (v *verifier) importSnapshot(ctx context.Context, header *types.Header) error {
+	hash := header.Hash()
+	if _, ok := v.known[hash]; ok {
+		return nil
+	}
+	snap := new(parlia.Snapshot)
+	if err := v.client.Client().CallContext(ctx, snap, "parlia_getSnapshotAtHash", hash); err != nil {
+		return fmt.Errorf("failed to fetch snapshot %d: %w", header.Number, err)
+	}
+	if snap.Hash != hash || snap.Number != header.Number.Uint64() {
+		return fmt.Errorf("snapshot %d does not match its header", header.Number)
+	}
+	if err := v.engine.ImportTrustedSnapshot(snap); err != nil {
+		return err
+	}
+	v.known[hash] = struct{}{}
+	return nil
+}
+
+// loadChainConfig returns the chain config to verify with, along with the hash
+// of the genesis block for the built-in networks.
+func loadChainConfig(ctx *cli.Context) (*params.ChainConfig, common.Hash, error) {
+	if path := ctx.String(chainConfigFlag.Name); path != "" {
+		blob, err := os.ReadFile(path)
+		if err != nil {
+			return nil, common.Hash{}, err
+		}
+		config := new(params.ChainConfig)
+		if err := json.Unmarshal(blob, config); err != nil {
+			return nil, common.Hash{}, fmt.Errorf("invalid chain config: %w", err)
+		}
+		return config, common.Hash{}, nil
+	}
+	switch network := strings.ToLower(ctx.String(networkFlag.Name)); network {
+	case "bsc", "mainnet":
+		return params.BSCChainConfig, params.BSCGenesisHash, nil
+	case "chapel", "testnet":
+		return params.ChapelChainConfig, params.ChapelGenesisHash, nil
+	case "rialto":
+		return params.RialtoChainConfig, params.RialtoGenesisHash, nil
+	default:
+		return nil, common.Hash{}, fmt.Errorf("unknown network %q", network)
+	}
+}
@@ -71,6 +71,12 @@ type VotePool interface {
 	FetchVotesByBlockHash(targetBlockHash common.Hash, sourceBlockNum uint64) []*types.VoteEnvelope
 }
 
+// PriceOracle provides the price reports of the validators to be included by
+// the block proposer.
+type PriceOracle interface {
+	PriceReports(round uint64) []*types.PriceReport
+}
+
 // ChainReader defines a small collection of methods needed to access the local
 // blockchain during header and/or uncle verification.
 type ChainReader interface {
@@ -163,7 +169,20 @@ type PoSA interface {
 	GetJustifiedNumberAndHash(chain ChainHeaderReader, headers []*types.Header) (uint64, common.Hash, error)
 	GetFinalizedHeader(chain ChainHeaderReader, header *types.Header) *types.Header
 	CheckFinalityAndNotify(chain ChainHeaderReader, targetBlockHash common.Hash, notifyFn func(finalizedHeader *types.Header))
+	GetVoteAttestation(chain ChainHeaderReader, header *types.Header) (*types.VoteAttestation, error)
+	GetFinalityAttestation(chain ChainHeaderReader, header *types.Header) (*types.VoteAttestation, error)
 	VerifyVote(chain ChainHeaderReader, vote *types.VoteEnvelope) error
 	IsActiveValidatorAt(chain ChainHeaderReader, header *types.Header, checkVoteKeyFn func(bLSPublicKey *types.BLSPublicKey) bool) bool
 	NextProposalBlock(chain ChainHeaderReader, header *types.Header, proposer common.Address) (uint64, uint64, error)
 }
+
+// TracingState returns the state of a block being assembled wrapped with the
+// given tracer, so that the balance changes made while finalizing it are
+// reported as they are when the block is processed. Without a tracer the state
+// is returned as is.
+func TracingState(statedb *state.StateDB, tracer *tracing.Hooks) vm.StateDB {
+	if tracer == nil {
+		return statedb
+	}
+	return state.NewHookedState(statedb, tracer)
+}
@@ -18,8 +18,10 @@ package parlia
 
 import (
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
@@ -114,6 +116,20 @@ func (api *API) GetFinalizedNumber(number *rpc.BlockNumber) (uint64, error) {
 	return snap.Attestation.SourceNumber, nil
 }
 
+// SubmitReserveAttestation queues an RLP encoded reserve attestation signed by
+// the reserve auditors for inclusion in the next block proposed by the local
+// validator, returning its digest.
+func (api *API) SubmitReserveAttestation(blob hexutil.Bytes) (common.Hash, error) {
+	att := new(types.ReserveAttestation)
+	if err := rlp.DecodeBytes(blob, att); err != nil {
+		return common.Hash{}, err
+	}
+	if err := api.parlia.SubmitReserveAttestation(att); err != nil {
+		return common.Hash{}, err
+	}
+	return att.Digest(), nil
+}
+
 func (api *API) getHeader(number *rpc.BlockNumber) (header *types.Header) {
 	currentHeader := api.chain.CurrentHeader()
 
@@ -0,0 +1,127 @@
+package parlia
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// feeSplitDenominator is the denominator of the fee split shares, which are
+// expressed in basis points.
+const feeSplitDenominator = 10000
+
+var (
+	feeSplitContract = common.HexToAddress(systemcontracts.FeeSplitContract)
+
+	// Storage layout of the fee split contract. The first slot packs the four
+	// uint16 shares in declaration order (validators, system reward, treasury,
+	// yield) starting from the lowest-order bytes, followed by the treasury and
+	// the yield distributor addresses.
+	feeSplitSharesSlot   = common.BigToHash(big.NewInt(0))
+	feeSplitTreasurySlot = common.BigToHash(big.NewInt(1))
+	feeSplitYieldSlot    = common.BigToHash(big.NewInt(2))
+)
+
+// feeSplit is the governance configured split of the fees collected in a block.
+type feeSplit struct {
+	Validators   uint64 // Share deposited to the validator contract
+	SystemReward uint64 // Share sent to the system reward contract
+	Treasury     uint64 // Share credited to the treasury
+	Yield        uint64 // Share credited to the holder yield distributor
+
+	TreasuryAddr common.Address
+	YieldAddr    common.Address
+}
+
+// getFeeSplit reads the fee split from the state of the fee split contract. It
+// returns nil if no valid split is configured, in which case the legacy
+// distribution applies.
+func getFeeSplit(state vm.StateDB) *feeSplit {
+	shares := state.GetState(feeSplitContract, feeSplitSharesSlot)
+	if shares == (common.Hash{}) {
+		return nil
+	}
+	split := &feeSplit{
+		Validators:   uint64(shares[30])<<8 | uint64(shares[31]),
+		SystemReward: uint64(shares[28])<<8 | uint64(shares[29]),
+		Treasury:     uint64(shares[26])<<8 | uint64(shares[27]),
+		Yield:        uint64(shares[24])<<8 | uint64(shares[25]),
+		TreasuryAddr: common.BytesToAddress(state.GetState(feeSplitContract, feeSplitTreasurySlot).Bytes()),
+		YieldAddr:    common.BytesToAddress(state.GetState(feeSplitContract, feeSplitYieldSlot).Bytes()),
+	}
+	if sum := split.Validators + split.SystemReward + split.Treasury + split.Yield; sum != feeSplitDenominator {
+		log.Warn("Ignoring invalid fee split", "total", sum)
+		return nil
+	}
+	if (split.Treasury > 0 && split.TreasuryAddr == (common.Address{})) || (split.Yield > 0 && split.YieldAddr == (common.Address{})) {
+		log.Warn("Ignoring fee split without recipient", "treasury", split.TreasuryAddr, "yield", split.YieldAddr)
+		return nil
+	}
+	return split
+}
+
+// share returns the part of amount allotted to the given share.
+func (s *feeSplit) share(amount *uint256.Int, share uint64) *uint256.Int {
+	part, _ := new(uint256.Int).MulDivOverflow(amount, uint256.NewInt(share), uint256.NewInt(feeSplitDenominator))
+	return part
+}
+
+// distributeFeeSplit distributes the system incoming of the block according to
+// the governance fee split. The system reward and the validator shares are paid
+// through system transactions as before, while the treasury and the yield
+// distributor are credited directly since they are not system contracts. If the
+// yield distributor is the yield index account, the holder share accrues to the
+// native holders instead. Any rounding dust is left to the validators.
+func (p *Parlia) distributeFeeSplit(split *feeSplit, val common.Address, state vm.StateDB, header *types.Header, chain core.ChainContext,
+	txs *[]*types.Transaction, receipts *[]*types.Receipt, receivedTxs *[]*types.Transaction, usedGas *uint64, mining bool, tracer *tracing.Hooks) error {
+	balance := state.GetBalance(consensus.SystemAddress).Clone()
+	if balance.IsZero() {
+		return nil
+	}
+	var (
+		treasury = split.share(balance, split.Treasury)
+		yield    = split.share(balance, split.Yield)
+		system   = split.share(balance, split.SystemReward)
+	)
+	if !treasury.IsZero() {
+		state.SubBalance(consensus.SystemAddress, treasury, tracing.BalanceDecreaseBSCDistributeReward)
+		state.AddBalance(split.TreasuryAddr, treasury, tracing.BalanceIncreaseBSCDistributeReward)
+		log.Trace("distribute to treasury", "block hash", header.Hash(), "amount", treasury)
+	}
+	if !yield.IsZero() {
+		state.SubBalance(consensus.SystemAddress, yield, tracing.BalanceDecreaseBSCDistributeReward)
+		if split.YieldAddr == params.YieldIndexAddress {
+			// Accrued to the native holders, who are paid as their balances are touched
+			state.AccrueYield(yield)
+			log.Trace("accrue to native holders", "block hash", header.Hash(), "amount", yield)
+		} else {
+			state.AddBalance(split.YieldAddr, yield, tracing.BalanceIncreaseBSCDistributeReward)
+			log.Trace("distribute to yield distributor", "block hash", header.Hash(), "amount", yield)
+		}
+	}
+	if !system.IsZero() {
+		state.SubBalance(consensus.SystemAddress, system, tracing.BalanceDecreaseBSCDistributeReward)
+		state.AddBalance(header.Coinbase, system, tracing.BalanceIncreaseBSCDistributeReward)
+		if err := p.distributeToSystem(system.ToBig(), state, header, chain, txs, receipts, receivedTxs, usedGas, mining, tracer); err != nil {
+			return err
+		}
+		log.Trace("distribute to system reward pool", "block hash", header.Hash(), "amount", system)
+	}
+	rest := state.GetBalance(consensus.SystemAddress)
+	if rest.IsZero() {
+		return nil
+	}
+	state.SetBalance(consensus.SystemAddress, common.U2560, tracing.BalanceDecreaseBSCDistributeReward)
+	state.AddBalance(header.Coinbase, rest, tracing.BalanceIncreaseBSCDistributeReward)
+	log.Trace("distribute to validator contract", "block hash", header.Hash(), "amount", rest)
+	return p.distributeToValidator(rest.ToBig(), val, state, header, chain, txs, receipts, receivedTxs, usedGas, mining, tracer)
+}
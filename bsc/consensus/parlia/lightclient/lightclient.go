@@ -0,0 +1,223 @@
+// Package lightclient implements a header-only Parlia client which follows the
+// finality of a BSC-style chain from a trusted checkpoint. It verifies header
+// seals against the validator set rotated at epoch boundaries and checks the
+// aggregated BLS vote attestations carried in the header extra-data, without
+// executing any block.
+package lightclient
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/parlia"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	// CheckpointDepth is the number of consecutive headers, ending at the
+	// trusted block, a non-genesis checkpoint has to provide. Vote attestations
+	// may target an ancestor up to three blocks back, whose parent snapshot is
+	// needed to check the quorum.
+	CheckpointDepth = 4
+
+	// defaultRetain is the default number of recent headers kept in memory.
+	defaultRetain = 2048
+)
+
+var (
+	// errInvalidCheckpoint is returned if the trusted checkpoint is malformed.
+	errInvalidCheckpoint = errors.New("invalid checkpoint")
+
+	// errUnknownParent is returned if a header does not connect to any
+	// retained header.
+	errUnknownParent = errors.New("unknown parent")
+
+	// errFinalityConflict is returned if a header branches off the verified
+	// chain below the latest finalized block.
+	errFinalityConflict = errors.New("header conflicts with finalized chain")
+)
+
+// Checkpoint is the trusted starting point of the light client.
+type Checkpoint struct {
+	// Headers is a contiguous run of headers ending at the trusted block, in
+	// ascending order. A genesis checkpoint consists of the genesis header
+	// alone. Otherwise at least CheckpointDepth headers are required, and if
+	// the trusted block is shortly after an epoch boundary the run has to
+	// reach back to the epoch block, whose validator set is yet to be applied.
+	Headers []*types.Header
+
+	// Snapshots holds the Parlia snapshots at the last len(Snapshots) headers,
+	// in the same order, and must cover at least CheckpointDepth of them. It
+	// is left empty for a genesis checkpoint, whose validator set is taken
+	// from the genesis extra-data.
+	Snapshots []*parlia.Snapshot
+}
+
+// Config contains the tunables of the light client.
+type Config struct {
+	Retain uint64 // Number of recent headers to keep for verification and queries
+}
+
+// Client verifies a stream of Parlia headers and tracks the latest finalized
+// block proven by fast-finality vote attestations.
+type Client struct {
+	config *Config
+	engine *parlia.Parlia
+	store  *headerStore
+
+	finalized *types.Header
+	lock      sync.RWMutex
+}
+
+// New creates a light client anchored at the given trusted checkpoint.
+func New(chainConfig *params.ChainConfig, checkpoint *Checkpoint, config *Config) (*Client, error) {
+	if chainConfig == nil || chainConfig.Parlia == nil {
+		return nil, errors.New("light client requires a parlia chain config")
+	}
+	if checkpoint == nil || len(checkpoint.Headers) == 0 {
+		return nil, fmt.Errorf("%w: no headers", errInvalidCheckpoint)
+	}
+	if config == nil {
+		config = &Config{}
+	}
+	if config.Retain == 0 {
+		config.Retain = defaultRetain
+	}
+	headers := checkpoint.Headers
+	for i := 1; i < len(headers); i++ {
+		if headers[i].Number.Uint64() != headers[i-1].Number.Uint64()+1 || headers[i].ParentHash != headers[i-1].Hash() {
+			return nil, fmt.Errorf("%w: headers not contiguous at %d", errInvalidCheckpoint, headers[i].Number)
+		}
+	}
+	// The engine keeps its snapshots in an ephemeral database, the genesis
+	// hash is only consulted when finalizing blocks and is thus irrelevant.
+	engine := parlia.New(chainConfig, rawdb.NewMemoryDatabase(), nil, common.Hash{})
+	store := newHeaderStore(chainConfig)
+
+	trusted := headers[len(headers)-1]
+	if trusted.Number.Uint64() == 0 {
+		if len(headers) != 1 || len(checkpoint.Snapshots) != 0 {
+			return nil, fmt.Errorf("%w: genesis checkpoint takes the genesis header only", errInvalidCheckpoint)
+		}
+		store.genesis = trusted
+	} else {
+		if len(headers) < CheckpointDepth {
+			return nil, fmt.Errorf("%w: need %d headers, have %d", errInvalidCheckpoint, CheckpointDepth, len(headers))
+		}
+		if len(checkpoint.Snapshots) < CheckpointDepth || len(checkpoint.Snapshots) > len(headers) {
+			return nil, fmt.Errorf("%w: need %d to %d snapshots, have %d", errInvalidCheckpoint, CheckpointDepth, len(headers), len(checkpoint.Snapshots))
+		}
+		offset := len(headers) - len(checkpoint.Snapshots)
+		for i, snap := range checkpoint.Snapshots {
+			header := headers[offset+i]
+			if snap == nil || snap.Hash != header.Hash() || snap.Number != header.Number.Uint64() {
+				return nil, fmt.Errorf("%w: snapshot %d does not match its header", errInvalidCheckpoint, i)
+			}
+			if snap.Attestation == nil && chainConfig.IsLuban(header.Number) {
+				return nil, fmt.Errorf("%w: snapshot %d lacks attestation", errInvalidCheckpoint, i)
+			}
+			if err := engine.ImportTrustedSnapshot(snap); err != nil {
+				return nil, err
+			}
+		}
+	}
+	for _, header := range headers {
+		store.add(header)
+	}
+	store.setHead(trusted)
+
+	c := &Client{
+		config: config,
+		engine: engine,
+		store:  store,
+	}
+	c.finalized = c.engine.GetFinalizedHeader(store, trusted)
+	return c, nil
+}
+
+// InsertHeaders verifies the given headers in order and appends them to the
+// tracked chain. Headers may branch off any retained header above the
+// finalized block, in which case the branch becomes the new head. It returns
+// the number of headers accepted before the first failure.
+func (c *Client) InsertHeaders(headers []*types.Header) (int, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for i, header := range headers {
+		if err := c.insert(header); err != nil {
+			return i, err
+		}
+	}
+	return len(headers), nil
+}
+
+func (c *Client) insert(header *types.Header) error {
+	if c.store.GetHeaderByHash(header.Hash()) != nil {
+		return nil // Already verified
+	}
+	parent := c.store.GetHeaderByHash(header.ParentHash)
+	if parent == nil {
+		return fmt.Errorf("%w: number %d, parent %x", errUnknownParent, header.Number, header.ParentHash)
+	}
+	if c.finalized != nil {
+		fork, ok := c.store.forkPoint(parent)
+		if !ok || fork < c.finalized.Number.Uint64() {
+			return fmt.Errorf("%w: number %d, hash %x", errFinalityConflict, header.Number, header.Hash())
+		}
+	}
+	if err := c.engine.VerifyHeader(c.store, header); err != nil {
+		return err
+	}
+	c.store.add(header)
+	c.store.setHead(header)
+
+	if finalized := c.engine.GetFinalizedHeader(c.store, header); finalized != nil {
+		if c.finalized == nil || finalized.Number.Uint64() > c.finalized.Number.Uint64() {
+			log.Debug("Light client finalized block", "number", finalized.Number, "hash", finalized.Hash())
+			c.finalized = finalized
+		}
+	}
+	if number := header.Number.Uint64(); number > c.config.Retain {
+		c.store.prune(number - c.config.Retain)
+	}
+	return nil
+}
+
+// Head returns the latest verified header.
+func (c *Client) Head() *types.Header {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.store.CurrentHeader()
+}
+
+// Finalized returns the latest header proven final by vote attestations, or
+// nil if none is known yet.
+func (c *Client) Finalized() *types.Header {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.finalized
+}
+
+// Justified returns the number and hash of the highest justified block on
+// the verified chain.
+func (c *Client) Justified() (uint64, common.Hash, error) {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.engine.GetJustifiedNumberAndHash(c.store, []*types.Header{c.store.CurrentHeader()})
+}
+
+// Header returns a retained verified header by hash.
+func (c *Client) Header(hash common.Hash) *types.Header {
+	c.lock.RLock()
+	defer c.lock.RUnlock()
+
+	return c.store.GetHeaderByHash(hash)
+}
@@ -0,0 +1,278 @@
+package lightclient
+
+import (
+	"bytes"
+	"crypto/ecdsa"
+	"errors"
+	"math/big"
+	"sort"
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/parlia"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+const (
+	testEpoch      = 200 // Default Parlia epoch length
+	testValidators = 3
+)
+
+type testValidator struct {
+	key     *ecdsa.PrivateKey
+	addr    common.Address
+	voteKey bls.SecretKey
+}
+
+func newTestValidators(t *testing.T, n int) []*testValidator {
+	vals := make([]*testValidator, n)
+	for i := range vals {
+		key, _ := crypto.GenerateKey()
+		voteKey, err := bls.RandKey()
+		if err != nil {
+			t.Fatalf("failed to generate vote key: %v", err)
+		}
+		vals[i] = &testValidator{key: key, addr: crypto.PubkeyToAddress(key.PublicKey), voteKey: voteKey}
+	}
+	sort.Slice(vals, func(i, j int) bool { return bytes.Compare(vals[i].addr[:], vals[j].addr[:]) < 0 })
+	return vals
+}
+
+// testChain generates a Parlia header chain signed by a validator set which is
+// rotated at the first epoch boundary, with every block attesting its parent.
+type testChain struct {
+	config  *params.ChainConfig
+	initial []*testValidator // Validators up to the first epoch switch
+	rotated []*testValidator // Validators listed in the first epoch block
+	headers []*types.Header
+}
+
+func newTestChain(t *testing.T, n int) *testChain {
+	config := *params.ParliaTestChainConfig
+	config.CancunTime = nil
+	c := &testChain{
+		config:  &config,
+		initial: newTestValidators(t, testValidators),
+		rotated: newTestValidators(t, testValidators),
+	}
+	genesis := &types.Header{
+		Number:     big.NewInt(0),
+		Time:       uint64(time.Now().Add(-time.Hour).Unix()),
+		GasLimit:   30_000_000,
+		Difficulty: big.NewInt(1),
+		BaseFee:    new(big.Int),
+		UncleHash:  types.EmptyUncleHash,
+		Extra:      c.extra(0, c.initial, nil),
+	}
+	c.headers = append(c.headers, genesis)
+	for i := 1; i <= n; i++ {
+		c.headers = append(c.headers, c.next(t, c.headers[i-1], nil))
+	}
+	return c
+}
+
+// validatorsAt returns the validator set of the snapshot at the given block.
+func (c *testChain) validatorsAt(number uint64) []*testValidator {
+	// The set listed in the epoch block becomes active once the block after
+	// it is applied, as 3 validators give a miner history check length of 1.
+	if number > testEpoch {
+		return c.rotated
+	}
+	return c.initial
+}
+
+func (c *testChain) extra(number uint64, validators []*testValidator, attestation *types.VoteAttestation) []byte {
+	extra := make([]byte, 32)
+	if number%testEpoch == 0 {
+		extra = append(extra, byte(len(validators)))
+		for _, val := range validators {
+			extra = append(extra, val.addr.Bytes()...)
+			extra = append(extra, val.voteKey.PublicKey().Marshal()...)
+		}
+	}
+	if attestation != nil {
+		enc, _ := rlp.EncodeToBytes(attestation)
+		extra = append(extra, enc...)
+	}
+	return append(extra, make([]byte, 65)...)
+}
+
+// next creates a child of parent sealed by the in-turn validator. The signer
+// may be overridden to produce invalid headers.
+func (c *testChain) next(t *testing.T, parent *types.Header, signer *testValidator) *types.Header {
+	number := parent.Number.Uint64() + 1
+	vals := c.validatorsAt(number - 1)
+	if signer == nil {
+		signer = vals[number%uint64(len(vals))]
+	}
+	var attestation *types.VoteAttestation
+	if number >= 2 {
+		grandparent := c.headers[number-2]
+		if grandparent.Hash() != parent.ParentHash {
+			t.Fatalf("test chain only supports the canonical branch")
+		}
+		// Every block attests its parent, so the parent's parent is justified.
+		data := &types.VoteData{
+			SourceNumber: number - 2,
+			SourceHash:   grandparent.Hash(),
+			TargetNumber: number - 1,
+			TargetHash:   parent.Hash(),
+		}
+		voters := c.validatorsAt(number - 2)
+		sigs := make([]bls.Signature, len(voters))
+		attestation = &types.VoteAttestation{Data: data}
+		for i, val := range voters {
+			sigs[i] = val.voteKey.Sign(data.Hash().Bytes())
+			attestation.VoteAddressSet |= 1 << i
+		}
+		copy(attestation.AggSignature[:], bls.AggregateSignatures(sigs).Marshal())
+	}
+	var validators []*testValidator
+	if number%testEpoch == 0 {
+		validators = c.rotated
+	}
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		UncleHash:  types.EmptyUncleHash,
+		Coinbase:   signer.addr,
+		Number:     new(big.Int).SetUint64(number),
+		Time:       parent.Time + 3,
+		GasLimit:   parent.GasLimit,
+		Difficulty: big.NewInt(2),
+		BaseFee:    new(big.Int),
+		Extra:      c.extra(number, validators, attestation),
+	}
+	sig, err := crypto.Sign(types.SealHash(header, c.config.ChainID).Bytes(), signer.key)
+	if err != nil {
+		t.Fatalf("failed to seal header: %v", err)
+	}
+	copy(header.Extra[len(header.Extra)-65:], sig)
+	return header
+}
+
+func TestFollowFromGenesis(t *testing.T) {
+	chain := newTestChain(t, testEpoch+10)
+
+	client, err := New(chain.config, &Checkpoint{Headers: chain.headers[:1]}, nil)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if n, err := client.InsertHeaders(chain.headers[1:]); err != nil {
+		t.Fatalf("failed to insert header %d: %v", n+1, err)
+	}
+	head := chain.headers[len(chain.headers)-1]
+	if have := client.Head(); have.Hash() != head.Hash() {
+		t.Fatalf("head mismatch: have %d, want %d", have.Number, head.Number)
+	}
+	want := chain.headers[len(chain.headers)-3]
+	if have := client.Finalized(); have == nil || have.Hash() != want.Hash() {
+		t.Fatalf("finalized mismatch: have %v, want %d", have, want.Number)
+	}
+	number, hash, err := client.Justified()
+	if err != nil {
+		t.Fatalf("failed to get justified block: %v", err)
+	}
+	if want := chain.headers[len(chain.headers)-2]; number != want.Number.Uint64() || hash != want.Hash() {
+		t.Fatalf("justified mismatch: have %d, want %d", number, want.Number)
+	}
+}
+
+func TestFollowFromCheckpoint(t *testing.T) {
+	chain := newTestChain(t, testEpoch+10)
+
+	// Derive the checkpoint snapshots from a client that followed from genesis.
+	full, err := New(chain.config, &Checkpoint{Headers: chain.headers[:1]}, nil)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	const trusted = 150
+	if n, err := full.InsertHeaders(chain.headers[1 : trusted+1]); err != nil {
+		t.Fatalf("failed to insert header %d: %v", n+1, err)
+	}
+	api := full.engine.APIs(full.store)[0].Service.(*parlia.API)
+	checkpoint := &Checkpoint{Headers: chain.headers[trusted-CheckpointDepth+1 : trusted+1]}
+	for _, header := range checkpoint.Headers {
+		snap, err := api.GetSnapshotAtHash(header.Hash())
+		if err != nil {
+			t.Fatalf("failed to get snapshot %d: %v", header.Number, err)
+		}
+		checkpoint.Snapshots = append(checkpoint.Snapshots, snap)
+	}
+	client, err := New(chain.config, checkpoint, nil)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if want := chain.headers[trusted-2]; client.Finalized().Hash() != want.Hash() {
+		t.Fatalf("checkpoint finalized mismatch: have %d, want %d", client.Finalized().Number, want.Number)
+	}
+	if n, err := client.InsertHeaders(chain.headers[trusted+1:]); err != nil {
+		t.Fatalf("failed to insert header %d: %v", trusted+n+1, err)
+	}
+	if want := chain.headers[len(chain.headers)-3]; client.Finalized().Hash() != want.Hash() {
+		t.Fatalf("finalized mismatch: have %d, want %d", client.Finalized().Number, want.Number)
+	}
+}
+
+func TestInvalidCheckpoint(t *testing.T) {
+	chain := newTestChain(t, 10)
+
+	if _, err := New(chain.config, &Checkpoint{Headers: chain.headers[5:7]}, nil); !errors.Is(err, errInvalidCheckpoint) {
+		t.Fatalf("short checkpoint: have %v, want %v", err, errInvalidCheckpoint)
+	}
+	headers := []*types.Header{chain.headers[1], chain.headers[3], chain.headers[4], chain.headers[5]}
+	if _, err := New(chain.config, &Checkpoint{Headers: headers}, nil); !errors.Is(err, errInvalidCheckpoint) {
+		t.Fatalf("gapped checkpoint: have %v, want %v", err, errInvalidCheckpoint)
+	}
+}
+
+func TestRejectInvalidHeaders(t *testing.T) {
+	chain := newTestChain(t, testEpoch+10)
+
+	client, err := New(chain.config, &Checkpoint{Headers: chain.headers[:1]}, nil)
+	if err != nil {
+		t.Fatalf("failed to create client: %v", err)
+	}
+	if n, err := client.InsertHeaders(chain.headers[1:]); err != nil {
+		t.Fatalf("failed to insert header %d: %v", n+1, err)
+	}
+	head := chain.headers[len(chain.headers)-1]
+
+	// A validator rotated out at the epoch boundary can no longer seal.
+	stale := chain.next(t, head, chain.initial[0])
+	if _, err := client.InsertHeaders([]*types.Header{stale}); err == nil {
+		t.Fatal("header sealed by a retired validator accepted")
+	}
+	// An attestation signed by too few validators is rejected.
+	forged := chain.next(t, head, nil)
+	attestation := &types.VoteAttestation{
+		VoteAddressSet: 1,
+		Data: &types.VoteData{
+			SourceNumber: head.Number.Uint64() - 1,
+			SourceHash:   head.ParentHash,
+			TargetNumber: head.Number.Uint64(),
+			TargetHash:   head.Hash(),
+		},
+	}
+	copy(attestation.AggSignature[:], chain.rotated[0].voteKey.Sign(attestation.Data.Hash().Bytes()).Marshal())
+	forged.Extra = chain.extra(forged.Number.Uint64(), nil, attestation)
+	sig, _ := crypto.Sign(types.SealHash(forged, chain.config.ChainID).Bytes(), chain.validatorsAt(head.Number.Uint64())[forged.Number.Uint64()%testValidators].key)
+	copy(forged.Extra[len(forged.Extra)-65:], sig)
+	if _, err := client.InsertHeaders([]*types.Header{forged}); err == nil {
+		t.Fatal("header with insufficient attestation accepted")
+	}
+	// Headers that do not connect to the verified chain are rejected.
+	orphan := chain.next(t, head, nil)
+	orphan.ParentHash = common.Hash{0x1}
+	if _, err := client.InsertHeaders([]*types.Header{orphan}); !errors.Is(err, errUnknownParent) {
+		t.Fatalf("orphan header: have %v, want %v", err, errUnknownParent)
+	}
+	if client.Head().Hash() != head.Hash() {
+		t.Fatal("head moved after rejected headers")
+	}
+}
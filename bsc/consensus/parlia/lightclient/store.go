@@ -0,0 +1,146 @@
+package lightclient
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// headerStore is an in-memory window of recently verified headers. It serves
+// as the consensus.ChainHeaderReader the Parlia engine verifies against, so
+// only the headers needed for snapshot and attestation checks are retained.
+type headerStore struct {
+	config  *params.ChainConfig
+	genesis *types.Header // Only set when bootstrapping from genesis
+
+	headers map[common.Hash]*types.Header // All retained headers, canonical or not
+	canon   map[uint64]common.Hash        // Canonical number -> hash mapping
+	head    *types.Header
+}
+
+var _ consensus.ChainHeaderReader = (*headerStore)(nil)
+
+func newHeaderStore(config *params.ChainConfig) *headerStore {
+	return &headerStore{
+		config:  config,
+		headers: make(map[common.Hash]*types.Header),
+		canon:   make(map[uint64]common.Hash),
+	}
+}
+
+// add stores a header without touching the canonical chain.
+func (s *headerStore) add(header *types.Header) {
+	s.headers[header.Hash()] = header
+}
+
+// setHead marks header as the new canonical head, rewriting the canonical
+// mapping back to the first ancestor that is already canonical. It returns
+// the number of that common ancestor.
+func (s *headerStore) setHead(header *types.Header) uint64 {
+	if s.head != nil {
+		for n := header.Number.Uint64() + 1; n <= s.head.Number.Uint64(); n++ {
+			delete(s.canon, n)
+		}
+	}
+	s.head = header
+
+	cur := header
+	for cur != nil {
+		number, hash := cur.Number.Uint64(), cur.Hash()
+		if s.canon[number] == hash {
+			return number
+		}
+		s.canon[number] = hash
+		if number == 0 {
+			return 0
+		}
+		cur = s.headers[cur.ParentHash]
+	}
+	return header.Number.Uint64()
+}
+
+// forkPoint returns the number of the highest canonical ancestor of header,
+// walking back through retained headers. The boolean is false if the branch
+// cannot be connected to the retained canonical chain.
+func (s *headerStore) forkPoint(header *types.Header) (uint64, bool) {
+	cur := header
+	for cur != nil {
+		number := cur.Number.Uint64()
+		if s.canon[number] == cur.Hash() {
+			return number, true
+		}
+		if number == 0 {
+			break
+		}
+		cur = s.headers[cur.ParentHash]
+	}
+	return 0, false
+}
+
+// prune drops every header below the given number, keeping the store bounded
+// while the client follows the chain.
+func (s *headerStore) prune(below uint64) {
+	for hash, header := range s.headers {
+		if header.Number.Uint64() < below {
+			delete(s.headers, hash)
+		}
+	}
+	for number := range s.canon {
+		if number < below {
+			delete(s.canon, number)
+		}
+	}
+}
+
+// Config retrieves the chain configuration.
+func (s *headerStore) Config() *params.ChainConfig { return s.config }
+
+// CurrentHeader retrieves the head of the verified header chain.
+func (s *headerStore) CurrentHeader() *types.Header { return s.head }
+
+// GetHeader retrieves a retained header by hash and number.
+func (s *headerStore) GetHeader(hash common.Hash, number uint64) *types.Header {
+	header := s.headers[hash]
+	if header == nil || header.Number.Uint64() != number {
+		return nil
+	}
+	return header
+}
+
+// GetHeaderByNumber retrieves a retained canonical header by number.
+func (s *headerStore) GetHeaderByNumber(number uint64) *types.Header {
+	if number == 0 && s.genesis != nil {
+		return s.genesis
+	}
+	hash, ok := s.canon[number]
+	if !ok {
+		return nil
+	}
+	return s.headers[hash]
+}
+
+// GetHeaderByHash retrieves a retained header by hash.
+func (s *headerStore) GetHeaderByHash(hash common.Hash) *types.Header {
+	return s.headers[hash]
+}
+
+// GenesisHeader returns the genesis header if the client was bootstrapped
+// from it, nil otherwise.
+func (s *headerStore) GenesisHeader() *types.Header { return s.genesis }
+
+// GetTd is not tracked by the light client, Parlia does not rely on it.
+func (s *headerStore) GetTd(hash common.Hash, number uint64) *big.Int { return nil }
+
+// GetHighestVerifiedHeader returns the head of the verified header chain.
+func (s *headerStore) GetHighestVerifiedHeader() *types.Header { return s.head }
+
+// GetVerifiedBlockByHash retrieves a retained header by hash.
+func (s *headerStore) GetVerifiedBlockByHash(hash common.Hash) *types.Header {
+	return s.headers[hash]
+}
+
+// ChasingHead returns the head of the verified header chain.
+func (s *headerStore) ChasingHead() *types.Header { return s.head }
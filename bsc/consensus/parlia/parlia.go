@@ -10,6 +10,7 @@ import (
 	"math"
 	"math/big"
 	"math/rand"
+	"slices"
 	"sort"
 	"strings"
 	"sync"
@@ -87,6 +88,8 @@ const (
 	finalityRewardInterval = 200
 
 	kAncestorGenerationDepth = 3
+
+	finalityAttestationSearchDepth = 8 // Number of descendants searched for the attestation finalizing a block
 )
 
 var (
@@ -260,11 +263,15 @@ type Parlia struct {
 
 	ethAPI                     *ethapi.BlockChainAPI
 	VotePool                   consensus.VotePool
+	PriceOracle                consensus.PriceOracle
 	validatorSetABIBeforeLuban abi.ABI
 	validatorSetABI            abi.ABI
 	slashABI                   abi.ABI
 	stakeHubABI                abi.ABI
 
+	pendingReserve *types.ReserveAttestation // Reserve attestation to include when proposing
+	reserveLock    sync.Mutex                // Protects the pending reserve attestation
+
 	// finalizedNotified tracks blocks that have already triggered early finalization notification
 	// to avoid duplicate notifications
 	finalizedNotified *lru.Cache[common.Hash, struct{}]
@@ -321,7 +328,10 @@ func New(
 }
 
 func (p *Parlia) IsSystemTransaction(tx *types.Transaction, header *types.Header) (bool, error) {
-	if tx.To() == nil || !isToSystemContract(*tx.To()) {
+	if tx.To() == nil {
+		return false, nil
+	}
+	if !isToSystemContract(*tx.To()) && !p.isToMendelSystemAccount(*tx.To(), header) {
 		return false, nil
 	}
 	if tx.GasPrice().Sign() != 0 {
@@ -1098,18 +1108,38 @@ func (p *Parlia) assembleVoteAttestation(chain consensus.ChainHeaderReader, head
 	}
 
 	// === Step 3: Build vote attestation ===
-	attestation := &types.VoteAttestation{
-		Data: &types.VoteData{
-			SourceNumber: justifiedBlockNumber,
-			SourceHash:   justifiedBlockHash,
-			TargetNumber: targetHeader.Number.Uint64(),
-			TargetHash:   targetHeader.Hash(),
-		},
+	attestation, err := newVoteAttestation(&types.VoteData{
+		SourceNumber: justifiedBlockNumber,
+		SourceHash:   justifiedBlockHash,
+		TargetNumber: targetHeader.Number.Uint64(),
+		TargetHash:   targetHeader.Hash(),
+	}, votes, targetHeaderParentSnap)
+	if err != nil {
+		return err
+	}
+
+	// === Step 4: Encode & insert into header extra ===
+	buf := new(bytes.Buffer)
+	if err = rlp.Encode(buf, attestation); err != nil {
+		return fmt.Errorf("attestation: failed to encode: %w", err)
 	}
+	extraSealStart := len(header.Extra) - extraSeal
+	extraSealBytes := header.Extra[extraSealStart:]
+	header.Extra = append(header.Extra[:extraSealStart], buf.Bytes()...)
+	header.Extra = append(header.Extra, extraSealBytes...)
+
+	return nil
+}
+
+// newVoteAttestation aggregates the votes for data into an attestation, marking
+// the voters in the bitset according to their index in snap, the snapshot of
+// the target block's parent.
+func newVoteAttestation(data *types.VoteData, votes []*types.VoteEnvelope, snap *Snapshot) (*types.VoteAttestation, error) {
+	attestation := &types.VoteAttestation{Data: data}
 	// Validate vote data consistency
 	for _, vote := range votes {
 		if vote.Data.Hash() != attestation.Data.Hash() {
-			return fmt.Errorf("vote check error, expected: %v, real: %v", attestation.Data, vote.Data)
+			return nil, fmt.Errorf("vote check error, expected: %v, real: %v", attestation.Data, vote.Data)
 		}
 	}
 	// Prepare aggregated vote signature
@@ -1121,11 +1151,11 @@ func (p *Parlia) assembleVoteAttestation(chain consensus.ChainHeaderReader, head
 	}
 	sigs, err := bls.MultipleSignaturesFromBytes(signatures)
 	if err != nil {
-		return err
+		return nil, err
 	}
 	copy(attestation.AggSignature[:], bls.AggregateSignatures(sigs).Marshal())
 	// Prepare vote address bitset.
-	for _, valInfo := range targetHeaderParentSnap.Validators {
+	for _, valInfo := range snap.Validators {
 		if _, ok := voteAddrSet[valInfo.VoteAddress]; ok {
 			attestation.VoteAddressSet |= 1 << (valInfo.Index - 1) // Index is offset by 1
 		}
@@ -1133,20 +1163,9 @@ func (p *Parlia) assembleVoteAttestation(chain consensus.ChainHeaderReader, head
 	bitsetCount := bitset.From([]uint64{uint64(attestation.VoteAddressSet)}).Count()
 	if bitsetCount < uint(len(signatures)) {
 		log.Warn(fmt.Sprintf("assembleVoteAttestation, check VoteAddress Set failed, expected:%d, real:%d", len(signatures), bitsetCount))
-		return errors.New("invalid attestation, check VoteAddress Set failed")
+		return nil, errors.New("invalid attestation, check VoteAddress Set failed")
 	}
-
-	// === Step 4: Encode & insert into header extra ===
-	buf := new(bytes.Buffer)
-	if err = rlp.Encode(buf, attestation); err != nil {
-		return fmt.Errorf("attestation: failed to encode: %w", err)
-	}
-	extraSealStart := len(header.Extra) - extraSeal
-	extraSealBytes := header.Extra[extraSealStart:]
-	header.Extra = append(header.Extra[:extraSealStart], buf.Bytes()...)
-	header.Extra = append(header.Extra, extraSealBytes...)
-
-	return nil
+	return attestation, nil
 }
 
 // NextInTurnValidator return the next in-turn validator for header
@@ -1159,6 +1178,22 @@ func (p *Parlia) NextInTurnValidator(chain consensus.ChainHeaderReader, header *
 	return snap.inturnValidator(), nil
 }
 
+// InTurnValidators returns the validator in turn to propose the block after
+// header, followed by the validator whose turn comes next.
+func (p *Parlia) InTurnValidators(chain consensus.ChainHeaderReader, header *types.Header) ([]common.Address, error) {
+	snap, err := p.snapshot(chain, header.Number.Uint64(), header.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	validators := snap.validators()
+	current := snap.inturnValidator()
+	next := validators[(slices.Index(validators, current)+1)%len(validators)]
+	if next == current {
+		return []common.Address{current}, nil
+	}
+	return []common.Address{current, next}, nil
+}
+
 // Prepare implements consensus.Engine, preparing all the consensus fields of the
 // header for running the transactions on top.
 func (p *Parlia) Prepare(chain consensus.ChainHeaderReader, header *types.Header) error {
@@ -1210,6 +1245,11 @@ func (p *Parlia) Prepare(chain consensus.ChainHeaderReader, header *types.Header
 }
 
 func (p *Parlia) verifyValidators(chain consensus.ChainHeaderReader, header *types.Header) error {
+	// Without a backing chain the contracts can't be queried, the validators in
+	// the header are then left to the header verification.
+	if p.ethAPI == nil {
+		return nil
+	}
 	epochLength, err := p.epochLength(chain, header, nil)
 	if err != nil {
 		return err
@@ -1255,6 +1295,9 @@ func (p *Parlia) verifyValidators(chain consensus.ChainHeaderReader, header *typ
 }
 
 func (p *Parlia) verifyTurnLength(chain consensus.ChainHeaderReader, header *types.Header) error {
+	if p.ethAPI == nil {
+		return nil
+	}
 	epochLength, err := p.epochLength(chain, header, nil)
 	if err != nil {
 		return err
@@ -1494,6 +1537,14 @@ func (p *Parlia) Finalize(chain consensus.ChainHeaderReader, header *types.Heade
 		}
 	}
 
+	if err := p.updatePriceOracle(chain, state, header, cx, txs, receipts, systemTxs, usedGas, false, tracer); err != nil {
+		return err
+	}
+	if err := p.updateReserve(state, header, cx, txs, receipts, systemTxs, usedGas, false, tracer); err != nil {
+		return err
+	}
+	p.updateReserveMetrics(state, header)
+
 	if len(*systemTxs) > 0 {
 		return errors.New("the length of systemTxs do not match")
 	}
@@ -1506,6 +1557,8 @@ func (p *Parlia) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *
 	body *types.Body, receipts []*types.Receipt, tracer *tracing.Hooks) (*types.Block, []*types.Receipt, error) {
 	// No block rewards in PoA, so the state remains as is and uncles are dropped
 	cx := chainContext{ChainHeaderReader: chain, parlia: p}
+	// Trace the balance changes made outside of the system transactions as well
+	tracingState := consensus.TracingState(state, tracer)
 
 	if body.Transactions == nil {
 		body.Transactions = make([]*types.Transaction, 0)
@@ -1522,14 +1575,14 @@ func (p *Parlia) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *
 	systemcontracts.TryUpdateBuildInSystemContract(p.chainConfig, header.Number, parent.Time, header.Time, state, false)
 
 	if p.chainConfig.IsOnFeynman(header.Number, parent.Time, header.Time) {
-		err := p.initializeFeynmanContract(state, header, cx, &body.Transactions, &receipts, nil, &header.GasUsed, true, tracer)
+		err := p.initializeFeynmanContract(tracingState, header, cx, &body.Transactions, &receipts, nil, &header.GasUsed, true, tracer)
 		if err != nil {
 			log.Error("init feynman contract failed", "error", err)
 		}
 	}
 
 	if header.Number.Cmp(common.Big1) == 0 {
-		err := p.initContract(state, header, cx, &body.Transactions, &receipts, nil, &header.GasUsed, true, tracer)
+		err := p.initContract(tracingState, header, cx, &body.Transactions, &receipts, nil, &header.GasUsed, true, tracer)
 		if err != nil {
 			log.Error("init contract failed")
 		}
@@ -1553,7 +1606,7 @@ func (p *Parlia) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *
 			}
 		}
 		if !signedRecently {
-			err = p.slash(spoiledVal, state, header, cx, &body.Transactions, &receipts, nil, &header.GasUsed, true, tracer)
+			err = p.slash(spoiledVal, tracingState, header, cx, &body.Transactions, &receipts, nil, &header.GasUsed, true, tracer)
 			if err != nil {
 				// it is possible that slash validator failed because of the slash channel is disabled.
 				log.Error("slash validator failed", "block hash", header.Hash(), "address", spoiledVal)
@@ -1561,13 +1614,13 @@ func (p *Parlia) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *
 		}
 	}
 
-	err := p.distributeIncoming(p.val, state, header, cx, &body.Transactions, &receipts, nil, &header.GasUsed, true, tracer)
+	err := p.distributeIncoming(p.val, tracingState, header, cx, &body.Transactions, &receipts, nil, &header.GasUsed, true, tracer)
 	if err != nil {
 		return nil, nil, err
 	}
 
 	if p.chainConfig.IsPlato(header.Number) {
-		if err := p.distributeFinalityReward(chain, state, header, cx, &body.Transactions, &receipts, nil, &header.GasUsed, true, tracer); err != nil {
+		if err := p.distributeFinalityReward(chain, tracingState, header, cx, &body.Transactions, &receipts, nil, &header.GasUsed, true, tracer); err != nil {
 			return nil, nil, err
 		}
 	}
@@ -1576,12 +1629,19 @@ func (p *Parlia) FinalizeAndAssemble(chain consensus.ChainHeaderReader, header *
 	if p.chainConfig.IsFeynman(header.Number, header.Time) && isBreatheBlock(parent.Time, header.Time) {
 		// we should avoid update validators in the Feynman upgrade block
 		if !p.chainConfig.IsOnFeynman(header.Number, parent.Time, header.Time) {
-			if err := p.updateValidatorSetV2(state, header, cx, &body.Transactions, &receipts, nil, &header.GasUsed, true, tracer); err != nil {
+			if err := p.updateValidatorSetV2(tracingState, header, cx, &body.Transactions, &receipts, nil, &header.GasUsed, true, tracer); err != nil {
 				return nil, nil, err
 			}
 		}
 	}
 
+	if err := p.updatePriceOracle(chain, tracingState, header, cx, &body.Transactions, &receipts, nil, &header.GasUsed, true, tracer); err != nil {
+		return nil, nil, err
+	}
+	if err := p.updateReserve(tracingState, header, cx, &body.Transactions, &receipts, nil, &header.GasUsed, true, tracer); err != nil {
+		return nil, nil, err
+	}
+
 	// should not happen. Once happen, stop the node is better than broadcast the block
 	if header.GasLimit < header.GasUsed {
 		return nil, nil, errors.New("gas consumption of system txs exceed the gas limit")
@@ -2037,6 +2097,11 @@ func (p *Parlia) distributeIncoming(val common.Address, state vm.StateDB, header
 	txs *[]*types.Transaction, receipts *[]*types.Receipt, receivedTxs *[]*types.Transaction, usedGas *uint64, mining bool, tracer *tracing.Hooks) error {
 	coinbase := header.Coinbase
 
+	if p.chainConfig.IsMendel(header.Number, header.Time) {
+		if split := getFeeSplit(state); split != nil {
+			return p.distributeFeeSplit(split, val, state, header, chain, txs, receipts, receivedTxs, usedGas, mining, tracer)
+		}
+	}
 	doDistributeSysReward := !p.chainConfig.IsKepler(header.Number, header.Time) &&
 		state.GetBalance(common.HexToAddress(systemcontracts.SystemRewardContract)).Cmp(maxSystemBalance) < 0
 	if doDistributeSysReward {
@@ -2346,6 +2411,85 @@ func (p *Parlia) CheckFinalityAndNotify(chain consensus.ChainHeaderReader, targe
 	notifyFn(finalizedHeader)
 }
 
+// GetVoteAttestation returns the vote attestation carried in the extra-data of
+// the given header, or nil if the header does not carry one.
+func (p *Parlia) GetVoteAttestation(chain consensus.ChainHeaderReader, header *types.Header) (*types.VoteAttestation, error) {
+	epochLength, err := p.epochLength(chain, header, nil)
+	if err != nil {
+		return nil, err
+	}
+	return getVoteAttestationFromHeader(header, chain.Config(), epochLength)
+}
+
+// GetFinalityAttestation returns the vote attestation which finalized the given
+// header, that is, an attestation with the header as source and its direct child
+// as target. The attestation is looked up in the canonical descendants of the
+// header first. If none of them carries it yet, it is assembled from the votes
+// in the VotePool, which may reach quorum before being included in a block.
+func (p *Parlia) GetFinalityAttestation(chain consensus.ChainHeaderReader, header *types.Header) (*types.VoteAttestation, error) {
+	number := header.Number.Uint64()
+	child := chain.GetHeaderByNumber(number + 1)
+	if child == nil || child.ParentHash != header.Hash() {
+		return nil, errors.New("finalized header has no canonical child")
+	}
+	data := &types.VoteData{
+		SourceNumber: number,
+		SourceHash:   header.Hash(),
+		TargetNumber: number + 1,
+		TargetHash:   child.Hash(),
+	}
+	for n := number + 2; n <= number+1+finalityAttestationSearchDepth; n++ {
+		descendant := chain.GetHeaderByNumber(n)
+		if descendant == nil {
+			break
+		}
+		attestation, err := p.GetVoteAttestation(chain, descendant)
+		if err != nil {
+			return nil, err
+		}
+		if attestation != nil && attestation.Data.Hash() == data.Hash() {
+			return attestation, nil
+		}
+	}
+	if p.VotePool == nil {
+		return nil, errors.New("finality attestation not found")
+	}
+	snap, err := p.snapshot(chain, number, header.Hash(), nil)
+	if err != nil {
+		return nil, err
+	}
+	votes := p.VotePool.FetchVotesByBlockHash(child.Hash(), number)
+	if len(votes) < cmath.CeilDiv(len(snap.Validators)*2, 3) {
+		return nil, errors.New("finality attestation not found")
+	}
+	return newVoteAttestation(data, votes, snap)
+}
+
+// ImportTrustedSnapshot installs an externally obtained snapshot as a trusted
+// starting point for header verification. It is meant for header-only
+// consumers, such as light clients, that bootstrap from a checkpoint rather
+// than replaying the chain from genesis.
+func (p *Parlia) ImportTrustedSnapshot(snap *Snapshot) error {
+	if snap == nil || snap.Hash == (common.Hash{}) || len(snap.Validators) == 0 {
+		return errors.New("invalid trusted snapshot")
+	}
+	cpy := snap.copy()
+	cpy.config = p.config
+	cpy.sigCache = p.signatures
+	cpy.ethAPI = p.ethAPI
+	if cpy.EpochLength == 0 {
+		cpy.EpochLength = defaultEpochLength
+	}
+	if cpy.BlockInterval == 0 {
+		cpy.BlockInterval = defaultBlockInterval
+	}
+	if cpy.TurnLength == 0 {
+		cpy.TurnLength = defaultTurnLength
+	}
+	p.recentSnaps.Add(cpy.Hash, cpy)
+	return cpy.store(p.db)
+}
+
 // ===========================     utility function        ==========================
 func (p *Parlia) backOffTime(snap *Snapshot, parent, header *types.Header, val common.Address) uint64 {
 	if snap.inturn(val) {
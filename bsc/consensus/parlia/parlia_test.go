@@ -725,6 +725,147 @@ func TestParlia_applyTransactionTracing(t *testing.T) {
 	}
 }
 
+func TestParlia_distributeIncomingFeeSplit(t *testing.T) {
+	var (
+		treasury = common.HexToAddress("0x7eA5")
+		yield    = common.HexToAddress("0x1e1d")
+		income   = new(uint256.Int).Add(uint256.NewInt(params.Ether), uint256.NewInt(7))
+		portion  = func(share uint64) *uint256.Int {
+			return new(uint256.Int).Div(new(uint256.Int).Mul(income, uint256.NewInt(share)), uint256.NewInt(feeSplitDenominator))
+		}
+	)
+	// Shares in basis points: validators 5000, system reward 1000, treasury 1500, yield 2500.
+	var shares common.Hash
+	shares[30], shares[31] = 0x13, 0x88
+	shares[28], shares[29] = 0x03, 0xe8
+	shares[26], shares[27] = 0x05, 0xdc
+	shares[24], shares[25] = 0x09, 0xc4
+
+	tests := []struct {
+		name      string
+		mendel    bool
+		shares    common.Hash
+		yieldAddr common.Address
+		treasury  *uint256.Int
+		yield     *uint256.Int
+		system    *uint256.Int
+		validator *uint256.Int
+		txs       int
+	}{
+		{
+			name:      "split before fork",
+			mendel:    false,
+			shares:    shares,
+			treasury:  uint256.NewInt(0),
+			yield:     uint256.NewInt(0),
+			system:    uint256.NewInt(0),
+			validator: income,
+			txs:       1,
+		},
+		{
+			name:      "no split after fork",
+			mendel:    true,
+			shares:    common.Hash{},
+			treasury:  uint256.NewInt(0),
+			yield:     uint256.NewInt(0),
+			system:    uint256.NewInt(0),
+			validator: income,
+			txs:       1,
+		},
+		{
+			name:      "split after fork",
+			mendel:    true,
+			shares:    shares,
+			treasury:  portion(1500),
+			yield:     portion(2500),
+			system:    portion(1000),
+			validator: new(uint256.Int).Sub(income, new(uint256.Int).Add(portion(1500), new(uint256.Int).Add(portion(2500), portion(1000)))),
+			txs:       2,
+		},
+		{
+			name:      "split to yield index after fork",
+			mendel:    true,
+			shares:    shares,
+			yieldAddr: params.YieldIndexAddress,
+			treasury:  portion(1500),
+			yield:     portion(2500),
+			system:    portion(1000),
+			validator: new(uint256.Int).Sub(income, new(uint256.Int).Add(portion(1500), new(uint256.Int).Add(portion(2500), portion(1000)))),
+			txs:       2,
+		},
+		{
+			name:      "invalid split after fork",
+			mendel:    true,
+			shares:    common.Hash{31: 0x01},
+			treasury:  uint256.NewInt(0),
+			yield:     uint256.NewInt(0),
+			system:    uint256.NewInt(0),
+			validator: income,
+			txs:       1,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			config := *params.ParliaTestChainConfig
+			if tt.mendel {
+				config.MendelTime = new(uint64)
+			}
+			yieldAddr := yield
+			if tt.yieldAddr != (common.Address{}) {
+				yieldAddr = tt.yieldAddr
+			}
+			engine := New(&config, rawdb.NewMemoryDatabase(), nil, common.Hash{})
+			engine.Authorize(testAddr, nil, func(_ accounts.Account, tx *types.Transaction, _ *big.Int) (*types.Transaction, error) {
+				return tx, nil
+			})
+			statedb, err := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+			if err != nil {
+				t.Fatalf("failed to create stateDB: %v", err)
+			}
+			statedb.SetBalance(consensus.SystemAddress, income.Clone(), tracing.BalanceChangeUnspecified)
+			statedb.SetState(feeSplitContract, feeSplitSharesSlot, tt.shares)
+			statedb.SetState(feeSplitContract, feeSplitTreasurySlot, common.BytesToHash(treasury.Bytes()))
+			statedb.SetState(feeSplitContract, feeSplitYieldSlot, common.BytesToHash(yieldAddr.Bytes()))
+
+			header := &types.Header{
+				Number:     big.NewInt(1),
+				Coinbase:   testAddr,
+				Difficulty: diffInTurn,
+				GasLimit:   params.GenesisGasLimit,
+				BaseFee:    new(big.Int),
+			}
+			var (
+				txs      []*types.Transaction
+				receipts []*types.Receipt
+				usedGas  uint64
+				cx       = chainContext{parlia: engine}
+			)
+			if err := engine.distributeIncoming(testAddr, statedb, header, cx, &txs, &receipts, nil, &usedGas, true, nil); err != nil {
+				t.Fatalf("failed to distribute incoming: %v", err)
+			}
+			if len(txs) != tt.txs {
+				t.Errorf("system transaction count mismatch: have %d, want %d", len(txs), tt.txs)
+			}
+			for _, check := range []struct {
+				name string
+				addr common.Address
+				want *uint256.Int
+			}{
+				{"system address", consensus.SystemAddress, uint256.NewInt(0)},
+				{"coinbase", testAddr, uint256.NewInt(0)},
+				{"treasury", treasury, tt.treasury},
+				{"yield distributor", yieldAddr, tt.yield},
+				{"system reward", common.HexToAddress(systemcontracts.SystemRewardContract), tt.system},
+				{"validator contract", common.HexToAddress(systemcontracts.ValidatorContract), tt.validator},
+			} {
+				if have := statedb.GetBalance(check.addr); !have.Eq(check.want) {
+					t.Errorf("%s balance mismatch: have %v, want %v", check.name, have, check.want)
+				}
+			}
+		})
+	}
+}
+
 func formatRecords(records []string) string {
 	indented := make([]string, 0, len(records))
 	for _, record := range records {
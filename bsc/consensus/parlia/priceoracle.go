@@ -0,0 +1,197 @@
+package parlia
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	cmath "github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+var (
+	priceOracleMedianSlot = common.BigToHash(big.NewInt(params.PriceOracleMedianSlot))
+	priceOracleRoundSlot  = common.BigToHash(big.NewInt(params.PriceOracleRoundSlot))
+	priceOracleNumberSlot = common.BigToHash(big.NewInt(params.PriceOracleNumberSlot))
+	priceOracleTimeSlot   = common.BigToHash(big.NewInt(params.PriceOracleTimeSlot))
+)
+
+// priceOracleRound returns the price oracle round a block number belongs to.
+func priceOracleRound(number uint64) uint64 {
+	return number / params.PriceOracleInterval
+}
+
+// isPriceOracleRoundPending reports whether no attestation of the given round,
+// nor of any later one, has been included yet.
+func isPriceOracleRoundPending(state vm.StateDB, round uint64) bool {
+	if state.GetState(params.PriceOracleAddress, priceOracleNumberSlot) == (common.Hash{}) {
+		return true
+	}
+	return state.GetState(params.PriceOracleAddress, priceOracleRoundSlot).Big().Uint64() < round
+}
+
+// VerifyPriceReport checks that the report is signed by an active validator for
+// the pending or the upcoming price oracle round.
+func (p *Parlia) VerifyPriceReport(chain consensus.ChainHeaderReader, report *types.PriceReport) error {
+	head := chain.CurrentHeader()
+	if head == nil {
+		return errors.New("current header is nil")
+	}
+	next := new(big.Int).Add(head.Number, common.Big1)
+	if !p.chainConfig.IsMendel(next, head.Time) {
+		return errors.New("price oracle not activated")
+	}
+	round := priceOracleRound(next.Uint64())
+	if report.Round != round && report.Round != round+1 {
+		return fmt.Errorf("price report of round %d out of range, current round %d", report.Round, round)
+	}
+	snap, err := p.snapshot(chain, head.Number.Uint64(), head.Hash(), nil)
+	if err != nil {
+		return err
+	}
+	if !slices.Contains(snap.voteAddresses(), report.VoteAddress) {
+		return errors.New("price report of unknown validator")
+	}
+	return report.Verify(p.chainConfig.ChainID)
+}
+
+// voteAddresses returns the vote addresses of the validators in the snapshot.
+func (s *Snapshot) voteAddresses() []types.BLSPublicKey {
+	addrs := make([]types.BLSPublicKey, 0, len(s.Validators))
+	for _, validator := range s.Validators {
+		if validator != nil {
+			addrs = append(addrs, validator.VoteAddress)
+		}
+	}
+	return addrs
+}
+
+// assemblePriceAttestation collects the pooled reports of the validators for
+// the round, returning nil if they don't reach the quorum.
+func (p *Parlia) assemblePriceAttestation(snap *Snapshot, round uint64) *types.PriceAttestation {
+	var (
+		voteAddrs = snap.voteAddresses()
+		att       = &types.PriceAttestation{Round: round}
+	)
+	for _, report := range p.PriceOracle.PriceReports(round) {
+		if report.Round == round && slices.Contains(voteAddrs, report.VoteAddress) {
+			att.Reports = append(att.Reports, report)
+		}
+	}
+	slices.SortFunc(att.Reports, func(a, b *types.PriceReport) int {
+		return bytes.Compare(a.VoteAddress[:], b.VoteAddress[:])
+	})
+	att.Reports = slices.CompactFunc(att.Reports, func(a, b *types.PriceReport) bool {
+		return a.VoteAddress == b.VoteAddress
+	})
+	if len(att.Reports) < cmath.CeilDiv(len(snap.Validators)*2, 3) {
+		return nil
+	}
+	return att
+}
+
+// verifyPriceAttestation checks that the attestation holds valid reports of a
+// quorum of distinct validators for the round on the given chain.
+func verifyPriceAttestation(snap *Snapshot, att *types.PriceAttestation, round uint64, chainID *big.Int) error {
+	if att.Round != round {
+		return fmt.Errorf("price attestation of round %d, want %d", att.Round, round)
+	}
+	if quorum := cmath.CeilDiv(len(snap.Validators)*2, 3); len(att.Reports) < quorum {
+		return fmt.Errorf("price attestation without quorum, have %d reports, want %d", len(att.Reports), quorum)
+	}
+	voteAddrs := snap.voteAddresses()
+	for i, report := range att.Reports {
+		if report.Round != round {
+			return fmt.Errorf("price report of round %d, want %d", report.Round, round)
+		}
+		if i > 0 && bytes.Compare(att.Reports[i-1].VoteAddress[:], report.VoteAddress[:]) >= 0 {
+			return errors.New("price reports not sorted by vote address")
+		}
+		if !slices.Contains(voteAddrs, report.VoteAddress) {
+			return errors.New("price report of unknown validator")
+		}
+		if err := report.Verify(chainID); err != nil {
+			return fmt.Errorf("invalid price report: %v", err)
+		}
+	}
+	return nil
+}
+
+// updatePriceOracle includes the reports of the validators for the round of the
+// block through a system transaction to the price oracle, which then keeps
+// their median. The proposer only includes a round once and when a quorum of
+// the validators reported it, so blocks without an attestation are valid.
+func (p *Parlia) updatePriceOracle(chain consensus.ChainHeaderReader, state vm.StateDB, header *types.Header, cx core.ChainContext,
+	txs *[]*types.Transaction, receipts *[]*types.Receipt, receivedTxs *[]*types.Transaction, usedGas *uint64, mining bool, tracer *tracing.Hooks) error {
+	if !p.chainConfig.IsMendel(header.Number, header.Time) {
+		return nil
+	}
+	round := priceOracleRound(header.Number.Uint64())
+	if !isPriceOracleRoundPending(state, round) {
+		return nil
+	}
+	var att *types.PriceAttestation
+	if mining {
+		if p.PriceOracle == nil {
+			return nil
+		}
+		snap, err := p.snapshot(chain, header.Number.Uint64()-1, header.ParentHash, nil)
+		if err != nil {
+			return err
+		}
+		if att = p.assemblePriceAttestation(snap, round); att == nil {
+			return nil
+		}
+		if err := verifyPriceAttestation(snap, att, round, p.chainConfig.ChainID); err != nil {
+			log.Warn("Skipping invalid price attestation", "round", round, "err", err)
+			return nil
+		}
+	} else {
+		if receivedTxs == nil || len(*receivedTxs) == 0 {
+			return nil
+		}
+		if to := (*receivedTxs)[0].To(); to == nil || *to != params.PriceOracleAddress {
+			return nil
+		}
+		att = new(types.PriceAttestation)
+		if err := rlp.DecodeBytes((*receivedTxs)[0].Data(), att); err != nil {
+			return fmt.Errorf("invalid price attestation: %v", err)
+		}
+		snap, err := p.snapshot(chain, header.Number.Uint64()-1, header.ParentHash, nil)
+		if err != nil {
+			return err
+		}
+		if err := verifyPriceAttestation(snap, att, round, p.chainConfig.ChainID); err != nil {
+			return err
+		}
+	}
+	data, err := rlp.EncodeToBytes(att)
+	if err != nil {
+		return err
+	}
+	msg := p.getSystemMessage(header.Coinbase, params.PriceOracleAddress, data, common.Big0)
+	if err := p.applyTransaction(msg, state, header, cx, txs, receipts, receivedTxs, usedGas, mining, tracer); err != nil {
+		return err
+	}
+	median := att.Median()
+	if state.GetNonce(params.PriceOracleAddress) == 0 {
+		// Keep the oracle alive, it has neither code nor balance
+		state.SetNonce(params.PriceOracleAddress, 1, tracing.NonceChangeUnspecified)
+	}
+	state.SetState(params.PriceOracleAddress, priceOracleMedianSlot, common.BigToHash(median))
+	state.SetState(params.PriceOracleAddress, priceOracleRoundSlot, common.BigToHash(new(big.Int).SetUint64(round)))
+	state.SetState(params.PriceOracleAddress, priceOracleNumberSlot, common.BigToHash(header.Number))
+	state.SetState(params.PriceOracleAddress, priceOracleTimeSlot, common.BigToHash(new(big.Int).SetUint64(header.Time)))
+	log.Debug("Updated price oracle", "number", header.Number, "round", round, "reports", len(att.Reports), "median", median)
+	return nil
+}
@@ -0,0 +1,124 @@
+package parlia
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+	blscommon "github.com/prysmaticlabs/prysm/v5/crypto/bls/common"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+type testPriceOracle []*types.PriceReport
+
+func (o testPriceOracle) PriceReports(round uint64) []*types.PriceReport { return o }
+
+var testPriceChainID = big.NewInt(56)
+
+func signPriceReport(key blscommon.SecretKey, round uint64, price int64) *types.PriceReport {
+	return signChainPriceReport(key, testPriceChainID, round, price)
+}
+
+func signChainPriceReport(key blscommon.SecretKey, chainID *big.Int, round uint64, price int64) *types.PriceReport {
+	report := &types.PriceReport{Round: round, Price: big.NewInt(price)}
+	hash := report.SigningHash(chainID)
+	copy(report.VoteAddress[:], key.PublicKey().Marshal())
+	copy(report.Signature[:], key.Sign(hash[:]).Marshal())
+	return report
+}
+
+func TestPriceAttestation(t *testing.T) {
+	var (
+		keys []blscommon.SecretKey
+		snap = &Snapshot{Validators: make(map[common.Address]*ValidatorInfo)}
+	)
+	for i := 0; i < 4; i++ {
+		key, err := bls.RandKey()
+		if err != nil {
+			t.Fatalf("failed to create BLS key: %v", err)
+		}
+		keys = append(keys, key)
+		snap.Validators[common.BytesToAddress([]byte{byte(i + 1)})] = &ValidatorInfo{
+			Index:       i + 1,
+			VoteAddress: types.BLSPublicKey(key.PublicKey().Marshal()),
+		}
+	}
+	outsider, _ := bls.RandKey()
+
+	// Two reports don't reach the quorum of three validators
+	p := &Parlia{PriceOracle: testPriceOracle{signPriceReport(keys[0], 5, 100), signPriceReport(keys[1], 5, 300)}}
+	if att := p.assemblePriceAttestation(snap, 5); att != nil {
+		t.Fatal("attestation assembled without quorum")
+	}
+	// Reports of other rounds, outsiders and duplicates are left out
+	p.PriceOracle = testPriceOracle{
+		signPriceReport(keys[0], 5, 100), signPriceReport(keys[1], 5, 300), signPriceReport(keys[3], 5, 200),
+		signPriceReport(keys[2], 4, 100), signPriceReport(outsider, 5, 100), signPriceReport(keys[3], 5, 200),
+	}
+	att := p.assemblePriceAttestation(snap, 5)
+	if att == nil || len(att.Reports) != 3 {
+		t.Fatalf("assembled attestation mismatch: %v", att)
+	}
+	if err := verifyPriceAttestation(snap, att, 5, testPriceChainID); err != nil {
+		t.Fatalf("failed to verify attestation: %v", err)
+	}
+	if median := att.Median(); median.Int64() != 200 {
+		t.Fatalf("median mismatch: have %v, want 200", median)
+	}
+	att.Reports = append(att.Reports, signPriceReport(keys[2], 5, 400))
+	if median := att.Median(); median.Int64() != 200 {
+		t.Fatalf("median of even reports mismatch: have %v, want 200", median)
+	}
+
+	// Invalid attestations are rejected
+	valid := p.assemblePriceAttestation(snap, 5)
+	tests := []struct {
+		name   string
+		modify func(att *types.PriceAttestation)
+	}{
+		{"wrong round", func(att *types.PriceAttestation) { att.Round = 6 }},
+		{"no quorum", func(att *types.PriceAttestation) { att.Reports = att.Reports[:2] }},
+		{"unsorted", func(att *types.PriceAttestation) { att.Reports[0], att.Reports[1] = att.Reports[1], att.Reports[0] }},
+		{"duplicate", func(att *types.PriceAttestation) { att.Reports[1] = att.Reports[0] }},
+		{"report round", func(att *types.PriceAttestation) { att.Reports[0] = signPriceReport(keys[0], 4, 100) }},
+		{"outsider", func(att *types.PriceAttestation) {
+			att.Reports = append(att.Reports, signPriceReport(outsider, 5, 100))
+		}},
+		{"signature", func(att *types.PriceAttestation) { att.Reports[2].Signature[10] ^= 1 }},
+		{"other chain", func(att *types.PriceAttestation) {
+			att.Reports[0] = signChainPriceReport(keys[0], big.NewInt(97), 5, att.Reports[0].Price.Int64())
+		}},
+	}
+	for _, tt := range tests {
+		att := &types.PriceAttestation{Round: valid.Round}
+		for _, report := range valid.Reports {
+			att.Reports = append(att.Reports, &types.PriceReport{Round: report.Round, Price: report.Price, VoteAddress: report.VoteAddress, Signature: report.Signature})
+		}
+		tt.modify(att)
+		if err := verifyPriceAttestation(snap, att, 5, testPriceChainID); err == nil {
+			t.Errorf("%s: invalid attestation accepted", tt.name)
+		}
+	}
+}
+
+func TestPriceOracleRoundPending(t *testing.T) {
+	statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+	if !isPriceOracleRoundPending(statedb, 0) {
+		t.Fatal("first round not pending")
+	}
+	statedb.SetState(params.PriceOracleAddress, priceOracleRoundSlot, common.BigToHash(big.NewInt(3)))
+	statedb.SetState(params.PriceOracleAddress, priceOracleNumberSlot, common.BigToHash(big.NewInt(600)))
+	if isPriceOracleRoundPending(statedb, 3) {
+		t.Fatal("included round pending")
+	}
+	if !isPriceOracleRoundPending(statedb, 4) {
+		t.Fatal("next round not pending")
+	}
+	if priceOracleRound(params.PriceOracleInterval*4-1) != 3 || priceOracleRound(params.PriceOracleInterval*4) != 4 {
+		t.Fatal("round boundaries mismatch")
+	}
+}
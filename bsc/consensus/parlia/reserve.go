@@ -0,0 +1,146 @@
+package parlia
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+var (
+	mintUtilisationGauge = metrics.NewRegisteredGaugeFloat64("parlia/reserve/mint/utilisation", nil)
+	mintPauseGauge       = metrics.NewRegisteredGauge("parlia/reserve/mint/pause", nil)
+	collateralGauge      = metrics.NewRegisteredGaugeFloat64("parlia/reserve/collateral", nil)
+)
+
+// isToMendelSystemAccount reports whether to is one of the accounts updated by
+// system transactions from Mendel on, which are not genesis system contracts.
+func (p *Parlia) isToMendelSystemAccount(to common.Address, header *types.Header) bool {
+	if to != params.PriceOracleAddress && to != systemcontracts.ReserveRegistryAddr {
+		return false
+	}
+	return p.chainConfig.IsMendel(header.Number, header.Time)
+}
+
+// SubmitReserveAttestation queues a reserve attestation to be included when
+// the local validator proposes a block. Only the signatures are checked here,
+// whether the signers are auditors is checked against the state on inclusion.
+func (p *Parlia) SubmitReserveAttestation(att *types.ReserveAttestation) error {
+	if err := att.VerifySignatures(); err != nil {
+		return err
+	}
+	p.reserveLock.Lock()
+	defer p.reserveLock.Unlock()
+
+	if p.pendingReserve != nil && p.pendingReserve.Nonce >= att.Nonce {
+		return fmt.Errorf("reserve attestation with nonce %d already pending", p.pendingReserve.Nonce)
+	}
+	p.pendingReserve = att
+	return nil
+}
+
+// pendingReserveAttestation returns the reserve attestation queued for
+// inclusion, if any.
+func (p *Parlia) pendingReserveAttestation() *types.ReserveAttestation {
+	p.reserveLock.Lock()
+	defer p.reserveLock.Unlock()
+
+	return p.pendingReserve
+}
+
+// dropReserveAttestation removes the attestation from the queue unless it has
+// been replaced in the meantime.
+func (p *Parlia) dropReserveAttestation(att *types.ReserveAttestation) {
+	p.reserveLock.Lock()
+	defer p.reserveLock.Unlock()
+
+	if p.pendingReserve == att {
+		p.pendingReserve = nil
+	}
+}
+
+// updateReserve includes the pending reserve attestation through a system
+// transaction to the reserve registry contract and records it as the last
+// attested reserve, which limits the supply of the reserve backed token.
+// Attestations are included as they are submitted, so blocks without one are
+// valid.
+func (p *Parlia) updateReserve(state vm.StateDB, header *types.Header, cx core.ChainContext,
+	txs *[]*types.Transaction, receipts *[]*types.Receipt, receivedTxs *[]*types.Transaction, usedGas *uint64, mining bool, tracer *tracing.Hooks) error {
+	if !p.chainConfig.IsMendel(header.Number, header.Time) {
+		return nil
+	}
+	var att *types.ReserveAttestation
+	if mining {
+		if att = p.pendingReserveAttestation(); att == nil {
+			return nil
+		}
+		if err := systemcontracts.VerifyReserveAttestation(state, att); err != nil {
+			log.Warn("Dropping invalid reserve attestation", "nonce", att.Nonce, "err", err)
+			p.dropReserveAttestation(att)
+			return nil
+		}
+	} else {
+		if receivedTxs == nil || len(*receivedTxs) == 0 {
+			return nil
+		}
+		if to := (*receivedTxs)[0].To(); to == nil || *to != systemcontracts.ReserveRegistryAddr {
+			return nil
+		}
+		var err error
+		if att, err = systemcontracts.UnpackReserveAttestation((*receivedTxs)[0].Data()); err != nil {
+			return fmt.Errorf("invalid reserve attestation: %v", err)
+		}
+		if err := systemcontracts.VerifyReserveAttestation(state, att); err != nil {
+			return err
+		}
+	}
+	data, err := systemcontracts.PackReserveAttestation(att)
+	if err != nil {
+		return err
+	}
+	msg := p.getSystemMessage(header.Coinbase, systemcontracts.ReserveRegistryAddr, data, common.Big0)
+	if err := p.applyTransaction(msg, state, header, cx, txs, receipts, receivedTxs, usedGas, mining, tracer); err != nil {
+		return err
+	}
+	systemcontracts.WriteReserveAttestation(state, att, header.Number)
+	log.Debug("Attested reserve", "number", header.Number, "nonce", att.Nonce, "source", att.SourceBlock, "total", att.Total())
+	return nil
+}
+
+// updateReserveMetrics reports the share of the mint window limit used, the
+// pause reason and the ratio of the attested reserve to the supply of the
+// reserve backed token after the block.
+func (p *Parlia) updateReserveMetrics(state vm.StateDB, header *types.Header) {
+	if !metrics.Enabled() || !p.chainConfig.IsMendel(header.Number, header.Time) {
+		return
+	}
+	token, supplySlot := systemcontracts.ReserveToken(state)
+	if token == (common.Address{}) {
+		return
+	}
+	limit := systemcontracts.GetMintLimit(state)
+	if limit.Window != 0 && limit.Limit.Sign() > 0 {
+		mintUtilisationGauge.Update(ratio(limit.Used(header.Time), limit.Limit))
+	}
+	mintPauseGauge.Update(int64(limit.Pause))
+
+	if reserve := systemcontracts.GetReserve(state); reserve != nil {
+		if supply := state.GetState(token, supplySlot).Big(); supply.Sign() > 0 {
+			collateralGauge.Update(ratio(reserve.Total, supply))
+		}
+	}
+}
+
+// ratio returns x/y as a float.
+func ratio(x, y *big.Int) float64 {
+	r, _ := new(big.Rat).SetFrac(x, y).Float64()
+	return r
+}
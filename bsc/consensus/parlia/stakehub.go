@@ -108,6 +108,53 @@ func (p *Parlia) getNodeIDsForValidators(validatorsToQuery []common.Address) (ma
 	return addressToNodeIDs, nil
 }
 
+// getOperatorAddress retrieves the operator address of the validator with the
+// given consensus address
+func (p *Parlia) getOperatorAddress(consensusAddr common.Address) (common.Address, error) {
+	// Create the call data for consensusToOperator
+	data, err := p.stakeHubABI.Pack("consensusToOperator", consensusAddr)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to pack consensusToOperator: %v", err)
+	}
+
+	// Make the call
+	blockNr := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	msgData := (hexutil.Bytes)(data)
+	toAddress := common.HexToAddress(systemcontracts.StakeHubContract)
+	gas := (hexutil.Uint64)(uint64(math.MaxUint64 / 2))
+
+	result, err := p.ethAPI.Call(context.Background(), ethapi.TransactionArgs{
+		Gas:  &gas,
+		To:   &toAddress,
+		Data: &msgData,
+	}, &blockNr, nil, nil)
+	if err != nil {
+		return common.Address{}, fmt.Errorf("failed to call consensusToOperator: %v", err)
+	}
+
+	// Unpack the result
+	var operatorAddr common.Address
+	if err := p.stakeHubABI.UnpackIntoInterface(&operatorAddr, "consensusToOperator", result); err != nil {
+		return common.Address{}, fmt.Errorf("failed to unpack consensusToOperator result: %v", err)
+	}
+	return operatorAddr, nil
+}
+
+// GetNodeIDsForValidators returns a map of the given consensus addresses to
+// the node IDs registered by their validators
+func (p *Parlia) GetNodeIDsForValidators(consensusAddrs []common.Address) (map[common.Address][]enode.ID, error) {
+	operatorAddrs := make([]common.Address, 0, len(consensusAddrs))
+	for _, consensusAddr := range consensusAddrs {
+		operatorAddr, err := p.getOperatorAddress(consensusAddr)
+		if err != nil {
+			log.Debug("Failed to get operator address", "validator", consensusAddr, "error", err)
+			return nil, err
+		}
+		operatorAddrs = append(operatorAddrs, operatorAddr)
+	}
+	return p.getNodeIDsForValidators(operatorAddrs)
+}
+
 // GetNodeIDs returns a flattened array of all node IDs for current validators
 func (p *Parlia) GetNodeIDs() ([]enode.ID, error) {
 	// Call GetValidators with latest block number
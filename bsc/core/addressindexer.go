@@ -0,0 +1,356 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	// transferTopic is the topic of the token transfer events.
+	// keccak256("Transfer(address,address,uint256)")
+	transferTopic = common.HexToHash("ddf252ad1be2c89b69c2b068fc378daa952ba7f163c4a11628f55a4df523b3ef")
+
+	// errAddressIndexDisabled is returned if the transactions of an address are
+	// requested while the address index is disabled.
+	errAddressIndexDisabled = errors.New("address index is disabled")
+)
+
+// addressIndexEntry is an entry of the address index, referring to a transaction
+// of a block.
+type addressIndexEntry struct {
+	address  common.Address
+	token    common.Address // Transferred token, zero for the native coin
+	transfer bool           // Whether the entry is a transfer of the token
+	index    uint32         // Index of the transaction within the block
+}
+
+// addressIndexer is the module responsible for maintaining the index of the
+// transactions by the addresses they involve: the sender, the recipient, the
+// created contract and the parties of the token and native coin transfers.
+//
+// The blocks are indexed in the background as the chain progresses, within the
+// same range as the transaction indexes.
+type addressIndexer struct {
+	// limit is the maximum number of blocks from head whose transactions
+	// are indexed, 0 meaning the entire chain.
+	limit uint64
+
+	// internal denotes whether the native coin transfers made by internal
+	// calls are indexed, requiring them to be recorded during import.
+	internal bool
+
+	chain  *BlockChain
+	db     ethdb.Database
+	term   chan chan struct{}
+	closed chan struct{}
+}
+
+// newAddressIndexer initializes the address indexer.
+func newAddressIndexer(limit uint64, internal bool, chain *BlockChain) *addressIndexer {
+	indexer := &addressIndexer{
+		limit:    limit,
+		internal: internal,
+		chain:    chain,
+		db:       chain.db,
+		term:     make(chan chan struct{}),
+		closed:   make(chan struct{}),
+	}
+	go indexer.loop()
+
+	var msg string
+	if limit == 0 {
+		msg = "entire chain"
+	} else {
+		msg = fmt.Sprintf("last %d blocks", limit)
+	}
+	log.Info("Initialized address indexer", "range", msg, "internal", internal)
+	return indexer
+}
+
+// run brings the address index in line with the given chain head. The blocks
+// no longer canonical are unindexed first, followed by indexing the new blocks,
+// backfilling the older ones and pruning the ones out of the configured range.
+// If the stop channel is closed, the task terminates as soon as possible. The
+// done channel will be closed once the task is complete.
+func (indexer *addressIndexer) run(head *types.Header, stop chan struct{}, done chan struct{}) {
+	defer close(done)
+
+	// Determine the first block to index, taking the configured cutoff point
+	// into account.
+	var (
+		number    = head.Number.Uint64()
+		cutoff, _ = indexer.chain.HistoryPruningCutoff()
+		from      = cutoff
+	)
+	if indexer.limit != 0 && number >= indexer.limit {
+		from = max(from, number-indexer.limit+1)
+	}
+	if number < from {
+		return
+	}
+	// Unwind the indexed blocks which are no longer canonical. The block bodies
+	// of the side chains are kept, otherwise the whole index is rebuilt.
+	r := rawdb.ReadAddressIndexRange(indexer.db)
+	for r != nil && r.Head >= r.Tail && (r.Head > number || rawdb.ReadCanonicalHash(indexer.db, r.Head) != r.HeadHash) {
+		block := rawdb.ReadBlock(indexer.db, r.HeadHash, r.Head)
+		if block == nil {
+			log.Warn("Purge address indexes", "number", r.Head, "hash", r.HeadHash)
+			rawdb.DeleteAddressIndexRange(indexer.db)
+			rawdb.DeleteAllAddressIndexes(indexer.db, nil)
+			r = nil
+			break
+		}
+		batch := indexer.db.NewBatch()
+		indexer.unindexBlock(batch, block)
+		r.Head, r.HeadHash = r.Head-1, block.ParentHash()
+		rawdb.WriteAddressIndexRange(batch, *r)
+		if err := batch.Write(); err != nil {
+			log.Crit("Failed to unindex addresses", "err", err)
+		}
+		if stopped(stop) {
+			return
+		}
+	}
+	// Start afresh if nothing is indexed, or if the indexes are entirely out
+	// of the configured range.
+	if r != nil && r.Head >= r.Tail && r.Head < from {
+		log.Warn("Purge stale address indexes", "tail", r.Tail, "head", r.Head, "from", from)
+		rawdb.DeleteAddressIndexRange(indexer.db)
+		rawdb.DeleteAllAddressIndexes(indexer.db, nil)
+		r = nil
+	}
+	if r == nil || r.Head < r.Tail {
+		r = &rawdb.AddressIndexRange{Tail: number + 1, Head: number, HeadHash: head.Hash()}
+	}
+	var (
+		batch = indexer.db.NewBatch()
+		flush = func(force bool) {
+			if !force && batch.ValueSize() < ethdb.IdealBatchSize {
+				return
+			}
+			rawdb.WriteAddressIndexRange(batch, *r)
+			if err := batch.Write(); err != nil {
+				log.Crit("Failed to index addresses", "err", err)
+			}
+			batch.Reset()
+		}
+	)
+	defer flush(true)
+
+	// Index the blocks imported since the last run
+	for n := r.Head + 1; n <= number; n++ {
+		hash := rawdb.ReadCanonicalHash(indexer.db, n)
+		block := rawdb.ReadBlock(indexer.db, hash, n)
+		if block == nil || block.ParentHash() != r.HeadHash {
+			// The chain has been reorganised meanwhile, resume on the next head
+			return
+		}
+		indexer.indexBlock(batch, block)
+		r.Head, r.HeadHash = n, hash
+		flush(false)
+		if stopped(stop) {
+			return
+		}
+	}
+	// Backfill the older blocks within the configured range
+	for r.Tail > from {
+		n := r.Tail - 1
+		block := rawdb.ReadBlock(indexer.db, rawdb.ReadCanonicalHash(indexer.db, n), n)
+		if block == nil {
+			log.Warn("Address indexing stopped at unavailable block", "number", n)
+			break
+		}
+		indexer.indexBlock(batch, block)
+		r.Tail = n
+		flush(false)
+		if stopped(stop) {
+			return
+		}
+	}
+	// Prune the blocks out of the configured range. If the pruned block isn't
+	// available anymore, its entries are found by traversing the index.
+	for r.Tail < from {
+		block := rawdb.ReadBlock(indexer.db, rawdb.ReadCanonicalHash(indexer.db, r.Tail), r.Tail)
+		if block == nil {
+			r.Tail = from
+			flush(true)
+			rawdb.DeleteAllAddressIndexes(indexer.db, func(number uint64) bool { return number < from })
+			break
+		}
+		indexer.unindexBlock(batch, block)
+		r.Tail++
+		flush(false)
+		if stopped(stop) {
+			return
+		}
+	}
+}
+
+// stopped reports whether the given stop channel has been closed.
+func stopped(stop chan struct{}) bool {
+	select {
+	case <-stop:
+		return true
+	default:
+		return false
+	}
+}
+
+// indexBlock writes the address index entries of the given block.
+func (indexer *addressIndexer) indexBlock(db ethdb.KeyValueWriter, block *types.Block) {
+	txs := block.Transactions()
+	for entry := range indexer.blockEntries(block) {
+		if entry.transfer {
+			rawdb.WriteAddressTransfer(db, entry.address, entry.token, block.NumberU64(), entry.index, txs[entry.index].Hash())
+		} else {
+			rawdb.WriteAddressTx(db, entry.address, block.NumberU64(), entry.index, txs[entry.index].Hash())
+		}
+	}
+}
+
+// unindexBlock removes the address index entries of the given block.
+func (indexer *addressIndexer) unindexBlock(db ethdb.KeyValueWriter, block *types.Block) {
+	for entry := range indexer.blockEntries(block) {
+		if entry.transfer {
+			rawdb.DeleteAddressTransfer(db, entry.address, entry.token, block.NumberU64(), entry.index)
+		} else {
+			rawdb.DeleteAddressTx(db, entry.address, block.NumberU64(), entry.index)
+		}
+	}
+}
+
+// blockEntries collects the address index entries of the given block from its
+// transactions, receipts and, if enabled, the recorded native coin transfers.
+func (indexer *addressIndexer) blockEntries(block *types.Block) map[addressIndexEntry]struct{} {
+	var (
+		config  = indexer.chain.Config()
+		signer  = types.MakeSigner(config, block.Number(), block.Time())
+		txs     = block.Transactions()
+		entries = make(map[addressIndexEntry]struct{})
+		add     = func(index int, address common.Address, token *common.Address) {
+			entries[addressIndexEntry{address: address, index: uint32(index)}] = struct{}{}
+			if token != nil {
+				entries[addressIndexEntry{address: address, token: *token, transfer: true, index: uint32(index)}] = struct{}{}
+			}
+		}
+		native = common.Address{}
+	)
+	for i, tx := range txs {
+		var token *common.Address
+		if tx.Value().Sign() > 0 {
+			token = &native
+		}
+		if from, err := types.Sender(signer, tx); err == nil {
+			add(i, from, token)
+		}
+		if to := tx.To(); to != nil {
+			add(i, *to, token)
+		}
+	}
+	receipts := rawdb.ReadReceipts(indexer.db, block.Hash(), block.NumberU64(), block.Time(), config)
+	if len(receipts) == len(txs) {
+		for i, receipt := range receipts {
+			if receipt.ContractAddress != (common.Address{}) {
+				add(i, receipt.ContractAddress, nil)
+			}
+			for _, l := range receipt.Logs {
+				if len(l.Topics) < 3 || l.Topics[0] != transferTopic {
+					continue
+				}
+				token := l.Address
+				add(i, common.BytesToAddress(l.Topics[1].Bytes()), &token)
+				add(i, common.BytesToAddress(l.Topics[2].Bytes()), &token)
+			}
+		}
+	}
+	if indexer.internal {
+		indexes := make(map[common.Hash]int, len(txs))
+		for i, tx := range txs {
+			indexes[tx.Hash()] = i
+		}
+		for _, transfer := range rawdb.ReadNativeTransfers(indexer.db, block.Hash(), block.NumberU64()) {
+			i, ok := indexes[transfer.TxHash]
+			if transfer.Kind != types.NativeTransferCall || !ok {
+				continue
+			}
+			add(i, transfer.From, &native)
+			add(i, transfer.To, &native)
+		}
+	}
+	return entries
+}
+
+// loop is the scheduler of the indexer, updating the address index in the
+// background whenever a new chain head is received.
+func (indexer *addressIndexer) loop() {
+	defer close(indexer.closed)
+
+	var (
+		stop   chan struct{} // Non-nil if background routine is active.
+		done   chan struct{} // Non-nil if background routine is active.
+		headCh = make(chan ChainHeadEvent)
+		sub    = indexer.chain.SubscribeChainHeadEvent(headCh)
+	)
+	defer sub.Unsubscribe()
+
+	// Launch the initial processing if chain is not empty (head != genesis).
+	if head := indexer.chain.CurrentBlock(); head.Number.Uint64() != 0 {
+		stop = make(chan struct{})
+		done = make(chan struct{})
+		go indexer.run(head, stop, done)
+	}
+	for {
+		select {
+		case h := <-headCh:
+			if done == nil {
+				stop = make(chan struct{})
+				done = make(chan struct{})
+				go indexer.run(h.Header, stop, done)
+			}
+
+		case <-done:
+			stop = nil
+			done = nil
+
+		case ch := <-indexer.term:
+			if stop != nil {
+				close(stop)
+			}
+			if done != nil {
+				log.Info("Waiting background address indexer to exit")
+				<-done
+			}
+			close(ch)
+			return
+		}
+	}
+}
+
+// close shutdown the indexer. Safe to be called for multiple times.
+func (indexer *addressIndexer) close() {
+	ch := make(chan struct{})
+	select {
+	case indexer.term <- ch:
+		<-ch
+	case <-indexer.closed:
+	}
+}
+
+// AddressTransactions retrieves at most limit transactions involving the given
+// address from the most recent, starting at the given position of the address
+// index. If token is specified, only the transactions transferring the token
+// are returned, the zero address denoting the native coin. The position of the
+// following transaction is returned as well, nil if there is none.
+func (bc *BlockChain) AddressTransactions(address common.Address, token *common.Address, start []byte, limit int) ([]rawdb.AddressTx, []byte, error) {
+	if !bc.cfg.AddressIndex {
+		return nil, nil, errAddressIndexDisabled
+	}
+	txs, next := rawdb.ReadAddressTxs(bc.db, address, token, start, limit)
+	return txs, next, nil
+}
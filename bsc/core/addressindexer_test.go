@@ -0,0 +1,153 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// waitAddressIndex waits until the address index covers the given range.
+func waitAddressIndex(t *testing.T, chain *BlockChain, tail uint64, head uint64) {
+	for i := 0; i < 500; i++ {
+		r := rawdb.ReadAddressIndexRange(chain.db)
+		if r != nil && r.Tail == tail && r.Head == head && r.HeadHash == chain.GetCanonicalHash(head) {
+			return
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatalf("address index not complete, range %v, want [%d, %d]", rawdb.ReadAddressIndexRange(chain.db), tail, head)
+}
+
+// checkAddressTxs checks the indexed transactions of the given address in the
+// order from the most recent.
+func checkAddressTxs(t *testing.T, chain *BlockChain, address common.Address, token *common.Address, want []uint64) {
+	t.Helper()
+
+	txs, _, err := chain.AddressTransactions(address, token, nil, 100)
+	if err != nil {
+		t.Fatalf("failed to read address transactions: %v", err)
+	}
+	if len(txs) != len(want) {
+		t.Fatalf("transaction count mismatch of %x: have %d, want %d", address, len(txs), len(want))
+	}
+	for i, tx := range txs {
+		block := chain.GetBlockByNumber(tx.Number)
+		if tx.Number != want[i] || block.Transactions()[tx.Index].Hash() != tx.Hash {
+			t.Fatalf("transaction %d of %x mismatch: have %v, want block %d", i, address, tx, want[i])
+		}
+	}
+}
+
+// TestAddressIndexer tests that the transactions are indexed by the addresses
+// involved, following the reorgs and the configured indexing range.
+func TestAddressIndexer(t *testing.T) {
+	var (
+		key, _    = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr      = crypto.PubkeyToAddress(key.PublicKey)
+		recipient = common.HexToAddress("0xbeef")
+		other     = common.HexToAddress("0xcafe")
+		holder    = common.HexToAddress("0xf00d")
+		internal  = common.HexToAddress("0xfeed")
+		token     = common.HexToAddress("0x7e57")
+		native    = common.Address{}
+		signer    = types.LatestSigner(params.TestChainConfig)
+		engine    = ethash.NewFaker()
+	)
+	// The token emits a transfer event to the holder and forwards 1 wei to
+	// the internal recipient.
+	code := []byte{byte(vm.PUSH20)}
+	code = append(code, holder.Bytes()...)
+	code = append(code, byte(vm.PUSH20))
+	code = append(code, addr.Bytes()...)
+	code = append(code, byte(vm.PUSH32))
+	code = append(code, transferTopic.Bytes()...)
+	code = append(code, byte(vm.PUSH1), 0, byte(vm.PUSH1), 0, byte(vm.LOG3))
+	for i := 0; i < 4; i++ {
+		code = append(code, byte(vm.PUSH1), 0) // no input and output
+	}
+	code = append(code, byte(vm.PUSH1), 1, byte(vm.PUSH20))
+	code = append(code, internal.Bytes()...)
+	code = append(code, byte(vm.GAS), byte(vm.CALL), byte(vm.STOP))
+
+	gspec := &Genesis{
+		Config: params.TestChainConfig,
+		Alloc: types.GenesisAlloc{
+			addr:  {Balance: big.NewInt(1000000000000000000)},
+			token: {Code: code, Balance: big.NewInt(1000)},
+		},
+	}
+	db, blocks, _ := GenerateChainWithGenesis(gspec, engine, 4, func(i int, b *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(addr), recipient, big.NewInt(1), params.TxGas, b.header.BaseFee, nil), signer, key)
+		b.AddTx(tx)
+		if i == 1 {
+			tx, _ = types.SignTx(types.NewTransaction(b.TxNonce(addr), token, nil, 100000, b.header.BaseFee, nil), signer, key)
+			b.AddTx(tx)
+		}
+	})
+	// The fork replaces the last two blocks, sending to another address
+	fork, _ := GenerateChain(gspec.Config, blocks[1], engine, db, 3, func(i int, b *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(addr), other, big.NewInt(1), params.TxGas, b.header.BaseFee, nil), signer, key)
+		b.AddTx(tx)
+	})
+	cfg := DefaultConfig()
+	cfg.NativeTransfers = true
+	cfg.AddressIndex = true
+	cfg.AddressIndexInternal = true
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), gspec, engine, cfg)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	waitAddressIndex(t, chain, 0, 4)
+
+	checkAddressTxs(t, chain, addr, nil, []uint64{4, 3, 2, 2, 1})
+	checkAddressTxs(t, chain, recipient, nil, []uint64{4, 3, 2, 1})
+	checkAddressTxs(t, chain, recipient, &native, []uint64{4, 3, 2, 1})
+	checkAddressTxs(t, chain, token, nil, []uint64{2})
+	checkAddressTxs(t, chain, token, &native, []uint64{2}) // forwarded value
+	checkAddressTxs(t, chain, holder, &token, []uint64{2})
+	checkAddressTxs(t, chain, addr, &token, []uint64{2})
+	checkAddressTxs(t, chain, internal, &native, []uint64{2})
+
+	// Transactions are paginated from the most recent
+	txs, next, _ := chain.AddressTransactions(addr, nil, nil, 2)
+	if len(txs) != 2 || txs[0].Number != 4 || next == nil {
+		t.Fatalf("unexpected first page: %v, next %x", txs, next)
+	}
+	txs, next, _ = chain.AddressTransactions(addr, nil, next, 3)
+	if len(txs) != 3 || txs[0].Number != 2 || txs[0].Index != 1 || next != nil {
+		t.Fatalf("unexpected last page: %v, next %x", txs, next)
+	}
+	// The blocks dropped by a reorg are unindexed
+	if _, err := chain.InsertChain(fork); err != nil {
+		t.Fatalf("failed to insert fork: %v", err)
+	}
+	waitAddressIndex(t, chain, 0, 5)
+
+	checkAddressTxs(t, chain, recipient, nil, []uint64{2, 1})
+	checkAddressTxs(t, chain, other, nil, []uint64{5, 4, 3})
+	checkAddressTxs(t, chain, addr, nil, []uint64{5, 4, 3, 2, 2, 1})
+	chain.Stop()
+
+	// The indexes out of the configured range are pruned on restart
+	cfg.TxLookupLimit = 2
+	chain, err = NewBlockChain(chain.db, gspec, engine, cfg)
+	if err != nil {
+		t.Fatalf("failed to reopen chain: %v", err)
+	}
+	defer chain.Stop()
+	waitAddressIndex(t, chain, 4, 5)
+
+	checkAddressTxs(t, chain, recipient, nil, nil)
+	checkAddressTxs(t, chain, addr, nil, []uint64{5, 4})
+}
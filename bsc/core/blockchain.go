@@ -40,6 +40,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/monitor"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/state/pruner"
 	"github.com/ethereum/go-ethereum/core/state/snapshot"
 	"github.com/ethereum/go-ethereum/core/stateless"
 	"github.com/ethereum/go-ethereum/core/systemcontracts"
@@ -200,6 +201,8 @@ type BlockChainConfig struct {
 	TrieTimeLimit        time.Duration // Time limit after which to flush the current in-memory trie to disk
 	TrieNoAsyncFlush     bool          // Whether the asynchronous buffer flushing is disallowed
 	TrieJournalDirectory string        // Directory path to the journal used for persisting trie data across node restarts
+	TrieServeReplicas    bool          // Whether the state layers are persisted for the read replicas
+	TrieReplica          bool          // Whether the chain is a read replica of the database of another node
 
 	Preimages   bool   // Whether to store preimage of trie key to the disk
 	StateScheme string // Scheme used to store ethereum states and merkle tree nodes on top
@@ -209,6 +212,10 @@ type BlockChainConfig struct {
 	// If set to 0, all state histories across the entire chain will be retained;
 	StateHistory uint64
 
+	// HistoricalState enables serving the states within the state histories,
+	// along with the trie nodes required for their proofs, without archive mode.
+	HistoricalState bool
+
 	// State snapshot related options
 	SnapshotLimit   int  // Memory allowance (MB) to use for caching snapshot entries in memory
 	SnapshotNoBuild bool // Whether the background generation is allowed
@@ -235,6 +242,30 @@ type BlockChainConfig struct {
 
 	// EnableBAL enables the block access list feature
 	EnableBAL bool
+
+	// NativeTransfers enables recording the native coin transfers, mints and
+	// burns of imported blocks, which are not visible as EVM logs.
+	NativeTransfers bool
+
+	// AddressIndex enables indexing the transactions by the addresses they
+	// involve, within the same range of blocks as the transaction indexes.
+	AddressIndex bool
+
+	// AddressIndexInternal enables indexing the native transfers made by the
+	// internal calls as well, which requires them to be recorded.
+	AddressIndexInternal bool
+
+	// StatePruneIOBudget is the disk bandwidth in megabytes per second the online
+	// state pruning may use, 0 for unthrottled.
+	StatePruneIOBudget uint64
+
+	// StatePruneBloomSize is the size in megabytes of the bloom filter marking
+	// the live trie nodes during the online pruning of the hash scheme.
+	StatePruneBloomSize uint64
+
+	// StatePruneInterval is the interval between the automatic online state
+	// prunings. If the value is 0, the state is only pruned on demand.
+	StatePruneInterval time.Duration
 }
 
 // DefaultConfig returns the default config.
@@ -295,7 +326,8 @@ func (cfg *BlockChainConfig) triedbConfig(isVerkle bool) *triedb.Config {
 			IncrKeptBlocks:  cfg.IncrKeptBlocks,
 
 			StateHistory:        cfg.StateHistory,
-			EnableStateIndexing: cfg.ArchiveMode,
+			EnableStateIndexing: cfg.ArchiveMode || cfg.HistoricalState,
+			TrienodeHistory:     cfg.HistoricalState,
 			TrieCleanSize:       cfg.TrieCleanLimit * 1024 * 1024,
 			StateCleanSize:      cfg.SnapshotLimit * 1024 * 1024,
 			JournalDirectory:    cfg.TrieJournalDirectory,
@@ -305,6 +337,8 @@ func (cfg *BlockChainConfig) triedbConfig(isVerkle bool) *triedb.Config {
 			// should be updated to eliminate the confusion.
 			WriteBufferSize: cfg.TrieDirtyLimit * 1024 * 1024,
 			NoAsyncFlush:    cfg.TrieNoAsyncFlush,
+			ServeReplicas:   cfg.TrieServeReplicas,
+			Replica:         cfg.TrieReplica,
 		}
 	}
 	return config
@@ -364,6 +398,9 @@ type BlockChain struct {
 	triesInMemory uint64
 	txIndexer     *txIndexer // Transaction indexer, might be nil if not enabled
 
+	addressIndexer *addressIndexer // Address indexer, might be nil if not enabled
+	statePruner    *statePruner    // Online state pruner, nil on read replicas
+
 	hc                       *HeaderChain
 	rmLogsFeed               event.Feed
 	chainFeed                event.Feed
@@ -433,6 +470,9 @@ func NewBlockChain(db ethdb.Database, genesis *Genesis, engine consensus.Engine,
 		log.Warn("TriesInMemory isn't the default value (128), you need specify the same TriesInMemory when pruning data",
 			"triesInMemory", cfg.TriesInMemory, "scheme", cfg.StateScheme)
 	}
+	if cfg.TrieReplica && cfg.StateScheme != rawdb.PathScheme {
+		return nil, errors.New("read replica requires the path-based state scheme")
+	}
 
 	// Open trie database with provided config
 	enableVerkle, err := EnableVerkleAtGenesis(db, genesis)
@@ -548,6 +588,10 @@ func NewBlockChain(db ethdb.Database, genesis *Genesis, engine consensus.Engine,
 			// there is no possible recovery approach except for rerunning a snap sync.
 			// Do nothing here until the state syncer picks it up.
 			log.Info("Genesis state is missing, wait state sync")
+		} else if bc.cfg.TrieReplica {
+			// The chain of a read replica can't be repaired, the state must be
+			// provided by the writer.
+			return nil, fmt.Errorf("head state missing in read replica: #%d [%x..]", head.Number, head.Hash().Bytes()[:4])
 		} else {
 			// Head state is missing, before the state recovery, find out the disk
 			// layer point of snapshot(if it's enabled). Make sure the rewound point
@@ -587,7 +631,7 @@ func NewBlockChain(db ethdb.Database, genesis *Genesis, engine consensus.Engine,
 		}
 	}
 	// Ensure that a previous crash in SetHead doesn't leave extra ancients
-	if frozen, err := bc.db.Ancients(); err == nil && frozen > 0 {
+	if frozen, err := bc.db.Ancients(); err == nil && frozen > 0 && !bc.cfg.TrieReplica {
 		var (
 			needRewind bool
 			low        uint64
@@ -640,6 +684,9 @@ func NewBlockChain(db ethdb.Database, genesis *Genesis, engine consensus.Engine,
 	bc.setupSnapshot()
 
 	// Rewind the chain in case of an incompatible config upgrade.
+	if compatErr != nil && bc.cfg.TrieReplica {
+		return nil, fmt.Errorf("incompatible chain configuration in read replica: %v", compatErr)
+	}
 	if compatErr != nil {
 		log.Warn("Rewinding chain to upgrade configuration", "err", compatErr)
 		if compatErr.RewindToTime > 0 {
@@ -654,6 +701,24 @@ func NewBlockChain(db ethdb.Database, genesis *Genesis, engine consensus.Engine,
 	if bc.cfg.TxLookupLimit >= 0 {
 		bc.txIndexer = newTxIndexer(uint64(bc.cfg.TxLookupLimit), bc)
 	}
+	// Start address indexer if it's enabled, indexing the same range of blocks.
+	// The address indexes of a read replica are maintained by the writer.
+	if bc.cfg.AddressIndex && !bc.cfg.TrieReplica {
+		limit := uint64(0)
+		if bc.cfg.TxLookupLimit > 0 {
+			limit = uint64(bc.cfg.TxLookupLimit)
+		}
+		bc.addressIndexer = newAddressIndexer(limit, bc.cfg.AddressIndexInternal, bc)
+	}
+	// Start the online state pruner, resuming any interrupted run. The state of
+	// a read replica is pruned by the writer.
+	if !bc.cfg.TrieReplica {
+		config := pruner.OnlineConfig{
+			BloomSize: bc.cfg.StatePruneBloomSize,
+			IOBudget:  bc.cfg.StatePruneIOBudget,
+		}
+		bc.statePruner = newStatePruner(config, bc.cfg.StatePruneInterval, bc)
+	}
 
 	// Start state size tracker
 	if bc.cfg.StateSizeTracking {
@@ -881,8 +946,8 @@ func (bc *BlockChain) loadLastState() error {
 	if pivot := rawdb.ReadLastPivotNumber(bc.db); pivot != nil {
 		log.Info("Loaded last snap-sync pivot marker", "number", *pivot)
 	}
-	if pruning := bc.historyPrunePoint.Load(); pruning != nil {
-		log.Info("Chain history is pruned", "earliest", pruning.BlockNumber, "hash", pruning.BlockHash)
+	if earliest, hash := bc.HistoryPruningCutoff(); earliest > 0 {
+		log.Info("Chain history is pruned", "earliest", earliest, "hash", hash)
 	}
 	return nil
 }
@@ -933,6 +998,11 @@ func (bc *BlockChain) initializeHistoryPruning(latest uint64) error {
 		bc.historyPrunePoint.Store(predefinedPoint)
 		return nil
 
+	case history.KeepRecent:
+		// The freezer prunes the bodies and receipts as the chain progresses,
+		// the cutoff follows its tail.
+		return nil
+
 	default:
 		return fmt.Errorf("invalid history mode: %d", bc.cfg.ChainHistoryMode)
 	}
@@ -1544,6 +1614,12 @@ func (bc *BlockChain) stopWithoutSaving() {
 	if bc.txIndexer != nil {
 		bc.txIndexer.close()
 	}
+	if bc.addressIndexer != nil {
+		bc.addressIndexer.close()
+	}
+	if bc.statePruner != nil {
+		bc.statePruner.close()
+	}
 	// Unsubscribe all subscriptions registered from blockchain.
 	bc.scope.Close()
 
@@ -1939,7 +2015,10 @@ func (bc *BlockChain) writeKnownBlock(block *types.Block) error {
 
 // writeBlockWithState writes block, metadata and corresponding state data to the
 // database.
-func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.Receipt, statedb *state.StateDB) error {
+//
+// The native transfers are only written if they were recorded, which is not the
+// case for blocks sealed locally.
+func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.Receipt, transfers []*types.NativeTransfer, statedb *state.StateDB) error {
 	// Calculate the total difficulty of the block
 	ptd := bc.GetTd(block.ParentHash(), block.NumberU64()-1)
 	if ptd == nil {
@@ -1965,6 +2044,9 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 			rawdb.WriteBlobSidecars(blockBatch, block.Hash(), block.NumberU64(), block.Sidecars())
 		}
 		rawdb.WriteBAL(blockBatch, block.Hash(), block.NumberU64(), block.BAL())
+		if transfers != nil {
+			rawdb.WriteNativeTransfers(blockBatch, block.Hash(), block.NumberU64(), transfers)
+		}
 		if bc.db.HasSeparateStateStore() {
 			rawdb.WritePreimages(bc.db.GetStateStore(), statedb.Preimages())
 		} else {
@@ -2065,19 +2147,24 @@ func (bc *BlockChain) writeBlockWithState(block *types.Block, receipts []*types.
 }
 
 // WriteBlockAndSetHead writes the given block and all associated state to the database,
-// and applies the block as the new chain head.
-func (bc *BlockChain) WriteBlockAndSetHead(block *types.Block, receipts []*types.Receipt, logs []*types.Log, state *state.StateDB, sealedBlockSender *event.TypeMux) (status WriteStatus, err error) {
+// and applies the block as the new chain head. The native transfers are those
+// recorded while building the block, they are ignored unless recording is
+// enabled, see RecordsNativeTransfers.
+func (bc *BlockChain) WriteBlockAndSetHead(block *types.Block, receipts []*types.Receipt, logs []*types.Log, transfers []*types.NativeTransfer, state *state.StateDB, sealedBlockSender *event.TypeMux) (status WriteStatus, err error) {
+	if !bc.cfg.NativeTransfers {
+		transfers = nil
+	}
 	if !bc.chainmu.TryLock() {
 		return NonStatTy, errChainStopped
 	}
 	defer bc.chainmu.Unlock()
 
-	return bc.writeBlockAndSetHead(block, receipts, logs, state, sealedBlockSender)
+	return bc.writeBlockAndSetHead(block, receipts, logs, transfers, state, sealedBlockSender)
 }
 
 // writeBlockAndSetHead is the internal implementation of WriteBlockAndSetHead.
 // This function expects the chain mutex to be held.
-func (bc *BlockChain) writeBlockAndSetHead(block *types.Block, receipts []*types.Receipt, logs []*types.Log, state *state.StateDB, sealedBlockSender *event.TypeMux) (status WriteStatus, err error) {
+func (bc *BlockChain) writeBlockAndSetHead(block *types.Block, receipts []*types.Receipt, logs []*types.Log, transfers []*types.NativeTransfer, state *state.StateDB, sealedBlockSender *event.TypeMux) (status WriteStatus, err error) {
 	currentBlock := bc.CurrentBlock()
 	reorg, err := bc.forker.ReorgNeededWithFastFinality(currentBlock, block.Header())
 	if err != nil {
@@ -2096,7 +2183,7 @@ func (bc *BlockChain) writeBlockAndSetHead(block *types.Block, receipts []*types
 		}
 	}
 
-	if err := bc.writeBlockWithState(block, receipts, state); err != nil {
+	if err := bc.writeBlockWithState(block, receipts, transfers, state); err != nil {
 		return NonStatTy, err
 	}
 	if reorg {
@@ -2631,10 +2718,18 @@ func (bc *BlockChain) ProcessBlock(parentRoot common.Hash, block *types.Block, s
 	}
 
 	// Process block using the parent state as reference point
-	pstart := time.Now()
+	var (
+		pstart   = time.Now()
+		vmCfg    = bc.cfg.VmConfig
+		recorder *NativeTransferRecorder
+	)
+	if bc.cfg.NativeTransfers {
+		recorder = NewNativeTransferRecorder()
+		vmCfg.Tracer = recorder.Hooks(vmCfg.Tracer)
+	}
 	statedb.SetExpectedStateRoot(block.Root())
 	statedb.SetNeedBadSharedStorage(needBadSharedStorage)
-	res, err := bc.processor.Process(block, statedb, bc.cfg.VmConfig)
+	res, err := bc.processor.Process(block, statedb, vmCfg)
 	if err != nil {
 		bc.reportBlock(block, res, err)
 		return nil, err
@@ -2704,11 +2799,15 @@ func (bc *BlockChain) ProcessBlock(parentRoot common.Hash, block *types.Block, s
 		wstart = time.Now()
 		status WriteStatus
 	)
+	var transfers []*types.NativeTransfer
+	if recorder != nil {
+		transfers = recorder.Transfers()
+	}
 	if !setHead {
 		// Don't set the head, only insert the block
-		err = bc.writeBlockWithState(block, res.Receipts, statedb)
+		err = bc.writeBlockWithState(block, res.Receipts, transfers, statedb)
 	} else {
-		status, err = bc.writeBlockAndSetHead(block, res.Receipts, res.Logs, statedb, nil)
+		status, err = bc.writeBlockAndSetHead(block, res.Receipts, res.Logs, transfers, statedb, nil)
 	}
 	if err != nil {
 		return nil, err
@@ -3166,6 +3265,53 @@ func (bc *BlockChain) InsertBlockWithoutSetHead(block *types.Block, makeWitness
 	return witness, err
 }
 
+// GenerateWitness re-executes the block with the given hash on top of the state
+// of its parent and returns the stateless witness of the execution. The parent
+// state has to be available, which limits it to the recent blocks on a node not
+// keeping the archive.
+//
+// The execution is read-only: nothing is written nor cached for the chain, so
+// the chain lock is not needed.
+func (bc *BlockChain) GenerateWitness(hash common.Hash) (*stateless.Witness, error) {
+	block := bc.GetBlockByHash(hash)
+	if block == nil {
+		return nil, fmt.Errorf("block %x not found", hash)
+	}
+	parent := bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
+	if parent == nil {
+		return nil, consensus.ErrUnknownAncestor
+	}
+	if !bc.HasState(parent.Root) {
+		return nil, consensus.ErrPrunedAncestor
+	}
+	if !bc.chainConfig.IsByzantium(block.Number()) {
+		return nil, errors.New("witness unavailable before byzantium")
+	}
+	statedb, err := state.New(parent.Root, bc.statedb)
+	if err != nil {
+		return nil, err
+	}
+	witness, err := stateless.NewWitness(block.Header(), bc)
+	if err != nil {
+		return nil, err
+	}
+	statedb.StartPrefetcher("witness", witness, nil)
+	defer statedb.StopPrefetcher()
+
+	// Execute the block without the tracers of the chain, then hash the state
+	// to collect the trie nodes touched by the post state into the witness
+	vmCfg := bc.cfg.VmConfig
+	vmCfg.Tracer = nil
+	statedb.SetNeedBadSharedStorage(bc.chainConfig.NeedBadSharedStorage(block.Number()))
+	if _, err := bc.processor.Process(block, statedb, vmCfg); err != nil {
+		return nil, err
+	}
+	if root := statedb.IntermediateRoot(bc.chainConfig.IsEIP158(block.Number())); root != block.Root() {
+		return nil, fmt.Errorf("state root mismatch (remote: %x local: %x) dberr: %w", block.Root(), root, statedb.Error())
+	}
+	return witness, nil
+}
+
 // SetCanonical rewinds the chain to set the new head block as the specified
 // block. It's possible that the state of the new head is missing, and it will
 // be recovered in this function as well.
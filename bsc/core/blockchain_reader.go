@@ -24,6 +24,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
+	"github.com/ethereum/go-ethereum/core/history"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/state/snapshot"
@@ -313,6 +314,22 @@ func (bc *BlockChain) GetReceiptsByHash(hash common.Hash) types.Receipts {
 	return receipts
 }
 
+// RecordsNativeTransfers reports whether the native transfers of the blocks are
+// recorded. Blocks built locally have to be traced with a NativeTransferRecorder
+// then, their transfers are passed along when writing them.
+func (bc *BlockChain) RecordsNativeTransfers() bool {
+	return bc.cfg.NativeTransfers
+}
+
+// GetNativeTransfers retrieves the native transfers recorded for a block, and
+// whether they were recorded at all.
+func (bc *BlockChain) GetNativeTransfers(hash common.Hash, number uint64) ([]*types.NativeTransfer, bool) {
+	if !rawdb.HasNativeTransfers(bc.db, hash, number) {
+		return nil, false
+	}
+	return rawdb.ReadNativeTransfers(bc.db, hash, number), true
+}
+
 // GetSidecarsByHash retrieves the sidecars for all transactions in a given block.
 func (bc *BlockChain) GetSidecarsByHash(hash common.Hash) types.BlobSidecars {
 	if sidecars, ok := bc.sidecarsCache.Get(hash); ok {
@@ -593,9 +610,21 @@ func (bc *BlockChain) StateIndexProgress() (uint64, error) {
 	return bc.triedb.IndexProgress()
 }
 
+// StateHistoryRange returns the block numbers associated with the earliest and
+// latest state history in the local store.
+func (bc *BlockChain) StateHistoryRange() (uint64, uint64, error) {
+	return bc.triedb.HistoryRange()
+}
+
 // HistoryPruningCutoff returns the configured history pruning point.
 // Blocks before this might not be available in the database.
 func (bc *BlockChain) HistoryPruningCutoff() (uint64, common.Hash) {
+	if bc.cfg.ChainHistoryMode == history.KeepRecent {
+		if tail, _ := bc.db.Tail(); tail > 0 {
+			return tail, bc.GetCanonicalHash(tail)
+		}
+		return 0, bc.genesisBlock.Hash()
+	}
 	pt := bc.historyPrunePoint.Load()
 	if pt == nil {
 		return 0, bc.genesisBlock.Hash()
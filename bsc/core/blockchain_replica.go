@@ -0,0 +1,87 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// errNotReplica is returned if a replica operation is requested on a chain
+// which isn't a read replica.
+var errNotReplica = errors.New("not a read replica")
+
+// PrepareReplicaRefresh is invoked with the reopened database of a read replica
+// before it replaces the one in use, checking the chain head written by the
+// writer is complete in the new view.
+func (bc *BlockChain) PrepareReplicaRefresh(next ethdb.Database) error {
+	if !bc.cfg.TrieReplica {
+		return errNotReplica
+	}
+	head := rawdb.ReadHeadBlockHash(next)
+	if head == (common.Hash{}) {
+		return errors.New("head block missing")
+	}
+	number, ok := rawdb.ReadHeaderNumber(next, head)
+	if !ok {
+		return fmt.Errorf("head header missing: %x", head)
+	}
+	if !rawdb.HasBody(next, head, number) {
+		return fmt.Errorf("head block body missing: #%d [%x..]", number, head.Bytes()[:4])
+	}
+	return bc.triedb.PrepareRefresh(next)
+}
+
+// RefreshReplica reloads the state layers and the chain head of a read replica
+// once the database has been reopened, announcing the new head if it changed.
+func (bc *BlockChain) RefreshReplica() error {
+	if !bc.cfg.TrieReplica {
+		return errNotReplica
+	}
+	if !bc.chainmu.TryLock() {
+		return errChainStopped
+	}
+	defer bc.chainmu.Unlock()
+
+	if err := bc.triedb.Refresh(); err != nil {
+		return err
+	}
+	hash := rawdb.ReadHeadBlockHash(bc.db)
+	current := bc.CurrentBlock()
+	if hash == current.Hash() {
+		return nil
+	}
+	block := bc.GetBlockByHash(hash)
+	if block == nil {
+		return fmt.Errorf("head block missing: %x", hash)
+	}
+	if !bc.HasState(block.Root()) {
+		return fmt.Errorf("head state missing: #%d [%x..]", block.Number(), hash.Bytes()[:4])
+	}
+	// The transaction lookups cached on the abandoned branch are no longer
+	// valid after a reorg.
+	if block.ParentHash() != current.Hash() {
+		bc.txLookupCache.Purge()
+	}
+	header := block.Header()
+	bc.currentBlock.Store(header)
+	bc.currentSnapBlock.Store(header)
+	headBlockGauge.Update(int64(block.NumberU64()))
+	headFastBlockGauge.Update(int64(block.NumberU64()))
+	justifiedBlockGauge.Update(int64(bc.GetJustifiedNumber(header)))
+	finalizedBlockGauge.Update(int64(bc.GetFinalizedNumber(header)))
+
+	if head := rawdb.ReadHeadHeaderHash(bc.db); head != (common.Hash{}) {
+		if h := bc.GetHeaderByHash(head); h != nil {
+			header = h
+		}
+	}
+	bc.hc.SetCurrentHeader(header)
+
+	log.Debug("Refreshed replica chain head", "number", block.Number(), "hash", hash)
+	bc.chainHeadFeed.Send(ChainHeadEvent{Header: block.Header()})
+	return nil
+}
@@ -52,6 +52,7 @@ import (
 	"github.com/ethereum/go-ethereum/ethdb/pebble"
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
 	"github.com/holiman/uint256"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
@@ -4743,3 +4744,56 @@ func TestGetCanonicalReceipt(t *testing.T) {
 		}
 	}
 }
+
+// Tests that the witness of an imported block is generated without touching the
+// chain, and that it is sufficient to execute the block.
+func TestGenerateWitness(t *testing.T) {
+	var (
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		gspec  = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  types.GenesisAlloc{addr: {Balance: big.NewInt(params.Ether)}},
+		}
+		signer = types.LatestSigner(gspec.Config)
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, ethash.NewFaker(), 4, func(i int, gen *BlockGen) {
+		tx, _ := types.SignTx(types.NewTransaction(gen.TxNonce(addr), common.Address{0xaa}, big.NewInt(1000), params.TxGas, gen.BaseFee(), nil), signer, key)
+		gen.AddTx(tx)
+	})
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), gspec, ethash.NewFaker(), DefaultConfig().WithStateScheme(rawdb.HashScheme))
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	var (
+		head      = chain.CurrentBlock().Hash()
+		gcproc    = chain.gcproc
+		lastWrite = chain.lastWrite
+		triegc    = chain.triegc.Size()
+		block     = blocks[2]
+	)
+	witness, err := chain.GenerateWitness(block.Hash())
+	if err != nil {
+		t.Fatalf("failed to generate witness: %v", err)
+	}
+	if chain.CurrentBlock().Hash() != head || chain.gcproc != gcproc || chain.lastWrite != lastWrite || chain.triegc.Size() != triegc {
+		t.Fatal("chain modified by the witness generation")
+	}
+	statedb, err := state.New(witness.Root(), state.NewDatabase(triedb.NewDatabase(witness.MakeHashDB(), triedb.HashDefaults), nil))
+	if err != nil {
+		t.Fatalf("failed to open witness state: %v", err)
+	}
+	if _, err := chain.processor.Process(block, statedb, vm.Config{}); err != nil {
+		t.Fatalf("failed to execute on the witness: %v", err)
+	}
+	if root := statedb.IntermediateRoot(true); root != block.Root() {
+		t.Fatalf("witness state root mismatch: have %x, want %x", root, block.Root())
+	}
+	if _, err := chain.GenerateWitness(common.Hash{0x01}); err == nil {
+		t.Fatal("witness generated for unknown block")
+	}
+}
@@ -0,0 +1,62 @@
+package core
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// ErrBundleReverted is returned if a transaction of a bundle failed without
+// being allowed to revert.
+var ErrBundleReverted = errors.New("bundle transaction reverted")
+
+// ApplyBundle applies the transactions of a bundle in order. An error is
+// returned if a transaction is invalid, or fails without being allowed to
+// revert. As the state is finalised after every transaction, a failed bundle
+// cannot be reverted: the state, the gas pool and the used gas must then be
+// discarded, so bundles are to be applied on copies first. On success the
+// receipts of the transactions are returned along with the bundle simulation,
+// whose fees include the direct payments to the block producer.
+func ApplyBundle(evm *vm.EVM, gp *GasPool, statedb *state.StateDB, header *types.Header, bundle *types.Bundle, usedGas *uint64, txIndex int, receiptProcessors ...ReceiptProcessor) ([]*types.Receipt, *types.SimulatedBundle, error) {
+	var (
+		used     = *usedGas
+		balances = bundleRecipientBalances(evm, statedb)
+		receipts = make([]*types.Receipt, 0, len(bundle.Txs))
+	)
+	for i, tx := range bundle.Txs {
+		statedb.SetTxContext(tx.Hash(), txIndex+i)
+		receipt, err := ApplyTransaction(evm, gp, statedb, header, tx, usedGas, receiptProcessors...)
+		if err != nil {
+			return nil, nil, fmt.Errorf("bundle tx %d [%v]: %w", i, tx.Hash().Hex(), err)
+		}
+		if receipt.Status == types.ReceiptStatusFailed && !bundle.AllowsRevert(tx.Hash()) {
+			return nil, nil, fmt.Errorf("%w: tx %d [%v]", ErrBundleReverted, i, tx.Hash().Hex())
+		}
+		receipts = append(receipts, receipt)
+	}
+	fees := new(big.Int)
+	for i, balance := range bundleRecipientBalances(evm, statedb) {
+		fees.Add(fees, balance.Sub(balance, balances[i]))
+	}
+	return receipts, &types.SimulatedBundle{
+		Bundle:  bundle,
+		GasUsed: *usedGas - used,
+		Fees:    fees,
+	}, nil
+}
+
+// bundleRecipientBalances returns the balances of the accounts receiving the
+// payments of a bundle: the account credited with the transaction fees and,
+// if different, the block producer.
+func bundleRecipientBalances(evm *vm.EVM, statedb *state.StateDB) []*big.Int {
+	balances := []*big.Int{statedb.GetBalance(evm.Context.Coinbase).ToBig()}
+	if evm.ChainConfig().IsInBSC() && evm.Context.Coinbase != consensus.SystemAddress {
+		balances = append(balances, statedb.GetBalance(consensus.SystemAddress).ToBig())
+	}
+	return balances
+}
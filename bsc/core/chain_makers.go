@@ -416,6 +416,10 @@ func GenerateChain(config *params.ChainConfig, parent *types.Block, engine conse
 		}
 
 		systemcontracts.TryUpdateBuildInSystemContract(config, b.header.Number, parent.Time(), b.header.Time, statedb, true)
+		if config.IsMendel(b.header.Number, b.header.Time) {
+			// Settle the holder yield as the processor does, even without transactions
+			statedb.EnableYield()
+		}
 		if config.IsPrague(b.header.Number, b.header.Time) || config.IsVerkle(b.header.Number, b.header.Time) {
 			// EIP-2935
 			blockContext := NewEVMBlockContext(b.header, cm, &b.header.Coinbase)
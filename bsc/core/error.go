@@ -115,6 +115,10 @@ var (
 	// ErrSenderNoEOA is returned if the sender of a transaction is a contract.
 	ErrSenderNoEOA = errors.New("sender not an eoa")
 
+	// ErrFrozenAccount is returned if the sender or the recipient of a transaction
+	// is on the governance freeze list.
+	ErrFrozenAccount = errors.New("account frozen by governance")
+
 	// -- EIP-4844 errors --
 
 	// ErrBlobFeeCapTooLow is returned if the transaction fee cap is less than the
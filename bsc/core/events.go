@@ -38,6 +38,13 @@ type RemovedLogsEvent struct{ Logs []*types.Log }
 // NewVoteEvent is posted when a batch of votes enters the vote pool.
 type NewVoteEvent struct{ Vote *types.VoteEnvelope }
 
+// NewBridgeAttestationEvent is posted when an attestation enters the bridge
+// attestation pool.
+type NewBridgeAttestationEvent struct{ Attestation *types.BridgeAttestation }
+
+// NewPriceReportEvent is posted when a report enters the price report pool.
+type NewPriceReportEvent struct{ Report *types.PriceReport }
+
 // FinalizedHeaderEvent is posted when a finalized header is reached.
 type FinalizedHeaderEvent struct{ Header *types.Header }
 
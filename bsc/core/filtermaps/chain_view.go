@@ -32,6 +32,12 @@ type blockchain interface {
 	GetRawReceipts(hash common.Hash, number uint64) types.Receipts
 }
 
+// nativeTransferChain is implemented by the blockchains recording the native
+// transfers of the blocks.
+type nativeTransferChain interface {
+	GetNativeTransfers(hash common.Hash, number uint64) ([]*types.NativeTransfer, bool)
+}
+
 // ChainView represents an immutable view of a chain with a block id and a set
 // of receipts associated to each block number and a block hash associated with
 // all block numbers except the head block. This is because in the future
@@ -122,6 +128,22 @@ func (cv *ChainView) RawReceipts(number uint64) types.Receipts {
 	return cv.chain.GetRawReceipts(blockHash, number)
 }
 
+// NativeTransfers returns the native transfers recorded for the block at the
+// given block number, nil if they were not recorded.
+func (cv *ChainView) NativeTransfers(number uint64) []*types.NativeTransfer {
+	chain, ok := cv.chain.(nativeTransferChain)
+	if !ok {
+		return nil
+	}
+	blockHash := cv.BlockHash(number)
+	if blockHash == (common.Hash{}) {
+		log.Error("Chain view: block hash unavailable", "number", number, "head", cv.headNumber)
+		return nil
+	}
+	transfers, _ := chain.GetNativeTransfers(blockHash, number)
+	return transfers
+}
+
 // SharedRange returns the block range shared by two chain views.
 func (cv *ChainView) SharedRange(cv2 *ChainView) common.Range[uint64] {
 	if cv == nil || cv2 == nil {
@@ -58,6 +58,10 @@ const (
 	cachedRenderSnapshots = 8    // saved map renderer data at block boundaries
 )
 
+// nativeTransfersFlag is the database version flag of the log index including
+// the native transfers.
+const nativeTransfersFlag = 1 << 16
+
 // FilterMaps is the in-memory representation of the log index structure that is
 // responsible for building and updating the index according to the canonical
 // chain.
@@ -72,6 +76,11 @@ type FilterMaps struct {
 	// case logic in eth/filters.
 	disabled   bool
 	disabledCh chan struct{} // closed by indexer if disabled
+	keepIndex  bool          // leave the existing index untouched while disabled
+
+	// If nativeTransfers is set, the native transfers recorded for the blocks
+	// are indexed as synthetic logs, see nativeTransferLogs.
+	nativeTransfers bool
 
 	closeCh        chan struct{}
 	closeWg        sync.WaitGroup
@@ -217,6 +226,15 @@ type Config struct {
 	History  uint64 // number of historical blocks to index
 	Disabled bool   // disables indexing completely
 
+	// KeepIndex leaves the index stored in the database untouched while the
+	// indexing is disabled, for a database shared with another process.
+	KeepIndex bool
+
+	// NativeTransfers indexes the native transfers recorded for the blocks as
+	// synthetic logs. The index is rebuilt if the option is changed, and it is
+	// rendered from the history start as the checkpoints don't apply.
+	NativeTransfers bool
+
 	// CheckpointFileName specifies the path to the checkpoint JSON file.
 	// If set, checkpoints will be loaded from this file during initialization,
 	// and the file will be updated with new checkpoint information during operation.
@@ -230,7 +248,7 @@ type Config struct {
 // NewFilterMaps creates a new FilterMaps and starts the indexer.
 func NewFilterMaps(db ethdb.KeyValueStore, initView *ChainView, historyCutoff, finalBlock uint64, params Params, config Config) (*FilterMaps, error) {
 	rs, initialized, err := rawdb.ReadFilterMapsRange(db)
-	if err != nil || (initialized && rs.Version != databaseVersion) {
+	if err != nil || (initialized && rs.Version != indexVersion(config.NativeTransfers)) {
 		rs, initialized = rawdb.FilterMapsRange{}, false
 		log.Warn("Invalid log index database version; resetting log index")
 	}
@@ -245,6 +263,8 @@ func NewFilterMaps(db ethdb.KeyValueStore, initView *ChainView, historyCutoff, f
 		blockProcessingCh: make(chan bool, 1),
 		history:           config.History,
 		disabled:          config.Disabled,
+		keepIndex:         config.KeepIndex,
+		nativeTransfers:   config.NativeTransfers,
 		hashScheme:        config.HashScheme,
 		disabledCh:        make(chan struct{}),
 		checkpointFile:    config.CheckpointFileName,
@@ -270,7 +290,14 @@ func NewFilterMaps(db ethdb.KeyValueStore, initView *ChainView, historyCutoff, f
 		lvPointerCache:      lru.NewCache[uint64, uint64](cachedLvPointers),
 		renderSnapshots:     lru.NewCache[uint64, *renderedMap](cachedRenderSnapshots),
 	}
-	f.checkRevertRange() // revert maps that are inconsistent with the current chain view
+	if f.nativeTransfers {
+		// The checkpoints refer to the log values of the blocks without the
+		// native transfers, neither loaded nor exported.
+		f.checkpointFile = ""
+	}
+	if !f.disabled || !f.keepIndex {
+		f.checkRevertRange() // revert maps that are inconsistent with the current chain view
+	}
 
 	if f.indexedRange.hasIndexedBlocks() {
 		log.Info("Initialized log indexer",
@@ -283,6 +310,10 @@ func NewFilterMaps(db ethdb.KeyValueStore, initView *ChainView, historyCutoff, f
 
 // Start starts the indexer.
 func (f *FilterMaps) Start() {
+	if f.disabled && f.keepIndex {
+		close(f.disabledCh)
+		return
+	}
 	if !f.testDisableSnapshots && f.indexedRange.hasIndexedBlocks() && f.indexedRange.headIndexed {
 		// previous target head rendered; load last map as snapshot
 		if err := f.loadHeadSnapshot(); err != nil {
@@ -405,8 +436,14 @@ func (f *FilterMaps) init() error {
 		}
 	}
 
-	var bestIdx, bestLen int
-	for idx, checkpointList := range checkpoints {
+	var (
+		candidates       = checkpoints
+		bestIdx, bestLen int
+	)
+	if f.nativeTransfers {
+		candidates = nil
+	}
+	for idx, checkpointList := range candidates {
 		// binary search for the last matching epoch head
 		min, max := 0, len(checkpointList)
 		for min < max {
@@ -431,7 +468,7 @@ func (f *FilterMaps) init() error {
 	}
 	var initBlockNumber uint64
 	if bestLen > 0 {
-		initBlockNumber = checkpoints[bestIdx][bestLen-1].BlockNumber
+		initBlockNumber = candidates[bestIdx][bestLen-1].BlockNumber
 	}
 	if initBlockNumber < f.historyCutoff {
 		return errors.New("cannot start indexing before history cutoff point")
@@ -447,7 +484,7 @@ func (f *FilterMaps) init() error {
 	}
 	batch := f.db.NewBatch()
 	for epoch := range bestLen {
-		cp := checkpoints[bestIdx][epoch]
+		cp := candidates[bestIdx][epoch]
 		f.storeLastBlockOfMap(batch, f.lastEpochMap(uint32(epoch)), cp.BlockNumber, cp.BlockId)
 		f.storeBlockLvPointer(batch, cp.BlockNumber, cp.FirstIndex)
 	}
@@ -455,7 +492,7 @@ func (f *FilterMaps) init() error {
 		initialized: true,
 	}
 	if bestLen > 0 {
-		cp := checkpoints[bestIdx][bestLen-1]
+		cp := candidates[bestIdx][bestLen-1]
 		fmr.blocks = common.NewRange(cp.BlockNumber+1, 0)
 		fmr.maps = common.NewRange(f.firstEpochMap(uint32(bestLen)), 0)
 	}
@@ -510,7 +547,7 @@ func (f *FilterMaps) setRange(batch ethdb.KeyValueWriter, newView *ChainView, ne
 	f.updateMatchersValidRange()
 	if newRange.initialized {
 		rs := rawdb.FilterMapsRange{
-			Version:          databaseVersion,
+			Version:          indexVersion(f.nativeTransfers),
 			HeadIndexed:      newRange.headIndexed,
 			HeadDelimiter:    newRange.headDelimiter,
 			BlocksFirst:      newRange.blocks.First(),
@@ -536,9 +573,12 @@ func (f *FilterMaps) setRange(batch ethdb.KeyValueWriter, newView *ChainView, ne
 // with the canonical chain at the point where the given log value index points.
 // If this is not the case then an invalid result or an error may be returned.
 //
+// If synthetic is set, only the synthetic logs of the native transfers are
+// returned, otherwise only the logs of the transactions.
+//
 // Note that this function assumes that the indexer read lock is being held when
 // called from outside the indexerLoop goroutine.
-func (f *FilterMaps) getLogByLvIndex(lvIndex uint64) (*types.Log, error) {
+func (f *FilterMaps) getLogByLvIndex(lvIndex uint64, synthetic bool) (*types.Log, error) {
 	mapIndex := uint32(lvIndex >> f.logValuesPerMap)
 	if !f.indexedRange.maps.Includes(mapIndex) {
 		return nil, nil
@@ -572,7 +612,7 @@ func (f *FilterMaps) getLogByLvIndex(lvIndex uint64) (*types.Log, error) {
 		}
 	}
 	// get block receipts
-	receipts := f.indexedView.Receipts(firstBlockNumber)
+	receipts, withSynthetic := derivedReceipts(f.indexedView, firstBlockNumber, f.nativeTransfers)
 	if receipts == nil {
 		return nil, fmt.Errorf("failed to retrieve receipts for block %d containing searched log value index %d: %v", firstBlockNumber, lvIndex, err)
 	}
@@ -581,7 +621,7 @@ func (f *FilterMaps) getLogByLvIndex(lvIndex uint64) (*types.Log, error) {
 		return nil, fmt.Errorf("failed to retrieve log value pointer of block %d containing searched log value index %d: %v", firstBlockNumber, lvIndex, err)
 	}
 	// iterate through receipts to find the exact log starting at lvIndex
-	for _, receipt := range receipts {
+	for i, receipt := range receipts {
 		for _, log := range receipt.Logs {
 			l := uint64(len(log.Topics) + 1)
 			r := f.valuesPerMap - lvPointer%f.valuesPerMap
@@ -595,6 +635,9 @@ func (f *FilterMaps) getLogByLvIndex(lvIndex uint64) (*types.Log, error) {
 				return nil, nil
 			}
 			if lvPointer == lvIndex {
+				if synthetic != (withSynthetic && i == len(receipts)-1) {
+					return nil, nil // the log is not of the searched kind
+				}
 				return log, nil // potential match
 			}
 			lvPointer += l
@@ -932,3 +975,12 @@ func (f *FilterMaps) exportCheckpoints() {
 	w.WriteString("]\n")
 	f.lastFinalEpoch = epochCount
 }
+
+// indexVersion returns the database version of the log index, distinguishing
+// the index including the native transfers.
+func indexVersion(nativeTransfers bool) uint32 {
+	if nativeTransfers {
+		return databaseVersion | nativeTransfersFlag
+	}
+	return databaseVersion
+}
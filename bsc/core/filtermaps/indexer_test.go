@@ -232,7 +232,7 @@ func TestLogsByIndex(t *testing.T) {
 	firstLog := make([]uint64, 1001) // first valid log position per block
 	lastLog := make([]uint64, 1001)  // last valid log position per block
 	for i := uint64(0); i <= ts.fm.indexedRange.headDelimiter; i++ {
-		log, err := ts.fm.getLogByLvIndex(i)
+		log, err := ts.fm.getLogByLvIndex(i, false)
 		if err != nil {
 			t.Fatalf("Error getting log by index %d: %v", i, err)
 		}
@@ -249,14 +249,14 @@ func TestLogsByIndex(t *testing.T) {
 			return
 		}
 		if lvi := firstLog[ts.fm.indexedRange.blocks.First()]; lvi != 0 {
-			log, err := ts.fm.getLogByLvIndex(lvi)
+			log, err := ts.fm.getLogByLvIndex(lvi, false)
 			if log == nil || err != nil {
 				t.Errorf("Error getting first log of indexed block range: %v", err)
 				failed = true
 			}
 		}
 		if lvi := lastLog[ts.fm.indexedRange.blocks.Last()]; lvi != 0 {
-			log, err := ts.fm.getLogByLvIndex(lvi)
+			log, err := ts.fm.getLogByLvIndex(lvi, false)
 			if log == nil || err != nil {
 				t.Errorf("Error getting last log of indexed block range: %v", err)
 				failed = true
@@ -345,6 +345,7 @@ type testSetup struct {
 	params               Params
 	dbHashes             map[string]common.Hash
 	testDisableSnapshots bool
+	nativeTransfers      bool
 }
 
 func newTestSetup(t *testing.T) *testSetup {
@@ -367,8 +368,9 @@ func (ts *testSetup) setHistory(history uint64, noHistory bool) {
 	head := ts.chain.CurrentBlock()
 	view := NewChainView(ts.chain, head.Number.Uint64(), head.Hash())
 	config := Config{
-		History:  history,
-		Disabled: noHistory,
+		History:         history,
+		Disabled:        noHistory,
+		NativeTransfers: ts.nativeTransfers,
 	}
 	ts.fm, _ = NewFilterMaps(ts.db, view, 0, 0, ts.params, config)
 	ts.fm.testDisableSnapshots = ts.testDisableSnapshots
@@ -443,7 +445,7 @@ func (ts *testSetup) matcherViewHash() common.Hash {
 		hasher.Reset()
 		hasher.Write(hash[:])
 		lvptr := binary.LittleEndian.Uint64(hash[:8]) % headPtr
-		if log, _ := mb.GetLogByLvIndex(ctx, lvptr); log != nil {
+		if log, _ := mb.GetLogByLvIndex(ctx, lvptr, false); log != nil {
 			enc, err := rlp.EncodeToBytes(log)
 			if err != nil {
 				panic(err)
@@ -470,13 +472,15 @@ type testChain struct {
 	canonical []common.Hash
 	blocks    map[common.Hash]*types.Block
 	receipts  map[common.Hash]types.Receipts
+	transfers map[common.Hash][]*types.NativeTransfer
 }
 
 func (ts *testSetup) newTestChain() *testChain {
 	return &testChain{
-		ts:       ts,
-		blocks:   make(map[common.Hash]*types.Block),
-		receipts: make(map[common.Hash]types.Receipts),
+		ts:        ts,
+		blocks:    make(map[common.Hash]*types.Block),
+		receipts:  make(map[common.Hash]types.Receipts),
+		transfers: make(map[common.Hash][]*types.NativeTransfer),
 	}
 }
 
@@ -524,6 +528,14 @@ func (tc *testChain) GetRawReceipts(hash common.Hash, number uint64) types.Recei
 	return tc.receipts[hash]
 }
 
+func (tc *testChain) GetNativeTransfers(hash common.Hash, number uint64) ([]*types.NativeTransfer, bool) {
+	tc.lock.RLock()
+	defer tc.lock.RUnlock()
+
+	transfers, ok := tc.transfers[hash]
+	return transfers, ok
+}
+
 func (tc *testChain) addBlocks(count, maxTxPerBlock, maxLogsPerReceipt, maxTopicsPerLog int, random bool) {
 	tc.lock.Lock()
 	blockGen := func(i int, gen *core.BlockGen) {
@@ -591,6 +603,7 @@ func (tc *testChain) addBlocks(count, maxTxPerBlock, maxLogsPerReceipt, maxTopic
 		}
 		tc.canonical = append(tc.canonical, hash)
 		tc.blocks[hash] = block
+		tc.transfers[hash] = randomNativeTransfers(block)
 		if receipts[i] != nil {
 			tc.receipts[hash] = receipts[i]
 		} else {
@@ -601,6 +614,23 @@ func (tc *testChain) addBlocks(count, maxTxPerBlock, maxLogsPerReceipt, maxTopic
 	tc.setTargetHead()
 }
 
+// randomNativeTransfers returns a random internal transfer for each transaction
+// of the given block, followed by a mint.
+func randomNativeTransfers(block *types.Block) []*types.NativeTransfer {
+	var transfers []*types.NativeTransfer
+	for _, tx := range block.Transactions() {
+		transfer := &types.NativeTransfer{Kind: types.NativeTransferCall, Amount: big.NewInt(rand.Int63()), TxHash: tx.Hash(), Internal: true}
+		crand.Read(transfer.From[:])
+		crand.Read(transfer.To[:])
+		transfers = append(transfers, transfer)
+	}
+	mint := &types.NativeTransfer{Kind: types.NativeTransferMint, Amount: big.NewInt(rand.Int63())}
+	crand.Read(mint.To[:])
+	transfers = append(transfers, mint)
+	types.DeriveNativeTransferFields(transfers, block.Hash(), block.NumberU64())
+	return transfers
+}
+
 func (tc *testChain) setHead(headNum int) {
 	tc.lock.Lock()
 	tc.canonical = tc.canonical[:headNum+1]
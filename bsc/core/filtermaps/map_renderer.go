@@ -664,6 +664,7 @@ func (fmr *filterMapsRange) addRenderedRange(firstRendered, afterLastRendered, a
 type logIterator struct {
 	params                                          *Params
 	chainView                                       *ChainView
+	nativeTransfers                                 bool
 	blockNumber                                     uint64
 	receipts                                        types.Receipts
 	blockStart, delimiter, skipToBoundary, finished bool
@@ -685,12 +686,13 @@ func (f *FilterMaps) newLogIteratorFromBlockDelimiter(blockNumber, lvIndex uint6
 	}
 	finished := blockNumber == f.targetView.HeadNumber()
 	l := &logIterator{
-		chainView:   f.targetView,
-		params:      &f.Params,
-		blockNumber: blockNumber,
-		finished:    finished,
-		delimiter:   !finished,
-		lvIndex:     lvIndex,
+		chainView:       f.targetView,
+		params:          &f.Params,
+		nativeTransfers: f.nativeTransfers,
+		blockNumber:     blockNumber,
+		finished:        finished,
+		delimiter:       !finished,
+		lvIndex:         lvIndex,
 	}
 	l.enforceValidState()
 	return l, nil
@@ -703,18 +705,19 @@ func (f *FilterMaps) newLogIteratorFromMapBoundary(mapIndex uint32, startBlock,
 		return nil, fmt.Errorf("iterator entry point %d after target chain head block %d", startBlock, f.targetView.HeadNumber())
 	}
 	// get block receipts
-	receipts := f.targetView.RawReceipts(startBlock)
+	receipts := rawReceipts(f.targetView, startBlock, f.nativeTransfers)
 	if receipts == nil {
 		return nil, fmt.Errorf("receipts not found for start block %d", startBlock)
 	}
 	// initialize iterator at block start
 	l := &logIterator{
-		chainView:   f.targetView,
-		params:      &f.Params,
-		blockNumber: startBlock,
-		receipts:    receipts,
-		blockStart:  true,
-		lvIndex:     startLvPtr,
+		chainView:       f.targetView,
+		params:          &f.Params,
+		nativeTransfers: f.nativeTransfers,
+		blockNumber:     startBlock,
+		receipts:        receipts,
+		blockStart:      true,
+		lvIndex:         startLvPtr,
 	}
 	l.enforceValidState()
 	targetIndex := uint64(mapIndex) << f.logValuesPerMap
@@ -770,7 +773,7 @@ func (l *logIterator) next() error {
 	if l.delimiter {
 		l.delimiter = false
 		l.blockNumber++
-		l.receipts = l.chainView.RawReceipts(l.blockNumber)
+		l.receipts = rawReceipts(l.chainView, l.blockNumber, l.nativeTransfers)
 		if l.receipts == nil {
 			return fmt.Errorf("receipts not found for block %d", l.blockNumber)
 		}
@@ -45,7 +45,8 @@ type MatcherBackend interface {
 	GetParams() *Params
 	GetBlockLvPointer(ctx context.Context, blockNumber uint64) (uint64, error)
 	GetFilterMapRows(ctx context.Context, mapIndices []uint32, rowIndex uint32, baseLayerOnly bool) ([]FilterRow, error)
-	GetLogByLvIndex(ctx context.Context, lvIndex uint64) (*types.Log, error)
+	GetLogByLvIndex(ctx context.Context, lvIndex uint64, synthetic bool) (*types.Log, error)
+	NativeTransfers() bool
 	SyncLogIndex(ctx context.Context) (SyncRange, error)
 	Close()
 }
@@ -70,7 +71,24 @@ type SyncRange struct {
 // missing or changed during the search process then the resulting logs belonging
 // to that block range might be missing or incorrect.
 // Also note that the returned list may contain false positives.
+// The synthetic logs of the native transfers are not returned, see
+// GetPotentialNativeTransferMatches.
 func GetPotentialMatches(ctx context.Context, backend MatcherBackend, firstBlock, lastBlock uint64, addresses []common.Address, topics [][]common.Hash) ([]*types.Log, error) {
+	return getPotentialMatches(ctx, backend, firstBlock, lastBlock, addresses, topics, false)
+}
+
+// GetPotentialNativeTransferMatches returns a list of the synthetic logs of the
+// native transfers that are potential matches for the given filter criteria,
+// with the same caveats as GetPotentialMatches. ErrNativeTransfersNotIndexed is
+// returned if the log index does not include them.
+func GetPotentialNativeTransferMatches(ctx context.Context, backend MatcherBackend, firstBlock, lastBlock uint64, addresses []common.Address, topics [][]common.Hash) ([]*types.Log, error) {
+	if !backend.NativeTransfers() {
+		return nil, ErrNativeTransfersNotIndexed
+	}
+	return getPotentialMatches(ctx, backend, firstBlock, lastBlock, addresses, topics, true)
+}
+
+func getPotentialMatches(ctx context.Context, backend MatcherBackend, firstBlock, lastBlock uint64, addresses []common.Address, topics [][]common.Hash, synthetic bool) ([]*types.Log, error) {
 	params := backend.GetParams()
 	// find the log value index range to search
 	firstIndex, err := backend.GetBlockLvPointer(ctx, firstBlock)
@@ -120,6 +138,7 @@ func GetPotentialMatches(ctx context.Context, backend MatcherBackend, firstBlock
 		lastIndex:  lastIndex,
 		firstMap:   uint32(firstIndex >> params.logValuesPerMap),
 		lastMap:    uint32(lastIndex >> params.logValuesPerMap),
+		synthetic:  synthetic,
 	}
 
 	start := time.Now()
@@ -148,6 +167,7 @@ type matcherEnv struct {
 	matcher               matcher
 	firstIndex, lastIndex uint64
 	firstMap, lastMap     uint32
+	synthetic             bool // searching the synthetic logs of the native transfers
 }
 
 func (m *matcherEnv) process() ([]*types.Log, error) {
@@ -269,7 +289,7 @@ func (m *matcherEnv) getLogsFromMatches(matches potentialMatches) ([]*types.Log,
 		if match < m.firstIndex || match > m.lastIndex {
 			continue
 		}
-		log, err := m.backend.GetLogByLvIndex(m.ctx, match)
+		log, err := m.backend.GetLogByLvIndex(m.ctx, match, m.synthetic)
 		if err != nil {
 			return logs, fmt.Errorf("failed to retrieve log at index %d: %v", match, err)
 		}
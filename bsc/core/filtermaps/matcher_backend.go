@@ -110,12 +110,21 @@ func (fm *FilterMapsMatcherBackend) GetBlockLvPointer(ctx context.Context, block
 // No error is returned though because of an inconsistency between the chain and
 // the log index. It is the caller's responsibility to verify this consistency
 // using SyncLogIndex and re-process certain blocks if necessary.
+// If synthetic is set, only the synthetic logs of the native transfers are
+// returned, otherwise only the logs of the transactions.
 // GetLogByLvIndex implements MatcherBackend.
-func (fm *FilterMapsMatcherBackend) GetLogByLvIndex(ctx context.Context, lvIndex uint64) (*types.Log, error) {
+func (fm *FilterMapsMatcherBackend) GetLogByLvIndex(ctx context.Context, lvIndex uint64, synthetic bool) (*types.Log, error) {
 	fm.f.indexLock.RLock()
 	defer fm.f.indexLock.RUnlock()
 
-	return fm.f.getLogByLvIndex(lvIndex)
+	return fm.f.getLogByLvIndex(lvIndex, synthetic)
+}
+
+// NativeTransfers returns whether the native transfers are indexed as synthetic
+// logs.
+// NativeTransfers implements MatcherBackend.
+func (fm *FilterMapsMatcherBackend) NativeTransfers() bool {
+	return fm.f.nativeTransfers
 }
 
 // synced signals to the matcher that has triggered a synchronisation that it
@@ -19,10 +19,12 @@ package filtermaps
 import (
 	"context"
 	crand "crypto/rand"
+	"math/big"
 	"math/rand"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
 )
 
 func TestMatcher(t *testing.T) {
@@ -85,3 +87,88 @@ func TestMatcher(t *testing.T) {
 		}
 	}
 }
+
+func TestMatcherNativeTransfers(t *testing.T) {
+	ts := newTestSetup(t)
+	defer ts.close()
+
+	ts.nativeTransfers = true
+	ts.chain.addBlocks(100, 10, 10, 4, true)
+	ts.setHistory(0, false)
+	ts.fm.WaitIdle()
+
+	search := func(addresses []common.Address, topics [][]common.Hash, synthetic bool) []*types.Log {
+		mb := ts.fm.NewMatcherBackend()
+		defer mb.Close()
+		var (
+			logs []*types.Log
+			err  error
+		)
+		if synthetic {
+			logs, err = GetPotentialNativeTransferMatches(context.Background(), mb, 0, 1000, addresses, topics)
+		} else {
+			logs, err = GetPotentialMatches(context.Background(), mb, 0, 1000, addresses, topics)
+		}
+		if err != nil {
+			t.Fatalf("Log search error: %v", err)
+		}
+		return logs
+	}
+	for i := 0; i < 200; i++ {
+		bhash := ts.chain.canonical[1+rand.Intn(len(ts.chain.canonical)-1)]
+		transfers := ts.chain.transfers[bhash]
+		transfer := transfers[rand.Intn(len(transfers))]
+
+		// Transfers are found by recipient at the native token address
+		var found bool
+		for _, l := range search([]common.Address{NativeTokenAddress}, [][]common.Hash{{NativeTransferTopic}, {}, {common.BytesToHash(transfer.To.Bytes())}}, true) {
+			if l.BlockHash == bhash && l.TxHash == transfer.TxHash && new(big.Int).SetBytes(l.Data).Cmp(transfer.Amount) == 0 {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("Native transfer search did not return expected transfer %+v", transfer)
+		}
+		// Internal calls are found at the callee
+		if transfer.Kind != types.NativeTransferCall {
+			continue
+		}
+		found = false
+		for _, l := range search([]common.Address{transfer.To}, [][]common.Hash{{InternalCallTopic}}, true) {
+			if l.BlockHash == bhash && l.TxHash == transfer.TxHash && l.Topics[1] == common.BytesToHash(transfer.From.Bytes()) {
+				found = true
+			}
+		}
+		if !found {
+			t.Fatalf("Internal call search did not return expected call %+v", transfer)
+		}
+	}
+	// The synthetic logs are kept out of the standard search
+	if logs := search([]common.Address{NativeTokenAddress}, nil, false); len(logs) != 0 {
+		t.Fatalf("Native transfers found by the log search: %d", len(logs))
+	}
+	// The logs of the transactions are still found
+	for _, bhash := range ts.chain.canonical {
+		for _, receipt := range ts.chain.receipts[bhash] {
+			for _, log := range receipt.Logs {
+				var found bool
+				for _, l := range search([]common.Address{log.Address}, nil, false) {
+					found = found || l == log
+				}
+				if !found {
+					t.Fatalf("Log search did not return expected log %v", *log)
+				}
+			}
+		}
+	}
+	// The index is rebuilt without the native transfers
+	ts.nativeTransfers = false
+	ts.setHistory(0, false)
+	ts.fm.WaitIdle()
+
+	mb := ts.fm.NewMatcherBackend()
+	defer mb.Close()
+	if _, err := GetPotentialNativeTransferMatches(context.Background(), mb, 0, 1000, []common.Address{NativeTokenAddress}, nil); err != ErrNativeTransfersNotIndexed {
+		t.Fatalf("Native transfer search error mismatch: have %v, want %v", err, ErrNativeTransfersNotIndexed)
+	}
+}
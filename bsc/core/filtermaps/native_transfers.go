@@ -0,0 +1,138 @@
+package filtermaps
+
+import (
+	"errors"
+	"math/big"
+	"slices"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/math"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	// NativeTokenAddress is the address the native transfers are indexed at, as
+	// if the native coin was a token.
+	NativeTokenAddress = common.HexToAddress("0xEeeeeEeeeEeEeeEeEeEeeEEEeeeeEeeeeeeeEEeE")
+
+	// NativeTransferTopic is the first topic of the native transfers, distinct
+	// from the one of the token transfer events. The sender, the recipient and
+	// the kind of the transfer follow as topics, the amount is the data.
+	NativeTransferTopic = crypto.Keccak256Hash([]byte("NativeTransfer(address,address,uint256,uint8)"))
+
+	// InternalCallTopic is the first topic of the internal calls moving value,
+	// indexed at the address of the callee. The caller follows as topic.
+	InternalCallTopic = crypto.Keccak256Hash([]byte("InternalCall(address)"))
+
+	// ErrNativeTransfersNotIndexed is returned when searching the synthetic logs
+	// of the native transfers in a log index not including them.
+	ErrNativeTransfersNotIndexed = errors.New("native transfers not indexed")
+)
+
+// nativeTransferLogs returns the synthetic logs indexing the given native
+// transfers, along with the internal calls they were made by.
+func nativeTransferLogs(transfers []*types.NativeTransfer) []*types.Log {
+	var logs []*types.Log
+	for _, transfer := range transfers {
+		var kind common.Hash
+		kind[common.HashLength-1] = byte(transfer.Kind)
+
+		logs = append(logs, &types.Log{
+			Address: NativeTokenAddress,
+			Topics:  []common.Hash{NativeTransferTopic, common.BytesToHash(transfer.From.Bytes()), common.BytesToHash(transfer.To.Bytes()), kind},
+			Data:    math.U256Bytes(new(big.Int).Set(transfer.Amount)),
+			TxHash:  transfer.TxHash,
+		})
+		if transfer.Kind == types.NativeTransferCall && transfer.Internal {
+			logs = append(logs, &types.Log{
+				Address: transfer.To,
+				Topics:  []common.Hash{InternalCallTopic, common.BytesToHash(transfer.From.Bytes())},
+				TxHash:  transfer.TxHash,
+			})
+		}
+	}
+	return logs
+}
+
+// rawReceipts returns the receipts of the block at the given number without
+// the derived fields, used for rendering the maps. If the native transfers are
+// indexed, their synthetic logs are added as an extra receipt after the ones of
+// the transactions.
+func rawReceipts(cv *ChainView, number uint64, nativeTransfers bool) types.Receipts {
+	receipts := cv.RawReceipts(number)
+	if receipts == nil || !nativeTransfers {
+		return receipts
+	}
+	logs := nativeTransferLogs(cv.NativeTransfers(number))
+	if len(logs) == 0 {
+		return receipts
+	}
+	return append(slices.Clip(receipts), &types.Receipt{Logs: logs})
+}
+
+// derivedReceipts returns the receipts of the block at the given number with
+// the derived fields, used for retrieving the matching logs. If the synthetic
+// logs of the native transfers are added as by rawReceipts, the last receipt is
+// theirs and synthetic is set.
+func derivedReceipts(cv *ChainView, number uint64, nativeTransfers bool) (receipts types.Receipts, synthetic bool) {
+	receipts = cv.Receipts(number)
+	if receipts == nil || !nativeTransfers {
+		return receipts, false
+	}
+	logs := deriveNativeTransferLogs(cv, number, receipts)
+	if len(logs) == 0 {
+		return receipts, false
+	}
+	return append(slices.Clip(receipts), &types.Receipt{Logs: logs}), true
+}
+
+// NativeTransferLogs returns the synthetic logs of the native transfers recorded
+// for the block at the given number with the derived fields, as found in the log
+// index including them. The logs are located after the ones of the transactions,
+// with the position of their transaction in the block.
+func NativeTransferLogs(cv *ChainView, number uint64) []*types.Log {
+	receipts := cv.Receipts(number)
+	if receipts == nil {
+		return nil
+	}
+	return deriveNativeTransferLogs(cv, number, receipts)
+}
+
+// deriveNativeTransferLogs returns the synthetic logs of the native transfers
+// of the block at the given number, located after the logs of the given
+// receipts of the block.
+func deriveNativeTransferLogs(cv *ChainView, number uint64, receipts types.Receipts) []*types.Log {
+	logs := nativeTransferLogs(cv.NativeTransfers(number))
+	if len(logs) == 0 {
+		return nil
+	}
+	var (
+		hash    = cv.BlockHash(number)
+		time    uint64
+		indexes = make(map[common.Hash]uint, len(receipts))
+		index   uint
+	)
+	for _, receipt := range receipts {
+		indexes[receipt.TxHash] = receipt.TransactionIndex
+		index += uint(len(receipt.Logs))
+	}
+	if header := cv.Header(number); header != nil {
+		time = header.Time
+	}
+	for _, log := range logs {
+		log.BlockNumber = number
+		log.BlockHash = hash
+		log.BlockTimestamp = time
+		log.Index = index
+		index++
+
+		// The transfers made outside transactions are placed after them
+		if i, ok := indexes[log.TxHash]; ok {
+			log.TxIndex = i
+		} else {
+			log.TxIndex = uint(len(receipts))
+		}
+	}
+	return logs
+}
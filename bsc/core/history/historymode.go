@@ -32,10 +32,14 @@ const (
 
 	// KeepPostMerge sets the history pruning point to the merge activation block.
 	KeepPostMerge
+
+	// KeepRecent continuously prunes the bodies and receipts of the blocks older
+	// than a configured window, keeping all the headers.
+	KeepRecent
 )
 
 func (m HistoryMode) IsValid() bool {
-	return m <= KeepPostMerge
+	return m <= KeepRecent
 }
 
 func (m HistoryMode) String() string {
@@ -44,6 +48,8 @@ func (m HistoryMode) String() string {
 		return "all"
 	case KeepPostMerge:
 		return "postmerge"
+	case KeepRecent:
+		return "recent"
 	default:
 		return fmt.Sprintf("invalid HistoryMode(%d)", m)
 	}
@@ -64,8 +70,10 @@ func (m *HistoryMode) UnmarshalText(text []byte) error {
 		*m = KeepAll
 	case "postmerge":
 		*m = KeepPostMerge
+	case "recent":
+		*m = KeepRecent
 	default:
-		return fmt.Errorf(`unknown sync mode %q, want "all" or "postmerge"`, text)
+		return fmt.Errorf(`unknown sync mode %q, want "all", "postmerge" or "recent"`, text)
 	}
 	return nil
 }
@@ -0,0 +1,351 @@
+package core
+
+import (
+	"archive/tar"
+	"crypto/md5"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/pierrec/lz4/v4"
+)
+
+const (
+	incrMetadataFileName = "incr_metadata.json"
+	incrServeInterval    = time.Minute
+
+	// DefaultIncrServeKeptFiles is the default number of incremental snapshot
+	// files kept by the server.
+	DefaultIncrServeKeptFiles = 64
+)
+
+// incrServedFile is an incremental snapshot file published by the server.
+type incrServedFile struct {
+	metadata   IncrMetadata
+	startBlock uint64
+	modTime    time.Time
+}
+
+// IncrServer publishes incremental snapshots over HTTP, so that other nodes can
+// download and merge them with --incr.remote-url pointing to it. The completed
+// incremental directories generated by the node are packed into tar.lz4 files,
+// which are listed in incr_metadata.json the way the IncrDownloader expects and
+// served with support for ranged requests. Only the newest files are kept.
+type IncrServer struct {
+	incrPath  string // directory of the incremental snapshots generated by the node
+	servePath string // directory of the served tar.lz4 files
+	prefix    string // prefix of the served file names
+	keptFiles int
+
+	files []*incrServedFile // served files sorted by start block
+	lock  sync.RWMutex
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// NewIncrServer creates a server publishing the incremental snapshots of the
+// given directory, keeping the newest keptFiles files. The incrPath may be
+// empty if the served directory is filled externally.
+func NewIncrServer(incrPath, servePath, prefix string, keptFiles uint64) *IncrServer {
+	if keptFiles == 0 {
+		keptFiles = DefaultIncrServeKeptFiles
+	}
+	return &IncrServer{
+		incrPath:  incrPath,
+		servePath: servePath,
+		prefix:    prefix,
+		keptFiles: int(keptFiles),
+		quit:      make(chan struct{}),
+	}
+}
+
+// Start implements node.Lifecycle, starting the background packing of the
+// incremental snapshots.
+func (s *IncrServer) Start() error {
+	if err := os.MkdirAll(s.servePath, 0755); err != nil {
+		return fmt.Errorf("failed to create incr serve directory %s: %v", s.servePath, err)
+	}
+	s.update()
+
+	s.wg.Add(1)
+	go s.loop()
+
+	log.Info("Incremental snapshot server started", "incrPath", s.incrPath, "servePath", s.servePath, "keptFiles", s.keptFiles)
+	return nil
+}
+
+// Stop implements node.Lifecycle, terminating the background packing.
+func (s *IncrServer) Stop() error {
+	close(s.quit)
+	s.wg.Wait()
+	return nil
+}
+
+func (s *IncrServer) loop() {
+	defer s.wg.Done()
+
+	ticker := time.NewTicker(incrServeInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			s.update()
+		case <-s.quit:
+			return
+		}
+	}
+}
+
+// update packs the new completed incremental directories, prunes the old files
+// and refreshes the list of served files.
+func (s *IncrServer) update() {
+	if s.incrPath != "" {
+		if err := s.pack(); err != nil {
+			log.Warn("Failed to pack incremental snapshots", "err", err)
+		}
+	}
+	files, err := s.scan()
+	if err != nil {
+		log.Warn("Failed to list incremental snapshot files", "err", err)
+		return
+	}
+	if len(files) > s.keptFiles {
+		for _, file := range files[:len(files)-s.keptFiles] {
+			if err := os.Remove(filepath.Join(s.servePath, file.metadata.FileName)); err != nil {
+				log.Warn("Failed to prune incremental snapshot file", "file", file.metadata.FileName, "err", err)
+			} else {
+				log.Info("Pruned incremental snapshot file", "file", file.metadata.FileName)
+			}
+		}
+		files = files[len(files)-s.keptFiles:]
+	}
+	s.lock.Lock()
+	s.files = files
+	s.lock.Unlock()
+}
+
+// pack archives the newest completed incremental directories which aren't served
+// yet. The latest directory is still being written to, so it is skipped. Already
+// pruned files are not packed again.
+func (s *IncrServer) pack() error {
+	dirs, err := rawdb.GetAllIncrDirs(s.incrPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if len(dirs) < 2 {
+		return nil
+	}
+	s.lock.RLock()
+	var oldest uint64
+	if len(s.files) > 0 {
+		oldest = s.files[0].startBlock
+	}
+	s.lock.RUnlock()
+
+	completed := dirs[:len(dirs)-1]
+	if len(completed) > s.keptFiles {
+		completed = completed[len(completed)-s.keptFiles:]
+	}
+	for _, dir := range completed {
+		if dir.StartBlockNum < oldest {
+			continue
+		}
+		name := fmt.Sprintf("%s-incr-%d-%d.tar.lz4", s.prefix, dir.StartBlockNum, dir.EndBlockNum)
+		if _, err := os.Stat(filepath.Join(s.servePath, name)); err == nil {
+			continue
+		}
+		start := time.Now()
+		if err := packIncrDir(dir.Path, filepath.Join(s.servePath, name)); err != nil {
+			return fmt.Errorf("failed to pack %s: %v", dir.Name, err)
+		}
+		log.Info("Packed incremental snapshot", "file", name, "elapsed", time.Since(start))
+	}
+	return nil
+}
+
+// scan lists the incremental snapshot files in the served directory, reusing
+// the metadata of the already known files.
+func (s *IncrServer) scan() ([]*incrServedFile, error) {
+	entries, err := os.ReadDir(s.servePath)
+	if err != nil {
+		return nil, err
+	}
+	s.lock.RLock()
+	known := make(map[string]*incrServedFile, len(s.files))
+	for _, file := range s.files {
+		known[file.metadata.FileName] = file
+	}
+	s.lock.RUnlock()
+
+	var (
+		pattern = regexp.MustCompile("^" + incrSnapshotNamePattern + "$")
+		files   []*incrServedFile
+	)
+	for _, entry := range entries {
+		matches := pattern.FindStringSubmatch(entry.Name())
+		if entry.IsDir() || len(matches) != 4 {
+			continue
+		}
+		info, err := entry.Info()
+		if err != nil {
+			return nil, err
+		}
+		if file, ok := known[entry.Name()]; ok && file.modTime.Equal(info.ModTime()) && file.metadata.Size == uint64(info.Size()) {
+			files = append(files, file)
+			continue
+		}
+		startBlock, err := strconv.ParseUint(matches[2], 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid start block in %s: %v", entry.Name(), err)
+		}
+		sum, err := md5File(filepath.Join(s.servePath, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, &incrServedFile{
+			metadata: IncrMetadata{
+				FileName: entry.Name(),
+				MD5Sum:   sum,
+				Size:     uint64(info.Size()),
+			},
+			startBlock: startBlock,
+			modTime:    info.ModTime(),
+		})
+	}
+	sort.Slice(files, func(i, j int) bool {
+		return files[i].startBlock < files[j].startBlock
+	})
+	return files, nil
+}
+
+// ServeHTTP implements http.Handler, serving the metadata of the incremental
+// snapshots and the files themselves, with support for ranged requests.
+func (s *IncrServer) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodGet && r.Method != http.MethodHead {
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+		return
+	}
+	s.lock.RLock()
+	files := s.files
+	s.lock.RUnlock()
+
+	name := path.Base(r.URL.Path)
+	if name == incrMetadataFileName {
+		metadata := make([]IncrMetadata, 0, len(files))
+		for _, file := range files {
+			metadata = append(metadata, file.metadata)
+		}
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(metadata)
+		return
+	}
+	for _, file := range files {
+		if file.metadata.FileName != name {
+			continue
+		}
+		f, err := os.Open(filepath.Join(s.servePath, name))
+		if err != nil {
+			http.NotFound(w, r)
+			return
+		}
+		defer f.Close()
+
+		w.Header().Set("Content-Type", "application/octet-stream")
+		http.ServeContent(w, r, name, file.modTime, f)
+		return
+	}
+	http.NotFound(w, r)
+}
+
+// packIncrDir archives the incremental directory into a tar.lz4 file, with the
+// entries relative to the parent of the directory as extracted by the
+// IncrDownloader. The file is written to a temporary file first, so that a
+// partial archive is never served.
+func packIncrDir(dir, dest string) error {
+	tmp := dest + ".tmp"
+	out, err := os.Create(tmp)
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp)
+	defer out.Close()
+
+	var (
+		zw   = lz4.NewWriter(out)
+		tw   = tar.NewWriter(zw)
+		base = filepath.Dir(dir)
+	)
+	err = filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(base, path)
+		if err != nil {
+			return err
+		}
+		header, err := tar.FileInfoHeader(info, "")
+		if err != nil {
+			return err
+		}
+		header.Name = filepath.ToSlash(rel)
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		f, err := os.Open(path)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+
+		_, err = io.Copy(tw, f)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	if err := zw.Close(); err != nil {
+		return err
+	}
+	if err := out.Close(); err != nil {
+		return err
+	}
+	return os.Rename(tmp, dest)
+}
+
+// md5File returns the hex encoded md5 hash of the file.
+func md5File(name string) (string, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	h := md5.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
@@ -0,0 +1,92 @@
+package core
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	"github.com/ethereum/go-ethereum/triedb"
+)
+
+func TestIncrServer_ServeToDownloader(t *testing.T) {
+	// Create the incremental directories, the last one is still being written
+	incrPath := t.TempDir()
+	for _, dir := range []string{"incr-0-99", "incr-100-199", "incr-200-299", "incr-300-399"} {
+		require.NoError(t, os.MkdirAll(filepath.Join(incrPath, dir, "chain"), 0755))
+		require.NoError(t, os.WriteFile(filepath.Join(incrPath, dir, "chain", "data"), []byte(dir), 0644))
+	}
+	servePath := t.TempDir()
+	server := NewIncrServer(incrPath, servePath, "test", 2)
+	require.NoError(t, server.Start())
+	defer server.Stop()
+
+	// Only the newest completed directories are packed
+	entries, err := os.ReadDir(servePath)
+	require.NoError(t, err)
+	var names []string
+	for _, entry := range entries {
+		names = append(names, entry.Name())
+	}
+	assert.Equal(t, []string{"test-incr-100-199.tar.lz4", "test-incr-200-299.tar.lz4"}, names)
+
+	mux := http.NewServeMux()
+	mux.Handle("/incr/", server)
+	httpServer := httptest.NewServer(mux)
+	defer httpServer.Close()
+
+	db := createTestDB()
+	defer db.Close()
+	trieDB := triedb.NewDatabase(db, nil)
+	defer trieDB.Close()
+
+	downloadPath := t.TempDir()
+	downloader := NewIncrDownloader(db, trieDB, httpServer.URL+"/incr/", downloadPath, 150)
+
+	metadata, err := downloader.fetchMetadata()
+	require.NoError(t, err)
+	require.Len(t, metadata, 2)
+
+	files, err := downloader.parseFileInfo(metadata)
+	require.NoError(t, err)
+	require.Len(t, files, 2)
+
+	// Ranged downloads are supported
+	supported, size, err := downloader.checkRangeSupport(httpServer.URL + "/incr/" + files[0].Metadata.FileName)
+	require.NoError(t, err)
+	assert.True(t, supported)
+	assert.Equal(t, int64(files[0].Metadata.Size), size)
+
+	for _, file := range files {
+		require.NoError(t, downloader.downloadWithHTTP(file))
+		require.NoError(t, downloader.verifyAndExtract(file))
+	}
+	content, err := os.ReadFile(filepath.Join(downloadPath, "incr-200-299", "chain", "data"))
+	require.NoError(t, err)
+	assert.Equal(t, "incr-200-299", string(content))
+
+	// Unknown files are not served
+	resp, err := http.Get(httpServer.URL + "/incr/test-incr-0-99.tar.lz4")
+	require.NoError(t, err)
+	resp.Body.Close()
+	assert.Equal(t, http.StatusNotFound, resp.StatusCode)
+}
+
+func TestIncrServer_Prune(t *testing.T) {
+	servePath := t.TempDir()
+	for _, name := range []string{"test-incr-0-99.tar.lz4", "test-incr-100-199.tar.lz4", "test-incr-200-299.tar.lz4"} {
+		require.NoError(t, os.WriteFile(filepath.Join(servePath, name), []byte(name), 0644))
+	}
+	server := NewIncrServer("", servePath, "test", 2)
+	server.update()
+
+	_, err := os.Stat(filepath.Join(servePath, "test-incr-0-99.tar.lz4"))
+	assert.True(t, os.IsNotExist(err))
+	require.Len(t, server.files, 2)
+	assert.Equal(t, "test-incr-100-199.tar.lz4", server.files[0].metadata.FileName)
+	assert.Equal(t, uint64(len("test-incr-100-199.tar.lz4")), server.files[0].metadata.Size)
+}
@@ -0,0 +1,206 @@
+package core
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// NativeTransferRecorder collects the native transfers of a block from the
+// tracing hooks invoked while it is processed or built. Value transfers are
+// taken from the call frames and dropped with reverted frames and failed
+// transactions, all other movements are classified by their balance change
+// reason.
+type NativeTransferRecorder struct {
+	transfers []*types.NativeTransfer
+
+	tx     common.Hash    // Transaction being processed
+	sender common.Address // Sender of the transaction
+	frames []int          // First transfer made within each open call frame
+	call   [2]int         // Transfers made within the outermost call frame
+
+	paid     *big.Int       // Fee paid by the sender
+	credited *big.Int       // Fee credited to the fee recipient
+	feeTo    common.Address // Fee recipient
+
+	rewardFrom common.Address // Account the pending reward is paid out of
+	yieldFrom  common.Address // Account the pending yield settlement is paid out of
+}
+
+// NewNativeTransferRecorder creates an empty recorder.
+func NewNativeTransferRecorder() *NativeTransferRecorder {
+	return &NativeTransferRecorder{
+		paid:     new(big.Int),
+		credited: new(big.Int),
+	}
+}
+
+// Transfers returns the transfers recorded so far. The result is never nil, so
+// that a block without transfers is still stored as recorded.
+func (r *NativeTransferRecorder) Transfers() []*types.NativeTransfer {
+	if r.transfers == nil {
+		return []*types.NativeTransfer{}
+	}
+	return r.transfers
+}
+
+// Snapshot returns an identifier of the transfers recorded so far. It is meant
+// to be taken between transactions, along with the snapshot of the state.
+func (r *NativeTransferRecorder) Snapshot() int {
+	return len(r.transfers)
+}
+
+// RevertToSnapshot drops the transfers recorded since the given snapshot, for
+// the transactions reverted along with the state.
+func (r *NativeTransferRecorder) RevertToSnapshot(id int) {
+	r.transfers = r.transfers[:id]
+}
+
+// Hooks returns the tracing hooks of the recorder, chained after those of
+// inner, which may be nil.
+func (r *NativeTransferRecorder) Hooks(inner *tracing.Hooks) *tracing.Hooks {
+	hooks := new(tracing.Hooks)
+	if inner != nil {
+		*hooks = *inner
+	}
+	var (
+		onTxStart       = hooks.OnTxStart
+		onTxEnd         = hooks.OnTxEnd
+		onEnter         = hooks.OnEnter
+		onExit          = hooks.OnExit
+		onBalanceChange = hooks.OnBalanceChange
+	)
+	hooks.OnTxStart = func(vm *tracing.VMContext, tx *types.Transaction, from common.Address) {
+		if onTxStart != nil {
+			onTxStart(vm, tx, from)
+		}
+		r.onTxStart(tx, from)
+	}
+	hooks.OnTxEnd = func(receipt *types.Receipt, err error) {
+		if onTxEnd != nil {
+			onTxEnd(receipt, err)
+		}
+		r.onTxEnd(receipt, err)
+	}
+	hooks.OnEnter = func(depth int, typ byte, from common.Address, to common.Address, input []byte, gas uint64, value *big.Int) {
+		if onEnter != nil {
+			onEnter(depth, typ, from, to, input, gas, value)
+		}
+		r.onEnter(typ, from, to, value)
+	}
+	hooks.OnExit = func(depth int, output []byte, gasUsed uint64, err error, reverted bool) {
+		if onExit != nil {
+			onExit(depth, output, gasUsed, err, reverted)
+		}
+		r.onExit(reverted)
+	}
+	hooks.OnBalanceChange = func(addr common.Address, prev, next *big.Int, reason tracing.BalanceChangeReason) {
+		if onBalanceChange != nil {
+			onBalanceChange(addr, prev, next, reason)
+		}
+		r.onBalanceChange(addr, prev, next, reason)
+	}
+	return hooks
+}
+
+func (r *NativeTransferRecorder) add(kind types.NativeTransferKind, from, to common.Address, amount *big.Int) *types.NativeTransfer {
+	if amount.Sign() <= 0 {
+		return nil
+	}
+	transfer := &types.NativeTransfer{
+		Kind:   kind,
+		From:   from,
+		To:     to,
+		Amount: new(big.Int).Set(amount),
+		TxHash: r.tx,
+	}
+	r.transfers = append(r.transfers, transfer)
+	return transfer
+}
+
+func (r *NativeTransferRecorder) onTxStart(tx *types.Transaction, from common.Address) {
+	r.tx, r.sender, r.frames = tx.Hash(), from, r.frames[:0]
+	r.call = [2]int{len(r.transfers), len(r.transfers)}
+	r.paid.SetUint64(0)
+	r.credited.SetUint64(0)
+}
+
+func (r *NativeTransferRecorder) onTxEnd(receipt *types.Receipt, err error) {
+	if err != nil {
+		r.tx = common.Hash{}
+		return
+	}
+	if receipt.Status == types.ReceiptStatusFailed {
+		// The call of a failed transaction is reverted even if its frame did not
+		// report it, such as for a mint rejected after the call returned
+		r.transfers = append(r.transfers[:r.call[0]], r.transfers[r.call[1]:]...)
+	}
+	r.add(types.NativeTransferFee, r.sender, r.feeTo, r.credited)
+	r.add(types.NativeTransferBurn, r.sender, common.Address{}, new(big.Int).Sub(r.paid, r.credited))
+	r.tx = common.Hash{}
+}
+
+func (r *NativeTransferRecorder) onEnter(typ byte, from, to common.Address, value *big.Int) {
+	if len(r.frames) == 0 {
+		r.call[0] = len(r.transfers)
+	}
+	r.frames = append(r.frames, len(r.transfers))
+
+	switch vm.OpCode(typ) {
+	case vm.CALL, vm.CREATE, vm.CREATE2, vm.SELFDESTRUCT:
+		if value == nil {
+			break
+		}
+		// The outermost frame is the call of the transaction itself
+		if transfer := r.add(types.NativeTransferCall, from, to, value); transfer != nil && len(r.frames) > 1 {
+			transfer.Internal = true
+		}
+	}
+}
+
+func (r *NativeTransferRecorder) onExit(reverted bool) {
+	if len(r.frames) == 0 {
+		return
+	}
+	start := r.frames[len(r.frames)-1]
+	r.frames = r.frames[:len(r.frames)-1]
+	if reverted {
+		r.transfers = r.transfers[:start]
+	}
+	if len(r.frames) == 0 {
+		r.call[1] = len(r.transfers)
+	}
+}
+
+func (r *NativeTransferRecorder) onBalanceChange(addr common.Address, prev, next *big.Int, reason tracing.BalanceChangeReason) {
+	var (
+		increase = new(big.Int).Sub(next, prev)
+		decrease = new(big.Int).Neg(increase)
+	)
+	switch reason {
+	case tracing.BalanceIncreaseRewardMineBlock, tracing.BalanceIncreaseRewardMineUncle, tracing.BalanceIncreaseWithdrawal:
+		r.add(types.NativeTransferMint, common.Address{}, addr, increase)
+	case tracing.BalanceDecreaseSelfdestructBurn:
+		r.add(types.NativeTransferBurn, addr, common.Address{}, decrease)
+
+	case tracing.BalanceDecreaseGasBuy:
+		r.paid.Add(r.paid, decrease)
+	case tracing.BalanceIncreaseGasReturn:
+		r.paid.Sub(r.paid, increase)
+	case tracing.BalanceIncreaseRewardTransactionFee:
+		r.credited.Add(r.credited, increase)
+		r.feeTo = addr
+
+	case tracing.BalanceDecreaseBSCDistributeReward:
+		r.rewardFrom = addr
+	case tracing.BalanceIncreaseBSCDistributeReward, tracing.BalanceIncreaseYieldAccrual:
+		r.add(types.NativeTransferReward, r.rewardFrom, addr, increase)
+	case tracing.BalanceDecreaseYieldSettlement:
+		r.yieldFrom = addr
+	case tracing.BalanceIncreaseYieldSettlement:
+		r.add(types.NativeTransferReward, r.yieldFrom, addr, increase)
+	}
+}
@@ -0,0 +1,120 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/beacon"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// TestNativeTransfers tests that the native transfers of imported blocks are
+// recorded, leaving out the value of reverted calls.
+func TestNativeTransfers(t *testing.T) {
+	var (
+		key, _    = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr      = crypto.PubkeyToAddress(key.PublicKey)
+		recipient = common.HexToAddress("0xbeef")
+		reverter  = common.HexToAddress("0xdead")
+		coinbase  = common.HexToAddress("0xc0ffee")
+		config    = *params.MergedTestChainConfig
+		signer    = types.LatestSigner(&config)
+		gspec     = &Genesis{
+			Config: &config,
+			Alloc: types.GenesisAlloc{
+				addr:     {Balance: big.NewInt(1000000000000000000)},
+				reverter: {Code: []byte{0x5f, 0x5f, 0xfd}}, // revert(0, 0)
+			},
+		}
+		tip = big.NewInt(params.GWei)
+	)
+	_, blocks, receipts := GenerateChainWithGenesis(gspec, beacon.New(ethash.NewFaker()), 1, func(i int, b *BlockGen) {
+		b.SetCoinbase(coinbase)
+		price := new(big.Int).Add(b.BaseFee(), tip)
+		tx, _ := types.SignTx(types.NewTransaction(0, recipient, big.NewInt(100), params.TxGas, price, nil), signer, key)
+		b.AddTx(tx)
+		tx, _ = types.SignTx(types.NewTransaction(1, reverter, big.NewInt(5), 100000, price, nil), signer, key)
+		b.AddTx(tx)
+		b.AddWithdrawal(&types.Withdrawal{Address: recipient, Amount: 1})
+	})
+	cfg := DefaultConfig()
+	cfg.NativeTransfers = true
+	chain, err := NewBlockChain(rawdb.NewMemoryDatabase(), gspec, beacon.New(ethash.NewFaker()), cfg)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	block := blocks[0]
+	transfers, ok := chain.GetNativeTransfers(block.Hash(), block.NumberU64())
+	if !ok {
+		t.Fatal("native transfers not recorded")
+	}
+	fee := func(i int) *big.Int {
+		return new(big.Int).Mul(tip, new(big.Int).SetUint64(receipts[0][i].GasUsed))
+	}
+	burn := func(i int) *big.Int {
+		return new(big.Int).Mul(block.BaseFee(), new(big.Int).SetUint64(receipts[0][i].GasUsed))
+	}
+	txs := block.Transactions()
+	want := []*types.NativeTransfer{
+		{Kind: types.NativeTransferCall, From: addr, To: recipient, Amount: big.NewInt(100), TxHash: txs[0].Hash()},
+		{Kind: types.NativeTransferFee, From: addr, To: coinbase, Amount: fee(0), TxHash: txs[0].Hash()},
+		{Kind: types.NativeTransferBurn, From: addr, Amount: burn(0), TxHash: txs[0].Hash()},
+		{Kind: types.NativeTransferFee, From: addr, To: coinbase, Amount: fee(1), TxHash: txs[1].Hash()},
+		{Kind: types.NativeTransferBurn, From: addr, Amount: burn(1), TxHash: txs[1].Hash()},
+		{Kind: types.NativeTransferMint, To: recipient, Amount: big.NewInt(params.GWei)},
+	}
+	if len(transfers) != len(want) {
+		t.Fatalf("transfer count mismatch: have %d, want %d", len(transfers), len(want))
+	}
+	for i, transfer := range transfers {
+		if transfer.Kind != want[i].Kind || transfer.From != want[i].From || transfer.To != want[i].To ||
+			transfer.Amount.Cmp(want[i].Amount) != 0 || transfer.TxHash != want[i].TxHash || transfer.Internal != want[i].Internal {
+			t.Errorf("transfer %d: have %+v, want %+v", i, transfer, want[i])
+		}
+		if transfer.BlockHash != block.Hash() || transfer.Index != uint(i) {
+			t.Errorf("transfer %d: derived fields mismatch", i)
+		}
+	}
+
+	// Blocks sealed locally are written with the transfers recorded while they
+	// were built, they are not executed again
+	sealer, err := NewBlockChain(rawdb.NewMemoryDatabase(), gspec, beacon.New(ethash.NewFaker()), cfg)
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	defer sealer.Stop()
+	statedb, err := sealer.StateAt(sealer.Genesis().Root())
+	if err != nil {
+		t.Fatalf("failed to open genesis state: %v", err)
+	}
+	recorder := NewNativeTransferRecorder()
+	res, err := sealer.processor.Process(block, statedb, vm.Config{Tracer: recorder.Hooks(nil)})
+	if err != nil {
+		t.Fatalf("failed to process block: %v", err)
+	}
+	if _, err := sealer.WriteBlockAndSetHead(block, res.Receipts, res.Logs, recorder.Transfers(), statedb, nil); err != nil {
+		t.Fatalf("failed to write block: %v", err)
+	}
+	sealed, ok := sealer.GetNativeTransfers(block.Hash(), block.NumberU64())
+	if !ok {
+		t.Fatal("native transfers of sealed block not recorded")
+	}
+	if len(sealed) != len(transfers) {
+		t.Fatalf("sealed transfer count mismatch: have %d, want %d", len(sealed), len(transfers))
+	}
+	for i := range sealed {
+		if sealed[i].Kind != transfers[i].Kind || sealed[i].Amount.Cmp(transfers[i].Amount) != 0 {
+			t.Errorf("sealed transfer %d: have %+v, want %+v", i, sealed[i], transfers[i])
+		}
+	}
+}
@@ -0,0 +1,226 @@
+package oracle
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+	blscommon "github.com/prysmaticlabs/prysm/v5/crypto/bls/common"
+
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+func TestParsePrice(t *testing.T) {
+	tests := []struct {
+		input string
+		want  string
+	}{
+		{"2650", "2650000000000000000000"},
+		{"2650.5", "2650500000000000000000"},
+		{"0.000000000000000001", "1"},
+		{"1.0000000000000000019", "1000000000000000001"},
+		{"", ""},
+		{".5", ""},
+		{"-1", ""},
+		{"1e3", ""},
+		{"0.0", ""},
+	}
+	for _, tt := range tests {
+		price, err := parsePrice(tt.input)
+		if tt.want == "" {
+			if err == nil {
+				t.Errorf("%q: expected error, got %v", tt.input, price)
+			}
+			continue
+		}
+		if err != nil || price.String() != tt.want {
+			t.Errorf("%q: have %v (err %v), want %s", tt.input, price, err, tt.want)
+		}
+	}
+}
+
+func TestHTTPSource(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/number":
+			w.Write([]byte(`{"data":{"price":2650.25}}`))
+		case "/string":
+			w.Write([]byte(`{"price":"2650.25"}`))
+		default:
+			http.NotFound(w, r)
+		}
+	}))
+	defer server.Close()
+
+	want, _ := new(big.Int).SetString("2650250000000000000000", 10)
+	for _, source := range []*HTTPSource{
+		NewHTTPSource(server.URL+"/number", "data.price"),
+		NewHTTPSource(server.URL+"/string", "price"),
+	} {
+		price, err := source.Price(context.Background())
+		if err != nil || price.Cmp(want) != 0 {
+			t.Errorf("%s: have %v (err %v), want %v", source.url, price, err, want)
+		}
+	}
+	if _, err := NewHTTPSource(server.URL+"/string", "data.price").Price(context.Background()); err == nil {
+		t.Error("expected error for missing field")
+	}
+	if _, err := NewHTTPSource(server.URL+"/missing", "price").Price(context.Background()); err == nil {
+		t.Error("expected error for failed request")
+	}
+}
+
+// testChainID is the chain the test reports are signed for.
+var testChainID = params.AllDevChainProtocolChanges.ChainID
+
+// testEngine accepts the reports of the known validators for rounds from the
+// current one on.
+type testEngine struct {
+	validators map[types.BLSPublicKey]bool
+	round      uint64
+}
+
+func (e *testEngine) VerifyPriceReport(_ consensus.ChainHeaderReader, report *types.PriceReport) error {
+	if !e.validators[report.VoteAddress] {
+		return errors.New("unknown validator")
+	}
+	if report.Round < e.round {
+		return errors.New("stale round")
+	}
+	return report.Verify(testChainID)
+}
+
+// testSigner signs price reports with a plain BLS key.
+type testSigner struct {
+	key blscommon.SecretKey
+}
+
+func newTestSigner(t *testing.T) *testSigner {
+	key, err := bls.RandKey()
+	if err != nil {
+		t.Fatalf("failed to create BLS key: %v", err)
+	}
+	return &testSigner{key}
+}
+
+func (s *testSigner) SignPriceReport(report *types.PriceReport, chainID *big.Int) error {
+	hash := report.SigningHash(chainID)
+	copy(report.VoteAddress[:], s.key.PublicKey().Marshal())
+	copy(report.Signature[:], s.key.Sign(hash[:]).Marshal())
+	return nil
+}
+
+func (s *testSigner) report(round uint64, price int64) *types.PriceReport {
+	report := &types.PriceReport{Round: round, Price: big.NewInt(price)}
+	s.SignPriceReport(report, testChainID)
+	return report
+}
+
+func TestPool(t *testing.T) {
+	var (
+		signer   = newTestSigner(t)
+		outsider = newTestSigner(t)
+		engine   = &testEngine{validators: map[types.BLSPublicKey]bool{}}
+		pool     = NewPool(nil, engine)
+	)
+	defer pool.Close()
+	engine.validators[types.BLSPublicKey(signer.key.PublicKey().Marshal())] = true
+
+	ch := make(chan core.NewPriceReportEvent, 8)
+	sub := pool.SubscribeNewPriceReportEvent(ch)
+	defer sub.Unsubscribe()
+
+	corrupt := signer.report(1, 100)
+	corrupt.Price = big.NewInt(101)
+	replayed := &types.PriceReport{Round: 1, Price: big.NewInt(100)}
+	signer.SignPriceReport(replayed, big.NewInt(97))
+
+	pool.PutReport(replayed) // signed for another chain
+	pool.PutReport(signer.report(1, 100))
+	pool.PutReport(signer.report(1, 100))   // duplicate
+	pool.PutReport(outsider.report(1, 100)) // unknown validator
+	pool.PutReport(corrupt)                 // invalid signature
+	if len(ch) != 1 || len(pool.PriceReports(1)) != 1 {
+		t.Fatalf("pooled reports mismatch: announced %d, pooled %d", len(ch), len(pool.PriceReports(1)))
+	}
+	// Reports of old rounds are pruned as newer ones arrive
+	pool.PutReport(signer.report(2, 100))
+	pool.PutReport(signer.report(3, 100))
+	if len(pool.PriceReports(1)) != 0 || len(pool.PriceReports(2)) != 1 || len(pool.PriceReports(3)) != 1 {
+		t.Fatal("stale reports not pruned")
+	}
+	pool.PutReport(signer.report(1, 200))
+	if len(pool.PriceReports(1)) != 0 {
+		t.Fatal("stale report accepted")
+	}
+}
+
+// testChain feeds chain head events to the reporter.
+type testChain struct {
+	config *params.ChainConfig
+	feed   event.Feed
+}
+
+func (c *testChain) Config() *params.ChainConfig { return c.config }
+
+func (c *testChain) SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription {
+	return c.feed.Subscribe(ch)
+}
+
+type staticSource struct{ price *big.Int }
+
+func (s *staticSource) Price(context.Context) (*big.Int, error) { return s.price, nil }
+
+func TestReporter(t *testing.T) {
+	var (
+		signer = newTestSigner(t)
+		engine = &testEngine{validators: map[types.BLSPublicKey]bool{types.BLSPublicKey(signer.key.PublicKey().Marshal()): true}}
+		pool   = NewPool(nil, engine)
+		config = *params.AllDevChainProtocolChanges
+		chain  = &testChain{config: &config}
+		price  = big.NewInt(2650)
+	)
+	mendel := uint64(0)
+	config.MendelTime = &mendel
+
+	ch := make(chan core.NewPriceReportEvent, 8)
+	sub := pool.SubscribeNewPriceReportEvent(ch)
+	defer sub.Unsubscribe()
+
+	reporter := NewReporter(chain, pool, &staticSource{price}, signer)
+	defer reporter.Stop()
+
+	// Wait for the reporter to subscribe, then feed a few heads of one round
+	for chain.feed.Send(core.ChainHeadEvent{Header: &types.Header{Number: big.NewInt(0)}}) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	for number := int64(1); number < 5; number++ {
+		chain.feed.Send(core.ChainHeadEvent{Header: &types.Header{Number: big.NewInt(number)}})
+	}
+	chain.feed.Send(core.ChainHeadEvent{Header: &types.Header{Number: new(big.Int).SetUint64(params.PriceOracleInterval - 1)}})
+
+	for round := uint64(0); round < 2; round++ {
+		select {
+		case ev := <-ch:
+			if ev.Report.Round != round || ev.Report.Price.Cmp(price) != 0 {
+				t.Fatalf("report mismatch: have round %d price %v, want round %d price %v", ev.Report.Round, ev.Report.Price, round, price)
+			}
+		case <-time.After(time.Second):
+			t.Fatalf("round %d not reported", round)
+		}
+	}
+	select {
+	case ev := <-ch:
+		t.Fatalf("unexpected report of round %d", ev.Report.Round)
+	case <-time.After(50 * time.Millisecond):
+	}
+}
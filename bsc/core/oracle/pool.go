@@ -0,0 +1,104 @@
+package oracle
+
+import (
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Engine is the consensus engine checking that price reports are signed by the
+// active validators for a current round.
+type Engine interface {
+	VerifyPriceReport(chain consensus.ChainHeaderReader, report *types.PriceReport) error
+}
+
+// Pool collects the price reports of the validators, grouped by round. Only
+// verified reports enter the pool, every one of them is announced once to be
+// gossiped to the peers.
+type Pool struct {
+	chain  consensus.ChainHeaderReader
+	engine Engine
+
+	reports map[uint64]map[types.BLSPublicKey]*types.PriceReport // Reports by round and validator
+	latest  uint64                                               // Latest round with pooled reports
+	mu      sync.RWMutex
+
+	feed  event.Feed
+	scope event.SubscriptionScope
+}
+
+// NewPool creates a price report pool verifying the reports with the engine.
+func NewPool(chain consensus.ChainHeaderReader, engine Engine) *Pool {
+	return &Pool{
+		chain:   chain,
+		engine:  engine,
+		reports: make(map[uint64]map[types.BLSPublicKey]*types.PriceReport),
+	}
+}
+
+// PutReport adds a report to the pool if it is valid and not yet known,
+// announcing it to the subscribers.
+func (p *Pool) PutReport(report *types.PriceReport) {
+	p.mu.RLock()
+	_, known := p.reports[report.Round][report.VoteAddress]
+	stale := report.Round+1 < p.latest
+	p.mu.RUnlock()
+
+	if known || stale {
+		return
+	}
+	if err := p.engine.VerifyPriceReport(p.chain, report); err != nil {
+		log.Debug("Dropping invalid price report", "round", report.Round, "validator", common.Bytes2Hex(report.VoteAddress[:]), "err", err)
+		return
+	}
+	p.mu.Lock()
+	reports, ok := p.reports[report.Round]
+	if !ok {
+		reports = make(map[types.BLSPublicKey]*types.PriceReport)
+		p.reports[report.Round] = reports
+	}
+	if _, known = reports[report.VoteAddress]; !known {
+		reports[report.VoteAddress] = report
+	}
+	if report.Round > p.latest {
+		p.latest = report.Round
+		for round := range p.reports {
+			if round+1 < p.latest {
+				delete(p.reports, round)
+			}
+		}
+	}
+	p.mu.Unlock()
+
+	if !known {
+		p.feed.Send(core.NewPriceReportEvent{Report: report})
+	}
+}
+
+// PriceReports returns the pooled reports of the round.
+func (p *Pool) PriceReports(round uint64) []*types.PriceReport {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+
+	reports := make([]*types.PriceReport, 0, len(p.reports[round]))
+	for _, report := range p.reports[round] {
+		reports = append(reports, report)
+	}
+	return reports
+}
+
+// SubscribeNewPriceReportEvent registers a subscription of the reports entering
+// the pool.
+func (p *Pool) SubscribeNewPriceReportEvent(ch chan<- core.NewPriceReportEvent) event.Subscription {
+	return p.scope.Track(p.feed.Subscribe(ch))
+}
+
+// Close terminates all subscriptions.
+func (p *Pool) Close() {
+	p.scope.Close()
+}
@@ -0,0 +1,113 @@
+package oracle
+
+import (
+	"context"
+	"math/big"
+	"time"
+
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+const (
+	// chainHeadChanSize is the size of channel listening to ChainHeadEvent.
+	chainHeadChanSize = 10
+
+	// fetchTimeout is the time allowed to fetch the price from the source.
+	fetchTimeout = 10 * time.Second
+)
+
+// Chain is the local blockchain followed by the reporter.
+type Chain interface {
+	Config() *params.ChainConfig
+	SubscribeChainHeadEvent(ch chan<- core.ChainHeadEvent) event.Subscription
+}
+
+// Signer signs the price reports of the local validator.
+type Signer interface {
+	SignPriceReport(report *types.PriceReport, chainID *big.Int) error
+}
+
+// Reporter reports the price of the source once per oracle round, as soon as
+// the round starts, to the price report pool.
+type Reporter struct {
+	chain  Chain
+	pool   *Pool
+	source Source
+	signer Signer
+
+	reported uint64 // Next round to report
+	quit     chan struct{}
+	done     chan struct{}
+}
+
+// NewReporter creates a price reporter and starts its event loop.
+func NewReporter(chain Chain, pool *Pool, source Source, signer Signer) *Reporter {
+	r := &Reporter{
+		chain:  chain,
+		pool:   pool,
+		source: source,
+		signer: signer,
+		quit:   make(chan struct{}),
+		done:   make(chan struct{}),
+	}
+	go r.loop()
+	return r
+}
+
+// Stop terminates the reporter.
+func (r *Reporter) Stop() {
+	close(r.quit)
+	<-r.done
+}
+
+func (r *Reporter) loop() {
+	defer close(r.done)
+
+	headCh := make(chan core.ChainHeadEvent, chainHeadChanSize)
+	headSub := r.chain.SubscribeChainHeadEvent(headCh)
+	defer headSub.Unsubscribe()
+
+	for {
+		select {
+		case ev := <-headCh:
+			next := new(big.Int).Add(ev.Header.Number, big.NewInt(1))
+			if !r.chain.Config().IsMendel(next, ev.Header.Time) {
+				continue
+			}
+			if round := next.Uint64() / params.PriceOracleInterval; round >= r.reported {
+				if err := r.report(round); err != nil {
+					log.Warn("Failed to report price", "round", round, "err", err)
+					continue
+				}
+				r.reported = round + 1
+			}
+		case <-headSub.Err():
+			return
+		case <-r.quit:
+			return
+		}
+	}
+}
+
+// report fetches the current price and puts the signed report of the round
+// into the pool.
+func (r *Reporter) report(round uint64) error {
+	ctx, cancel := context.WithTimeout(context.Background(), fetchTimeout)
+	defer cancel()
+
+	price, err := r.source.Price(ctx)
+	if err != nil {
+		return err
+	}
+	report := &types.PriceReport{Round: round, Price: price}
+	if err := r.signer.SignPriceReport(report, r.chain.Config().ChainID); err != nil {
+		return err
+	}
+	log.Debug("Reporting price", "round", round, "price", price)
+	r.pool.PutReport(report)
+	return nil
+}
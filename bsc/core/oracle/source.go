@@ -0,0 +1,98 @@
+// Package oracle implements the validator side of the price oracle: fetching
+// the price from the configured source, signing it with the vote key and
+// pooling the reports of all validators for the block proposer.
+package oracle
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// priceDecimals is the number of decimals of the fixed point reported prices.
+const priceDecimals = 18
+
+// Source is a provider of the price reported by the validator.
+type Source interface {
+	// Price returns the current price as a fixed point number with 18 decimals.
+	Price(ctx context.Context) (*big.Int, error)
+}
+
+// HTTPSource fetches the price from a JSON object served over HTTP. The price
+// is read from a field of the object, either as a number or a decimal string.
+type HTTPSource struct {
+	url    string
+	field  string
+	client *http.Client
+}
+
+// NewHTTPSource creates a source reading the price from the given field of the
+// JSON object served at url. Nested fields are separated by dots.
+func NewHTTPSource(url, field string) *HTTPSource {
+	return &HTTPSource{
+		url:    url,
+		field:  field,
+		client: &http.Client{Timeout: 10 * time.Second},
+	}
+}
+
+// Price implements Source.
+func (s *HTTPSource) Price(ctx context.Context) (*big.Int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, s.url, nil)
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Accept", "application/json")
+	res, err := s.client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("price source returned status %s", res.Status)
+	}
+	body, err := io.ReadAll(io.LimitReader(res.Body, 1<<20))
+	if err != nil {
+		return nil, err
+	}
+	value := json.RawMessage(body)
+	for _, name := range strings.Split(s.field, ".") {
+		if name == "" {
+			continue
+		}
+		var object map[string]json.RawMessage
+		if err := json.Unmarshal(value, &object); err != nil {
+			return nil, fmt.Errorf("invalid price source response: %v", err)
+		}
+		var ok bool
+		if value, ok = object[name]; !ok {
+			return nil, fmt.Errorf("price source response without field %q", s.field)
+		}
+	}
+	return parsePrice(strings.Trim(string(value), "\" \t\r\n"))
+}
+
+// parsePrice converts a positive decimal number into a fixed point number with
+// 18 decimals, truncating any further decimals.
+func parsePrice(s string) (*big.Int, error) {
+	whole, frac, _ := strings.Cut(s, ".")
+	if len(frac) > priceDecimals {
+		frac = frac[:priceDecimals]
+	}
+	digits := whole + frac + strings.Repeat("0", priceDecimals-len(frac))
+	if whole == "" || strings.TrimLeft(digits, "0123456789") != "" {
+		return nil, fmt.Errorf("invalid price %q", s)
+	}
+	price, _ := new(big.Int).SetString(digits, 10)
+	if price.Sign() == 0 {
+		return nil, errors.New("zero price")
+	}
+	return price, nil
+}
@@ -0,0 +1,150 @@
+package rawdb
+
+import (
+	"encoding/binary"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// AddressIndexRange is the range of blocks whose transactions are indexed by
+// address. The hash of the last indexed block is tracked to detect reorgs.
+type AddressIndexRange struct {
+	Tail     uint64      // Number of the oldest indexed block
+	Head     uint64      // Number of the latest indexed block
+	HeadHash common.Hash // Hash of the latest indexed block
+}
+
+// AddressTx is a transaction found in the address index.
+type AddressTx struct {
+	Number uint64      // Number of the block containing the transaction
+	Index  uint32      // Index of the transaction within the block
+	Hash   common.Hash // Hash of the transaction
+}
+
+// ReadAddressIndexRange retrieves the range of blocks indexed by address, nil
+// if nothing has been indexed yet.
+func ReadAddressIndexRange(db ethdb.KeyValueReader) *AddressIndexRange {
+	data, _ := db.Get(addressIndexRangeKey)
+	if len(data) == 0 {
+		return nil
+	}
+	var r AddressIndexRange
+	if err := rlp.DecodeBytes(data, &r); err != nil {
+		log.Error("Invalid address index range", "err", err)
+		return nil
+	}
+	return &r
+}
+
+// WriteAddressIndexRange stores the range of blocks indexed by address.
+func WriteAddressIndexRange(db ethdb.KeyValueWriter, r AddressIndexRange) {
+	data, err := rlp.EncodeToBytes(&r)
+	if err != nil {
+		log.Crit("Failed to encode address index range", "err", err)
+	}
+	if err := db.Put(addressIndexRangeKey, data); err != nil {
+		log.Crit("Failed to store address index range", "err", err)
+	}
+}
+
+// DeleteAddressIndexRange removes the range of blocks indexed by address.
+func DeleteAddressIndexRange(db ethdb.KeyValueWriter) {
+	if err := db.Delete(addressIndexRangeKey); err != nil {
+		log.Crit("Failed to delete address index range", "err", err)
+	}
+}
+
+// WriteAddressTx stores a transaction sent or received by the given address.
+func WriteAddressTx(db ethdb.KeyValueWriter, address common.Address, number uint64, index uint32, hash common.Hash) {
+	if err := db.Put(addressTxKey(address, number, index), hash.Bytes()); err != nil {
+		log.Crit("Failed to store address transaction", "err", err)
+	}
+}
+
+// DeleteAddressTx removes a transaction sent or received by the given address.
+func DeleteAddressTx(db ethdb.KeyValueWriter, address common.Address, number uint64, index uint32) {
+	if err := db.Delete(addressTxKey(address, number, index)); err != nil {
+		log.Crit("Failed to delete address transaction", "err", err)
+	}
+}
+
+// WriteAddressTransfer stores a transaction transferring the given token from
+// or to the given address. The zero token address denotes the native coin.
+func WriteAddressTransfer(db ethdb.KeyValueWriter, address common.Address, token common.Address, number uint64, index uint32, hash common.Hash) {
+	if err := db.Put(addressTransferKey(address, token, number, index), hash.Bytes()); err != nil {
+		log.Crit("Failed to store address transfer", "err", err)
+	}
+}
+
+// DeleteAddressTransfer removes a transaction transferring the given token from
+// or to the given address.
+func DeleteAddressTransfer(db ethdb.KeyValueWriter, address common.Address, token common.Address, number uint64, index uint32) {
+	if err := db.Delete(addressTransferKey(address, token, number, index)); err != nil {
+		log.Crit("Failed to delete address transfer", "err", err)
+	}
+}
+
+// ReadAddressTxs retrieves at most limit transactions of the given address from
+// the most recent, starting at the given position. If token is specified, only
+// the transactions transferring the token are returned. The position of the
+// following transaction is returned as well, nil if there is none.
+func ReadAddressTxs(db ethdb.Iteratee, address common.Address, token *common.Address, start []byte, limit int) ([]AddressTx, []byte) {
+	var prefix []byte
+	if token == nil {
+		prefix = append(append([]byte{}, AddressTxPrefix...), address.Bytes()...)
+	} else {
+		prefix = append(append(append([]byte{}, AddressTransferPrefix...), address.Bytes()...), token.Bytes()...)
+	}
+	it := NewKeyLengthIterator(db.NewIterator(prefix, start), len(prefix)+12)
+	defer it.Release()
+
+	var txs []AddressTx
+	for it.Next() {
+		pos := it.Key()[len(prefix):]
+		if len(txs) == limit {
+			return txs, common.CopyBytes(pos)
+		}
+		txs = append(txs, AddressTx{
+			Number: ^binary.BigEndian.Uint64(pos),
+			Index:  ^binary.BigEndian.Uint32(pos[8:]),
+			Hash:   common.BytesToHash(it.Value()),
+		})
+	}
+	return txs, nil
+}
+
+// DeleteAllAddressIndexes removes the address index entries of the blocks
+// matching the given condition, or all the entries if no condition is given.
+func DeleteAllAddressIndexes(db ethdb.KeyValueStore, condition func(number uint64) bool) {
+	batch := db.NewBatch()
+	for _, prefix := range []struct {
+		prefix []byte
+		length int
+	}{
+		{AddressTxPrefix, len(AddressTxPrefix) + common.AddressLength + 12},
+		{AddressTransferPrefix, len(AddressTransferPrefix) + 2*common.AddressLength + 12},
+	} {
+		iter := NewKeyLengthIterator(db.NewIterator(prefix.prefix, nil), prefix.length)
+		for iter.Next() {
+			key := iter.Key()
+			if condition == nil || condition(^binary.BigEndian.Uint64(key[len(key)-12:])) {
+				batch.Delete(key)
+			}
+			if batch.ValueSize() >= ethdb.IdealBatchSize {
+				if err := batch.Write(); err != nil {
+					log.Crit("Failed to delete address indexes", "err", err)
+				}
+				batch.Reset()
+			}
+		}
+		iter.Release()
+	}
+	if batch.ValueSize() > 0 {
+		if err := batch.Write(); err != nil {
+			log.Crit("Failed to delete address indexes", "err", err)
+		}
+	}
+}
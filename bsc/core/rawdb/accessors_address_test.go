@@ -0,0 +1,97 @@
+package rawdb
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+func TestAddressTxs(t *testing.T) {
+	var (
+		db    = NewMemoryDatabase()
+		addr  = common.Address{0x1}
+		other = common.Address{0x2}
+		token = common.Address{0x3}
+	)
+	if r := ReadAddressIndexRange(db); r != nil {
+		t.Fatalf("unexpected range: %v", r)
+	}
+	WriteAddressIndexRange(db, AddressIndexRange{Tail: 1, Head: 3, HeadHash: common.Hash{0x3}})
+	if r := ReadAddressIndexRange(db); r == nil || *r != (AddressIndexRange{Tail: 1, Head: 3, HeadHash: common.Hash{0x3}}) {
+		t.Fatalf("unexpected range: %v", r)
+	}
+	for number := uint64(1); number <= 3; number++ {
+		for index := uint32(0); index < 2; index++ {
+			hash := common.Hash{byte(number), byte(index)}
+			WriteAddressTx(db, addr, number, index, hash)
+			WriteAddressTx(db, other, number, index, hash)
+			if index == 1 {
+				WriteAddressTransfer(db, addr, token, number, index, hash)
+			}
+		}
+	}
+	// The index must not be mistaken for the state data
+	for _, prefix := range [][]byte{SnapshotAccountPrefix, TrieNodeAccountPrefix} {
+		it := db.NewIterator(prefix, nil)
+		if it.Next() {
+			t.Fatalf("address index overlaps the state prefix %q: %x", prefix, it.Key())
+		}
+		it.Release()
+	}
+	// Transactions are paginated from the most recent
+	var (
+		start []byte
+		all   []AddressTx
+	)
+	for {
+		txs, next := ReadAddressTxs(db, addr, nil, start, 4)
+		all = append(all, txs...)
+		if next == nil {
+			break
+		}
+		start = next
+	}
+	if len(all) != 6 {
+		t.Fatalf("unexpected transaction count: have %d, want 6", len(all))
+	}
+	for i, tx := range all {
+		number, index := uint64(3-i/2), uint32(1-i%2)
+		if tx.Number != number || tx.Index != index || tx.Hash != (common.Hash{byte(number), byte(index)}) {
+			t.Fatalf("transaction %d: unexpected entry %v", i, tx)
+		}
+	}
+	txs, next := ReadAddressTxs(db, addr, &token, nil, 10)
+	if len(txs) != 3 || next != nil {
+		t.Fatalf("unexpected token transfers: %v, next %x", txs, next)
+	}
+	if txs, _ := ReadAddressTxs(db, addr, &other, nil, 10); len(txs) != 0 {
+		t.Fatalf("unexpected transfers of other token: %v", txs)
+	}
+	// Deleted entries are skipped
+	DeleteAddressTx(db, addr, 3, 1)
+	DeleteAddressTransfer(db, addr, token, 3, 1)
+	if txs, _ := ReadAddressTxs(db, addr, nil, nil, 1); txs[0].Number != 3 || txs[0].Index != 0 {
+		t.Fatalf("unexpected latest transaction: %v", txs[0])
+	}
+	if txs, _ := ReadAddressTxs(db, addr, &token, nil, 1); txs[0].Number != 2 {
+		t.Fatalf("unexpected latest transfer: %v", txs[0])
+	}
+	// Entries are deleted by block number
+	DeleteAllAddressIndexes(db, func(number uint64) bool { return number < 3 })
+	if txs, _ := ReadAddressTxs(db, other, nil, nil, 10); len(txs) != 2 || txs[1].Number != 3 {
+		t.Fatalf("unexpected transactions after pruning: %v", txs)
+	}
+	DeleteAllAddressIndexes(db, nil)
+	DeleteAddressIndexRange(db)
+	if r := ReadAddressIndexRange(db); r != nil {
+		t.Fatalf("unexpected range after deletion: %v", r)
+	}
+	it := db.NewIterator(nil, nil)
+	defer it.Release()
+	for it.Next() {
+		if bytes.HasPrefix(it.Key(), AddressTxPrefix) || bytes.HasPrefix(it.Key(), AddressTransferPrefix) {
+			t.Fatalf("address index left after deletion: %x", it.Key())
+		}
+	}
+}
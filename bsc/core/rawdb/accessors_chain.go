@@ -19,6 +19,7 @@ package rawdb
 import (
 	"bytes"
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"math/big"
 	"slices"
@@ -992,6 +993,68 @@ func DeleteBAL(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
 	}
 }
 
+// ReadNativeTransfers retrieves the native transfers recorded for a block. The
+// transfers are only in the key-value store, they are kept when the block is
+// moved to the ancient store.
+func ReadNativeTransfers(db ethdb.KeyValueReader, hash common.Hash, number uint64) []*types.NativeTransfer {
+	data, _ := db.Get(nativeTransfersKey(number, hash))
+	if len(data) == 0 {
+		return nil
+	}
+	var transfers []*types.NativeTransfer
+	if err := rlp.DecodeBytes(data, &transfers); err != nil {
+		log.Error("Invalid native transfers RLP", "hash", hash, "err", err)
+		return nil
+	}
+	types.DeriveNativeTransferFields(transfers, hash, number)
+	return transfers
+}
+
+// HasNativeTransfers verifies the existence of the native transfers recorded
+// for a block.
+func HasNativeTransfers(db ethdb.KeyValueReader, hash common.Hash, number uint64) bool {
+	has, _ := db.Has(nativeTransfersKey(number, hash))
+	return has
+}
+
+// WriteNativeTransfers stores the native transfers recorded for a block.
+func WriteNativeTransfers(db ethdb.KeyValueWriter, hash common.Hash, number uint64, transfers []*types.NativeTransfer) {
+	data, err := rlp.EncodeToBytes(transfers)
+	if err != nil {
+		log.Crit("Failed to encode native transfers", "err", err)
+	}
+	if err := db.Put(nativeTransfersKey(number, hash), data); err != nil {
+		log.Crit("Failed to store native transfers", "err", err)
+	}
+}
+
+// DeleteNativeTransfers removes the native transfers recorded for a block.
+func DeleteNativeTransfers(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
+	if err := db.Delete(nativeTransfersKey(number, hash)); err != nil {
+		log.Crit("Failed to delete native transfers", "err", err)
+	}
+}
+
+// DeleteNativeTransfersBelow removes the native transfers recorded for all the
+// blocks below the given number, once their history is pruned.
+func DeleteNativeTransfersBelow(db ethdb.KeyValueRangeDeleter, number uint64) {
+	start := NativeTransfersPrefix
+	limit := nativeTransfersKey(number, common.Hash{})
+
+	// Try to remove the data in the range by a loop, as the leveldb
+	// doesn't support the native range deletion.
+	for {
+		err := db.DeleteRange(start, limit)
+		if err == nil {
+			return
+		}
+		if errors.Is(err, ethdb.ErrTooManyKeys) {
+			continue
+		}
+		log.Crit("Failed to delete native transfers", "err", err)
+	}
+}
+
 // WriteAncientHeaderChain writes the supplied headers along with nil block
 // bodies and receipts into the ancient store. It's supposed to be used for
 // storing chain segment before the chain cutoff.
@@ -1035,6 +1098,7 @@ func DeleteBlock(db ethdb.KeyValueWriter, hash common.Hash, number uint64) {
 	DeleteTd(db, hash, number)
 	DeleteBlobSidecars(db, hash, number) // it is safe to delete non-exist blob
 	DeleteBAL(db, hash, number)
+	DeleteNativeTransfers(db, hash, number)
 }
 
 // DeleteBlockWithoutNumber removes all block data associated with a hash, except
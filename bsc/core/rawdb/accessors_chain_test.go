@@ -1495,3 +1495,20 @@ func balEqual(a, b *types.BlockAccessListEncode) bool {
 	}
 	return true
 }
+
+// Tests that pruning the native transfers drops exactly the blocks below the
+// new history tail.
+func TestDeleteNativeTransfersBelow(t *testing.T) {
+	db := NewMemoryDatabase()
+	for number := uint64(1); number <= 4; number++ {
+		transfers := []*types.NativeTransfer{{Kind: types.NativeTransferCall, Amount: big.NewInt(int64(number))}}
+		WriteNativeTransfers(db, common.Hash{byte(number)}, number, transfers)
+	}
+	DeleteNativeTransfersBelow(db, 3)
+
+	for number := uint64(1); number <= 4; number++ {
+		if have, want := HasNativeTransfers(db, common.Hash{byte(number)}, number), number >= 3; have != want {
+			t.Errorf("block %d: native transfers kept %t, want %t", number, have, want)
+		}
+	}
+}
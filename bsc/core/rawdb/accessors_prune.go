@@ -0,0 +1,41 @@
+package rawdb
+
+import (
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// StatePruneProgress is the persisted progress of the online state pruning,
+// allowing an interrupted run to be resumed after a restart.
+type StatePruneProgress struct {
+	Running  bool   // Whether a run was in progress
+	Cursor   []byte // Key from which the interrupted run continues
+	Finished uint64 // Unix time of the last completed run, 0 if none
+}
+
+// ReadStatePruneProgress retrieves the progress of the online state pruning,
+// nil if it never ran.
+func ReadStatePruneProgress(db ethdb.KeyValueReader) *StatePruneProgress {
+	data, _ := db.Get(statePruneProgressKey)
+	if len(data) == 0 {
+		return nil
+	}
+	var progress StatePruneProgress
+	if err := rlp.DecodeBytes(data, &progress); err != nil {
+		log.Error("Invalid state prune progress", "err", err)
+		return nil
+	}
+	return &progress
+}
+
+// WriteStatePruneProgress stores the progress of the online state pruning.
+func WriteStatePruneProgress(db ethdb.KeyValueWriter, progress StatePruneProgress) {
+	data, err := rlp.EncodeToBytes(&progress)
+	if err != nil {
+		log.Crit("Failed to encode state prune progress", "err", err)
+	}
+	if err := db.Put(statePruneProgressKey, data); err != nil {
+		log.Crit("Failed to store state prune progress", "err", err)
+	}
+}
@@ -17,6 +17,7 @@
 package rawdb
 
 import (
+	"bytes"
 	"encoding/binary"
 	"errors"
 
@@ -150,6 +151,50 @@ func WriteTrieJournal(db ethdb.KeyValueWriter, journal []byte) {
 	}
 }
 
+// WriteReplicaLayer stores the diff layer with the given state id and root,
+// which is loaded by the read replicas sharing the database.
+func WriteReplicaLayer(db ethdb.KeyValueWriter, id uint64, root common.Hash, layer []byte) {
+	if err := db.Put(replicaLayerKey(id, root), layer); err != nil {
+		log.Crit("Failed to store replica layer", "err", err)
+	}
+}
+
+// IterateReplicaLayers returns an iterator over the diff layers stored for the
+// read replicas, starting at the given state id.
+func IterateReplicaLayers(db ethdb.Iteratee, from uint64) ethdb.Iterator {
+	return db.NewIterator(ReplicaLayerPrefix, encodeBlockNumber(from))
+}
+
+// ParseReplicaLayerKey parses the state id and root from the key of a diff
+// layer stored for the read replicas.
+func ParseReplicaLayerKey(key []byte) (uint64, common.Hash, bool) {
+	if !bytes.HasPrefix(key, ReplicaLayerPrefix) || len(key) != len(ReplicaLayerPrefix)+8+common.HashLength {
+		return 0, common.Hash{}, false
+	}
+	key = key[len(ReplicaLayerPrefix):]
+	return binary.BigEndian.Uint64(key[:8]), common.BytesToHash(key[8:]), true
+}
+
+// DeleteReplicaLayers removes the diff layers stored for the read replicas with
+// a state id not larger than the given one.
+func DeleteReplicaLayers(db ethdb.KeyValueRangeDeleter, id uint64) {
+	start := ReplicaLayerPrefix
+	limit := replicaLayerKey(id+1, common.Hash{})
+
+	// Try to remove the data in the range by a loop, as the leveldb
+	// doesn't support the native range deletion.
+	for {
+		err := db.DeleteRange(start, limit)
+		if err == nil {
+			return
+		}
+		if errors.Is(err, ethdb.ErrTooManyKeys) {
+			continue
+		}
+		log.Crit("Failed to delete replica layers", "err", err)
+	}
+}
+
 // ReadStateHistoryMeta retrieves the metadata corresponding to the specified
 // state history. Compute the position of state history in freezer by minus
 // one since the id of first state history starts from one(zero for initial
@@ -368,3 +413,18 @@ func WriteTrienodeHistory(db ethdb.AncientWriter, id uint64, header []byte, keyS
 	})
 	return err
 }
+
+// ResetTrienodeHistory resets the trienode history freezer, making the first
+// history written afterwards the one following the given start point. It's
+// used to start recording trienode histories on an existing database.
+func ResetTrienodeHistory(db ethdb.ResettableAncientStore, startPoint uint64) error {
+	if err := db.Reset(); err != nil {
+		return err
+	}
+	for _, kind := range []string{trienodeHistoryHeaderTable, trienodeHistoryKeySectionTable, trienodeHistoryValueSectionTable} {
+		if err := db.ResetTableForIncr(kind, startPoint, true); err != nil {
+			return err
+		}
+	}
+	return nil
+}
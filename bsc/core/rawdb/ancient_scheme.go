@@ -62,6 +62,10 @@ var chainFreezerTableConfigs = map[string]freezerTableConfig{
 }
 var additionTables = []string{ChainFreezerBlobSidecarTable}
 
+// headerTables are the chain tables which may keep items below the tail of the
+// freezer, as the history truncated by TruncateBodyTail keeps the headers.
+var headerTables = []string{ChainFreezerHeaderTable, ChainFreezerHashTable, ChainFreezerDifficultyTable}
+
 // incrChainFreezerTableConfigs configures the settings for tables in the incr chain freezer.
 // Hashes and difficulties don't compress well.
 var incrChainFreezerTableConfigs = map[string]freezerTableConfig{
@@ -77,8 +81,10 @@ var incrChainFreezerTableConfigs = map[string]freezerTableConfig{
 
 // freezerTableConfig contains the settings for a freezer table.
 type freezerTableConfig struct {
-	noSnappy bool // disables item compression
-	prunable bool // true for tables that can be pruned by TruncateTail
+	noSnappy bool         // disables item compression
+	prunable bool         // true for tables that can be pruned by TruncateTail
+	cold     *coldStorage // cold storage the completed data files are offloaded to, nil if disabled
+	replica  bool         // true for tables opened from a replica snapshot, sharing the files with the live table
 }
 
 const (
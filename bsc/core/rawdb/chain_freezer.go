@@ -21,6 +21,7 @@ import (
 	"fmt"
 	"math/big"
 	"os"
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -65,6 +66,9 @@ type chainFreezer struct {
 	// Optional Era database used as a backup for the pruned chain.
 	eradb *eradb.Store
 
+	// Optional cold storage the completed data files are offloaded to.
+	cold *coldStorage
+
 	quit    chan struct{}
 	wg      sync.WaitGroup
 	trigger chan chan struct{} // Manual blocking freeze trigger, test determinism
@@ -86,7 +90,11 @@ type chainFreezer struct {
 //     state freezer (e.g. dev mode).
 //   - if non-empty directory is given, initializes the regular file-based
 //     state freezer.
-func newChainFreezer(datadir string, eraDir string, namespace string, readonly bool) (*chainFreezer, error) {
+//
+// A replica freezer is opened read-only from a snapshot of the freezer of the
+// process writing to it. If cold storage is given, the completed data
+// files are offloaded to it as the chain is frozen.
+func newChainFreezer(datadir string, eraDir string, namespace string, readonly bool, replica bool, cold *coldStorage) (*chainFreezer, error) {
 	var (
 		err     error
 		freezer ethdb.AncientStore
@@ -95,9 +103,9 @@ func newChainFreezer(datadir string, eraDir string, namespace string, readonly b
 	if datadir == "" {
 		freezer = NewMemoryFreezer(readonly, chainFreezerTableConfigs)
 	} else {
-		freezer, err = NewFreezer(datadir, namespace, readonly, freezerTableSize, chainFreezerTableConfigs, false)
+		freezer, err = newFreezer(datadir, namespace, readonly, replica, freezerTableSize, chainFreezerTableConfigs, false, cold)
 		opener = func() (*Freezer, error) {
-			return NewFreezer(datadir, namespace, readonly, freezerTableSize, chainFreezerTableConfigs, false)
+			return newFreezer(datadir, namespace, readonly, replica, freezerTableSize, chainFreezerTableConfigs, false, cold)
 		}
 	}
 	if err != nil {
@@ -110,6 +118,7 @@ func newChainFreezer(datadir string, eraDir string, namespace string, readonly b
 	cf := chainFreezer{
 		ancients: freezer,
 		eradb:    edb,
+		cold:     cold,
 		quit:     make(chan struct{}),
 		trigger:  make(chan chan struct{}),
 		datadir:  datadir,
@@ -149,7 +158,13 @@ func (f *chainFreezer) Close() error {
 	if f.eradb != nil {
 		f.eradb.Close()
 	}
-	return f.ancients.Close()
+	err := f.ancients.Close()
+	if f.cold != nil {
+		if cerr := f.cold.close(); err == nil {
+			err = cerr
+		}
+	}
+	return err
 }
 
 // readHeadNumber returns the number of chain head block. 0 is returned if the
@@ -444,7 +459,9 @@ func (f *chainFreezer) freeze(db ethdb.KeyValueStore, continueFreeze bool) {
 		if isCancun(env, head.Number, head.Time) {
 			f.tryPruneBlobAncientTable(env, number)
 		}
-		f.tryPruneHistoryBlock(number)
+		f.tryPruneHistoryBlock(db, number)
+		f.tryPruneHistoryBodies(db, env, number)
+		f.tryOffload()
 
 		// TODO(galaio): Temporarily comment that the current BSC is suitable for small-volume writes,
 		// and then the large-volume mode will be enabled after optimizing the freeze performance of ancient.
@@ -459,6 +476,17 @@ func (f *chainFreezer) freeze(db ethdb.KeyValueStore, continueFreeze bool) {
 	}
 }
 
+// tryOffload moves the completed data files to the cold storage, if configured.
+func (f *chainFreezer) tryOffload() {
+	freezer, ok := f.ancients.(*Freezer)
+	if !ok || f.cold == nil {
+		return
+	}
+	if err := freezer.offload(); err != nil {
+		log.Error("Failed to offload ancient data files", "err", err)
+	}
+}
+
 func (f *chainFreezer) tryPruneBlobAncientTable(env *ethdb.FreezerEnv, num uint64) {
 	extraReserve := getBlobExtraReserveFromEnv(env)
 	// It means that there is no need for pruning
@@ -634,7 +662,7 @@ func (f *chainFreezer) checkFreezerEnv() error {
 }
 
 // tryPruneHistoryBlock try prune ancient data keep blockHistory
-func (f *chainFreezer) tryPruneHistoryBlock(best uint64) {
+func (f *chainFreezer) tryPruneHistoryBlock(db ethdb.KeyValueStore, best uint64) {
 	blockHistory := f.blockHistory.Load()
 	if blockHistory == 0 || best <= blockHistory {
 		return
@@ -655,9 +683,46 @@ func (f *chainFreezer) tryPruneHistoryBlock(best uint64) {
 			"expectTail", expectTail, "blockHistory", blockHistory, "err", err)
 		return
 	}
+	if old < expectTail {
+		DeleteNativeTransfersBelow(db, expectTail)
+	}
 	log.Debug("Prune block history successful", "oldtail", old, "tail", expectTail, "best", best, "history", blockHistory)
 }
 
+// bodyTailTruncater is implemented by the ancient stores able to truncate the
+// chain history while keeping the headers.
+type bodyTailTruncater interface {
+	TruncateBodyTail(tail uint64) (uint64, error)
+}
+
+// tryPruneHistoryBodies drops the bodies, receipts, blobs and native transfers of
+// the frozen blocks older than the recent history window, keeping their headers.
+func (f *chainFreezer) tryPruneHistoryBodies(db ethdb.KeyValueStore, env *ethdb.FreezerEnv, best uint64) {
+	if env == nil || env.HistoryRecent == 0 || best <= env.HistoryRecent {
+		return
+	}
+	truncater, ok := f.ancients.(bodyTailTruncater)
+	if !ok {
+		return
+	}
+	ancientHead, err := f.Ancients()
+	if err != nil {
+		log.Warn("PruneHistoryBodies query Ancients error", "best", best, "err", err)
+		return
+	}
+	expectTail := min(best-env.HistoryRecent, ancientHead)
+	old, err := truncater.TruncateBodyTail(expectTail)
+	if err != nil {
+		log.Warn("PruneHistoryBodies TruncateBodyTail error", "best", best,
+			"expectTail", expectTail, "history", env.HistoryRecent, "err", err)
+		return
+	}
+	if old < expectTail {
+		DeleteNativeTransfersBelow(db, expectTail)
+		log.Debug("Prune block bodies history successful", "oldtail", old, "tail", expectTail, "best", best, "history", env.HistoryRecent)
+	}
+}
+
 func isCancun(env *ethdb.FreezerEnv, num *big.Int, time uint64) bool {
 	if env == nil || env.ChainCfg == nil {
 		return false
@@ -674,7 +739,7 @@ func ResetEmptyBlobAncientTable(db ethdb.AncientWriter, next uint64) error {
 func (f *chainFreezer) Ancient(kind string, number uint64) ([]byte, error) {
 	// Lookup the entry in the underlying ancient store, assuming that
 	// headers and hashes are always available.
-	if chainFreezerTableConfigs[kind].prunable == false {
+	if chainFreezerTableConfigs[kind].prunable == false || slices.Contains(headerTables, kind) {
 		return f.ancients.Ancient(kind, number)
 	}
 	tail, err := f.ancients.Tail()
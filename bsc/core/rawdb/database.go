@@ -404,6 +404,10 @@ type OpenOptions struct {
 	Era              string // era files directory
 	MetricsNamespace string // prefix added to freezer metric names
 	ReadOnly         bool
+	Replica          bool // if true, the database is opened read-only from a snapshot published by another writer process
+
+	ColdStore ethdb.BlobStore // optional blob store the completed ancient data files are offloaded to
+	ColdCache int             // megabytes of offloaded data files to cache locally
 }
 
 // Open creates a high-level database wrapper for the given key-value store.
@@ -418,6 +422,9 @@ func Open(db ethdb.KeyValueStore, opts OpenOptions) (ethdb.Database, error) {
 
 	// if there has legacy offset, try to clean & reset the freezer metadata
 	if legacyOffset := ReadLegacyOffset(db); legacyOffset > 0 {
+		if opts.Replica {
+			return nil, errors.New("legacy freezer offset must be reset by the writer before opening a replica")
+		}
 		log.Info("Found legacy offset in freezerDB, will reset freezer meta", "offset", legacyOffset)
 		if err := resetFreezerMeta(chainFreezerDir, opts.MetricsNamespace, legacyOffset); err != nil {
 			return nil, err
@@ -425,8 +432,21 @@ func Open(db ethdb.KeyValueStore, opts OpenOptions) (ethdb.Database, error) {
 		CleanLegacyOffset(db)
 	}
 
+	// Set up the cold storage of the offloaded ancient data files, if configured
+	var cold *coldStorage
+	if opts.ColdStore != nil && chainFreezerDir != "" {
+		var err error
+		cold, err = openColdStorage(opts.ColdStore, chainFreezerDir, int64(opts.ColdCache)*1024*1024, opts.ReadOnly || opts.Replica)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// Create the idle freezer instance
-	frdb, err := newChainFreezer(chainFreezerDir, opts.Era, opts.MetricsNamespace, opts.ReadOnly)
+	frdb, err := newChainFreezer(chainFreezerDir, opts.Era, opts.MetricsNamespace, opts.ReadOnly || opts.Replica, opts.Replica, cold)
+	if err != nil && cold != nil {
+		cold.close()
+	}
 
 	// We are creating the freezerdb here because the validation logic for db and freezer below requires certain interfaces
 	// that need a database type. Therefore, we are pre-creating it for subsequent use.
@@ -530,7 +550,7 @@ func Open(db ethdb.KeyValueStore, opts OpenOptions) (ethdb.Database, error) {
 		}
 	}
 	// Freezer is consistent with the key-value database, permit combining the two
-	if !opts.ReadOnly {
+	if !opts.ReadOnly && !opts.Replica {
 		frdb.wg.Add(1)
 		go func() {
 			frdb.freeze(db, false)
@@ -673,6 +693,9 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		numHashPairings    stat
 		blobSidecars       stat
 		bals               stat
+		nativeTransfers    stat
+		replicaLayers      stat
+		addressIndexes     stat
 		hashNumPairings    stat
 		legacyTries        stat
 		stateLookups       stat
@@ -814,6 +837,14 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 				blobSidecars.add(size)
 			case bytes.HasPrefix(key, BlockBALPrefix):
 				bals.add(size)
+			case bytes.HasPrefix(key, NativeTransfersPrefix) && len(key) == len(NativeTransfersPrefix)+8+common.HashLength:
+				nativeTransfers.add(size)
+			case bytes.HasPrefix(key, ReplicaLayerPrefix) && len(key) == len(ReplicaLayerPrefix)+8+common.HashLength:
+				replicaLayers.add(size)
+			case bytes.HasPrefix(key, AddressTxPrefix) && len(key) == len(AddressTxPrefix)+common.AddressLength+12:
+				addressIndexes.add(size)
+			case bytes.HasPrefix(key, AddressTransferPrefix) && len(key) == len(AddressTransferPrefix)+2*common.AddressLength+12:
+				addressIndexes.add(size)
 			case bytes.HasPrefix(key, ParliaSnapshotPrefix) && len(key) == 7+common.HashLength:
 				parliaSnaps.add(size)
 
@@ -945,6 +976,9 @@ func InspectDatabase(db ethdb.Database, keyPrefix, keyStart []byte) error {
 		// bsc special
 		{"Key-Value store", "BlobSidecars", blobSidecars.sizeString(), blobSidecars.countString()},
 		{"Key-Value store", "Block access list", bals.sizeString(), bals.countString()},
+		{"Key-Value store", "Native transfers", nativeTransfers.sizeString(), nativeTransfers.countString()},
+		{"Key-Value store", "Replica layers", replicaLayers.sizeString(), replicaLayers.countString()},
+		{"Key-Value store", "Address indexes", addressIndexes.sizeString(), addressIndexes.countString()},
 		{"Key-Value store", "Parlia snapshots", parliaSnaps.sizeString(), parliaSnaps.countString()},
 	}
 
@@ -1183,7 +1217,7 @@ func DeleteTrieState(db ethdb.Database) error {
 var knownMetadataKeys = [][]byte{
 	databaseVersionKey, headHeaderKey, headBlockKey, headFastBlockKey, headFinalizedBlockKey,
 	lastPivotKey, fastTrieProgressKey, snapshotDisabledKey, SnapshotRootKey, snapshotJournalKey,
-	snapshotGeneratorKey, snapshotRecoveryKey, txIndexTailKey, fastTxLookupLimitKey,
+	snapshotGeneratorKey, snapshotRecoveryKey, txIndexTailKey, addressIndexRangeKey, statePruneProgressKey, fastTxLookupLimitKey,
 	uncleanShutdownKey, badBlockKey, transitionStatusKey, skeletonSyncStatusKey,
 	persistentStateIDKey, trieJournalKey, snapshotSyncStatusKey, snapSyncStatusFlagKey,
 	filterMapsRangeKey, headStateHistoryIndexKey, VerkleTransitionStatePrefix,
@@ -71,6 +71,7 @@ type Freezer struct {
 
 	readonly     bool
 	tables       map[string]*freezerTable // Data tables for storing everything
+	cold         *coldStorage             // Optional cold storage of the completed data files, owned by the caller
 	instanceLock *flock.Flock             // File-system lock to prevent double opens
 	closeOnce    sync.Once
 }
@@ -82,6 +83,14 @@ type Freezer struct {
 // entry is true, snappy compression is disabled for the table.
 // additionTables indicates the new add tables for freezerDB, it has some special rules.
 func NewFreezer(datadir string, namespace string, readonly bool, maxTableSize uint32, tables map[string]freezerTableConfig, isIncr bool) (*Freezer, error) {
+	return newFreezer(datadir, namespace, readonly, false, maxTableSize, tables, isIncr, nil)
+}
+
+// newFreezer creates a freezer instance. In replica mode the freezer is opened
+// read-only from a snapshot hard-linking the files of a live freezer, ignoring
+// the items appended to them after the snapshot. The data files offloaded to
+// the optional cold storage are fetched from it on demand.
+func newFreezer(datadir string, namespace string, readonly bool, replica bool, maxTableSize uint32, tables map[string]freezerTableConfig, isIncr bool, cold *coldStorage) (*Freezer, error) {
 	// Create the initial freezer object
 	var (
 		readMeter  = metrics.NewRegisteredMeter(namespace+"ancient/read", nil)
@@ -120,6 +129,7 @@ func NewFreezer(datadir string, namespace string, readonly bool, maxTableSize ui
 		datadir:      datadir,
 		readonly:     readonly,
 		tables:       make(map[string]*freezerTable),
+		cold:         cold,
 		instanceLock: lock,
 		isIncr:       isIncr,
 	}
@@ -130,7 +140,8 @@ func NewFreezer(datadir string, namespace string, readonly bool, maxTableSize ui
 			table *freezerTable
 			err   error
 		)
-		if slices.Contains(additionTables, name) {
+		config.cold, config.replica = cold, replica
+		if slices.Contains(additionTables, name) && !replica {
 			table, err = openAdditionTable(datadir, name, readMeter, writeMeter, sizeGauge, maxTableSize, config, readonly)
 		} else {
 			table, err = newTable(datadir, name, readMeter, writeMeter, sizeGauge, maxTableSize, config, readonly)
@@ -349,6 +360,16 @@ func (f *Freezer) TruncateHead(items uint64) (uint64, error) {
 // TruncateTail discards all data below the specified threshold. Note that only
 // 'prunable' tables will be truncated.
 func (f *Freezer) TruncateTail(tail uint64) (uint64, error) {
+	return f.truncateTail(tail, false)
+}
+
+// TruncateBodyTail discards all data below the specified threshold, except the
+// headers, hashes and difficulties of the chain, which are kept.
+func (f *Freezer) TruncateBodyTail(tail uint64) (uint64, error) {
+	return f.truncateTail(tail, true)
+}
+
+func (f *Freezer) truncateTail(tail uint64, keepHeaders bool) (uint64, error) {
 	if f.readonly {
 		return 0, errReadOnly
 	}
@@ -363,6 +384,9 @@ func (f *Freezer) TruncateTail(tail uint64) (uint64, error) {
 		if slices.Contains(additionTables, kind) && EmptyTable(table) {
 			continue
 		}
+		if keepHeaders && slices.Contains(headerTables, kind) {
+			continue
+		}
 		if table.config.prunable {
 			if err := table.truncateTail(tail); err != nil {
 				return 0, err
@@ -396,6 +420,7 @@ func (f *Freezer) validate() error {
 	var (
 		head       uint64
 		prunedTail *uint64
+		headerTail *uint64
 	)
 	// Hack to get boundary of any table
 	for kind, table := range f.tables {
@@ -433,21 +458,32 @@ func (f *Freezer) validate() error {
 				return fmt.Errorf("non-prunable freezer table '%s' has a non-zero tail: %d", kind, table.itemHidden.Load())
 			}
 		} else {
-			// prunable tables have to have the same length
-			if prunedTail == nil {
+			// prunable tables have to have the same length, the header tables
+			// among themselves
+			tail := &prunedTail
+			if slices.Contains(headerTables, kind) {
+				tail = &headerTail
+			}
+			if *tail == nil {
 				tmp := table.itemHidden.Load()
-				prunedTail = &tmp
+				*tail = &tmp
 			}
-			if *prunedTail != table.itemHidden.Load() {
-				return fmt.Errorf("freezer table %s has differing tail: %d != %d", kind, table.itemHidden.Load(), *prunedTail)
+			if **tail != table.itemHidden.Load() {
+				return fmt.Errorf("freezer table %s has differing tail: %d != %d", kind, table.itemHidden.Load(), **tail)
 			}
 		}
 	}
 
+	if prunedTail == nil {
+		prunedTail = headerTail
+	}
 	if prunedTail == nil {
 		tmp := uint64(0)
 		prunedTail = &tmp
 	}
+	if headerTail != nil && *headerTail > *prunedTail {
+		return fmt.Errorf("freezer header tables have a tail above the others: %d > %d", *headerTail, *prunedTail)
+	}
 
 	f.frozen.Store(head)
 	f.tail.Store(*prunedTail)
@@ -459,6 +495,7 @@ func (f *Freezer) repair() error {
 	var (
 		head       = uint64(math.MaxUint64)
 		prunedTail = uint64(0)
+		headerTail = uint64(0)
 	)
 	for kind, table := range f.tables {
 		// addition tables only align head
@@ -480,8 +517,14 @@ func (f *Freezer) repair() error {
 		}
 
 		head = min(head, table.items.Load())
-		prunedTail = max(prunedTail, table.itemHidden.Load())
+		if slices.Contains(headerTables, kind) {
+			headerTail = max(headerTail, table.itemHidden.Load())
+		} else {
+			prunedTail = max(prunedTail, table.itemHidden.Load())
+		}
 	}
+	// The header tables may be kept below the tail of the others
+	prunedTail = max(prunedTail, headerTail)
 	if f.isIncr && (head == math.MaxUint64) {
 		head = 0
 	}
@@ -519,8 +562,13 @@ func (f *Freezer) repair() error {
 				panic(fmt.Sprintf("non-prunable freezer table %s has non-zero tail: %v", kind, table.itemHidden.Load()))
 			}
 		} else {
-			// prunable tables have to have the same length
-			if err := table.truncateTail(prunedTail); err != nil {
+			// prunable tables have to have the same length, the header tables
+			// among themselves
+			tail := prunedTail
+			if slices.Contains(headerTables, kind) {
+				tail = headerTail
+			}
+			if err := table.truncateTail(tail); err != nil {
 				return err
 			}
 		}
@@ -0,0 +1,629 @@
+package rawdb
+
+import (
+	"container/list"
+	"crypto/sha256"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"path/filepath"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// errColdStorageMissing is returned if a data file offloaded to cold storage is
+// read while the freezer is opened without it.
+var errColdStorageMissing = errors.New("data file offloaded to cold storage, which is not configured")
+
+// coldCacheDir is the directory within the freezer directory holding the local
+// copies of the offloaded data files.
+const coldCacheDir = "coldcache"
+
+// coldStorage keeps the data files of the freezer tables, which are no longer
+// appended to, in an external blob store. The offloaded files are fetched on
+// demand into a local cache, limited in size by evicting the least recently
+// used files.
+type coldStorage struct {
+	store  ethdb.BlobStore
+	prefix string // Prefix of the blob names, distinguishing the freezers sharing a store
+	dir    string // Directory of the local cache
+	limit  int64  // Maximum size of the local cache in bytes
+
+	files map[string]*coldFile // Cached files by blob name
+	lru   *list.List           // Cached files ordered by the last use, least recent first
+	size  int64                // Total size of the cached files
+	lock  sync.Mutex
+}
+
+// coldFile is a local copy of an offloaded data file.
+type coldFile struct {
+	name  string
+	file  *os.File
+	size  int64
+	refs  int           // Number of readers using the file, preventing its eviction
+	ready chan struct{} // Closed once the file is fetched
+	err   error         // Error the fetching failed with
+	elem  *list.Element
+}
+
+// newColdStorage creates the cold storage of a freezer on top of the given blob
+// store, caching up to the given number of bytes locally. Any leftover of the
+// local cache is discarded.
+func newColdStorage(store ethdb.BlobStore, prefix string, dir string, limit int64) (*coldStorage, error) {
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	return &coldStorage{
+		store:  store,
+		prefix: prefix,
+		dir:    dir,
+		limit:  limit,
+		files:  make(map[string]*coldFile),
+		lru:    list.New(),
+	}, nil
+}
+
+// blobName returns the name of the blob holding the given data file.
+func (c *coldStorage) blobName(file string) string {
+	return c.prefix + "/" + file
+}
+
+// readAt reads the given offloaded data file at the given offset, fetching it
+// into the local cache if not present. The fetched file is verified against the
+// checksum recorded when offloaded.
+func (c *coldStorage) readAt(file string, sum common.Hash, p []byte, off int64) error {
+	f, err := c.acquire(c.blobName(file), sum)
+	if err != nil {
+		return err
+	}
+	defer c.release(f)
+
+	_, err = f.file.ReadAt(p, off)
+	return err
+}
+
+// acquire returns the local copy of the given blob, fetching it if it's not
+// cached yet. The file is kept until released.
+func (c *coldStorage) acquire(name string, sum common.Hash) (*coldFile, error) {
+	c.lock.Lock()
+	if f, ok := c.files[name]; ok {
+		f.refs++
+		if f.elem != nil {
+			c.lru.MoveToBack(f.elem)
+		}
+		c.lock.Unlock()
+
+		// Wait for the concurrent retrieval if the file is being fetched
+		<-f.ready
+		if f.err != nil {
+			c.release(f)
+			return nil, f.err
+		}
+		return f, nil
+	}
+	f := &coldFile{name: name, refs: 1, ready: make(chan struct{})}
+	c.files[name] = f
+	c.lock.Unlock()
+
+	file, size, err := c.fetch(name, sum)
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	f.file, f.size, f.err = file, size, err
+	close(f.ready)
+	if err != nil {
+		delete(c.files, name)
+		return nil, err
+	}
+	f.elem = c.lru.PushBack(f)
+	c.size += size
+	c.evict()
+	return f, nil
+}
+
+// release marks the file as no longer used by the reader.
+func (c *coldStorage) release(f *coldFile) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	f.refs--
+	if f.err == nil && f.refs == 0 {
+		c.evict()
+	}
+}
+
+// evict drops the least recently used files not in use until the cache fits
+// into the size limit. It assumes the lock is held.
+func (c *coldStorage) evict() {
+	for elem := c.lru.Front(); elem != nil && c.size > c.limit; {
+		next := elem.Next()
+		if f := elem.Value.(*coldFile); f.refs == 0 {
+			c.drop(f)
+		}
+		elem = next
+	}
+}
+
+// drop removes the file from the cache. It assumes the lock is held.
+func (c *coldStorage) drop(f *coldFile) {
+	c.lru.Remove(f.elem)
+	delete(c.files, f.name)
+	c.size -= f.size
+
+	f.file.Close()
+	os.Remove(f.file.Name())
+}
+
+// fetch downloads the given blob into the local cache.
+func (c *coldStorage) fetch(name string, sum common.Hash) (*os.File, int64, error) {
+	path := filepath.Join(c.dir, filepath.FromSlash(name))
+	if err := c.download(name, path, sum); err != nil {
+		return nil, 0, err
+	}
+	file, err := os.Open(path)
+	if err != nil {
+		return nil, 0, err
+	}
+	stat, err := file.Stat()
+	if err != nil {
+		file.Close()
+		return nil, 0, err
+	}
+	log.Debug("Fetched ancient data file from cold storage", "name", name, "size", common.StorageSize(stat.Size()))
+	return file, stat.Size(), nil
+}
+
+// download retrieves the given blob into the file at the given path, through a
+// temporary file to never leave it incomplete nor unverified against the given
+// checksum.
+func (c *coldStorage) download(name string, path string, sum common.Hash) error {
+	r, err := c.store.Get(name)
+	if err != nil {
+		return err
+	}
+	defer r.Close()
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.Create(path + ".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	hasher := sha256.New()
+	_, err = io.Copy(f, io.TeeReader(r, hasher))
+	if err == nil {
+		err = checkColdSum(name, common.Hash(hasher.Sum(nil)), sum)
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// upload stores the given local data file in the blob store, and returns its
+// checksum once verified that the stored blob matches it.
+func (c *coldStorage) upload(file string, path string) (common.Hash, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	defer f.Close()
+
+	stat, err := f.Stat()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	var (
+		name   = c.blobName(file)
+		hasher = sha256.New()
+	)
+	if err := c.store.Put(name, io.TeeReader(f, hasher), stat.Size()); err != nil {
+		return common.Hash{}, err
+	}
+	sum := common.Hash(hasher.Sum(nil))
+
+	// Read the stored blob back, as the store might have altered it
+	r, err := c.store.Get(name)
+	if err != nil {
+		return common.Hash{}, err
+	}
+	defer r.Close()
+
+	hasher.Reset()
+	if _, err := io.Copy(hasher, r); err != nil {
+		return common.Hash{}, err
+	}
+	if err := checkColdSum(name, common.Hash(hasher.Sum(nil)), sum); err != nil {
+		return common.Hash{}, err
+	}
+	return sum, nil
+}
+
+// restore downloads the given offloaded data file back to the given path,
+// verifying it against the given checksum.
+func (c *coldStorage) restore(file string, path string, sum common.Hash) error {
+	return c.download(c.blobName(file), path, sum)
+}
+
+// checkColdSum returns an error if the checksum of the given blob doesn't match
+// the expected one.
+func checkColdSum(name string, have, want common.Hash) error {
+	if have != want {
+		return fmt.Errorf("cold storage checksum mismatch of %s: have %x, want %x", name, have, want)
+	}
+	return nil
+}
+
+// remove deletes the given data file from the blob store, dropping its local
+// copy unless in use.
+func (c *coldStorage) remove(file string) error {
+	name := c.blobName(file)
+
+	c.lock.Lock()
+	if f, ok := c.files[name]; ok && f.err == nil && f.refs == 0 {
+		select {
+		case <-f.ready:
+			c.drop(f)
+		default:
+		}
+	}
+	c.lock.Unlock()
+
+	return c.store.Delete(name)
+}
+
+// close drops the local cache and closes the blob store.
+func (c *coldStorage) close() error {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	for _, f := range c.files {
+		if f.file != nil {
+			f.file.Close()
+		}
+	}
+	c.files = make(map[string]*coldFile)
+	c.lru.Init()
+	c.size = 0
+	os.RemoveAll(c.dir)
+
+	return c.store.Close()
+}
+
+// readColdMarker reads the number of the first data file of a table which is
+// not offloaded to cold storage, 0 if the table is entirely local, along with
+// the checksums of the offloaded data files by number.
+//
+// The marker holds the number of the first local data file, then the number of
+// the first offloaded one followed by the checksums of the offloaded ones.
+func readColdMarker(path string) (uint32, map[uint32]common.Hash, error) {
+	sums := make(map[uint32]common.Hash)
+	blob, err := os.ReadFile(path)
+	if errors.Is(err, os.ErrNotExist) {
+		return 0, sums, nil
+	}
+	if err != nil {
+		return 0, nil, err
+	}
+	if len(blob) < 8 || (len(blob)-8)%common.HashLength != 0 {
+		return 0, nil, fmt.Errorf("invalid cold storage marker %s", path)
+	}
+	var (
+		head  = binary.BigEndian.Uint32(blob)
+		first = binary.BigEndian.Uint32(blob[4:])
+	)
+	blob = blob[8:]
+	if first > head || uint64(len(blob)/common.HashLength) != uint64(head-first) {
+		return 0, nil, fmt.Errorf("invalid cold storage marker %s", path)
+	}
+	for num := first; num < head; num++ {
+		sums[num] = common.BytesToHash(blob[:common.HashLength])
+		blob = blob[common.HashLength:]
+	}
+	return head, sums, nil
+}
+
+// writeColdMarker persists the number of the first data file of a table which
+// is not offloaded to cold storage, along with the checksums of the offloaded
+// data files from the given first one.
+func writeColdMarker(path string, head uint32, first uint32, sums map[uint32]common.Hash) error {
+	if head == 0 {
+		if err := os.Remove(path); err != nil && !errors.Is(err, os.ErrNotExist) {
+			return err
+		}
+		return nil
+	}
+	first = min(first, head)
+	blob := binary.BigEndian.AppendUint32(nil, head)
+	blob = binary.BigEndian.AppendUint32(blob, first)
+	for num := first; num < head; num++ {
+		sum, ok := sums[num]
+		if !ok {
+			return fmt.Errorf("missing checksum of offloaded data file %d", num)
+		}
+		blob = append(blob, sum[:]...)
+	}
+	f, err := os.Create(path + ".tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	_, err = f.Write(blob)
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// coldPath returns the path of the file marking the data files of the table
+// offloaded to cold storage.
+func (t *freezerTable) coldPath() string {
+	return filepath.Join(t.path, fmt.Sprintf("%s.cold", t.name))
+}
+
+// setColdHead persists the given number of the first data file not offloaded
+// along with the checksums of the offloaded ones still in the table, dropping
+// the checksums of the others. It assumes the write-lock is held by the caller.
+func (t *freezerTable) setColdHead(head uint32, sums map[uint32]common.Hash) error {
+	if err := writeColdMarker(t.coldPath(), head, t.tailId, sums); err != nil {
+		return err
+	}
+	for num := range sums {
+		if num < t.tailId || num >= head {
+			delete(sums, num)
+		}
+	}
+	t.coldHead, t.coldSums = head, sums
+	return nil
+}
+
+// readCold reads the given data file offloaded to cold storage. It assumes the
+// read-lock is held by the caller.
+func (t *freezerTable) readCold(num uint32, p []byte, off int64) error {
+	if num < t.tailId || num >= t.coldHead {
+		return fmt.Errorf("missing data file %d", num)
+	}
+	if t.config.cold == nil {
+		return errColdStorageMissing
+	}
+	sum, ok := t.coldSums[num]
+	if !ok {
+		return fmt.Errorf("missing checksum of offloaded data file %d", num)
+	}
+	if err := t.config.cold.readAt(t.fileName(num), sum, p, off); err != nil {
+		return fmt.Errorf("%w, fileid: %d, start: %d, length: %d", err, num, off, len(p))
+	}
+	return nil
+}
+
+// offload moves the completed data files of the table to cold storage, and
+// returns the number of files moved. The files are uploaded without holding
+// the lock, as they are no longer modified, except by a concurrent truncation
+// which is checked for before dropping the local copies.
+func (t *freezerTable) offload() (int, error) {
+	cold := t.config.cold
+
+	t.lock.RLock()
+	from, to := max(t.coldHead, t.tailId), t.headId
+	t.lock.RUnlock()
+
+	var moved int
+	for num := from; num < to; num++ {
+		name := t.fileName(num)
+		sum, err := cold.upload(name, filepath.Join(t.path, name))
+		if err != nil {
+			return moved, err
+		}
+		t.lock.Lock()
+		if num < t.tailId || num >= t.headId {
+			// The file has been dropped by a truncation in the meantime
+			t.lock.Unlock()
+			if err := cold.remove(name); err != nil {
+				return moved, err
+			}
+			if num < t.tailId {
+				continue
+			}
+			return moved, nil
+		}
+		sums := maps.Clone(t.coldSums)
+		sums[num] = sum
+		if err := t.setColdHead(num+1, sums); err != nil {
+			t.lock.Unlock()
+			return moved, err
+		}
+		t.releaseFile(num)
+		err = os.Remove(filepath.Join(t.path, name))
+		t.lock.Unlock()
+
+		if err != nil {
+			return moved, err
+		}
+		moved++
+	}
+	return moved, nil
+}
+
+// restore moves the data files of the table offloaded to cold storage back to
+// the local disk, and returns the number of files moved.
+func (t *freezerTable) restore() (int, error) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	cold := t.config.cold
+	for num := t.tailId; num < t.coldHead; num++ {
+		name := t.fileName(num)
+		if path := filepath.Join(t.path, name); !common.FileExist(path) {
+			if err := cold.restore(name, path, t.coldSums[num]); err != nil {
+				return 0, err
+			}
+		}
+		if _, err := t.openFile(num, openFreezerFileForReadOnly); err != nil {
+			return 0, err
+		}
+	}
+	from, to := t.tailId, t.coldHead
+	if err := t.setColdHead(0, make(map[uint32]common.Hash)); err != nil {
+		return 0, err
+	}
+
+	var moved int
+	for num := from; num < to; num++ {
+		if err := cold.remove(t.fileName(num)); err != nil {
+			return moved, err
+		}
+		moved++
+	}
+	return moved, nil
+}
+
+// restoreColdFiles moves the data file with the given number back from cold
+// storage, as the head of the table is rewound into it, and drops the ones
+// above. It assumes the write-lock is held by the caller.
+func (t *freezerTable) restoreColdFiles(num uint32) error {
+	if num >= t.coldHead {
+		return nil
+	}
+	cold := t.config.cold
+	if path := filepath.Join(t.path, t.fileName(num)); !common.FileExist(path) {
+		if cold == nil {
+			return errColdStorageMissing
+		}
+		if err := cold.restore(t.fileName(num), path, t.coldSums[num]); err != nil {
+			return err
+		}
+	}
+	prev := t.coldHead
+	if err := t.setColdHead(num, maps.Clone(t.coldSums)); err != nil {
+		return err
+	}
+
+	if cold != nil {
+		for i := num; i < prev; i++ {
+			if err := cold.remove(t.fileName(i)); err != nil {
+				t.logger.Warn("Failed to delete offloaded data file", "file", i, "err", err)
+			}
+		}
+	}
+	return nil
+}
+
+// removeColdFiles deletes the offloaded data files within the given range of
+// numbers, dropped by a tail truncation. It assumes the write-lock is held by
+// the caller.
+func (t *freezerTable) removeColdFiles(from, to uint32) {
+	if t.config.cold == nil {
+		return
+	}
+	for num := from; num < to && num < t.coldHead; num++ {
+		if err := t.config.cold.remove(t.fileName(num)); err != nil {
+			t.logger.Warn("Failed to delete offloaded data file", "file", num, "err", err)
+		}
+	}
+}
+
+// offload moves the completed data files of all tables to the cold storage.
+func (f *Freezer) offload() error {
+	if f.readonly {
+		return errReadOnly
+	}
+	if f.cold == nil {
+		return errColdStorageMissing
+	}
+	for name, table := range f.tables {
+		moved, err := table.offload()
+		if moved > 0 {
+			log.Info("Offloaded ancient data files", "table", name, "files", moved)
+		}
+		if err != nil {
+			return fmt.Errorf("table %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// restore moves the data files of all tables offloaded to the cold storage back
+// to the local disk.
+func (f *Freezer) restore() error {
+	if f.readonly {
+		return errReadOnly
+	}
+	if f.cold == nil {
+		return errColdStorageMissing
+	}
+	for name, table := range f.tables {
+		moved, err := table.restore()
+		if moved > 0 {
+			log.Info("Restored ancient data files", "table", name, "files", moved)
+		}
+		if err != nil {
+			return fmt.Errorf("table %s: %w", name, err)
+		}
+	}
+	return nil
+}
+
+// openColdStorage creates the cold storage of the chain freezer in the given
+// directory on top of the blob store. Read-only instances cache the fetched
+// files in a temporary directory, leaving the cache of the writer intact.
+func openColdStorage(store ethdb.BlobStore, datadir string, limit int64, readonly bool) (*coldStorage, error) {
+	dir := filepath.Join(datadir, coldCacheDir)
+	if readonly {
+		var err error
+		if dir, err = os.MkdirTemp("", coldCacheDir); err != nil {
+			return nil, err
+		}
+	}
+	return newColdStorage(store, ChainFreezerName, dir, limit)
+}
+
+// MigrateAncients moves the completed data files of the chain freezer in the
+// given ancient directory to the blob store, or back to the local disk if
+// restore is set. The freezer must not be in use by another process. The blob
+// store is closed on return.
+func MigrateAncients(ancient string, store ethdb.BlobStore, restore bool) error {
+	datadir := resolveChainFreezerDir(ancient)
+	cold, err := openColdStorage(store, datadir, 0, false)
+	if err != nil {
+		store.Close()
+		return err
+	}
+	defer cold.close()
+
+	freezer, err := newFreezer(datadir, "", false, false, freezerTableSize, chainFreezerTableConfigs, false, cold)
+	if err != nil {
+		return err
+	}
+	defer freezer.Close()
+
+	if restore {
+		return freezer.restore()
+	}
+	return freezer.offload()
+}
@@ -0,0 +1,241 @@
+package rawdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/blobstore"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// TestFreezerColdStorage tests offloading the data files of a table to cold
+// storage, reading them back lazily, and truncating the table across them.
+func TestFreezerColdStorage(t *testing.T) {
+	t.Parallel()
+
+	var (
+		dir   = t.TempDir()
+		fname = "cold"
+	)
+	store, err := blobstore.NewDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Cache two data files at most, to exercise the eviction
+	cold, err := newColdStorage(store, ChainFreezerName, filepath.Join(dir, coldCacheDir), 100)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cold.close()
+
+	open := func(cold *coldStorage, readonly bool) *freezerTable {
+		t.Helper()
+		f, err := newTable(dir, fname, metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge(), 50, freezerTableConfig{noSnappy: true, cold: cold}, readonly)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+	checkRange := func(f *freezerTable, from, to uint64) {
+		t.Helper()
+		items := make(map[uint64][]byte)
+		for i := from; i < to; i++ {
+			items[i] = getChunk(15, int(i))
+		}
+		checkRetrieve(t, f, items)
+
+		values, err := f.RetrieveItems(from, to-from, 0)
+		if err != nil {
+			t.Fatalf("failed to retrieve items: %v", err)
+		}
+		for i, value := range values {
+			if want := getChunk(15, int(from)+i); !bytes.Equal(value, want) {
+				t.Fatalf("item %d has wrong value %x (want %x)", from+uint64(i), value, want)
+			}
+		}
+	}
+	localFile := func(num uint32) string {
+		return filepath.Join(dir, fmt.Sprintf("%s.%04d.rdat", fname, num))
+	}
+	blobExists := func(num uint32) bool {
+		_, err := store.Size(fmt.Sprintf("%s/%s.%04d.rdat", ChainFreezerName, fname, num))
+		if err != nil && !errors.Is(err, blobstore.ErrNotFound) {
+			t.Fatal(err)
+		}
+		return err == nil
+	}
+
+	// Write 15 bytes 255 times, results in 85 files with 3 items each
+	f := open(cold, false)
+	writeChunks(t, f, 255, 15)
+
+	moved, err := f.offload()
+	if err != nil {
+		t.Fatalf("failed to offload: %v", err)
+	}
+	if moved != 84 {
+		t.Fatalf("wrong number of offloaded files: have %d, want %d", moved, 84)
+	}
+	for num := uint32(0); num < 85; num++ {
+		if common.FileExist(localFile(num)) != (num == 84) {
+			t.Fatalf("local data file %d kept after offloading: %t", num, num != 84)
+		}
+		if blobExists(num) != (num < 84) {
+			t.Fatalf("data file %d offloaded: %t", num, num == 84)
+		}
+	}
+	checkRange(f, 0, 255)
+	if cold.size > cold.limit {
+		t.Fatalf("cold storage cache exceeds its limit: %d > %d", cold.size, cold.limit)
+	}
+	// Nothing is left to offload
+	if moved, err := f.offload(); err != nil || moved != 0 {
+		t.Fatalf("unexpected offloading: %d files, %v", moved, err)
+	}
+	f.Close()
+
+	// Offloaded data files can't be read without the cold storage
+	f = open(nil, true)
+	if _, err := f.Retrieve(0); !errors.Is(err, errColdStorageMissing) {
+		t.Fatalf("unexpected error reading offloaded item: %v", err)
+	}
+	checkRange(f, 252, 255)
+	f.Close()
+
+	// Reopen the table with cold storage and truncate the tail, dropping the
+	// offloaded files below it
+	f = open(cold, false)
+	checkRange(f, 0, 255)
+	if err := f.truncateTail(30); err != nil {
+		t.Fatal(err)
+	}
+	for num := uint32(0); num < 10; num++ {
+		if blobExists(num) {
+			t.Fatalf("data file %d kept after tail truncation", num)
+		}
+	}
+	checkRange(f, 30, 255)
+
+	// Truncate the head into an offloaded file, which gets restored
+	if err := f.truncateHead(100); err != nil {
+		t.Fatal(err)
+	}
+	if !common.FileExist(localFile(33)) {
+		t.Fatal("head data file not restored")
+	}
+	for num := uint32(33); num < 85; num++ {
+		if blobExists(num) {
+			t.Fatalf("data file %d kept after head truncation", num)
+		}
+	}
+	checkRange(f, 30, 100)
+	f.Close()
+
+	f = open(cold, false)
+	defer f.Close()
+	checkRange(f, 30, 100)
+
+	// Move the offloaded files back to the local disk
+	if moved, err := f.restore(); err != nil || moved != 23 {
+		t.Fatalf("unexpected restoration: %d files, %v", moved, err)
+	}
+	for num := uint32(10); num < 33; num++ {
+		if !common.FileExist(localFile(num)) || blobExists(num) {
+			t.Fatalf("data file %d not restored", num)
+		}
+	}
+	if common.FileExist(f.coldPath()) {
+		t.Fatal("cold storage marker kept after restoration")
+	}
+	checkRange(f, 30, 100)
+}
+
+// corruptingStore is a blob store altering the content of the stored blobs.
+type corruptingStore struct {
+	ethdb.BlobStore
+}
+
+func (s *corruptingStore) Put(name string, r io.Reader, size int64) error {
+	blob, err := io.ReadAll(r)
+	if err != nil {
+		return err
+	}
+	blob[0] ^= 0xff
+	return s.BlobStore.Put(name, bytes.NewReader(blob), size)
+}
+
+// TestFreezerColdStorageChecksum tests that the offloaded data files are verified
+// against the checksums recorded when offloaded, both when uploaded and fetched.
+func TestFreezerColdStorageChecksum(t *testing.T) {
+	t.Parallel()
+
+	var (
+		dir   = t.TempDir()
+		fname = "cold"
+	)
+	store, err := blobstore.NewDirectory(t.TempDir())
+	if err != nil {
+		t.Fatal(err)
+	}
+	// Cache nothing beyond the files in use, fetching them on every read
+	cold, err := newColdStorage(store, ChainFreezerName, filepath.Join(dir, coldCacheDir), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer cold.close()
+
+	open := func(cold *coldStorage) *freezerTable {
+		t.Helper()
+		f, err := newTable(dir, fname, metrics.NewMeter(), metrics.NewMeter(), metrics.NewGauge(), 50, freezerTableConfig{noSnappy: true, cold: cold}, false)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return f
+	}
+	// Write 15 bytes 30 times, results in 10 files with 3 items each
+	f := open(cold)
+	writeChunks(t, f, 30, 15)
+
+	// Uploads altered by the store are rejected
+	corrupted, err := newColdStorage(&corruptingStore{store}, ChainFreezerName, filepath.Join(t.TempDir(), coldCacheDir), 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.config.cold = corrupted
+	if _, err := f.offload(); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("unexpected error offloading to a corrupting store: %v", err)
+	}
+	if f.coldHead != 0 {
+		t.Fatalf("data files offloaded to a corrupting store: %d", f.coldHead)
+	}
+	f.config.cold = cold
+	if moved, err := f.offload(); err != nil || moved != 9 {
+		t.Fatalf("unexpected offloading: %d files, %v", moved, err)
+	}
+	f.Close()
+
+	// Alter the first offloaded file, which fails the reads after reopening
+	name := fmt.Sprintf("%s/%s.%04d.rdat", ChainFreezerName, fname, 0)
+	if err := store.Put(name, bytes.NewReader(make([]byte, 45)), 45); err != nil {
+		t.Fatal(err)
+	}
+	f = open(cold)
+	defer f.Close()
+	if _, err := f.Retrieve(0); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("unexpected error reading altered item: %v", err)
+	}
+	if value, err := f.Retrieve(3); err != nil || !bytes.Equal(value, getChunk(15, 3)) {
+		t.Fatalf("unexpected item read: %x, %v", value, err)
+	}
+	// Restoring the altered file fails as well
+	if _, err := f.restore(); err == nil || !strings.Contains(err.Error(), "checksum mismatch") {
+		t.Fatalf("unexpected error restoring altered file: %v", err)
+	}
+}
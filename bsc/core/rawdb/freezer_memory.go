@@ -360,6 +360,16 @@ func (f *MemoryFreezer) TruncateHead(items uint64) (uint64, error) {
 // Note this will only truncate 'prunable' tables. Block headers and canonical
 // hashes cannot be truncated at this time.
 func (f *MemoryFreezer) TruncateTail(tail uint64) (uint64, error) {
+	return f.truncateTail(tail, false)
+}
+
+// TruncateBodyTail discards all data below the provided threshold number,
+// except the headers, hashes and difficulties of the chain.
+func (f *MemoryFreezer) TruncateBodyTail(tail uint64) (uint64, error) {
+	return f.truncateTail(tail, true)
+}
+
+func (f *MemoryFreezer) truncateTail(tail uint64, keepHeaders bool) (uint64, error) {
 	f.lock.Lock()
 	defer f.lock.Unlock()
 
@@ -374,6 +384,9 @@ func (f *MemoryFreezer) TruncateTail(tail uint64) (uint64, error) {
 		if slices.Contains(additionTables, kind) && table.items == 0 {
 			continue
 		}
+		if keepHeaders && slices.Contains(headerTables, kind) {
+			continue
+		}
 		if table.config.prunable {
 			if err := table.truncateTail(tail); err != nil {
 				return 0, err
@@ -114,6 +114,12 @@ type freezerTable struct {
 	headId uint32              // number of the currently active head file
 	tailId uint32              // number of the earliest file
 
+	// coldHead is the number of the first data file not offloaded to the cold
+	// storage. The data files below are fetched from there if missing locally,
+	// verified against the checksums recorded when offloaded.
+	coldHead uint32
+	coldSums map[uint32]common.Hash
+
 	metadata    *freezerTableMeta // metadata of the table
 	uncommitted uint64            // Count of items written without flushing to file
 	lastSync    time.Time         // Timestamp when the last sync was performed
@@ -185,6 +191,10 @@ func newTable(path string, name string, readMeter, writeMeter *metrics.Meter, si
 	if err != nil {
 		return nil, err
 	}
+	coldHead, coldSums, err := readColdMarker(filepath.Join(path, fmt.Sprintf("%s.cold", name)))
+	if err != nil {
+		return nil, err
+	}
 	// Create the table and repair any past inconsistency
 	tab := &freezerTable{
 		index:       index,
@@ -200,6 +210,8 @@ func newTable(path string, name string, readMeter, writeMeter *metrics.Meter, si
 		config:      config,
 		readonly:    readonly,
 		maxFileSize: maxFilesize,
+		coldHead:    coldHead,
+		coldSums:    coldSums,
 	}
 	if err := tab.repair(); err != nil {
 		tab.Close()
@@ -232,12 +244,16 @@ func (t *freezerTable) repair() error {
 			return err
 		}
 	}
+	size, err := t.indexSize()
+	if err != nil {
+		return err
+	}
 	// Ensure the index is a multiple of indexEntrySize bytes
-	if overflow := stat.Size() % indexEntrySize; overflow != 0 {
+	if overflow := size % indexEntrySize; overflow != 0 {
 		if t.readonly {
 			return fmt.Errorf("index file(path: %s, name: %s) size is not a multiple of %d", t.path, t.name, indexEntrySize)
 		}
-		if err := truncateFreezerFile(t.index, stat.Size()-overflow); err != nil {
+		if err := truncateFreezerFile(t.index, size-overflow); err != nil {
 			return err
 		} // New file can't trigger this path
 	}
@@ -246,10 +262,10 @@ func (t *freezerTable) repair() error {
 	}
 	// Retrieve the file sizes and prepare for truncation. Note the file size
 	// might be changed after index repair.
-	if stat, err = t.index.Stat(); err != nil {
+	offsetsSize, err := t.indexSize()
+	if err != nil {
 		return err
 	}
-	offsetsSize := stat.Size()
 
 	// Open the head file
 	var (
@@ -302,6 +318,9 @@ func (t *freezerTable) repair() error {
 
 	// Keep truncating both files until they come in sync
 	contentExp = int64(lastIndex.offset)
+	if t.config.replica && contentSize > contentExp {
+		contentSize = contentExp // appended to the live table after the snapshot
+	}
 	for contentExp != contentSize {
 		if t.readonly {
 			return fmt.Errorf("freezer table(path: %s, name: %s, num: %d) is corrupted", t.path, t.name, lastIndex.filenum)
@@ -349,6 +368,9 @@ func (t *freezerTable) repair() error {
 			if newLastIndex.filenum != lastIndex.filenum {
 				// Release earlier opened file
 				t.releaseFile(lastIndex.filenum)
+				if err := t.restoreColdFiles(newLastIndex.filenum); err != nil {
+					return err
+				}
 				if t.head, err = t.openFile(newLastIndex.filenum, openFreezerFileForAppend); err != nil {
 					return err
 				}
@@ -400,11 +422,10 @@ func (t *freezerTable) repair() error {
 }
 
 func (t *freezerTable) repairIndex() error {
-	stat, err := t.index.Stat()
+	size, err := t.indexSize()
 	if err != nil {
 		return err
 	}
-	size := stat.Size()
 
 	// Validate the items in the index file to ensure the data integrity.
 	// It's possible some garbage data is retained in the index file after
@@ -464,6 +485,20 @@ func (t *freezerTable) repairIndex() error {
 	}
 }
 
+// indexSize returns the size of the index file. The tables opened from a replica
+// snapshot share the index file with the live table, the entries appended to it
+// after the snapshot are beyond the copied flush offset and ignored.
+func (t *freezerTable) indexSize() (int64, error) {
+	stat, err := t.index.Stat()
+	if err != nil {
+		return 0, err
+	}
+	if t.config.replica && stat.Size() > t.metadata.flushOffset {
+		return t.metadata.flushOffset, nil
+	}
+	return stat.Size(), nil
+}
+
 // checkIndex validates the integrity of the index file. According to the design,
 // the initial entry in the file denotes the earliest data file along with the
 // count of deleted items. Following this, all subsequent entries in the file must
@@ -592,8 +627,19 @@ func (t *freezerTable) preopen() (err error) {
 	// The repair might have already opened (some) files
 	t.releaseFilesAfter(0, false)
 
-	// Open all except head in RDONLY
+	// Open all except head in RDONLY, the offloaded files are fetched from the
+	// cold storage on demand.
 	for i := t.tailId; i < t.headId; i++ {
+		if i < t.coldHead {
+			path := filepath.Join(t.path, t.fileName(i))
+			if t.config.cold != nil && !t.readonly {
+				// Drop the local leftover of an interrupted offloading
+				os.Remove(path)
+			}
+			if !common.FileExist(path) {
+				continue
+			}
+		}
 		if _, err = t.openFile(i, openFreezerFileForReadOnly); err != nil {
 			return err
 		}
@@ -667,6 +713,9 @@ func (t *freezerTable) truncateHead(items uint64) error {
 	if expected.filenum != t.headId {
 		// If already open for reading, force-reopen for writing
 		t.releaseFile(expected.filenum)
+		if err := t.restoreColdFiles(expected.filenum); err != nil {
+			return err
+		}
 		newHead, err := t.openFile(expected.filenum, openFreezerFileForAppend)
 		if err != nil {
 			return err
@@ -818,9 +867,11 @@ func (t *freezerTable) truncateTail(items uint64) error {
 		return err
 	}
 	// Release any files before the current tail
+	prevTailId := t.tailId
 	t.tailId = newTailId
 	t.itemOffset.Store(newDeleted)
 	t.releaseFilesBefore(t.tailId, true)
+	t.removeColdFiles(prevTailId, t.tailId)
 
 	// Move the index flush offset backward due to the deletion of an index segment.
 	// A crash may occur before the offset is updated, leaving a dangling reference
@@ -881,13 +932,7 @@ func (t *freezerTable) Close() error {
 func (t *freezerTable) openFile(num uint32, opener func(string) (*os.File, error)) (f *os.File, err error) {
 	var exist bool
 	if f, exist = t.files[num]; !exist {
-		var name string
-		if t.config.noSnappy {
-			name = fmt.Sprintf("%s.%04d.rdat", t.name, num)
-		} else {
-			name = fmt.Sprintf("%s.%04d.cdat", t.name, num)
-		}
-		f, err = opener(filepath.Join(t.path, name))
+		f, err = opener(filepath.Join(t.path, t.fileName(num)))
 		if err != nil {
 			return nil, err
 		}
@@ -896,6 +941,14 @@ func (t *freezerTable) openFile(num uint32, opener func(string) (*os.File, error
 	return f, err
 }
 
+// fileName returns the name of the data file with the given number.
+func (t *freezerTable) fileName(num uint32) string {
+	if t.config.noSnappy {
+		return fmt.Sprintf("%s.%04d.rdat", t.name, num)
+	}
+	return fmt.Sprintf("%s.%04d.cdat", t.name, num)
+}
+
 // releaseFile closes a file, and removes it from the open file cache.
 // Assumes that the caller holds the write lock
 func (t *freezerTable) releaseFile(num uint32) {
@@ -1054,7 +1107,7 @@ func (t *freezerTable) retrieveItems(start, count, maxBytes uint64) ([]byte, []i
 		output = grow(output, length)
 		dataFile, exist := t.files[fileId]
 		if !exist {
-			return fmt.Errorf("missing data file %d", fileId)
+			return t.readCold(fileId, output[len(output)-length:], int64(start))
 		}
 		if _, err := dataFile.ReadAt(output[len(output)-length:], int64(start)); err != nil {
 			return fmt.Errorf("%w, fileid: %d, start: %d, length: %d", err, fileId, start, length)
@@ -1117,6 +1170,16 @@ func (t *freezerTable) retrieveItems(start, count, maxBytes uint64) ([]byte, []i
 	return output, sizes, nil
 }
 
+// readFile reads the given data file at the given offset, fetching it from the
+// cold storage if offloaded. It assumes the read-lock is held by the caller.
+func (t *freezerTable) readFile(num uint32, p []byte, off int64) error {
+	if f, exist := t.files[num]; exist {
+		_, err := f.ReadAt(p, off)
+		return err
+	}
+	return t.readCold(num, p, off)
+}
+
 // RetrieveBytes retrieves the value segment of the element specified by the id
 // and value offsets.
 func (t *freezerTable) RetrieveBytes(item, offset, length uint64) ([]byte, error) {
@@ -1141,11 +1204,6 @@ func (t *freezerTable) RetrieveBytes(item, offset, length uint64) ([]byte, error
 	itemStart, itemLimit, fileId := index0.bounds(index1)
 	itemSize := itemLimit - itemStart
 
-	dataFile, exist := t.files[fileId]
-	if !exist {
-		return nil, fmt.Errorf("missing data file %d", fileId)
-	}
-
 	// Perform the partial read if no-compression was enabled upon
 	if t.config.noSnappy {
 		if offset > uint64(itemSize) || offset+length > uint64(itemSize) {
@@ -1154,7 +1212,7 @@ func (t *freezerTable) RetrieveBytes(item, offset, length uint64) ([]byte, error
 		itemStart += uint32(offset)
 
 		buf := make([]byte, length)
-		_, err = dataFile.ReadAt(buf, int64(itemStart))
+		err = t.readFile(fileId, buf, int64(itemStart))
 		if err != nil {
 			return nil, err
 		}
@@ -1165,7 +1223,7 @@ func (t *freezerTable) RetrieveBytes(item, offset, length uint64) ([]byte, error
 		// Unfortunately, in this case, there is no performance gain
 		// by performing the partial read at all.
 		buf := make([]byte, itemSize)
-		_, err = dataFile.ReadAt(buf, int64(itemStart))
+		err = t.readFile(fileId, buf, int64(itemStart))
 		if err != nil {
 			return nil, err
 		}
@@ -567,6 +567,62 @@ func TestFreezer_ResetTailMeta_EmptyTable(t *testing.T) {
 	require.Equal(t, item, actual)
 }
 
+// Tests that truncating the body tail keeps the header tables, also across a
+// restart, and that a full tail truncation drops them again.
+func TestFreezerTruncateBodyTail(t *testing.T) {
+	tables := map[string]freezerTableConfig{
+		ChainFreezerHeaderTable: {noSnappy: true, prunable: true},
+		ChainFreezerBodiesTable: {noSnappy: true, prunable: true},
+	}
+	f, dir := newFreezerForTesting(t, tables)
+	item := make([]byte, 512)
+	_, err := f.ModifyAncients(func(op ethdb.AncientWriteOp) error {
+		for i := uint64(0); i < 10; i++ {
+			if err := appendSameItem(op, []string{ChainFreezerHeaderTable, ChainFreezerBodiesTable}, i, item); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	require.NoError(t, err)
+
+	_, err = f.TruncateBodyTail(5)
+	require.NoError(t, err)
+	check := func(f *Freezer, tail, headerTail uint64) {
+		t.Helper()
+
+		have, _ := f.Tail()
+		require.Equal(t, tail, have)
+		_, err := f.Ancient(ChainFreezerBodiesTable, tail-1)
+		require.Error(t, err)
+		_, err = f.Ancient(ChainFreezerBodiesTable, tail)
+		require.NoError(t, err)
+		_, err = f.Ancient(ChainFreezerHeaderTable, headerTail)
+		require.NoError(t, err)
+		if headerTail > 0 {
+			_, err = f.Ancient(ChainFreezerHeaderTable, headerTail-1)
+			require.Error(t, err)
+		}
+	}
+	check(f, 5, 0)
+	require.NoError(t, f.Close())
+
+	// Neither the validation nor the repair on reopening may align the header tail
+	f, err = NewFreezer(dir, "", true, 2049, tables, false)
+	require.NoError(t, err)
+	check(f, 5, 0)
+	require.NoError(t, f.Close())
+
+	f, err = NewFreezer(dir, "", false, 2049, tables, false)
+	require.NoError(t, err)
+	check(f, 5, 0)
+
+	_, err = f.TruncateTail(7)
+	require.NoError(t, err)
+	check(f, 7, 7)
+	require.NoError(t, f.Close())
+}
+
 func appendSameItem(op ethdb.AncientWriteOp, tables []string, i uint64, item []byte) error {
 	for _, t := range tables {
 		if err := op.AppendRaw(t, i, item); err != nil {
@@ -0,0 +1,556 @@
+package rawdb
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+const (
+	// replicaSnapshotLatest is the file in the replica snapshot directory
+	// holding the name of the latest snapshot.
+	replicaSnapshotLatest = "LATEST"
+
+	// replicaSnapshotsKept is the number of replica snapshots kept, giving the
+	// read replicas time to link the latest one before it's deleted.
+	replicaSnapshotsKept = 3
+)
+
+var (
+	// errReplicaReadOnly is returned if a write is attempted on a read replica.
+	errReplicaReadOnly = errors.New("read replica is read only")
+
+	// errReplicaSnapshotUnsupported is returned if replica snapshots are
+	// requested for a database not supporting them.
+	errReplicaSnapshotUnsupported = errors.New("replica snapshots require a pebble database with a file-based freezer")
+)
+
+// CreateReplicaSnapshot publishes a point-in-time snapshot of the chain database
+// in the given directory, to be opened by the read replicas without sharing the
+// database files locked by the writer. The key-value store is checkpointed first
+// and the chain freezer is hard-linked afterwards, so the snapshot contains all
+// the items moved from the key-value store to the freezer meanwhile.
+func CreateReplicaSnapshot(db ethdb.Database, dir string) error {
+	// Unwrap the database from the node tracking its closure
+	for {
+		wrapper, ok := db.(interface{ Unwrap() ethdb.Database })
+		if !ok {
+			break
+		}
+		db = wrapper.Unwrap()
+	}
+	frdb, ok := db.(*freezerdb)
+	if !ok || frdb.stateStore != nil {
+		return errReplicaSnapshotUnsupported
+	}
+	kvdb, ok := frdb.KeyValueStore.(interface{ Checkpoint(dir string) error })
+	if !ok {
+		return errReplicaSnapshotUnsupported
+	}
+	freezer, ok := frdb.chainFreezer.ancients.(*Freezer)
+	if !ok {
+		return errReplicaSnapshotUnsupported
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return err
+	}
+	seqs, err := replicaSnapshots(dir)
+	if err != nil {
+		return err
+	}
+	var seq uint64
+	if len(seqs) > 0 {
+		seq = seqs[len(seqs)-1] + 1
+	}
+	name := fmt.Sprintf("%016x", seq)
+	tmp := filepath.Join(dir, name+".tmp")
+	if err := os.RemoveAll(tmp); err != nil {
+		return err
+	}
+	if err := kvdb.Checkpoint(tmp); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	if err := freezer.snapshot(filepath.Join(tmp, "ancient", ChainFreezerName)); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	if err := os.Rename(tmp, filepath.Join(dir, name)); err != nil {
+		os.RemoveAll(tmp)
+		return err
+	}
+	if err := os.WriteFile(filepath.Join(dir, replicaSnapshotLatest+".tmp"), []byte(name), 0644); err != nil {
+		return err
+	}
+	if err := os.Rename(filepath.Join(dir, replicaSnapshotLatest+".tmp"), filepath.Join(dir, replicaSnapshotLatest)); err != nil {
+		return err
+	}
+	// Delete the snapshots superseded for long enough
+	for _, old := range seqs {
+		if old+replicaSnapshotsKept > seq {
+			break
+		}
+		if err := os.RemoveAll(filepath.Join(dir, fmt.Sprintf("%016x", old))); err != nil {
+			log.Warn("Failed to delete replica snapshot", "seq", old, "err", err)
+		}
+	}
+	return nil
+}
+
+// replicaSnapshots returns the sequence numbers of the published replica
+// snapshots in ascending order.
+func replicaSnapshots(dir string) ([]uint64, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, err
+	}
+	var seqs []uint64
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		seq, err := strconv.ParseUint(entry.Name(), 16, 64)
+		if err != nil {
+			continue // leftover of an interrupted snapshot
+		}
+		seqs = append(seqs, seq)
+	}
+	return seqs, nil // sorted by name, i.e. by sequence number
+}
+
+// snapshot hard-links the files of the freezer into the given directory. The
+// tables are flushed first, so the flush offsets in the copied metadata files
+// are consistent across the tables, while the items appended afterwards to
+// the linked files are beyond them and ignored by the replica.
+func (f *Freezer) snapshot(dir string) error {
+	f.writeLock.Lock()
+	defer f.writeLock.Unlock()
+
+	if err := f.SyncAncient(); err != nil {
+		return err
+	}
+	return linkReplicaFiles(f.datadir, dir)
+}
+
+// linkReplicaFiles hard-links the files of the source directory into the
+// destination one recursively. The freezer files rewritten in place are copied
+// instead, after the others are linked, while the lock files, the local cache
+// of the cold storage and the temporary files are skipped.
+func linkReplicaFiles(src string, dst string) error {
+	entries, err := os.ReadDir(src)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(dst, 0755); err != nil {
+		return err
+	}
+	var copies []string
+	for _, entry := range entries {
+		name := entry.Name()
+		switch {
+		case name == "FLOCK" || name == "LOCK" || name == coldCacheDir || strings.HasSuffix(name, ".tmp"):
+			continue
+		case entry.IsDir():
+			if err := linkReplicaFiles(filepath.Join(src, name), filepath.Join(dst, name)); err != nil {
+				return err
+			}
+		case strings.HasSuffix(name, ".meta") || strings.HasSuffix(name, ".cold"):
+			copies = append(copies, name)
+		default:
+			if err := os.Link(filepath.Join(src, name), filepath.Join(dst, name)); err != nil {
+				return err
+			}
+		}
+	}
+	for _, name := range copies {
+		if err := copyFrom(filepath.Join(src, name), filepath.Join(dst, name), 0, nil); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// OpenReplicaSnapshot opens the latest replica snapshot published in the given
+// directory. The snapshot is hard-linked into a private directory within dir,
+// which is opened with the given function and deleted once the database is
+// closed. The replica directory must be on the same file system as the
+// snapshots.
+func OpenReplicaSnapshot(source string, dir string, open func(path string) (ethdb.Database, error)) (ethdb.Database, error) {
+	name, err := os.ReadFile(filepath.Join(source, replicaSnapshotLatest))
+	if err != nil {
+		return nil, err
+	}
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, err
+	}
+	path, err := os.MkdirTemp(dir, string(name)+"-")
+	if err != nil {
+		return nil, err
+	}
+	if err := linkReplicaFiles(filepath.Join(source, string(name)), path); err != nil {
+		os.RemoveAll(path)
+		return nil, err
+	}
+	db, err := open(path)
+	if err != nil {
+		os.RemoveAll(path)
+		return nil, err
+	}
+	return &replicaSnapshot{Database: db, path: path}, nil
+}
+
+// replicaSnapshot is a database opened from a private link of a replica
+// snapshot, which is deleted on closure.
+type replicaSnapshot struct {
+	ethdb.Database
+	path string
+}
+
+// Close closes the database and deletes its files.
+func (db *replicaSnapshot) Close() error {
+	err := db.Database.Close()
+	if rerr := os.RemoveAll(db.path); err == nil {
+		err = rerr
+	}
+	return err
+}
+
+// replicaView is an opened snapshot of the database published by the writer,
+// which is closed once it's replaced and no longer referenced.
+type replicaView struct {
+	db   ethdb.Database
+	refs sync.WaitGroup
+}
+
+func (v *replicaView) release() {
+	v.refs.Done()
+}
+
+// ReplicaDatabase is a read-only database following the snapshots of the chain
+// database published by another process writing to it. The opened view is a
+// point-in-time snapshot of the data, the writes happening afterwards only
+// become visible once the database is reopened on a later snapshot. Each call is served by a single view, and the replaced views
+// are closed once the calls and iterators using them are finished.
+type ReplicaDatabase struct {
+	open func() (ethdb.Database, error)
+
+	view   *replicaView
+	env    *ethdb.FreezerEnv
+	blocks uint64
+	closed bool
+	lock   sync.RWMutex
+}
+
+// NewReplicaDatabase creates a read replica with the given function opening the
+// latest snapshot of the database.
+func NewReplicaDatabase(open func() (ethdb.Database, error)) (*ReplicaDatabase, error) {
+	db, err := open()
+	if err != nil {
+		return nil, err
+	}
+	view := &replicaView{db: db}
+	view.refs.Add(1) // reference held by the replica until replaced
+	return &ReplicaDatabase{open: open, view: view}, nil
+}
+
+// Reopen opens the latest snapshot of the database to observe the latest writes.
+// The prepare callback is invoked with the new view before it replaces the
+// current one, the new view is discarded if it fails.
+func (db *ReplicaDatabase) Reopen(prepare func(next ethdb.Database) error) error {
+	next, err := db.open()
+	if err != nil {
+		return err
+	}
+	db.lock.RLock()
+	env, blocks := db.env, db.blocks
+	db.lock.RUnlock()
+	if env != nil {
+		if err := next.SetupFreezerEnv(env, blocks); err != nil {
+			next.Close()
+			return err
+		}
+	}
+	if prepare != nil {
+		if err := prepare(next); err != nil {
+			next.Close()
+			return err
+		}
+	}
+	view := &replicaView{db: next}
+	view.refs.Add(1)
+
+	db.lock.Lock()
+	if db.closed {
+		db.lock.Unlock()
+		next.Close()
+		return errors.New("read replica closed")
+	}
+	prev := db.view
+	db.view = view
+	db.lock.Unlock()
+
+	go closeReplicaView(prev)
+	return nil
+}
+
+// closeReplicaView closes the view once it's no longer referenced.
+func closeReplicaView(view *replicaView) {
+	view.release()
+	view.refs.Wait()
+	if err := view.db.Close(); err != nil {
+		log.Warn("Failed to close replica database view", "err", err)
+	}
+}
+
+// acquire returns the current view, which must be released after use.
+func (db *ReplicaDatabase) acquire() *replicaView {
+	db.lock.RLock()
+	defer db.lock.RUnlock()
+
+	view := db.view
+	view.refs.Add(1)
+	return view
+}
+
+// Close closes the current view after the pending calls are finished.
+func (db *ReplicaDatabase) Close() error {
+	db.lock.Lock()
+	if db.closed {
+		db.lock.Unlock()
+		return nil
+	}
+	db.closed = true
+	view := db.view
+	db.lock.Unlock()
+
+	view.release()
+	view.refs.Wait()
+	return view.db.Close()
+}
+
+// Has retrieves if a key is present in the current view.
+func (db *ReplicaDatabase) Has(key []byte) (bool, error) {
+	view := db.acquire()
+	defer view.release()
+	return view.db.Has(key)
+}
+
+// Get retrieves the given key from the current view.
+func (db *ReplicaDatabase) Get(key []byte) ([]byte, error) {
+	view := db.acquire()
+	defer view.release()
+	return view.db.Get(key)
+}
+
+// Put is not supported by the read replica.
+func (db *ReplicaDatabase) Put(key []byte, value []byte) error {
+	return errReplicaReadOnly
+}
+
+// Delete is not supported by the read replica.
+func (db *ReplicaDatabase) Delete(key []byte) error {
+	return errReplicaReadOnly
+}
+
+// DeleteRange is not supported by the read replica.
+func (db *ReplicaDatabase) DeleteRange(start, end []byte) error {
+	return errReplicaReadOnly
+}
+
+// NewIterator creates an iterator over the current view, which is kept open
+// until the iterator is released.
+func (db *ReplicaDatabase) NewIterator(prefix []byte, start []byte) ethdb.Iterator {
+	view := db.acquire()
+	return &replicaIterator{Iterator: view.db.NewIterator(prefix, start), view: view}
+}
+
+// Stat returns the statistic data of the current view.
+func (db *ReplicaDatabase) Stat() (string, error) {
+	view := db.acquire()
+	defer view.release()
+	return view.db.Stat()
+}
+
+// Compact is not supported by the read replica.
+func (db *ReplicaDatabase) Compact(start []byte, limit []byte) error {
+	return errReplicaReadOnly
+}
+
+// SyncKeyValue is a noop, the read replica has nothing to flush.
+func (db *ReplicaDatabase) SyncKeyValue() error {
+	return nil
+}
+
+// NewBatch creates a batch which can't be written to the read replica.
+func (db *ReplicaDatabase) NewBatch() ethdb.Batch {
+	return replicaBatch{}
+}
+
+// NewBatchWithSize creates a batch which can't be written to the read replica.
+func (db *ReplicaDatabase) NewBatchWithSize(size int) ethdb.Batch {
+	return replicaBatch{}
+}
+
+// Ancient retrieves an ancient binary blob from the current view.
+func (db *ReplicaDatabase) Ancient(kind string, number uint64) ([]byte, error) {
+	view := db.acquire()
+	defer view.release()
+	return view.db.Ancient(kind, number)
+}
+
+// AncientRange retrieves multiple items in sequence from the current view.
+func (db *ReplicaDatabase) AncientRange(kind string, start, count, maxBytes uint64) ([][]byte, error) {
+	view := db.acquire()
+	defer view.release()
+	return view.db.AncientRange(kind, start, count, maxBytes)
+}
+
+// AncientBytes retrieves the value segment of the element from the current view.
+func (db *ReplicaDatabase) AncientBytes(kind string, id, offset, length uint64) ([]byte, error) {
+	view := db.acquire()
+	defer view.release()
+	return view.db.AncientBytes(kind, id, offset, length)
+}
+
+// Ancients returns the ancient item numbers in the current view.
+func (db *ReplicaDatabase) Ancients() (uint64, error) {
+	view := db.acquire()
+	defer view.release()
+	return view.db.Ancients()
+}
+
+// Tail returns the number of first stored item in the current view.
+func (db *ReplicaDatabase) Tail() (uint64, error) {
+	view := db.acquire()
+	defer view.release()
+	return view.db.Tail()
+}
+
+// AncientSize returns the ancient size of the specified category in the
+// current view.
+func (db *ReplicaDatabase) AncientSize(kind string) (uint64, error) {
+	view := db.acquire()
+	defer view.release()
+	return view.db.AncientSize(kind)
+}
+
+// ReadAncients runs the given read operation on the current view.
+func (db *ReplicaDatabase) ReadAncients(fn func(ethdb.AncientReaderOp) error) (err error) {
+	view := db.acquire()
+	defer view.release()
+	return view.db.ReadAncients(fn)
+}
+
+// AncientDatadir returns the ancient datadir of the current view.
+func (db *ReplicaDatabase) AncientDatadir() (string, error) {
+	view := db.acquire()
+	defer view.release()
+	return view.db.AncientDatadir()
+}
+
+// ModifyAncients is not supported by the read replica.
+func (db *ReplicaDatabase) ModifyAncients(func(ethdb.AncientWriteOp) error) (int64, error) {
+	return 0, errReplicaReadOnly
+}
+
+// SyncAncient is a noop, the read replica has nothing to flush.
+func (db *ReplicaDatabase) SyncAncient() error {
+	return nil
+}
+
+// TruncateHead is not supported by the read replica.
+func (db *ReplicaDatabase) TruncateHead(n uint64) (uint64, error) {
+	return 0, errReplicaReadOnly
+}
+
+// TruncateTail is not supported by the read replica.
+func (db *ReplicaDatabase) TruncateTail(n uint64) (uint64, error) {
+	return 0, errReplicaReadOnly
+}
+
+// TruncateTableTail is not supported by the read replica.
+func (db *ReplicaDatabase) TruncateTableTail(kind string, tail uint64) (uint64, error) {
+	return 0, errReplicaReadOnly
+}
+
+// ResetTable is not supported by the read replica.
+func (db *ReplicaDatabase) ResetTable(kind string, startAt uint64, onlyEmpty bool) error {
+	return errReplicaReadOnly
+}
+
+// ResetTableForIncr is not supported by the read replica.
+func (db *ReplicaDatabase) ResetTableForIncr(kind string, startAt uint64, onlyEmpty bool) error {
+	return errReplicaReadOnly
+}
+
+// SetupFreezerEnv sets up the freezer environment of the current view and of
+// the views opened afterwards.
+func (db *ReplicaDatabase) SetupFreezerEnv(env *ethdb.FreezerEnv, blockHistory uint64) error {
+	db.lock.Lock()
+	db.env, db.blocks = env, blockHistory
+	db.lock.Unlock()
+
+	view := db.acquire()
+	defer view.release()
+	return view.db.SetupFreezerEnv(env, blockHistory)
+}
+
+// CleanBlock is not supported by the read replica.
+func (db *ReplicaDatabase) CleanBlock(kvStore ethdb.KeyValueStore, start uint64) error {
+	return errReplicaReadOnly
+}
+
+// SetStateStore is not supported by the read replica, the separate state
+// database is unsupported.
+func (db *ReplicaDatabase) SetStateStore(state ethdb.Database) {
+	log.Error("Separate state database is not supported by read replicas")
+}
+
+// GetStateStore returns nil, the separate state database is unsupported.
+func (db *ReplicaDatabase) GetStateStore() ethdb.Database {
+	return nil
+}
+
+// HasSeparateStateStore returns false, the separate state database is
+// unsupported.
+func (db *ReplicaDatabase) HasSeparateStateStore() bool {
+	return false
+}
+
+// StateStoreReader returns the read replica itself.
+func (db *ReplicaDatabase) StateStoreReader() ethdb.Reader {
+	return db
+}
+
+// replicaIterator is an iterator keeping its view open until released.
+type replicaIterator struct {
+	ethdb.Iterator
+	view *replicaView
+	once sync.Once
+}
+
+// Release releases the iterator and the view it reads from.
+func (it *replicaIterator) Release() {
+	it.once.Do(func() {
+		it.Iterator.Release()
+		it.view.release()
+	})
+}
+
+// replicaBatch is a batch which can't be written to the read replica.
+type replicaBatch struct{}
+
+func (replicaBatch) Put(key []byte, value []byte) error  { return errReplicaReadOnly }
+func (replicaBatch) Delete(key []byte) error             { return errReplicaReadOnly }
+func (replicaBatch) DeleteRange(start, end []byte) error { return errReplicaReadOnly }
+func (replicaBatch) ValueSize() int                      { return 0 }
+func (replicaBatch) Write() error                        { return errReplicaReadOnly }
+func (replicaBatch) Reset()                              {}
+func (replicaBatch) Replay(w ethdb.KeyValueWriter) error { return nil }
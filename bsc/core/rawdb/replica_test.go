@@ -0,0 +1,205 @@
+package rawdb
+
+import (
+	"bytes"
+	"encoding/binary"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/ethdb/pebble"
+)
+
+func TestReplicaDatabaseReopen(t *testing.T) {
+	var (
+		writer = memorydb.New()
+		views  []ethdb.Database
+	)
+	writer.Put([]byte("a"), []byte{1})
+
+	// Every view is a snapshot of the data written so far
+	db, err := NewReplicaDatabase(func() (ethdb.Database, error) {
+		kv := memorydb.New()
+		it := writer.NewIterator(nil, nil)
+		for it.Next() {
+			kv.Put(it.Key(), it.Value())
+		}
+		it.Release()
+		views = append(views, NewDatabase(kv))
+		return views[len(views)-1], nil
+	})
+	if err != nil {
+		t.Fatalf("failed to open replica: %v", err)
+	}
+	defer db.Close()
+
+	if err := db.Put([]byte("b"), []byte{2}); err != errReplicaReadOnly {
+		t.Fatalf("unexpected write error: %v", err)
+	}
+	if err := db.NewBatch().Write(); err != errReplicaReadOnly {
+		t.Fatalf("unexpected batch write error: %v", err)
+	}
+	it := db.NewIterator(nil, nil)
+
+	// The new writes are only visible after reopening
+	writer.Put([]byte("b"), []byte{2})
+	if ok, _ := db.Has([]byte("b")); ok {
+		t.Fatal("write observed before reopening")
+	}
+	var prepared ethdb.Database
+	if err := db.Reopen(func(next ethdb.Database) error {
+		prepared = next
+		return nil
+	}); err != nil {
+		t.Fatalf("failed to reopen: %v", err)
+	}
+	if prepared != views[1] {
+		t.Fatal("prepare not invoked with the new view")
+	}
+	if blob, _ := db.Get([]byte("b")); !bytes.Equal(blob, []byte{2}) {
+		t.Fatalf("unexpected value after reopening: %x", blob)
+	}
+	// The replaced view is kept open until the iterator is released
+	var keys int
+	for it.Next() {
+		keys++
+	}
+	it.Release()
+	if keys != 1 {
+		t.Fatalf("unexpected key count of the replaced view: have %d, want 1", keys)
+	}
+}
+
+func TestReplicaSnapshot(t *testing.T) {
+	// Restore the addition tables replaced by the freezer tests
+	defer func(tables []string) { additionTables = tables }(additionTables)
+	additionTables = []string{ChainFreezerBlobSidecarTable}
+
+	var (
+		chaindata = filepath.Join(t.TempDir(), "chaindata")
+		snapshots = t.TempDir()
+		views     = t.TempDir()
+		blocks    = makeTestBlocks(10, 1)
+		receipts  = types.EncodeBlockReceiptLists(makeTestReceipts(10, 1))
+	)
+	kvdb, err := pebble.New(chaindata, 16, 16, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	db, err := Open(kvdb, OpenOptions{Ancient: filepath.Join(chaindata, "ancient")})
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := WriteAncientBlocks(db, blocks[:5], receipts[:5], big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	db.Put([]byte("a"), []byte{1})
+	if err := CreateReplicaSnapshot(db, snapshots); err != nil {
+		t.Fatalf("failed to create snapshot: %v", err)
+	}
+	// The writes after the snapshot are appended to the linked files
+	if _, err := WriteAncientBlocks(db, blocks[5:], receipts[5:], big.NewInt(100)); err != nil {
+		t.Fatal(err)
+	}
+	db.Put([]byte("b"), []byte{2})
+	if err := db.SyncAncient(); err != nil {
+		t.Fatal(err)
+	}
+	open := func(path string) (ethdb.Database, error) {
+		kvdb, err := pebble.New(path, 16, 16, "", true)
+		if err != nil {
+			return nil, err
+		}
+		db, err := Open(kvdb, OpenOptions{Ancient: filepath.Join(path, "ancient"), Replica: true})
+		if err != nil {
+			kvdb.Close()
+		}
+		return db, err
+	}
+	replica, err := OpenReplicaSnapshot(snapshots, views, open)
+	if err != nil {
+		t.Fatalf("failed to open snapshot: %v", err)
+	}
+	if frozen, _ := replica.Ancients(); frozen != 5 {
+		t.Fatalf("unexpected frozen items: have %d, want 5", frozen)
+	}
+	if hash := ReadCanonicalHash(replica, 4); hash != blocks[4].Hash() {
+		t.Fatalf("unexpected canonical hash: have %x, want %x", hash, blocks[4].Hash())
+	}
+	if ok, _ := replica.Has([]byte("a")); !ok {
+		t.Fatal("snapshot misses a write")
+	}
+	if ok, _ := replica.Has([]byte("b")); ok {
+		t.Fatal("snapshot observed a later write")
+	}
+	if err := replica.Put([]byte("c"), []byte{3}); err == nil {
+		t.Fatal("snapshot write succeeded")
+	}
+	if err := replica.Close(); err != nil {
+		t.Fatal(err)
+	}
+	if entries, _ := os.ReadDir(views); len(entries) != 0 {
+		t.Fatalf("snapshot view kept after closure: %d entries", len(entries))
+	}
+	// The writer is unaffected by the replica
+	if frozen, _ := db.Ancients(); frozen != 10 {
+		t.Fatalf("unexpected frozen items of the writer: have %d, want 10", frozen)
+	}
+	// Only the recent snapshots are kept
+	for i := 0; i < replicaSnapshotsKept; i++ {
+		if err := CreateReplicaSnapshot(db, snapshots); err != nil {
+			t.Fatalf("failed to create snapshot: %v", err)
+		}
+	}
+	if seqs, _ := replicaSnapshots(snapshots); len(seqs) != replicaSnapshotsKept || seqs[0] != 1 {
+		t.Fatalf("unexpected snapshots: %v", seqs)
+	}
+	replica, err = OpenReplicaSnapshot(snapshots, views, open)
+	if err != nil {
+		t.Fatalf("failed to open snapshot: %v", err)
+	}
+	defer replica.Close()
+	if frozen, _ := replica.Ancients(); frozen != 10 {
+		t.Fatalf("unexpected frozen items: have %d, want 10", frozen)
+	}
+}
+
+func BenchmarkCreateReplicaSnapshot(b *testing.B) {
+	defer func(tables []string) { additionTables = tables }(additionTables)
+	additionTables = []string{ChainFreezerBlobSidecarTable}
+
+	var (
+		chaindata = filepath.Join(b.TempDir(), "chaindata")
+		snapshots = b.TempDir()
+		blocks    = makeTestBlocks(1000, 10)
+		receipts  = types.EncodeBlockReceiptLists(makeTestReceipts(1000, 10))
+	)
+	kvdb, err := pebble.New(chaindata, 16, 16, "", false)
+	if err != nil {
+		b.Fatal(err)
+	}
+	db, err := Open(kvdb, OpenOptions{Ancient: filepath.Join(chaindata, "ancient")})
+	if err != nil {
+		b.Fatal(err)
+	}
+	defer db.Close()
+
+	if _, err := WriteAncientBlocks(db, blocks, receipts, big.NewInt(100)); err != nil {
+		b.Fatal(err)
+	}
+	for i := 0; i < 10000; i++ {
+		db.Put(binary.BigEndian.AppendUint32([]byte("bench-"), uint32(i)), bytes.Repeat([]byte{byte(i)}, 100))
+	}
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if err := CreateReplicaSnapshot(db, snapshots); err != nil {
+			b.Fatalf("failed to create snapshot: %v", err)
+		}
+	}
+}
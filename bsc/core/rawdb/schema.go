@@ -87,6 +87,9 @@ var (
 	// txIndexTailKey tracks the oldest block whose transactions have been indexed.
 	txIndexTailKey = []byte("TransactionIndexTail")
 
+	// statePruneProgressKey tracks the progress of the online state pruning.
+	statePruneProgressKey = []byte("StatePruneProgress")
+
 	// fastTxLookupLimitKey tracks the transaction lookup limit during fast sync.
 	// This flag is deprecated, it's kept to avoid reporting errors when inspect
 	// database.
@@ -151,6 +154,16 @@ var (
 
 	BlockBALPrefix = []byte("bal") // blockBALPrefix + blockNumber (uint64 big endian) + blockHash -> block access list
 
+	NativeTransfersPrefix = []byte("native-transfers-") // NativeTransfersPrefix + num (uint64 big endian) + hash -> native transfers
+
+	ReplicaLayerPrefix = []byte("replica-layer-") // ReplicaLayerPrefix + state id (uint64 big endian) + state root -> diff layer
+
+	// address index
+	addressIndexPrefix    = "X"
+	addressIndexRangeKey  = []byte(addressIndexPrefix + "R") // tracks the range of blocks whose transactions have been indexed
+	AddressTxPrefix       = []byte(addressIndexPrefix + "t") // AddressTxPrefix + address + position -> tx hash
+	AddressTransferPrefix = []byte(addressIndexPrefix + "e") // AddressTransferPrefix + address + token + position -> tx hash
+
 	// new log index
 	filterMapsPrefix         = "fm-"
 	filterMapsRangeKey       = []byte(filterMapsPrefix + "R")
@@ -229,6 +242,37 @@ func blockBALKey(number uint64, hash common.Hash) []byte {
 	return append(append(BlockBALPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
 }
 
+// nativeTransfersKey = NativeTransfersPrefix + num (uint64 big endian) + hash
+func nativeTransfersKey(number uint64, hash common.Hash) []byte {
+	return append(append(NativeTransfersPrefix, encodeBlockNumber(number)...), hash.Bytes()...)
+}
+
+// replicaLayerKey = ReplicaLayerPrefix + id (uint64 big endian) + root
+func replicaLayerKey(id uint64, root common.Hash) []byte {
+	return append(append(ReplicaLayerPrefix, encodeBlockNumber(id)...), root.Bytes()...)
+}
+
+// addressTxPosition encodes the position of a transaction in the address index,
+// inverting the block number and the transaction index to order the entries
+// from the most recent.
+func addressTxPosition(number uint64, index uint32) []byte {
+	pos := make([]byte, 12)
+	binary.BigEndian.PutUint64(pos, ^number)
+	binary.BigEndian.PutUint32(pos[8:], ^index)
+	return pos
+}
+
+// addressTxKey = AddressTxPrefix + address + position
+func addressTxKey(address common.Address, number uint64, index uint32) []byte {
+	return append(append(AddressTxPrefix, address.Bytes()...), addressTxPosition(number, index)...)
+}
+
+// addressTransferKey = AddressTransferPrefix + address + token + position
+func addressTransferKey(address common.Address, token common.Address, number uint64, index uint32) []byte {
+	key := append(append(AddressTransferPrefix, address.Bytes()...), token.Bytes()...)
+	return append(key, addressTxPosition(number, index)...)
+}
+
 // txLookupKey = txLookupPrefix + hash
 func txLookupKey(hash common.Hash) []byte {
 	return append(txLookupPrefix, hash.Bytes()...)
@@ -23,10 +23,13 @@ import (
 	"github.com/ethereum/go-ethereum/common/lru"
 	"github.com/ethereum/go-ethereum/core/state/snapshot"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/ethdb"
 	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
 	"github.com/ethereum/go-ethereum/trie/utils"
 	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/ethereum/go-ethereum/triedb/database"
 	"github.com/ethereum/go-ethereum/triedb/pathdb"
 )
 
@@ -98,6 +101,18 @@ func (r *historicReader) Storage(addr common.Address, key common.Hash) (common.H
 	return slot, nil
 }
 
+// historicNodeDB implements the database.NodeDatabase interface, providing
+// the trie nodes of historic states for trie construction.
+type historicNodeDB struct {
+	db *triedb.Database
+}
+
+// NodeReader implements database.NodeDatabase, returning a node reader of the
+// specified historic state.
+func (db *historicNodeDB) NodeReader(stateRoot common.Hash) (database.NodeReader, error) {
+	return db.db.HistoricNodeReader(stateRoot)
+}
+
 // HistoricDB is the implementation of Database interface, with the ability to
 // access historical state.
 type HistoricDB struct {
@@ -128,15 +143,22 @@ func (db *HistoricDB) Reader(stateRoot common.Hash) (Reader, error) {
 	return newReader(newCachingCodeReader(db.disk, db.codeCache, db.codeSizeCache), newHistoricReader(hr)), nil
 }
 
-// OpenTrie opens the main account trie. It's not supported by historic database.
+// OpenTrie opens the main account trie, resolving the trie nodes from the
+// trienode history.
 func (db *HistoricDB) OpenTrie(root common.Hash) (Trie, error) {
-	return nil, errors.New("not implemented")
+	if db.NoTries() {
+		return nil, errors.New("historical tries are not available")
+	}
+	return trie.NewStateTrie(trie.StateTrieID(root), &historicNodeDB{db: db.triedb})
 }
 
-// OpenStorageTrie opens the storage trie of an account. It's not supported by
-// historic database.
-func (db *HistoricDB) OpenStorageTrie(stateRoot common.Hash, address common.Address, root common.Hash, trie Trie) (Trie, error) {
-	return nil, errors.New("not implemented")
+// OpenStorageTrie opens the storage trie of an account, resolving the trie
+// nodes from the trienode history.
+func (db *HistoricDB) OpenStorageTrie(stateRoot common.Hash, address common.Address, root common.Hash, self Trie) (Trie, error) {
+	if db.NoTries() {
+		return nil, errors.New("historical tries are not available")
+	}
+	return trie.NewStateTrie(trie.StorageTrieID(stateRoot, crypto.Keccak256Hash(address.Bytes()), root), &historicNodeDB{db: db.triedb})
 }
 
 // PointCache returns the cache holding points used in verkle tree key computation
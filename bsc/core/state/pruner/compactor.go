@@ -0,0 +1,116 @@
+package pruner
+
+import (
+	"bytes"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// Compactor compacts the trie nodes of the path-based state scheme in the
+// background. The path scheme overwrites and deletes the stale nodes in place,
+// leaving the reclaiming of their space to the database compactions, which
+// lag behind on a busy node. The key space of the account and storage trie
+// nodes is split into small ranges compacted one after the other, so that the
+// node keeps serving while the compaction progresses.
+type Compactor struct {
+	db       ethdb.KeyValueStore // The state store holding the trie nodes
+	throttle *throttle
+
+	progress Progress
+	lock     sync.Mutex // Lock protecting the progress
+}
+
+// NewCompactor creates a compactor for the trie nodes of the given database,
+// reading and rewriting at most the given Megabytes per second.
+func NewCompactor(db ethdb.Database, budget uint64, stop chan struct{}) *Compactor {
+	return &Compactor{
+		db:       db.GetStateStore(),
+		throttle: newThrottle(budget, stop),
+	}
+}
+
+// Progress returns the current progress of the compaction.
+func (c *Compactor) Progress() Progress {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	progress := c.progress
+	progress.Cursor = common.CopyBytes(c.progress.Cursor)
+	return progress
+}
+
+// compactionRanges returns the key ranges the trie nodes are compacted in, each
+// covering the nodes sharing the first byte after the prefix.
+func compactionRanges() [][2][]byte {
+	var ranges [][2][]byte
+	for _, prefix := range [][]byte{rawdb.TrieNodeAccountPrefix, rawdb.TrieNodeStoragePrefix} {
+		for b := 0; b < 256; b++ {
+			start := append(common.CopyBytes(prefix), byte(b))
+			end := append(common.CopyBytes(prefix), byte(b+1))
+			if b == 255 {
+				end = []byte{prefix[0] + 1}
+			}
+			ranges = append(ranges, [2][]byte{start, end})
+		}
+	}
+	return ranges
+}
+
+// Compact compacts the trie node ranges starting from the one containing the
+// given key. The checkpoint callback is invoked with the key from which the
+// compaction can be resumed after each range.
+func (c *Compactor) Compact(start []byte, checkpoint func(cursor []byte)) error {
+	c.lock.Lock()
+	c.progress.Phase = "compacting"
+	c.lock.Unlock()
+
+	var (
+		begin  = time.Now()
+		logged = time.Now()
+	)
+	for _, r := range compactionRanges() {
+		if start != nil && bytes.Compare(r[1], start) <= 0 {
+			continue
+		}
+		// The compaction rewrites the live entries of the range, estimate their
+		// size by iterating the range to charge them against the budget.
+		var size int
+		iter := c.db.NewIterator(r[0], nil)
+		for iter.Next() {
+			n := len(iter.Key()) + len(iter.Value())
+			size += n
+			if err := c.throttle.wait(n); err != nil {
+				iter.Release()
+				return err
+			}
+		}
+		err := iter.Error()
+		iter.Release()
+		if err != nil {
+			return err
+		}
+		if err := c.db.Compact(r[0], r[1]); err != nil {
+			return err
+		}
+		if err := c.throttle.wait(size); err != nil {
+			return err
+		}
+		c.lock.Lock()
+		c.progress.Compacted++
+		c.progress.Cursor = r[1]
+		c.lock.Unlock()
+		checkpoint(r[1])
+
+		if time.Since(logged) > 8*time.Second {
+			log.Info("Compacting state data online", "ranges", c.Progress().Compacted, "elapsed", common.PrettyDuration(time.Since(begin)))
+			logged = time.Now()
+		}
+	}
+	log.Info("Compacted state data online", "ranges", c.Progress().Compacted, "elapsed", common.PrettyDuration(time.Since(begin)))
+	return nil
+}
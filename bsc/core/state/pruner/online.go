@@ -0,0 +1,399 @@
+package pruner
+
+import (
+	"bytes"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb"
+)
+
+// ErrPruningStopped is returned if an online pruning or compaction is
+// interrupted before finishing.
+var ErrPruningStopped = errors.New("pruning stopped")
+
+// OnlineConfig includes the configurations for pruning the state of a running
+// node.
+type OnlineConfig struct {
+	BloomSize uint64 // The Megabytes of memory allocated to bloom-filter
+	IOBudget  uint64 // The Megabytes per second of state read or rewritten, 0 for unlimited
+}
+
+// Progress reports the advancement of an online pruning or compaction.
+type Progress struct {
+	Phase       string             // Current phase: marking, sweeping or compacting
+	Marked      uint64             // Number of trie nodes and codes marked as live
+	Swept       uint64             // Number of database entries inspected
+	Deleted     uint64             // Number of stale trie nodes deleted
+	DeletedSize common.StorageSize // Storage size of the deleted trie nodes
+	Compacted   uint64             // Number of key ranges compacted
+	Cursor      []byte             // Key from which an interrupted run continues
+}
+
+// OnlinePruner deletes the stale trie nodes of the hash-based state scheme
+// while the node keeps running. Unlike the offline Pruner, it can't rely on
+// the state staying put, the workflow is thus:
+//
+//   - install a write hook in the trie database, marking every node flushed
+//     to disk from then on as live
+//   - mark the nodes of the target state as live, along with the nodes of the
+//     more recent states which differ from the target
+//   - iterate the database, deleting all trie nodes which are not marked
+//
+// Contract codes are only marked, as legacy codes share the key space of the
+// trie nodes, but never deleted.
+type OnlinePruner struct {
+	config   OnlineConfig
+	diskdb   ethdb.Database // The chain database
+	db       ethdb.Database // The state store holding the trie nodes
+	triedb   *triedb.Database
+	bloom    *stateBloom
+	throttle *throttle
+
+	progress Progress
+	lock     sync.Mutex // Lock protecting the bloom filter and the progress
+}
+
+// NewOnlinePruner creates an online pruner on top of the given hash-based trie
+// database, and starts marking the nodes written to it.
+func NewOnlinePruner(db ethdb.Database, triedb *triedb.Database, config OnlineConfig, stop chan struct{}) (*OnlinePruner, error) {
+	// Sanitize the bloom filter size if it's too small.
+	if config.BloomSize < 256 {
+		log.Warn("Sanitizing bloomfilter size", "provided(MB)", config.BloomSize, "updated(MB)", 256)
+		config.BloomSize = 256
+	}
+	bloom, err := newStateBloomWithSize(config.BloomSize)
+	if err != nil {
+		return nil, err
+	}
+	p := &OnlinePruner{
+		config:   config,
+		diskdb:   db,
+		db:       db.GetStateStore(),
+		triedb:   triedb,
+		bloom:    bloom,
+		throttle: newThrottle(config.IOBudget, stop),
+	}
+	if err := triedb.SetWriteHook(p.markWritten); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+// Close detaches the pruner from the trie database.
+func (p *OnlinePruner) Close() {
+	p.triedb.SetWriteHook(nil)
+}
+
+// Progress returns the current progress of the pruning.
+func (p *OnlinePruner) Progress() Progress {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	progress := p.progress
+	progress.Cursor = common.CopyBytes(p.progress.Cursor)
+	return progress
+}
+
+// markWritten is the write hook of the trie database, marking the node about
+// to be flushed as live.
+func (p *OnlinePruner) markWritten(hash common.Hash) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.bloom.Put(hash.Bytes(), nil)
+	p.progress.Marked++
+}
+
+// mark marks the given key as live.
+func (p *OnlinePruner) mark(key []byte) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.bloom.Put(key, nil)
+	p.progress.Marked++
+}
+
+// Mark marks all the nodes of the target state as live, along with the nodes of
+// the recent states not shared with the target one. The recent states have to
+// descend from the target.
+func (p *OnlinePruner) Mark(target common.Hash, recents []common.Hash) error {
+	p.setPhase("marking")
+
+	start := time.Now()
+	if err := p.markState(target, common.Hash{}); err != nil {
+		return err
+	}
+	for _, root := range recents {
+		if root == target {
+			continue
+		}
+		if err := p.markState(root, target); err != nil {
+			return err
+		}
+	}
+	// The genesis state is kept regardless, as the offline pruning does.
+	if err := extractGenesis(p.diskdb, p.bloom); err != nil {
+		return err
+	}
+	log.Info("Marked live state", "root", target, "recents", len(recents), "nodes", p.Progress().Marked, "elapsed", common.PrettyDuration(time.Since(start)))
+	return nil
+}
+
+// markState marks the nodes of the state with the given root. If the base root
+// is set, only the nodes not present in the base state are marked.
+func (p *OnlinePruner) markState(root common.Hash, base common.Hash) error {
+	t, err := trie.NewStateTrie(trie.StateTrieID(root), p.triedb)
+	if err != nil {
+		return err
+	}
+	iter, err := t.NodeIterator(nil)
+	if err != nil {
+		return err
+	}
+	var baseTrie *trie.StateTrie
+	if base != (common.Hash{}) {
+		baseTrie, err = trie.NewStateTrie(trie.StateTrieID(base), p.triedb)
+		if err != nil {
+			return err
+		}
+		baseIter, err := baseTrie.NodeIterator(nil)
+		if err != nil {
+			return err
+		}
+		iter, _ = trie.NewDifferenceIterator(baseIter, iter)
+	}
+	for iter.Next(true) {
+		if hash := iter.Hash(); hash != (common.Hash{}) {
+			p.mark(hash.Bytes())
+			if err := p.throttle.wait(common.HashLength + len(iter.NodeBlob())); err != nil {
+				return err
+			}
+		}
+		if !iter.Leaf() {
+			continue
+		}
+		var acc types.StateAccount
+		if err := rlp.DecodeBytes(iter.LeafBlob(), &acc); err != nil {
+			return err
+		}
+		if !bytes.Equal(acc.CodeHash, types.EmptyCodeHash.Bytes()) {
+			p.mark(acc.CodeHash)
+		}
+		if acc.Root == types.EmptyRootHash {
+			continue
+		}
+		accountHash := common.BytesToHash(iter.LeafKey())
+		baseRoot := types.EmptyRootHash
+		if baseTrie != nil {
+			prev, err := baseTrie.GetAccountByHash(accountHash)
+			if err != nil {
+				return err
+			}
+			if prev != nil {
+				baseRoot = prev.Root
+			}
+		}
+		if baseRoot == acc.Root {
+			continue
+		}
+		if err := p.markStorage(root, accountHash, acc.Root, base, baseRoot); err != nil {
+			return err
+		}
+	}
+	return iter.Error()
+}
+
+// markStorage marks the nodes of a storage trie, except the ones present in the
+// storage trie of the same account in the base state.
+func (p *OnlinePruner) markStorage(stateRoot common.Hash, accountHash common.Hash, root common.Hash, base common.Hash, baseRoot common.Hash) error {
+	t, err := trie.NewStateTrie(trie.StorageTrieID(stateRoot, accountHash, root), p.triedb)
+	if err != nil {
+		return err
+	}
+	iter, err := t.NodeIterator(nil)
+	if err != nil {
+		return err
+	}
+	if baseRoot != types.EmptyRootHash {
+		baseTrie, err := trie.NewStateTrie(trie.StorageTrieID(base, accountHash, baseRoot), p.triedb)
+		if err != nil {
+			return err
+		}
+		baseIter, err := baseTrie.NodeIterator(nil)
+		if err != nil {
+			return err
+		}
+		iter, _ = trie.NewDifferenceIterator(baseIter, iter)
+	}
+	for iter.Next(true) {
+		if hash := iter.Hash(); hash != (common.Hash{}) {
+			p.mark(hash.Bytes())
+			if err := p.throttle.wait(common.HashLength + len(iter.NodeBlob())); err != nil {
+				return err
+			}
+		}
+	}
+	return iter.Error()
+}
+
+// Sweep iterates the database from the given key, deleting the trie nodes not
+// marked as live. The checkpoint callback is invoked with the key from which
+// the sweeping can be resumed after each batch of deletions.
+func (p *OnlinePruner) Sweep(start []byte, checkpoint func(cursor []byte)) error {
+	p.setPhase("sweeping")
+
+	var (
+		keys   [][]byte
+		size   common.StorageSize
+		begin  = time.Now()
+		logged = time.Now()
+		iter   = p.db.NewIterator(nil, start)
+	)
+	defer func() { iter.Release() }()
+
+	for iter.Next() {
+		key, value := iter.Key(), iter.Value()
+		if err := p.throttle.wait(len(key) + len(value)); err != nil {
+			return err
+		}
+		p.lock.Lock()
+		p.progress.Swept++
+		p.lock.Unlock()
+
+		// Only the trie nodes and the legacy codes live in the hash key space,
+		// the latter are marked by the account referring to them.
+		if len(key) != common.HashLength {
+			continue
+		}
+		p.lock.Lock()
+		live := p.bloom.Contain(key)
+		p.lock.Unlock()
+		if live {
+			continue
+		}
+		keys = append(keys, common.CopyBytes(key))
+		size += common.StorageSize(len(key) + len(value))
+
+		if size >= ethdb.IdealBatchSize {
+			if err := p.delete(keys, size); err != nil {
+				return err
+			}
+			cursor := common.CopyBytes(key)
+			p.setCursor(cursor)
+			checkpoint(cursor)
+			keys, size = keys[:0], 0
+
+			// Recreate the iterator after every batch commit in order
+			// to allow the underlying compactor to delete the entries.
+			iter.Release()
+			iter = p.db.NewIterator(nil, cursor)
+		}
+		if time.Since(logged) > 8*time.Second {
+			progress := p.Progress()
+			log.Info("Pruning state data online", "swept", progress.Swept, "nodes", progress.Deleted, "size", progress.DeletedSize,
+				"elapsed", common.PrettyDuration(time.Since(begin)))
+			logged = time.Now()
+		}
+	}
+	if err := iter.Error(); err != nil {
+		return err
+	}
+	if err := p.delete(keys, size); err != nil {
+		return err
+	}
+	progress := p.Progress()
+	log.Info("Pruned state data online", "nodes", progress.Deleted, "size", progress.DeletedSize, "elapsed", common.PrettyDuration(time.Since(begin)))
+	return nil
+}
+
+// delete removes the given stale trie nodes. The nodes are checked against the
+// bloom filter once more while holding the lock, as they might have been marked
+// live by a concurrent flush of the trie database since they were picked.
+func (p *OnlinePruner) delete(keys [][]byte, size common.StorageSize) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	batch := p.db.NewBatch()
+	for _, key := range keys {
+		if p.bloom.Contain(key) {
+			continue
+		}
+		batch.Delete(key)
+		p.progress.Deleted++
+	}
+	p.progress.DeletedSize += size
+	return batch.Write()
+}
+
+func (p *OnlinePruner) setPhase(phase string) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.progress.Phase = phase
+}
+
+func (p *OnlinePruner) setCursor(cursor []byte) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.progress.Cursor = cursor
+}
+
+// minThrottleDelay is the minimal delay the throttle sleeps for, the shorter
+// ones are accumulated to avoid excessive timer churn.
+const minThrottleDelay = 10 * time.Millisecond
+
+// throttle limits the rate of database reads and writes of a background task
+// to the configured budget.
+type throttle struct {
+	budget uint64 // Bytes per second, 0 for unlimited
+	stop   chan struct{}
+	start  time.Time
+	bytes  uint64
+}
+
+// newThrottle creates a throttle with the given budget in megabytes per second.
+func newThrottle(budget uint64, stop chan struct{}) *throttle {
+	return &throttle{
+		budget: budget * 1024 * 1024,
+		stop:   stop,
+		start:  time.Now(),
+	}
+}
+
+// wait accounts the given amount of bytes, sleeping until they fit into the
+// budget. It returns ErrPruningStopped once the stop channel is closed.
+func (t *throttle) wait(size int) error {
+	select {
+	case <-t.stop:
+		return ErrPruningStopped
+	default:
+	}
+	if t.budget == 0 {
+		return nil
+	}
+	t.bytes += uint64(size)
+	due := time.Duration(float64(t.bytes) / float64(t.budget) * float64(time.Second))
+	if delay := due - time.Since(t.start); delay >= minThrottleDelay {
+		timer := time.NewTimer(delay)
+		defer timer.Stop()
+
+		select {
+		case <-t.stop:
+			return ErrPruningStopped
+		case <-timer.C:
+		}
+	}
+	return nil
+}
@@ -104,6 +104,9 @@ type StateDB struct {
 	// boundaries.
 	stateObjectsDestruct map[common.Address]*stateObject
 
+	// Whether balance changes settle the native holder yield (Mendel)
+	yieldEnabled bool
+
 	// This map tracks the account mutations that occurred during the
 	// transition. Uncommitted mutations belonging to the same account
 	// can be merged into a single one which is equivalent from database's
@@ -505,7 +508,10 @@ func (s *StateDB) AddBalance(addr common.Address, amount *uint256.Int, reason tr
 	if stateObject == nil {
 		return uint256.Int{}
 	}
-	return stateObject.AddBalance(amount)
+	s.SettleYield(addr)
+	prev := stateObject.AddBalance(amount)
+	s.recordYieldBalance(addr)
+	return prev
 }
 
 // SubBalance subtracts amount from the account associated with addr.
@@ -514,16 +520,21 @@ func (s *StateDB) SubBalance(addr common.Address, amount *uint256.Int, reason tr
 	if stateObject == nil {
 		return uint256.Int{}
 	}
+	s.SettleYield(addr)
 	if amount.IsZero() {
 		return *(stateObject.Balance())
 	}
-	return stateObject.SetBalance(new(uint256.Int).Sub(stateObject.Balance(), amount))
+	prev := stateObject.SetBalance(new(uint256.Int).Sub(stateObject.Balance(), amount))
+	s.recordYieldBalance(addr)
+	return prev
 }
 
 func (s *StateDB) SetBalance(addr common.Address, amount *uint256.Int, reason tracing.BalanceChangeReason) {
 	stateObject := s.getOrNewStateObject(addr)
 	if stateObject != nil {
+		s.SettleYield(addr)
 		stateObject.SetBalance(amount)
+		s.recordYieldBalance(addr)
 	}
 }
 
@@ -590,11 +601,14 @@ func (s *StateDB) SelfDestruct(addr common.Address) uint256.Int {
 	if stateObject == nil {
 		return prevBalance
 	}
+	// Pay out the accrued yield first, it is destructed along with the balance.
+	s.SettleYield(addr)
 	prevBalance = *(stateObject.Balance())
 	// Regardless of whether it is already destructed or not, we do have to
 	// journal the balance-change, if we set it to zero here.
 	if !stateObject.Balance().IsZero() {
 		stateObject.SetBalance(new(uint256.Int))
+		s.recordYieldBalance(addr)
 	}
 	// If it is already marked as self-destructed, we do not need to add it
 	// for journalling a second time.
@@ -781,6 +795,16 @@ func (s *StateDB) TransferBlockAccessList(prev *StateDB) {
 	prev.blockAccessList = nil
 }
 
+// TransferPrefetcher moves the trie prefetcher of prev over to the state, which
+// must share its original root.
+func (s *StateDB) TransferPrefetcher(prev *StateDB) {
+	if prev == nil {
+		return
+	}
+	s.prefetcher = prev.prefetcher
+	prev.prefetcher = nil
+}
+
 // Copy creates a deep, independent copy of the state.
 // Snapshots of the copied state cannot be applied to the copy.
 func (s *StateDB) Copy() *StateDB {
@@ -791,6 +815,7 @@ func (s *StateDB) Copy() *StateDB {
 		originalRoot:         s.originalRoot,
 		expectedRoot:         s.expectedRoot,
 		needBadSharedStorage: s.needBadSharedStorage,
+		yieldEnabled:         s.yieldEnabled,
 		stateObjects:         make(map[common.Address]*stateObject, len(s.stateObjects)),
 		stateObjectsDestruct: make(map[common.Address]*stateObject, len(s.stateObjectsDestruct)),
 		mutations:            make(map[common.Address]*mutation, len(s.mutations)),
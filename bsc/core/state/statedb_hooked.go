@@ -58,6 +58,7 @@ func (s *hookedStateDB) GetBalance(addr common.Address) *uint256.Int {
 }
 
 func (s *hookedStateDB) SetBalance(addr common.Address, amount *uint256.Int, reason tracing.BalanceChangeReason) {
+	s.SettleYield(addr)
 	s.inner.SetBalance(addr, amount, reason)
 }
 
@@ -178,6 +179,7 @@ func (s *hookedStateDB) AccessEvents() *AccessEvents {
 }
 
 func (s *hookedStateDB) SubBalance(addr common.Address, amount *uint256.Int, reason tracing.BalanceChangeReason) uint256.Int {
+	s.SettleYield(addr)
 	prev := s.inner.SubBalance(addr, amount, reason)
 	if s.hooks.OnBalanceChange != nil && !amount.IsZero() {
 		newBalance := new(uint256.Int).Sub(&prev, amount)
@@ -187,6 +189,7 @@ func (s *hookedStateDB) SubBalance(addr common.Address, amount *uint256.Int, rea
 }
 
 func (s *hookedStateDB) AddBalance(addr common.Address, amount *uint256.Int, reason tracing.BalanceChangeReason) uint256.Int {
+	s.SettleYield(addr)
 	prev := s.inner.AddBalance(addr, amount, reason)
 	if s.hooks.OnBalanceChange != nil && !amount.IsZero() {
 		newBalance := new(uint256.Int).Add(&prev, amount)
@@ -195,6 +198,37 @@ func (s *hookedStateDB) AddBalance(addr common.Address, amount *uint256.Int, rea
 	return prev
 }
 
+func (s *hookedStateDB) EnableYield() {
+	s.inner.EnableYield()
+}
+
+// AccrueYield pools amount for the native holders, reporting it as a balance
+// increase of the yield index account.
+func (s *hookedStateDB) AccrueYield(amount *uint256.Int) {
+	s.inner.AccrueYield(amount)
+	if s.hooks.OnBalanceChange != nil && !amount.IsZero() {
+		balance := s.inner.GetBalance(params.YieldIndexAddress).ToBig()
+		s.hooks.OnBalanceChange(params.YieldIndexAddress, new(big.Int).Sub(balance, amount.ToBig()), balance, tracing.BalanceIncreaseYieldAccrual)
+	}
+}
+
+// SettleYield settles the yield accrued to addr, reporting the transfer out of
+// the yield index account. Balance changes settle on their own, it is invoked
+// ahead of them only so the settlement is reported separately.
+func (s *hookedStateDB) SettleYield(addr common.Address) *uint256.Int {
+	owed := s.inner.SettleYield(addr)
+	if s.hooks.OnBalanceChange != nil && !owed.IsZero() {
+		var (
+			amount  = owed.ToBig()
+			holder  = s.inner.GetBalance(addr).ToBig()
+			balance = s.inner.GetBalance(params.YieldIndexAddress).ToBig()
+		)
+		s.hooks.OnBalanceChange(params.YieldIndexAddress, new(big.Int).Add(balance, amount), balance, tracing.BalanceDecreaseYieldSettlement)
+		s.hooks.OnBalanceChange(addr, new(big.Int).Sub(holder, amount), holder, tracing.BalanceIncreaseYieldSettlement)
+	}
+	return owed
+}
+
 func (s *hookedStateDB) SetNonce(address common.Address, nonce uint64, reason tracing.NonceChangeReason) {
 	prev := s.inner.GetNonce(address)
 	s.inner.SetNonce(address, nonce, reason)
@@ -241,6 +275,9 @@ func (s *hookedStateDB) SelfDestruct(address common.Address) uint256.Int {
 		prevCodeHash = s.inner.GetCodeHash(address)
 	}
 
+	if s.inner.Exist(address) {
+		s.SettleYield(address)
+	}
 	prev := s.inner.SelfDestruct(address)
 
 	if s.hooks.OnBalanceChange != nil && !prev.IsZero() {
@@ -0,0 +1,189 @@
+package state
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// Native holder yield.
+//
+// The share of the block fees allotted to holders is pooled in the yield index
+// account and paid out lazily. Per token, the account keeps a cumulative index
+// of the yield accrued per unit of balance and the total balance earning it,
+// and per holder the balance and the index recorded at its last settlement.
+// Whenever the balance of a holder is touched, the yield accrued since then is
+// credited from the pool before the change is applied, so paying a block's
+// share never has to touch more accounts than the block itself does.
+//
+// Earning yield is opt-in on touch: the holder balances existing at the Mendel
+// transition are not enumerated, as that would mean iterating the whole state
+// in a single block. A balance starts earning once it is first changed or
+// touched by a transaction after the first accrual, a zero value transfer to
+// the holder being enough, and only earns the yield accrued from then on. Up to
+// that point the yield is shared among the holders which have opted in, and
+// whatever is accrued while nobody has goes to the first ones to do so.
+//
+// The layout is keyed by token, but only the native coin is tracked by the
+// state as it is the only asset whose balance changes are observed here.
+
+// NativeYieldToken is the token key of the native coin in the yield index.
+var NativeYieldToken = common.Address{}
+
+// yieldIndexScale is the fixed point precision of the yield index.
+var yieldIndexScale = new(uint256.Int).Exp(uint256.NewInt(10), uint256.NewInt(27))
+
+// Storage slots of the yield index account mappings.
+const (
+	yieldIndexSlot         = iota // token => cumulative yield per unit of balance
+	yieldSupplySlot               // token => total balance earning yield
+	yieldUndistributedSlot        // token => yield accrued while nobody was earning
+	yieldBalanceSlot              // token => holder => balance at last settlement
+	yieldSnapshotSlot             // token => holder => index at last settlement
+)
+
+// yieldKey returns the storage key of the given token mapping.
+func yieldKey(token common.Address, slot byte) common.Hash {
+	return crypto.Keccak256Hash(common.LeftPadBytes(token.Bytes(), common.HashLength), common.Hash{31: slot}.Bytes())
+}
+
+// yieldHolderKey returns the storage key of the given token and holder mapping.
+func yieldHolderKey(token common.Address, holder common.Address, slot byte) common.Hash {
+	return crypto.Keccak256Hash(common.LeftPadBytes(holder.Bytes(), common.HashLength), yieldKey(token, slot).Bytes())
+}
+
+func (s *StateDB) getYieldValue(key common.Hash) *uint256.Int {
+	value := s.GetState(params.YieldIndexAddress, key)
+	return new(uint256.Int).SetBytes32(value[:])
+}
+
+func (s *StateDB) setYieldValue(key common.Hash, value *uint256.Int) {
+	s.SetState(params.YieldIndexAddress, key, value.Bytes32())
+}
+
+// EnableYield makes balance changes settle the native holder yield. It is
+// invoked once the Mendel fork is active, the yield index is never consulted
+// before.
+func (s *StateDB) EnableYield() {
+	s.yieldEnabled = true
+}
+
+// yieldActive reports whether yield has ever been accrued, which is marked by a
+// non-zero nonce of the yield index account.
+func (s *StateDB) yieldActive() bool {
+	if !s.yieldEnabled {
+		return false
+	}
+	obj := s.getStateObject(params.YieldIndexAddress)
+	return obj != nil && obj.Nonce() != 0
+}
+
+// yieldEligible reports whether the balance of addr earns yield.
+func (s *StateDB) yieldEligible(addr common.Address) bool {
+	if addr == params.YieldIndexAddress || addr == params.SystemAddress {
+		return false
+	}
+	return s.yieldActive()
+}
+
+// AccrueYield pools amount in the yield index account for the native holders
+// and advances their index. If nobody is earning yield yet, the amount is kept
+// aside and accrued along with the next one.
+func (s *StateDB) AccrueYield(amount *uint256.Int) {
+	pool := s.getOrNewStateObject(params.YieldIndexAddress)
+	if pool.Nonce() == 0 {
+		pool.SetNonce(1) // Activate settlement and keep the account from being pruned as empty
+	}
+	if amount.IsZero() {
+		return
+	}
+	pool.AddBalance(amount)
+
+	var (
+		indexKey         = yieldKey(NativeYieldToken, yieldIndexSlot)
+		undistributedKey = yieldKey(NativeYieldToken, yieldUndistributedSlot)
+		supply           = s.getYieldValue(yieldKey(NativeYieldToken, yieldSupplySlot))
+		total            = new(uint256.Int).Add(amount, s.getYieldValue(undistributedKey))
+	)
+	if supply.IsZero() {
+		s.setYieldValue(undistributedKey, total)
+		return
+	}
+	delta, _ := new(uint256.Int).MulDivOverflow(total, yieldIndexScale, supply)
+	s.setYieldValue(indexKey, new(uint256.Int).Add(s.getYieldValue(indexKey), delta))
+	s.setYieldValue(undistributedKey, new(uint256.Int))
+}
+
+// pendingYield returns the yield accrued to addr since its last settlement
+// along with the current index.
+func (s *StateDB) pendingYield(addr common.Address) (*uint256.Int, *uint256.Int) {
+	var (
+		index    = s.getYieldValue(yieldKey(NativeYieldToken, yieldIndexSlot))
+		snapshot = s.getYieldValue(yieldHolderKey(NativeYieldToken, addr, yieldSnapshotSlot))
+	)
+	if index.Eq(snapshot) {
+		return new(uint256.Int), index
+	}
+	balance := s.getYieldValue(yieldHolderKey(NativeYieldToken, addr, yieldBalanceSlot))
+	owed, _ := new(uint256.Int).MulDivOverflow(balance, new(uint256.Int).Sub(index, snapshot), yieldIndexScale)
+	return owed, index
+}
+
+// AccruedYield returns the yield accrued to addr for the given token which has
+// not been settled yet.
+func (s *StateDB) AccruedYield(addr common.Address, token common.Address) *uint256.Int {
+	if token != NativeYieldToken || !s.yieldEligible(addr) {
+		return new(uint256.Int)
+	}
+	owed, _ := s.pendingYield(addr)
+	return owed
+}
+
+// SettleYield credits addr with the native yield accrued since its last
+// settlement and returns the amount credited. Its current balance is recorded
+// as the one earning yield from now on. It is invoked on every balance change,
+// callers only need it to observe the settlement separately.
+func (s *StateDB) SettleYield(addr common.Address) *uint256.Int {
+	if !s.yieldEligible(addr) {
+		return new(uint256.Int)
+	}
+	owed, index := s.pendingYield(addr)
+	if snapshotKey := yieldHolderKey(NativeYieldToken, addr, yieldSnapshotSlot); !s.getYieldValue(snapshotKey).Eq(index) {
+		s.setYieldValue(snapshotKey, index)
+	}
+	if !owed.IsZero() {
+		// Rounding always favours the pool, but never pay out more than it holds.
+		pool := s.getOrNewStateObject(params.YieldIndexAddress)
+		if pool.Balance().Lt(owed) {
+			owed = pool.Balance().Clone()
+		}
+		pool.SetBalance(new(uint256.Int).Sub(pool.Balance(), owed))
+		s.getOrNewStateObject(addr).AddBalance(owed)
+	}
+	s.recordYieldBalance(addr)
+	return owed
+}
+
+// recordYieldBalance records the current balance of addr as the one earning
+// yield from now on. It is how a holder opts in, balances never recorded do not
+// count towards the supply earning yield.
+func (s *StateDB) recordYieldBalance(addr common.Address) {
+	if !s.yieldEligible(addr) {
+		return
+	}
+	var (
+		balanceKey = yieldHolderKey(NativeYieldToken, addr, yieldBalanceSlot)
+		recorded   = s.getYieldValue(balanceKey)
+		balance    = s.GetBalance(addr)
+	)
+	if recorded.Eq(balance) {
+		return
+	}
+	supplyKey := yieldKey(NativeYieldToken, yieldSupplySlot)
+	supply := s.getYieldValue(supplyKey)
+	supply.Sub(supply, recorded)
+	supply.Add(supply, balance)
+	s.setYieldValue(supplyKey, supply)
+	s.setYieldValue(balanceKey, balance)
+}
@@ -0,0 +1,172 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
+)
+
+// Tests that balance changes leave the yield index untouched until yield has
+// been accrued for the first time, and before the fork enabling it.
+func TestYieldInactive(t *testing.T) {
+	s, _ := New(types.EmptyRootHash, NewDatabaseForTesting())
+	s.EnableYield()
+	s.AddBalance(common.Address{0xaa}, uint256.NewInt(100), tracing.BalanceChangeUnspecified)
+	s.SubBalance(common.Address{0xaa}, uint256.NewInt(10), tracing.BalanceChangeUnspecified)
+
+	if s.Exist(params.YieldIndexAddress) {
+		t.Fatal("yield index account created before activation")
+	}
+	if have := s.AccruedYield(common.Address{0xaa}, NativeYieldToken); !have.IsZero() {
+		t.Fatalf("accrued yield before activation: %v", have)
+	}
+	// Accrued yield is ignored unless enabled
+	s, _ = New(types.EmptyRootHash, NewDatabaseForTesting())
+	s.AccrueYield(uint256.NewInt(40))
+	s.AddBalance(common.Address{0xaa}, uint256.NewInt(100), tracing.BalanceChangeUnspecified)
+
+	key := yieldHolderKey(NativeYieldToken, common.Address{0xaa}, yieldBalanceSlot)
+	if have := s.GetState(params.YieldIndexAddress, key); have != (common.Hash{}) {
+		t.Fatalf("balance recorded before the fork: %x", have)
+	}
+	if have := s.Copy().AccruedYield(common.Address{0xaa}, NativeYieldToken); !have.IsZero() {
+		t.Fatalf("accrued yield before the fork: %v", have)
+	}
+}
+
+// Tests that accrued yield is credited lazily in proportion to the recorded
+// balances, and that settlement is reverted along with the change causing it.
+func TestYieldSettlement(t *testing.T) {
+	var (
+		s, _ = New(types.EmptyRootHash, NewDatabaseForTesting())
+		a    = common.Address{0xaa}
+		b    = common.Address{0xbb}
+	)
+	s.EnableYield()
+	s.AddBalance(a, uint256.NewInt(100), tracing.BalanceChangeUnspecified)
+	s.AddBalance(b, uint256.NewInt(300), tracing.BalanceChangeUnspecified)
+
+	// Nobody earns yet, the first accrual is kept aside
+	s.AccrueYield(uint256.NewInt(40))
+	s.SubBalance(a, new(uint256.Int), tracing.BalanceChangeUnspecified)
+	s.SubBalance(b, new(uint256.Int), tracing.BalanceChangeUnspecified)
+	if have := s.AccruedYield(a, NativeYieldToken); !have.IsZero() {
+		t.Fatalf("yield accrued without supply: %v", have)
+	}
+	s.AccrueYield(uint256.NewInt(80))
+
+	if have, want := s.AccruedYield(a, NativeYieldToken), uint256.NewInt(30); !have.Eq(want) {
+		t.Fatalf("accrued yield of a mismatch: have %v, want %v", have, want)
+	}
+	if have, want := s.AccruedYield(b, NativeYieldToken), uint256.NewInt(90); !have.Eq(want) {
+		t.Fatalf("accrued yield of b mismatch: have %v, want %v", have, want)
+	}
+	if have, want := s.Copy().AccruedYield(b, NativeYieldToken), uint256.NewInt(90); !have.Eq(want) {
+		t.Fatalf("accrued yield of copied b mismatch: have %v, want %v", have, want)
+	}
+	if have := s.AccruedYield(a, common.Address{0x01}); !have.IsZero() {
+		t.Fatalf("accrued yield of untracked token: %v", have)
+	}
+	// Touching a balance settles the yield, reverting restores it
+	snap := s.Snapshot()
+	s.SubBalance(a, uint256.NewInt(50), tracing.BalanceChangeUnspecified)
+	if have, want := s.GetBalance(a), uint256.NewInt(80); !have.Eq(want) {
+		t.Fatalf("settled balance mismatch: have %v, want %v", have, want)
+	}
+	if have, want := s.GetBalance(params.YieldIndexAddress), uint256.NewInt(90); !have.Eq(want) {
+		t.Fatalf("pool balance mismatch: have %v, want %v", have, want)
+	}
+	if have := s.AccruedYield(a, NativeYieldToken); !have.IsZero() {
+		t.Fatalf("yield left after settlement: %v", have)
+	}
+	s.RevertToSnapshot(snap)
+	if have, want := s.AccruedYield(a, NativeYieldToken), uint256.NewInt(30); !have.Eq(want) {
+		t.Fatalf("reverted yield mismatch: have %v, want %v", have, want)
+	}
+	if have, want := s.GetBalance(params.YieldIndexAddress), uint256.NewInt(120); !have.Eq(want) {
+		t.Fatalf("reverted pool balance mismatch: have %v, want %v", have, want)
+	}
+}
+
+// Tests that holding a balance at the fork does not earn yield on its own, a
+// holder only starts earning the yield accrued after its balance is touched.
+func TestYieldOptInOnTouch(t *testing.T) {
+	var (
+		s, _ = New(types.EmptyRootHash, NewDatabaseForTesting())
+		a    = common.Address{0xaa}
+		b    = common.Address{0xbb}
+	)
+	// Balances held before the fork are not recorded
+	s.AddBalance(a, uint256.NewInt(100), tracing.BalanceChangeUnspecified)
+	s.AddBalance(b, uint256.NewInt(300), tracing.BalanceChangeUnspecified)
+	s.EnableYield()
+	s.AccrueYield(new(uint256.Int))
+
+	// Only the touched holder earns, so it gets the whole accrual
+	s.AddBalance(a, new(uint256.Int), tracing.BalanceChangeUnspecified)
+	s.AccrueYield(uint256.NewInt(100))
+	if have, want := s.AccruedYield(a, NativeYieldToken), uint256.NewInt(100); !have.Eq(want) {
+		t.Fatalf("accrued yield of a mismatch: have %v, want %v", have, want)
+	}
+	if have := s.AccruedYield(b, NativeYieldToken); !have.IsZero() {
+		t.Fatalf("accrued yield of untouched b: %v", have)
+	}
+	// Touching b opts it in without paying out anything accrued before
+	s.AddBalance(b, new(uint256.Int), tracing.BalanceChangeUnspecified)
+	if have, want := s.GetBalance(b), uint256.NewInt(300); !have.Eq(want) {
+		t.Fatalf("balance of b mismatch: have %v, want %v", have, want)
+	}
+	s.AccrueYield(uint256.NewInt(40))
+	if have, want := s.AccruedYield(a, NativeYieldToken), uint256.NewInt(110); !have.Eq(want) {
+		t.Fatalf("accrued yield of a mismatch: have %v, want %v", have, want)
+	}
+	if have, want := s.AccruedYield(b, NativeYieldToken), uint256.NewInt(30); !have.Eq(want) {
+		t.Fatalf("accrued yield of b mismatch: have %v, want %v", have, want)
+	}
+}
+
+// Tests that the hooked state reports accrual and settlement separately from
+// the balance change triggering the settlement.
+func TestYieldHooks(t *testing.T) {
+	type change struct {
+		addr      common.Address
+		prev, new int64
+		reason    tracing.BalanceChangeReason
+	}
+	var (
+		inner, _ = New(types.EmptyRootHash, NewDatabaseForTesting())
+		a        = common.Address{0xaa}
+		changes  []change
+	)
+	hooked := NewHookedState(inner, &tracing.Hooks{
+		OnBalanceChange: func(addr common.Address, prev, new *big.Int, reason tracing.BalanceChangeReason) {
+			changes = append(changes, change{addr, prev.Int64(), new.Int64(), reason})
+		},
+	})
+	hooked.EnableYield()
+	hooked.AccrueYield(new(uint256.Int))
+	hooked.AddBalance(a, uint256.NewInt(100), tracing.BalanceChangeUnspecified)
+	hooked.AccrueYield(uint256.NewInt(50))
+	hooked.AddBalance(a, uint256.NewInt(10), tracing.BalanceChangeUnspecified)
+
+	want := []change{
+		{a, 0, 100, tracing.BalanceChangeUnspecified},
+		{params.YieldIndexAddress, 0, 50, tracing.BalanceIncreaseYieldAccrual},
+		{params.YieldIndexAddress, 50, 0, tracing.BalanceDecreaseYieldSettlement},
+		{a, 100, 150, tracing.BalanceIncreaseYieldSettlement},
+		{a, 150, 160, tracing.BalanceChangeUnspecified},
+	}
+	if len(changes) != len(want) {
+		t.Fatalf("balance change count mismatch: have %d, want %d", len(changes), len(want))
+	}
+	for i := range want {
+		if changes[i] != want[i] {
+			t.Errorf("balance change %d mismatch: have %+v, want %+v", i, changes[i], want[i])
+		}
+	}
+}
@@ -21,6 +21,7 @@ import (
 	"errors"
 	"math"
 	"math/big"
+	"slices"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
@@ -30,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
 	"github.com/ethereum/go-ethereum/consensus/misc/eip4844"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
@@ -432,3 +434,238 @@ func GenerateBadBlock(parent *types.Block, engine consensus.Engine, txs types.Tr
 	}
 	return types.NewBlock(header, body, receipts, trie.NewStackTrie(nil))
 }
+
+// TestStateProcessorFrozenAccounts tests that blocks moving funds from or to an
+// account on the governance freeze list are rejected once Mendel is active.
+func TestStateProcessorFrozenAccounts(t *testing.T) {
+	var (
+		key1, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		key2, _ = crypto.HexToECDSA("0202020202020202020202020202020202020202020202020202002020202020")
+		addr1   = crypto.PubkeyToAddress(key1.PublicKey)
+		addr2   = crypto.PubkeyToAddress(key2.PublicKey)
+		frozen  = common.HexToAddress("0xdead")
+		freezer = common.HexToAddress(systemcontracts.FreezeListContract)
+	)
+	freezeKey := func(addr common.Address) common.Hash {
+		return crypto.Keccak256Hash(common.LeftPadBytes(addr.Bytes(), 32), common.Hash{}.Bytes())
+	}
+	for i, tt := range []struct {
+		mendel bool
+		key    *ecdsa.PrivateKey
+		to     common.Address
+		want   error
+	}{
+		{mendel: false, key: key1, to: frozen, want: nil},
+		{mendel: false, key: key2, to: addr1, want: nil},
+		{mendel: true, key: key1, to: common.HexToAddress("0xbeef"), want: nil},
+		{mendel: true, key: key1, to: frozen, want: ErrFrozenAccount},
+		{mendel: true, key: key2, to: addr1, want: ErrFrozenAccount},
+	} {
+		config := *params.MergedTestChainConfig
+		if tt.mendel {
+			config.MendelTime = u64(0)
+		}
+		var (
+			signer = types.LatestSigner(&config)
+			gspec  = &Genesis{
+				Config: &config,
+				Alloc: types.GenesisAlloc{
+					addr1: {Balance: big.NewInt(1000000000000000000)},
+					addr2: {Balance: big.NewInt(1000000000000000000)},
+					freezer: {
+						Code: []byte{0x0}, // Storage only, the contract logic is irrelevant here
+						Storage: map[common.Hash]common.Hash{
+							freezeKey(frozen): common.HexToHash("0x01"),
+							freezeKey(addr2):  common.HexToHash("0x02"),
+						},
+					},
+				},
+			}
+			blockchain, _ = NewBlockChain(rawdb.NewMemoryDatabase(), gspec, beacon.New(ethash.NewFaker()), nil)
+		)
+		tx, _ := types.SignTx(types.NewTransaction(0, tt.to, big.NewInt(1), params.TxGas, big.NewInt(params.InitialBaseFee), nil), signer, tt.key)
+		block := GenerateBadBlock(gspec.ToBlock(), beacon.New(ethash.NewFaker()), types.Transactions{tx}, gspec.Config, false)
+		_, err := blockchain.InsertChain(types.Blocks{block})
+		if tt.want == nil {
+			// The fake block carries a bogus state root, so only the transaction
+			// itself must have been accepted.
+			if errors.Is(err, ErrFrozenAccount) {
+				t.Errorf("test %d: transaction rejected: %v", i, err)
+			}
+		} else if !errors.Is(err, tt.want) {
+			t.Errorf("test %d: have %v, want %v", i, err, tt.want)
+		}
+		blockchain.Stop()
+	}
+}
+
+// TestStateProcessorFrozenInternalTransfers tests that value moved from or to an
+// account on the governance freeze list by internal calls and self-destructs
+// reverts the frame moving it once Mendel is active.
+func TestStateProcessorFrozenInternalTransfers(t *testing.T) {
+	var (
+		key, _     = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr       = crypto.PubkeyToAddress(key.PublicKey)
+		frozen     = common.HexToAddress("0xdead")
+		open       = common.HexToAddress("0xbeef")
+		forwarder  = common.HexToAddress("0xf0")
+		destructor = common.HexToAddress("0xd0")
+		freezer    = common.HexToAddress(systemcontracts.FreezeListContract)
+	)
+	freezeKey := func(addr common.Address) common.Hash {
+		return crypto.Keccak256Hash(common.LeftPadBytes(addr.Bytes(), 32), common.Hash{}.Bytes())
+	}
+	for i, tt := range []struct {
+		mendel bool
+		to     common.Address // Contract moving the value
+		value  int64
+		target common.Address // Recipient of the moved value
+		want   uint64
+	}{
+		{mendel: false, to: forwarder, value: 1, target: frozen, want: types.ReceiptStatusSuccessful},
+		{mendel: false, to: destructor, target: frozen, want: types.ReceiptStatusSuccessful},
+		{mendel: true, to: forwarder, value: 1, target: open, want: types.ReceiptStatusSuccessful},
+		{mendel: true, to: forwarder, value: 1, target: frozen, want: types.ReceiptStatusFailed},
+		{mendel: true, to: forwarder, target: frozen, want: types.ReceiptStatusSuccessful}, // No value moved
+		{mendel: true, to: destructor, target: open, want: types.ReceiptStatusSuccessful},
+		{mendel: true, to: destructor, target: frozen, want: types.ReceiptStatusFailed},
+	} {
+		config := *params.MergedTestChainConfig
+		if tt.mendel {
+			config.MendelTime = u64(0)
+		}
+		gspec := &Genesis{
+			Config: &config,
+			Alloc: types.GenesisAlloc{
+				addr: {Balance: big.NewInt(1000000000000000000)},
+				// The forwarder calls the address in the calldata with the call
+				// value, reverting if the call fails
+				forwarder: {
+					Code: []byte{
+						0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x60, 0x00, 0x34, 0x60, 0x00, 0x35, 0x5a, 0xf1, // call(gas, calldataload(0), callvalue, 0, 0, 0, 0)
+						0x15, 0x60, 0x13, 0x57, 0x00, 0x5b, 0x60, 0x00, 0x80, 0xfd, // revert if failed
+					},
+				},
+				// The destructor self-destructs to the address in the calldata
+				destructor: {
+					Code:    []byte{0x60, 0x00, 0x35, 0xff},
+					Balance: big.NewInt(1000),
+				},
+				freezer: {
+					Code:    []byte{0x0}, // Storage only, the contract logic is irrelevant here
+					Storage: map[common.Hash]common.Hash{freezeKey(frozen): common.HexToHash("0x01")},
+				},
+			},
+		}
+		var (
+			signer  = types.LatestSigner(&config)
+			balance *uint256.Int
+		)
+		_, _, receipts := GenerateChainWithGenesis(gspec, beacon.New(ethash.NewFaker()), 1, func(n int, b *BlockGen) {
+			tx, _ := types.SignTx(types.NewTransaction(0, tt.to, big.NewInt(tt.value), 100000, b.BaseFee(), common.LeftPadBytes(tt.target.Bytes(), 32)), signer, key)
+			b.AddTx(tx)
+			balance = b.statedb.GetBalance(frozen)
+		})
+		if have := receipts[0][0].Status; have != tt.want {
+			t.Errorf("test %d: receipt status mismatch: have %d, want %d", i, have, tt.want)
+		}
+		if tt.want == types.ReceiptStatusFailed && !balance.IsZero() {
+			t.Errorf("test %d: frozen account credited: %v", i, balance)
+		}
+	}
+}
+
+// TestStateProcessorReserveMint tests that mints of the reserve backed token
+// above the attested reserve are reverted once Mendel is active. Mints above
+// the attested reserve or the mint window limit also pause minting.
+func TestStateProcessorReserveMint(t *testing.T) {
+	var (
+		key, _   = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr     = crypto.PubkeyToAddress(key.PublicKey)
+		token    = common.HexToAddress("0x601d")
+		registry = common.HexToAddress(systemcontracts.ReserveRegistryContract)
+		slot     = func(n int64) common.Hash { return common.BigToHash(big.NewInt(n)) }
+	)
+	for i, tt := range []struct {
+		mendel   bool
+		attested bool
+		blocks   [][]int64 // Mints per block, the chain maker spaces blocks 10s apart
+		want     []uint64  // Receipt status per mint
+		supply   int64
+		window   int64 // Mint window in seconds
+		paused   int64
+		pause    int64
+	}{
+		{mendel: false, attested: true, blocks: [][]int64{{500}}, want: []uint64{1}, supply: 1400},
+		{mendel: true, attested: false, blocks: [][]int64{{500}}, want: []uint64{1}, supply: 1400},
+		{mendel: true, attested: true, blocks: [][]int64{{100}}, want: []uint64{1}, supply: 1000},
+		{mendel: true, attested: true, blocks: [][]int64{{101}}, want: []uint64{0}, supply: 900, pause: systemcontracts.MintPauseReserveExceeded},
+		{mendel: true, attested: true, blocks: [][]int64{{60, 41}}, want: []uint64{1, 0}, supply: 960, pause: systemcontracts.MintPauseReserveExceeded},
+		{mendel: true, attested: true, blocks: [][]int64{{101, 1}}, want: []uint64{0, 0}, supply: 900, pause: systemcontracts.MintPauseReserveExceeded},
+		{mendel: true, window: 100, blocks: [][]int64{{30}, {20}, {1}, {1}}, want: []uint64{1, 1, 0, 0}, supply: 950, pause: systemcontracts.MintPauseLimitExceeded},
+		{mendel: true, window: 5, blocks: [][]int64{{50}, {50}}, want: []uint64{1, 1}, supply: 1000}, // Block window rolls over
+		{mendel: true, paused: 3, blocks: [][]int64{{1}}, want: []uint64{0}, supply: 900, pause: 3},
+	} {
+		config := *params.MergedTestChainConfig
+		if tt.mendel {
+			config.MendelTime = u64(0)
+		}
+		storage := map[common.Hash]common.Hash{
+			slot(0): common.BytesToHash(token.Bytes()), // token
+			slot(1): {},                                // supply slot of the token
+			slot(4): slot(1000),                        // attested reserve
+			slot(8): slot(tt.window),                   // mint window
+			slot(9): slot(50),                          // mint limit per window
+		}
+		if tt.paused != 0 {
+			storage[slot(12)] = slot(tt.paused) // pause
+		}
+		if tt.attested {
+			storage[slot(6)] = slot(1) // attested at block
+		}
+		gspec := &Genesis{
+			Config: &config,
+			Alloc: types.GenesisAlloc{
+				addr: {Balance: big.NewInt(1000000000000000000)},
+				// The token mints the called amount:
+				// sstore(0, add(sload(0), calldataload(0)))
+				token: {
+					Code:    []byte{0x5f, 0x54, 0x5f, 0x35, 0x01, 0x5f, 0x55, 0x00},
+					Storage: map[common.Hash]common.Hash{{}: slot(900)},
+				},
+				registry: {
+					Code:    []byte{0x0}, // Storage only, the contract logic is irrelevant here
+					Storage: storage,
+				},
+			},
+		}
+		var (
+			signer = types.LatestSigner(&config)
+			supply common.Hash
+			pause  common.Hash
+		)
+		_, _, receipts := GenerateChainWithGenesis(gspec, beacon.New(ethash.NewFaker()), len(tt.blocks), func(n int, b *BlockGen) {
+			for _, mint := range tt.blocks[n] {
+				tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(addr), token, common.Big0, 100000, b.BaseFee(), slot(mint).Bytes()), signer, key)
+				b.AddTx(tx)
+			}
+			supply = b.statedb.GetState(token, common.Hash{})
+			pause = b.statedb.GetState(registry, slot(12))
+		})
+		var have []uint64
+		for _, block := range receipts {
+			for _, receipt := range block {
+				have = append(have, receipt.Status)
+			}
+		}
+		if !slices.Equal(have, tt.want) {
+			t.Errorf("test %d: receipt status mismatch: have %v, want %v", i, have, tt.want)
+		}
+		if supply != slot(tt.supply) {
+			t.Errorf("test %d: supply mismatch: have %v, want %d", i, supply.Big(), tt.supply)
+		}
+		if pause != slot(tt.pause) {
+			t.Errorf("test %d: pause mismatch: have %v, want %d", i, pause.Big(), tt.pause)
+		}
+	}
+}
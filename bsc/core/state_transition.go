@@ -26,6 +26,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
 	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -119,6 +120,28 @@ func IntrinsicGas(data []byte, accessList types.AccessList, authList []types.Set
 	return gas, nil
 }
 
+// YieldSettlementGas returns the gas charged from Mendel for settling the
+// native holder yield of the accounts whose balance a transaction changes
+// directly: the sender buying gas and the recipient of any value.
+func YieldSettlementGas(rules params.Rules, value *big.Int) uint64 {
+	if !rules.IsMendel {
+		return 0
+	}
+	if value != nil && value.Sign() > 0 {
+		return 2 * params.YieldSettlementGas
+	}
+	return params.YieldSettlementGas
+}
+
+// addYieldSettlementGas adds the yield settlement charge to the intrinsic gas.
+func addYieldSettlementGas(gas uint64, rules params.Rules, value *big.Int) (uint64, error) {
+	yieldGas := YieldSettlementGas(rules, value)
+	if gas > math.MaxUint64-yieldGas {
+		return 0, ErrGasUintOverflow
+	}
+	return gas + yieldGas, nil
+}
+
 // FloorDataGas computes the minimum gas required for a transaction based on its data tokens (EIP-7623).
 func FloorDataGas(data []byte) (uint64, error) {
 	var (
@@ -454,11 +477,24 @@ func (st *stateTransition) execute() (*ExecutionResult, error) {
 			}
 		}
 	}
+	if rules.IsMendel {
+		// The direct parties of the transaction are rejected upfront, the value
+		// moved by internal calls is checked by the EVM
+		if systemcontracts.IsFrozen(st.state, msg.From) {
+			return nil, fmt.Errorf("%w: sender %v", ErrFrozenAccount, msg.From.Hex())
+		}
+		if msg.To != nil && systemcontracts.IsFrozen(st.state, *msg.To) {
+			return nil, fmt.Errorf("%w: recipient %v", ErrFrozenAccount, msg.To.Hex())
+		}
+	}
 	// Check clauses 4-5, subtract intrinsic gas if everything is correct
 	gas, err := IntrinsicGas(msg.Data, msg.AccessList, msg.SetCodeAuthorizations, contractCreation, rules.IsHomestead, rules.IsIstanbul, rules.IsShanghai)
 	if err != nil {
 		return nil, err
 	}
+	if gas, err = addYieldSettlementGas(gas, rules, msg.Value); err != nil {
+		return nil, err
+	}
 	if st.gasRemaining < gas {
 		return nil, fmt.Errorf("%w: have %d, want %d", ErrIntrinsicGas, st.gasRemaining, gas)
 	}
@@ -507,8 +543,15 @@ func (st *stateTransition) execute() (*ExecutionResult, error) {
 	var (
 		ret   []byte
 		vmerr error // vm errors do not effect consensus and are therefore not assigned to err
+
+		mint     *systemcontracts.MintGuard
+		snapshot int
 	)
+	if rules.IsMendel {
+		mint = systemcontracts.NewMintGuard(st.state)
+	}
 	if contractCreation {
+		snapshot = st.state.Snapshot()
 		ret, _, st.gasRemaining, vmerr = st.evm.Create(msg.From, msg.Data, st.gasRemaining, value)
 	} else {
 		// Increment the nonce for the next transaction.
@@ -532,8 +575,24 @@ func (st *stateTransition) execute() (*ExecutionResult, error) {
 		}
 
 		// Execute the transaction's call.
+		snapshot = st.state.Snapshot()
 		ret, st.gasRemaining, vmerr = st.evm.Call(msg.From, st.to(), msg.Data, st.gasRemaining, value)
 	}
+	// Minting the reserve backed token is only allowed up to the attested reserve,
+	// breaching it reverts the transaction.
+	if mint != nil {
+		if err := mint.Settle(st.state, st.evm.Context.Time); err != nil {
+			st.state.RevertToSnapshot(snapshot)
+			if contractCreation {
+				// The creator nonce is incremented within the reverted scope
+				st.state.SetNonce(msg.From, st.state.GetNonce(msg.From)+1, tracing.NonceChangeContractCreator)
+			}
+			// Breaching the reserve or the mint window limit also pauses minting
+			// until governance lifts the pause
+			mint.Trip(st.state, err)
+			ret, vmerr = nil, err
+		}
+	}
 
 	// Record the gas used excluding gas refunds. This value represents the actual
 	// gas allowance required to complete execution.
@@ -19,6 +19,7 @@ package core
 import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/beacon"
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core/state"
@@ -41,6 +42,15 @@ import (
 //
 // TODO(karalabe): Would be nice to resolve both issues above somehow and move it.
 func ExecuteStateless(config *params.ChainConfig, vmconfig vm.Config, block *types.Block, witness *stateless.Witness) (common.Hash, common.Hash, error) {
+	return ExecuteStatelessWithEngine(config, vmconfig, beacon.New(ethash.NewFaker()), nil, block, witness)
+}
+
+// ExecuteStatelessWithEngine is like ExecuteStateless, but finalizes the block
+// with the given consensus engine instead of the beacon one. The genesis header
+// is needed by the engines consulting it during finalization, such as Parlia,
+// and may be nil otherwise. Any ancestor headers the engine requires have to be
+// included in the witness.
+func ExecuteStatelessWithEngine(config *params.ChainConfig, vmconfig vm.Config, engine consensus.Engine, genesis *types.Header, block *types.Block, witness *stateless.Witness) (common.Hash, common.Hash, error) {
 	// Sanity check if the supplied block accidentally contains a set root or
 	// receipt hash. If so, be very loud, but still continue.
 	if block.Root() != (common.Hash{}) {
@@ -57,10 +67,11 @@ func ExecuteStateless(config *params.ChainConfig, vmconfig vm.Config, block *typ
 	}
 	// Create a blockchain that is idle, but can be used to access headers through
 	chain := &HeaderChain{
-		config:      config,
-		chainDb:     memdb,
-		headerCache: lru.NewCache[common.Hash, *types.Header](256),
-		engine:      beacon.New(ethash.NewFaker()),
+		config:        config,
+		chainDb:       memdb,
+		headerCache:   lru.NewCache[common.Hash, *types.Header](256),
+		engine:        engine,
+		genesisHeader: genesis,
 	}
 	processor := NewStateProcessor(chain)
 	validator := NewBlockValidator(config, nil) // No chain, we only validate the state, not the block
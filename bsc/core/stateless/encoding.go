@@ -55,6 +55,14 @@ func (w *Witness) fromExtWitness(ext *ExtWitness) error {
 	return nil
 }
 
+// ToWitness converts the consensus witness format, e.g. as returned by the
+// debug_executionWitness RPC, into a witness usable for stateless execution.
+func (ext *ExtWitness) ToWitness() *Witness {
+	w := new(Witness)
+	w.fromExtWitness(ext)
+	return w
+}
+
 // EncodeRLP serializes a witness as RLP.
 func (w *Witness) EncodeRLP(wr io.Writer) error {
 	return rlp.Encode(wr, w.ToExtWitness())
@@ -0,0 +1,374 @@
+package core
+
+import (
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/state/pruner"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+var (
+	// errStatePruneDisabled is returned if the online state pruning is requested
+	// while it's not available, e.g. on a read replica.
+	errStatePruneDisabled = errors.New("online state pruning is disabled")
+
+	// errStatePruneRunning is returned if the online state pruning is requested
+	// while a run is already in progress.
+	errStatePruneRunning = errors.New("online state pruning is already running")
+)
+
+// StatePruneStatus is the status of the online state pruning.
+type StatePruneStatus struct {
+	Scheme       string             `json:"scheme"`                 // State scheme, deciding whether the state is pruned or compacted
+	Running      bool               `json:"running"`                // Whether a run is in progress
+	Phase        string             `json:"phase,omitempty"`        // Phase of the run in progress
+	Target       *uint64            `json:"target,omitempty"`       // Block whose state is pruned against, hash scheme only
+	Started      uint64             `json:"started,omitempty"`      // Unix time the run in progress was started
+	Marked       uint64             `json:"marked"`                 // Number of trie nodes marked as live
+	Swept        uint64             `json:"swept"`                  // Number of database entries inspected
+	Deleted      uint64             `json:"deleted"`                // Number of stale trie nodes deleted
+	DeletedSize  common.StorageSize `json:"deletedSize"`            // Storage size of the deleted trie nodes
+	Compacted    uint64             `json:"compacted"`              // Number of key ranges compacted, path scheme only
+	LastFinished uint64             `json:"lastFinished,omitempty"` // Unix time the last run was completed
+	Error        string             `json:"error,omitempty"`        // Error the last run failed with
+}
+
+// progressReporter is implemented by the online pruner and the compactor.
+type progressReporter interface {
+	Progress() pruner.Progress
+}
+
+// statePruner is the module responsible for pruning the state in the background
+// while the node keeps running. With the hash scheme the stale trie nodes are
+// deleted, while with the path scheme, which deletes them by itself, the trie
+// node ranges are compacted to reclaim the space they occupied.
+//
+// The progress is persisted, so that an interrupted run is resumed after a
+// restart. Runs are started on demand or every configured interval.
+type statePruner struct {
+	config   pruner.OnlineConfig
+	interval time.Duration // Interval between the automatic runs, 0 to disable
+
+	chain *BlockChain
+	db    ethdb.Database
+
+	lock     sync.Mutex
+	running  bool
+	current  progressReporter // Pruner or compactor of the run in progress
+	target   *uint64
+	started  time.Time
+	lastErr  error
+	baseline time.Time // Time the next automatic run is scheduled from
+
+	quit chan struct{}
+	wg   sync.WaitGroup
+}
+
+// newStatePruner initializes the state pruner, resuming any interrupted run.
+func newStatePruner(config pruner.OnlineConfig, interval time.Duration, chain *BlockChain) *statePruner {
+	p := &statePruner{
+		config:   config,
+		interval: interval,
+		chain:    chain,
+		db:       chain.db,
+		baseline: time.Now(),
+		quit:     make(chan struct{}),
+	}
+	progress := rawdb.ReadStatePruneProgress(p.db)
+	if progress != nil && progress.Finished != 0 {
+		p.baseline = time.Unix(int64(progress.Finished), 0)
+	}
+	if progress != nil && progress.Running {
+		log.Info("Resuming interrupted state pruning", "scheme", chain.triedb.Scheme())
+		if err := p.start(progress.Cursor); err != nil {
+			log.Warn("Failed to resume state pruning", "err", err)
+		}
+	}
+	if interval > 0 {
+		p.wg.Add(1)
+		go p.loop()
+	}
+	log.Info("Initialized state pruner", "scheme", chain.triedb.Scheme(), "iobudget(MB/s)", config.IOBudget, "interval", interval)
+	return p
+}
+
+// loop starts a run every configured interval.
+func (p *statePruner) loop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(time.Minute)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			p.lock.Lock()
+			due := !p.running && time.Since(p.baseline) >= p.interval
+			p.lock.Unlock()
+			if due {
+				if err := p.start(nil); err != nil {
+					log.Warn("Failed to start scheduled state pruning", "err", err)
+				}
+			}
+		case <-p.quit:
+			return
+		}
+	}
+}
+
+// start launches a run in the background, continuing from the given cursor.
+func (p *statePruner) start(cursor []byte) error {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	if p.running {
+		return errStatePruneRunning
+	}
+	if p.chain.triedb.Scheme() == rawdb.HashScheme && p.chain.cfg.ArchiveMode {
+		return errors.New("state pruning is not available in archive mode")
+	}
+	progress := rawdb.ReadStatePruneProgress(p.db)
+	if progress == nil {
+		progress = &rawdb.StatePruneProgress{}
+	}
+	progress.Running, progress.Cursor = true, cursor
+	rawdb.WriteStatePruneProgress(p.db, *progress)
+
+	p.running, p.current, p.target, p.lastErr = true, nil, nil, nil
+	p.started = time.Now()
+	p.baseline = p.started
+
+	p.wg.Add(1)
+	go p.run(cursor)
+	return nil
+}
+
+// run executes a run, recording its outcome.
+func (p *statePruner) run(cursor []byte) {
+	defer p.wg.Done()
+
+	var err error
+	if p.chain.triedb.Scheme() == rawdb.HashScheme {
+		err = p.prune(cursor)
+	} else {
+		compactor := pruner.NewCompactor(p.db, p.config.IOBudget, p.quit)
+		p.setCurrent(compactor)
+		err = compactor.Compact(cursor, p.checkpoint)
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.running = false
+	switch {
+	case errors.Is(err, pruner.ErrPruningStopped):
+		// Interrupted by the shutdown, leave the run marked in progress
+		log.Info("State pruning interrupted, resuming on restart")
+	case err != nil:
+		log.Error("State pruning failed", "err", err)
+		p.lastErr = err
+		p.finish(0)
+	default:
+		log.Info("State pruning finished", "elapsed", common.PrettyDuration(time.Since(p.started)))
+		p.finish(uint64(time.Now().Unix()))
+	}
+}
+
+// prune deletes the stale trie nodes of the hash scheme, continuing the sweep
+// from the given cursor. The nodes are marked afresh on every run, as the ones
+// marked by the trie database writes are not persisted.
+func (p *statePruner) prune(cursor []byte) error {
+	online, err := pruner.NewOnlinePruner(p.db, p.chain.triedb, p.config, p.quit)
+	if err != nil {
+		return err
+	}
+	defer online.Close()
+	p.setCurrent(online)
+
+	// The write hook is in place, pick the states to keep.
+	target, number, recents, err := p.chain.statePruneTarget()
+	if err != nil {
+		return err
+	}
+	p.lock.Lock()
+	p.target = &number
+	p.lock.Unlock()
+
+	log.Info("Pruning state online", "number", number, "root", target, "recents", len(recents), "cursor", cursor)
+	if err := online.Mark(target, recents); err != nil {
+		return err
+	}
+	return online.Sweep(cursor, p.checkpoint)
+}
+
+// checkpoint persists the cursor an interrupted run is resumed from.
+func (p *statePruner) checkpoint(cursor []byte) {
+	progress := rawdb.ReadStatePruneProgress(p.db)
+	if progress == nil {
+		progress = &rawdb.StatePruneProgress{}
+	}
+	progress.Running, progress.Cursor = true, cursor
+	rawdb.WriteStatePruneProgress(p.db, *progress)
+}
+
+// finish persists the end of a run, keeping the completion time of the last
+// successful one if failed. It assumes the lock is held.
+func (p *statePruner) finish(finished uint64) {
+	progress := rawdb.ReadStatePruneProgress(p.db)
+	if progress == nil {
+		progress = &rawdb.StatePruneProgress{}
+	}
+	progress.Running, progress.Cursor = false, nil
+	if finished != 0 {
+		progress.Finished = finished
+	}
+	rawdb.WriteStatePruneProgress(p.db, *progress)
+}
+
+func (p *statePruner) setCurrent(current progressReporter) {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.current = current
+}
+
+// status returns the status of the state pruning.
+func (p *statePruner) status() *StatePruneStatus {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	status := &StatePruneStatus{
+		Scheme:  p.chain.triedb.Scheme(),
+		Running: p.running,
+		Target:  p.target,
+	}
+	if p.running {
+		status.Started = uint64(p.started.Unix())
+	}
+	if p.current != nil {
+		progress := p.current.Progress()
+		status.Phase = progress.Phase
+		status.Marked = progress.Marked
+		status.Swept = progress.Swept
+		status.Deleted = progress.Deleted
+		status.DeletedSize = progress.DeletedSize
+		status.Compacted = progress.Compacted
+	}
+	if progress := rawdb.ReadStatePruneProgress(p.db); progress != nil {
+		status.LastFinished = progress.Finished
+	}
+	if p.lastErr != nil {
+		status.Error = p.lastErr.Error()
+	}
+	return status
+}
+
+// close terminates the state pruner, interrupting the run in progress.
+func (p *statePruner) close() {
+	close(p.quit)
+	p.wg.Wait()
+}
+
+// statePruneTarget picks the state the hash scheme is pruned against, which is
+// the oldest available state still reachable: the oldest one of the finalized
+// state, the states kept in memory and the ones a reorg can go back to. The more
+// recent available canonical states are returned as well, along with all the
+// states referenced in memory, side chains included, as they might refer to
+// nodes the target doesn't.
+func (bc *BlockChain) statePruneTarget() (common.Hash, uint64, []common.Hash, error) {
+	var (
+		head = bc.CurrentBlock().Number.Uint64()
+		from uint64
+	)
+	if head+1 > bc.triesInMemory {
+		from = head + 1 - bc.triesInMemory
+	}
+	if final := bc.CurrentFinalBlock(); final != nil {
+		from = min(from, final.Number.Uint64())
+	}
+	inMemory, oldest, err := bc.inMemoryStateRoots()
+	if err != nil {
+		return common.Hash{}, 0, nil, err
+	}
+	if len(inMemory) > 0 {
+		from = min(from, oldest)
+	}
+	var (
+		target  common.Hash
+		number  uint64
+		recents []common.Hash
+		seen    = make(map[common.Hash]struct{})
+	)
+	for n := from; n <= head; n++ {
+		header := bc.GetHeaderByNumber(n)
+		if header == nil || !bc.HasState(header.Root) {
+			continue
+		}
+		if target == (common.Hash{}) {
+			target, number = header.Root, n
+			seen[target] = struct{}{}
+			continue
+		}
+		if _, ok := seen[header.Root]; !ok {
+			seen[header.Root] = struct{}{}
+			recents = append(recents, header.Root)
+		}
+	}
+	if target == (common.Hash{}) {
+		return common.Hash{}, 0, nil, errors.New("no recent state available")
+	}
+	for _, root := range inMemory {
+		if _, ok := seen[root]; !ok {
+			seen[root] = struct{}{}
+			recents = append(recents, root)
+		}
+	}
+	return target, number, recents, nil
+}
+
+// inMemoryStateRoots returns the roots of the states referenced in memory by the
+// hash scheme, awaiting to be flushed or dereferenced, and the number of the
+// oldest one.
+func (bc *BlockChain) inMemoryStateRoots() ([]common.Hash, uint64, error) {
+	if !bc.chainmu.TryLock() {
+		return nil, 0, errChainStopped
+	}
+	defer bc.chainmu.Unlock()
+
+	var (
+		roots   []common.Hash
+		numbers []int64
+	)
+	for !bc.triegc.Empty() {
+		root, number := bc.triegc.Pop()
+		roots, numbers = append(roots, root), append(numbers, number)
+	}
+	for i, root := range roots {
+		bc.triegc.Push(root, numbers[i])
+	}
+	if len(roots) == 0 {
+		return nil, 0, nil
+	}
+	// The queue is ordered by the negated block numbers, oldest first
+	return roots, uint64(-numbers[0]), nil
+}
+
+// StartStatePrune starts pruning the state in the background.
+func (bc *BlockChain) StartStatePrune() error {
+	if bc.statePruner == nil {
+		return errStatePruneDisabled
+	}
+	return bc.statePruner.start(nil)
+}
+
+// StatePruneStatus returns the status of the online state pruning.
+func (bc *BlockChain) StatePruneStatus() (*StatePruneStatus, error) {
+	if bc.statePruner == nil {
+		return nil, errStatePruneDisabled
+	}
+	return bc.statePruner.status(), nil
+}
@@ -0,0 +1,175 @@
+package core
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/trie"
+)
+
+// waitStatePrune waits until the running state pruning is finished.
+func waitStatePrune(t *testing.T, chain *BlockChain) *StatePruneStatus {
+	for i := 0; i < 500; i++ {
+		status, err := chain.StatePruneStatus()
+		if err != nil {
+			t.Fatalf("failed to read prune status: %v", err)
+		}
+		if !status.Running {
+			return status
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+	t.Fatal("state pruning not finished")
+	return nil
+}
+
+// stateComplete reports whether all the account trie nodes of the given state
+// are present.
+func stateComplete(chain *BlockChain, root common.Hash) bool {
+	tr, err := trie.NewStateTrie(trie.StateTrieID(root), chain.triedb)
+	if err != nil {
+		return false
+	}
+	iter, err := tr.NodeIterator(nil)
+	if err != nil {
+		return false
+	}
+	for iter.Next(true) {
+	}
+	return iter.Error() == nil
+}
+
+// TestOnlineStatePrune tests that the stale states of the hash scheme are pruned
+// while the chain is running, keeping the recent and the genesis states.
+func TestOnlineStatePrune(t *testing.T) {
+	var (
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		signer = types.LatestSigner(params.TestChainConfig)
+		engine = ethash.NewFaker()
+		gspec  = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  types.GenesisAlloc{addr: {Balance: big.NewInt(1000000000000000000)}},
+		}
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 12, func(i int, b *BlockGen) {
+		recipient := common.BigToAddress(big.NewInt(int64(0x1000 + i)))
+		tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(addr), recipient, big.NewInt(1), params.TxGas, b.header.BaseFee, nil), signer, key)
+		b.AddTx(tx)
+	})
+	// Persist all the states in archive mode, then reopen the chain pruning
+	db := rawdb.NewMemoryDatabase()
+	chain, err := NewBlockChain(db, gspec, engine, DefaultConfig().WithArchive(true))
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	if _, err := chain.InsertChain(blocks); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	if err := chain.StartStatePrune(); err == nil {
+		t.Fatal("state pruning started in archive mode")
+	}
+	chain.Stop()
+
+	cfg := DefaultConfig()
+	cfg.TriesInMemory = 4
+	chain, err = NewBlockChain(db, gspec, engine, cfg)
+	if err != nil {
+		t.Fatalf("failed to reopen chain: %v", err)
+	}
+	defer chain.Stop()
+
+	if err := chain.StartStatePrune(); err != nil {
+		t.Fatalf("failed to start state pruning: %v", err)
+	}
+	status := waitStatePrune(t, chain)
+	if status.Error != "" {
+		t.Fatalf("state pruning failed: %v", status.Error)
+	}
+	if status.Target == nil || *status.Target != 9 {
+		t.Fatalf("unexpected prune target: %v", status.Target)
+	}
+	if status.Deleted == 0 || status.LastFinished == 0 {
+		t.Fatalf("unexpected prune status: %+v", status)
+	}
+	if progress := rawdb.ReadStatePruneProgress(db); progress == nil || progress.Running {
+		t.Fatalf("unexpected persisted progress: %+v", progress)
+	}
+	if !stateComplete(chain, chain.Genesis().Root()) {
+		t.Fatal("genesis state pruned")
+	}
+	for n := uint64(1); n <= 12; n++ {
+		root := chain.GetHeaderByNumber(n).Root
+		if have, want := stateComplete(chain, root), n >= 9; have != want {
+			t.Fatalf("state %d: complete %v, want %v", n, have, want)
+		}
+	}
+}
+
+// TestOnlineStatePruneInMemory tests that the states kept in memory by the hash
+// scheme are kept by the pruning, including the ones older than the recent
+// states, as their nodes refer to the persisted ones.
+func TestOnlineStatePruneInMemory(t *testing.T) {
+	var (
+		key, _ = crypto.HexToECDSA("b71c71a67e1177ad4e901695e1b4b9ee17ae16c6668d313eac2f96dbcda3f291")
+		addr   = crypto.PubkeyToAddress(key.PublicKey)
+		signer = types.LatestSigner(params.TestChainConfig)
+		engine = ethash.NewFaker()
+		gspec  = &Genesis{
+			Config: params.TestChainConfig,
+			Alloc:  types.GenesisAlloc{addr: {Balance: big.NewInt(1000000000000000000)}},
+		}
+	)
+	_, blocks, _ := GenerateChainWithGenesis(gspec, engine, 16, func(i int, b *BlockGen) {
+		recipient := common.BigToAddress(big.NewInt(int64(0x1000 + i)))
+		tx, _ := types.SignTx(types.NewTransaction(b.TxNonce(addr), recipient, big.NewInt(1), params.TxGas, b.header.BaseFee, nil), signer, key)
+		b.AddTx(tx)
+	})
+	// Persist the first states, then import the rest into memory
+	db := rawdb.NewMemoryDatabase()
+	chain, err := NewBlockChain(db, gspec, engine, DefaultConfig().WithArchive(true))
+	if err != nil {
+		t.Fatalf("failed to create chain: %v", err)
+	}
+	if _, err := chain.InsertChain(blocks[:12]); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	chain.Stop()
+
+	cfg := DefaultConfig()
+	cfg.TriesInMemory = 2
+	chain, err = NewBlockChain(db, gspec, engine, cfg)
+	if err != nil {
+		t.Fatalf("failed to reopen chain: %v", err)
+	}
+	defer chain.Stop()
+	if _, err := chain.InsertChain(blocks[12:]); err != nil {
+		t.Fatalf("failed to insert chain: %v", err)
+	}
+	if err := chain.StartStatePrune(); err != nil {
+		t.Fatalf("failed to start state pruning: %v", err)
+	}
+	status := waitStatePrune(t, chain)
+	if status.Error != "" {
+		t.Fatalf("state pruning failed: %v", status.Error)
+	}
+	if status.Target == nil || *status.Target != 13 {
+		t.Fatalf("unexpected prune target: %v", status.Target)
+	}
+	for n := uint64(1); n <= 16; n++ {
+		if n == 12 {
+			continue // Head state on reopening, its nodes stay cached
+		}
+		root := chain.GetHeaderByNumber(n).Root
+		if have, want := stateComplete(chain, root), n >= 13; have != want {
+			t.Fatalf("state %d: complete %v, want %v", n, have, want)
+		}
+	}
+}
@@ -19,4 +19,9 @@ const (
 	GovTokenContract           = "0x0000000000000000000000000000000000002005"
 	TimelockContract           = "0x0000000000000000000000000000000000002006"
 	TokenRecoverPortalContract = "0x0000000000000000000000000000000000003000"
+
+	// mendel contracts
+	FreezeListContract      = "0x0000000000000000000000000000000000003001"
+	FeeSplitContract        = "0x0000000000000000000000000000000000003002"
+	ReserveRegistryContract = "0x0000000000000000000000000000000000003005"
 )
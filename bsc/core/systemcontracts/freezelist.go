@@ -0,0 +1,46 @@
+package systemcontracts
+
+import (
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+var (
+	freezeListAddr = common.HexToAddress(FreezeListContract)
+
+	// freezeListSlot is the storage slot of the `mapping(address => uint256)`
+	// in the freeze list contract, which maps a frozen account to the id of the
+	// governance proposal that froze it. Governor proposal ids are hashes, so a
+	// zero value means the account is not frozen.
+	freezeListSlot = common.Hash{}
+)
+
+// freezeListKey returns the storage key of the freeze record of addr, following
+// the solidity layout of mappings.
+func freezeListKey(addr common.Address) common.Hash {
+	return crypto.Keccak256Hash(common.LeftPadBytes(addr.Bytes(), common.HashLength), freezeListSlot.Bytes())
+}
+
+// FrozenBy returns the id of the governance proposal which froze addr, or nil
+// if the account is not frozen in the given state. The list is read straight
+// from the contract storage, so it is cheap enough to consult for every
+// transaction.
+func FrozenBy(state vm.StateDB, addr common.Address) *big.Int {
+	value := state.GetState(freezeListAddr, freezeListKey(addr))
+	if value == (common.Hash{}) {
+		return nil
+	}
+	return value.Big()
+}
+
+// IsFrozen reports whether addr is on the governance freeze list.
+//
+// Transactions sent by or to a frozen account are invalid. Within execution,
+// the EVM fails any value transfer from or to a frozen account, whether by a
+// call, a contract creation or a self-destruct.
+func IsFrozen(state vm.StateDB, addr common.Address) bool {
+	return state.GetState(freezeListAddr, freezeListKey(addr)) != (common.Hash{})
+}
@@ -0,0 +1,67 @@
+package systemcontracts
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+// ErrReserveExceeded is returned if a transaction mints the reserve backed
+// token above the last attested reserve.
+var ErrReserveExceeded = errors.New("supply exceeds attested reserve")
+
+// MintGuard watches the supply of the reserve backed token across a
+// transaction, allowing mints only within the attested reserve.
+// Mints are further limited per mint window and stopped by a pause, see
+// MintLimit.
+type MintGuard struct {
+	token      common.Address
+	supplySlot common.Hash
+	supply     *big.Int // Supply before the transaction
+}
+
+// NewMintGuard records the supply of the reserve backed token before a
+// transaction, or returns nil if no token is configured.
+func NewMintGuard(state vm.StateDB) *MintGuard {
+	token, supplySlot := ReserveToken(state)
+	if token == (common.Address{}) {
+		return nil
+	}
+	return &MintGuard{
+		token:      token,
+		supplySlot: supplySlot,
+		supply:     state.GetState(token, supplySlot).Big(),
+	}
+}
+
+// Settle checks the amount minted by the transaction at the given time, if
+// any. An error is returned if the mint must be reverted.
+func (g *MintGuard) Settle(state vm.StateDB, time uint64) error {
+	supply := state.GetState(g.token, g.supplySlot).Big()
+	if supply.Cmp(g.supply) <= 0 {
+		return nil
+	}
+	limit := GetMintLimit(state)
+	if limit.Pause != 0 {
+		return ErrMintingPaused
+	}
+	if reserve := GetReserve(state); reserve != nil && supply.Cmp(reserve.Total) > 0 {
+		return fmt.Errorf("%w: supply %v, reserve %v", ErrReserveExceeded, supply, reserve.Total)
+	}
+	// Account the mint to the window containing time, nothing is accounted if
+	// the mint is reverted
+	if limit.Window == 0 {
+		return nil
+	}
+	used := limit.Used(time)
+	used.Add(used, supply.Sub(supply, g.supply))
+	if used.Cmp(limit.Limit) > 0 {
+		return fmt.Errorf("%w: minted %v, limit %v", ErrMintLimitExceeded, used, limit.Limit)
+	}
+	state.SetState(ReserveRegistryAddr, mintWindowStartSlot, common.BigToHash(new(big.Int).SetUint64(limit.windowStart(time))))
+	state.SetState(ReserveRegistryAddr, mintMintedSlot, common.BigToHash(used))
+	return nil
+}
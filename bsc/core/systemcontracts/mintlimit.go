@@ -0,0 +1,93 @@
+package systemcontracts
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/vm"
+)
+
+var (
+	// ErrMintLimitExceeded is returned if a transaction mints the reserve backed
+	// token above the limit of the current mint window.
+	ErrMintLimitExceeded = errors.New("mint window limit exceeded")
+
+	// ErrMintingPaused is returned if a transaction mints the reserve backed
+	// token while minting is paused.
+	ErrMintingPaused = errors.New("minting paused")
+)
+
+var (
+	// Storage layout of the mint limit, following the reserve record in the
+	// reserve registry contract. Governance configures the length of the mint
+	// window in seconds and the amount mintable per window, and lifts a pause
+	// by clearing the pause slot. The remaining slots are written whenever the
+	// token is minted.
+	mintWindowSlot      = common.BigToHash(big.NewInt(8))
+	mintLimitSlot       = common.BigToHash(big.NewInt(9))
+	mintWindowStartSlot = common.BigToHash(big.NewInt(10))
+	mintMintedSlot      = common.BigToHash(big.NewInt(11))
+	mintPauseSlot       = common.BigToHash(big.NewInt(12))
+)
+
+// Reasons for pausing the minting of the reserve backed token, as recorded in
+// the pause slot. Any other non zero value is a pause set by governance.
+const (
+	MintPauseLimitExceeded   = 1 // A mint exceeded the window limit
+	MintPauseReserveExceeded = 2 // A mint exceeded the attested reserve
+)
+
+// MintLimit is the rate limit on minting the reserve backed token.
+type MintLimit struct {
+	Window      uint64   // Length of the mint window in seconds, zero if unlimited
+	Limit       *big.Int // Amount mintable per window
+	WindowStart uint64   // Start of the window of the last mint
+	Minted      *big.Int // Amount minted in the window of the last mint
+	Pause       uint64   // Reason minting is paused, zero if allowed
+}
+
+// GetMintLimit returns the mint limit configured in the reserve registry.
+func GetMintLimit(state vm.StateDB) *MintLimit {
+	return &MintLimit{
+		Window:      state.GetState(ReserveRegistryAddr, mintWindowSlot).Big().Uint64(),
+		Limit:       state.GetState(ReserveRegistryAddr, mintLimitSlot).Big(),
+		WindowStart: state.GetState(ReserveRegistryAddr, mintWindowStartSlot).Big().Uint64(),
+		Minted:      state.GetState(ReserveRegistryAddr, mintMintedSlot).Big(),
+		Pause:       state.GetState(ReserveRegistryAddr, mintPauseSlot).Big().Uint64(),
+	}
+}
+
+// windowStart returns the start of the mint window containing time.
+func (l *MintLimit) windowStart(time uint64) uint64 {
+	return time - time%l.Window
+}
+
+// Used returns the amount minted in the window containing time.
+func (l *MintLimit) Used(time uint64) *big.Int {
+	if l.Window == 0 || l.windowStart(time) != l.WindowStart {
+		return new(big.Int)
+	}
+	return new(big.Int).Set(l.Minted)
+}
+
+// Trip pauses minting if err, as returned by Settle, is a breach of the
+// reserve or the mint limit. The pause stays in effect until it is lifted by
+// governance.
+func (g *MintGuard) Trip(state vm.StateDB, err error) {
+	var reason int64
+	switch {
+	case errors.Is(err, ErrMintLimitExceeded):
+		reason = MintPauseLimitExceeded
+	case errors.Is(err, ErrReserveExceeded):
+		reason = MintPauseReserveExceeded
+	default:
+		return
+	}
+	if state.GetNonce(ReserveRegistryAddr) == 0 {
+		// Keep the pause alive even if the registry has no code yet
+		state.SetNonce(ReserveRegistryAddr, 1, tracing.NonceChangeUnspecified)
+	}
+	state.SetState(ReserveRegistryAddr, mintPauseSlot, common.BigToHash(big.NewInt(reason)))
+}
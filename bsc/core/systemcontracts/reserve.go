@@ -0,0 +1,154 @@
+package systemcontracts
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/tracing"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// ReserveRegistryAddr is the address of the reserve registry contract, which
+// receives the reserve attestations as system transactions.
+var ReserveRegistryAddr = common.HexToAddress(ReserveRegistryContract)
+
+var (
+	// Storage layout of the reserve registry contract. Governance configures the
+	// reserve backed token, the storage slot of its total supply, the auditors
+	// as a `mapping(address => bool)` and the number of auditors required to
+	// sign an attestation. The remaining slots are written by the consensus
+	// engine when it includes an attestation.
+	reserveTokenSlot       = common.BigToHash(big.NewInt(0))
+	reserveSupplySlotSlot  = common.BigToHash(big.NewInt(1))
+	reserveAuditorsSlot    = common.BigToHash(big.NewInt(2))
+	reserveThresholdSlot   = common.BigToHash(big.NewInt(3))
+	reserveTotalSlot       = common.BigToHash(big.NewInt(4))
+	reserveSourceBlockSlot = common.BigToHash(big.NewInt(5))
+	reserveNumberSlot      = common.BigToHash(big.NewInt(6))
+	reserveNonceSlot       = common.BigToHash(big.NewInt(7))
+
+	// reserveRegistryABI contains the method of the reserve registry contract
+	// receiving the attestations included by the proposers.
+	reserveRegistryABI, _ = abi.JSON(strings.NewReader(`[{"type":"function","name":"attestReserve","inputs":[{"name":"attestation","type":"bytes"}],"outputs":[],"stateMutability":"nonpayable"}]`))
+)
+
+// Reserve is the last attested reserve of the reserve backed token.
+type Reserve struct {
+	Token       common.Address // Reserve backed token
+	SupplySlot  common.Hash    // Storage slot of the total supply of the token
+	Total       *big.Int       // Attested reserve of all backing assets
+	SourceBlock common.Hash    // Source chain block the reserve was read at
+	Number      uint64         // Block the attestation was included in
+	Nonce       uint64         // Sequence number of the attestation
+}
+
+// GetReserve returns the last attested reserve, or nil if no token is
+// configured or no reserve has been attested yet. The record is read straight
+// from the contract storage, so it is cheap enough to consult for every
+// transaction.
+func GetReserve(state vm.StateDB) *Reserve {
+	number := state.GetState(ReserveRegistryAddr, reserveNumberSlot)
+	if number == (common.Hash{}) {
+		return nil
+	}
+	token := state.GetState(ReserveRegistryAddr, reserveTokenSlot)
+	if token == (common.Hash{}) {
+		return nil
+	}
+	return &Reserve{
+		Token:       common.BytesToAddress(token.Bytes()),
+		SupplySlot:  state.GetState(ReserveRegistryAddr, reserveSupplySlotSlot),
+		Total:       state.GetState(ReserveRegistryAddr, reserveTotalSlot).Big(),
+		SourceBlock: state.GetState(ReserveRegistryAddr, reserveSourceBlockSlot),
+		Number:      number.Big().Uint64(),
+		Nonce:       state.GetState(ReserveRegistryAddr, reserveNonceSlot).Big().Uint64(),
+	}
+}
+
+// ReserveToken returns the reserve backed token and the storage slot of its
+// total supply, whether or not a reserve has been attested.
+func ReserveToken(state vm.StateDB) (common.Address, common.Hash) {
+	token := state.GetState(ReserveRegistryAddr, reserveTokenSlot)
+	return common.BytesToAddress(token.Bytes()), state.GetState(ReserveRegistryAddr, reserveSupplySlotSlot)
+}
+
+// IsReserveAuditor reports whether addr may sign reserve attestations.
+func IsReserveAuditor(state vm.StateDB, addr common.Address) bool {
+	key := crypto.Keccak256Hash(common.LeftPadBytes(addr.Bytes(), common.HashLength), reserveAuditorsSlot.Bytes())
+	return state.GetState(ReserveRegistryAddr, key) != (common.Hash{})
+}
+
+// VerifyReserveAttestation checks that the attestation follows the last one
+// and is signed by enough auditors.
+func VerifyReserveAttestation(state vm.StateDB, att *types.ReserveAttestation) error {
+	threshold := state.GetState(ReserveRegistryAddr, reserveThresholdSlot).Big()
+	if threshold.Sign() == 0 {
+		return errors.New("reserve registry not configured")
+	}
+	if state.GetState(ReserveRegistryAddr, reserveNumberSlot) != (common.Hash{}) {
+		if last := state.GetState(ReserveRegistryAddr, reserveNonceSlot).Big().Uint64(); att.Nonce <= last {
+			return fmt.Errorf("stale reserve attestation, nonce %d, last %d", att.Nonce, last)
+		}
+	}
+	if big.NewInt(int64(len(att.Signers))).Cmp(threshold) < 0 {
+		return fmt.Errorf("reserve attestation below threshold, have %d signers, want %v", len(att.Signers), threshold)
+	}
+	if att.Total().BitLen() > 256 {
+		return errors.New("reserve total overflows")
+	}
+	for _, signer := range att.Signers {
+		if !IsReserveAuditor(state, signer) {
+			return fmt.Errorf("reserve attestation signed by non auditor %v", signer)
+		}
+	}
+	return att.VerifySignatures()
+}
+
+// PackReserveAttestation encodes the attestation as a call to the reserve
+// registry contract.
+func PackReserveAttestation(att *types.ReserveAttestation) ([]byte, error) {
+	blob, err := rlp.EncodeToBytes(att)
+	if err != nil {
+		return nil, err
+	}
+	return reserveRegistryABI.Pack("attestReserve", blob)
+}
+
+// UnpackReserveAttestation decodes the attestation from a call to the reserve
+// registry contract.
+func UnpackReserveAttestation(data []byte) (*types.ReserveAttestation, error) {
+	method := reserveRegistryABI.Methods["attestReserve"]
+	if len(data) < 4 || !bytes.Equal(data[:4], method.ID) {
+		return nil, errors.New("not a reserve attestation")
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, err
+	}
+	att := new(types.ReserveAttestation)
+	if err := rlp.DecodeBytes(args[0].([]byte), att); err != nil {
+		return nil, err
+	}
+	return att, nil
+}
+
+// WriteReserveAttestation records the attestation included in the given block
+// as the last attested reserve.
+func WriteReserveAttestation(state vm.StateDB, att *types.ReserveAttestation, number *big.Int) {
+	if state.GetNonce(ReserveRegistryAddr) == 0 {
+		// Keep the record alive even if the registry has no code yet
+		state.SetNonce(ReserveRegistryAddr, 1, tracing.NonceChangeUnspecified)
+	}
+	state.SetState(ReserveRegistryAddr, reserveTotalSlot, common.BigToHash(att.Total()))
+	state.SetState(ReserveRegistryAddr, reserveSourceBlockSlot, att.SourceBlock)
+	state.SetState(ReserveRegistryAddr, reserveNumberSlot, common.BigToHash(number))
+	state.SetState(ReserveRegistryAddr, reserveNonceSlot, common.BigToHash(new(big.Int).SetUint64(att.Nonce)))
+}
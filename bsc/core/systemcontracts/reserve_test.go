@@ -0,0 +1,116 @@
+package systemcontracts
+
+import (
+	"crypto/ecdsa"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+func signReserveAttestation(att *types.ReserveAttestation, keys ...*ecdsa.PrivateKey) {
+	digest := att.Digest()
+	for _, key := range keys {
+		sig, _ := crypto.Sign(digest[:], key)
+		att.Signers = append(att.Signers, crypto.PubkeyToAddress(key.PublicKey))
+		att.Signatures = append(att.Signatures, sig)
+	}
+}
+
+// Tests that reserve attestations are only accepted in sequence and when
+// signed by enough auditors.
+func TestVerifyReserveAttestation(t *testing.T) {
+	var (
+		statedb, _ = state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+		auditors   []*ecdsa.PrivateKey
+		outsider   = mustGenerateKey(t)
+	)
+	for i := 0; i < 3; i++ {
+		key := mustGenerateKey(t)
+		auditors = append(auditors, key)
+		slot := crypto.Keccak256Hash(common.LeftPadBytes(crypto.PubkeyToAddress(key.PublicKey).Bytes(), 32), reserveAuditorsSlot.Bytes())
+		statedb.SetState(ReserveRegistryAddr, slot, common.BigToHash(common.Big1))
+	}
+	newAttestation := func(nonce uint64, keys ...*ecdsa.PrivateKey) *types.ReserveAttestation {
+		att := &types.ReserveAttestation{
+			Nonce:       nonce,
+			SourceBlock: common.Hash{0x01},
+			Assets: []types.ReserveAsset{
+				{Asset: common.Address{}, Amount: big.NewInt(600)},
+				{Asset: common.HexToAddress("0xa55e7"), Amount: big.NewInt(400)},
+			},
+		}
+		signReserveAttestation(att, keys...)
+		return att
+	}
+	if err := VerifyReserveAttestation(statedb, newAttestation(1, auditors...)); err == nil {
+		t.Fatal("attestation accepted by unconfigured registry")
+	}
+	statedb.SetState(ReserveRegistryAddr, reserveThresholdSlot, common.BigToHash(big.NewInt(2)))
+
+	corrupt := newAttestation(1, auditors[:2]...)
+	corrupt.Assets[0].Amount = big.NewInt(601)
+	duplicate := newAttestation(1, auditors[0])
+	signReserveAttestation(duplicate, auditors[0])
+
+	for _, tt := range []struct {
+		name  string
+		att   *types.ReserveAttestation
+		valid bool
+	}{
+		{"below threshold", newAttestation(1, auditors[0]), false},
+		{"non auditor", newAttestation(1, auditors[0], outsider), false},
+		{"corrupt", corrupt, false},
+		{"duplicate signer", duplicate, false},
+		{"valid", newAttestation(1, auditors[0], auditors[2]), true},
+	} {
+		if err := VerifyReserveAttestation(statedb, tt.att); (err == nil) != tt.valid {
+			t.Errorf("%s: unexpected verification result: %v", tt.name, err)
+		}
+	}
+
+	// Included attestations set the reserve and must be followed by later ones
+	att := newAttestation(1, auditors...)
+	WriteReserveAttestation(statedb, att, big.NewInt(10))
+	if reserve := GetReserve(statedb); reserve != nil {
+		t.Fatal("reserve returned without token")
+	}
+	statedb.SetState(ReserveRegistryAddr, reserveTokenSlot, common.BytesToHash(common.HexToAddress("0x601d").Bytes()))
+	reserve := GetReserve(statedb)
+	if reserve == nil || reserve.Total.Int64() != 1000 || reserve.Nonce != 1 || reserve.Number != 10 || reserve.SourceBlock != att.SourceBlock {
+		t.Fatalf("attested reserve mismatch: %+v", reserve)
+	}
+	if err := VerifyReserveAttestation(statedb, newAttestation(1, auditors...)); err == nil {
+		t.Fatal("replayed attestation accepted")
+	}
+	if err := VerifyReserveAttestation(statedb, newAttestation(2, auditors...)); err != nil {
+		t.Fatalf("next attestation rejected: %v", err)
+	}
+
+	// Attestations round trip through the registry call
+	data, err := PackReserveAttestation(att)
+	if err != nil {
+		t.Fatalf("failed to pack attestation: %v", err)
+	}
+	dec, err := UnpackReserveAttestation(data)
+	if err != nil {
+		t.Fatalf("failed to unpack attestation: %v", err)
+	}
+	if dec.Digest() != att.Digest() || len(dec.Signers) != 3 || dec.VerifySignatures() != nil {
+		t.Fatal("unpacked attestation mismatch")
+	}
+	if _, err := UnpackReserveAttestation(data[1:]); err == nil {
+		t.Fatal("malformed call unpacked")
+	}
+}
+
+func mustGenerateKey(t *testing.T) *ecdsa.PrivateKey {
+	key, err := crypto.GenerateKey()
+	if err != nil {
+		t.Fatalf("failed to generate key: %v", err)
+	}
+	return key
+}
@@ -26,23 +26,26 @@ func _() {
 	_ = x[BalanceChangeRevert-15]
 	_ = x[BalanceDecreaseBSCDistributeReward-210]
 	_ = x[BalanceIncreaseBSCDistributeReward-211]
+	_ = x[BalanceIncreaseYieldAccrual-212]
+	_ = x[BalanceIncreaseYieldSettlement-213]
+	_ = x[BalanceDecreaseYieldSettlement-214]
 }
 
 const (
 	_BalanceChangeReason_name_0 = "UnspecifiedBalanceIncreaseRewardMineUncleBalanceIncreaseRewardMineBlockBalanceIncreaseWithdrawalBalanceIncreaseGenesisBalanceBalanceIncreaseRewardTransactionFeeBalanceDecreaseGasBuyBalanceIncreaseGasReturnBalanceIncreaseDaoContractBalanceDecreaseDaoAccountTransferTouchAccountBalanceIncreaseSelfdestructBalanceDecreaseSelfdestructBalanceDecreaseSelfdestructBurnRevert"
-	_BalanceChangeReason_name_1 = "BalanceDecreaseBSCDistributeRewardBalanceIncreaseBSCDistributeReward"
+	_BalanceChangeReason_name_1 = "BalanceDecreaseBSCDistributeRewardBalanceIncreaseBSCDistributeRewardBalanceIncreaseYieldAccrualBalanceIncreaseYieldSettlementBalanceDecreaseYieldSettlement"
 )
 
 var (
 	_BalanceChangeReason_index_0 = [...]uint16{0, 11, 41, 71, 96, 125, 160, 181, 205, 231, 256, 264, 276, 303, 330, 361, 367}
-	_BalanceChangeReason_index_1 = [...]uint8{0, 34, 68}
+	_BalanceChangeReason_index_1 = [...]uint8{0, 34, 68, 95, 125, 155}
 )
 
 func (i BalanceChangeReason) String() string {
 	switch {
 	case i <= 15:
 		return _BalanceChangeReason_name_0[_BalanceChangeReason_index_0[i]:_BalanceChangeReason_index_0[i+1]]
-	case 210 <= i && i <= 211:
+	case 210 <= i && i <= 214:
 		i -= 210
 		return _BalanceChangeReason_name_1[_BalanceChangeReason_index_1[i]:_BalanceChangeReason_index_1[i+1]]
 	default:
@@ -325,6 +325,16 @@ const (
 	// BalanceIncreaseBSCDistributeReward is a balance change that increases the block validator's balance and
 	// happens when BSC is distributing rewards to validator.
 	BalanceIncreaseBSCDistributeReward BalanceChangeReason = 211
+
+	// BalanceIncreaseYieldAccrual is the share of the block fees pooled in the
+	// yield index account for native holders.
+	BalanceIncreaseYieldAccrual BalanceChangeReason = 212
+	// BalanceIncreaseYieldSettlement is the yield accrued to a holder, credited
+	// when its balance is touched.
+	BalanceIncreaseYieldSettlement BalanceChangeReason = 213
+	// BalanceDecreaseYieldSettlement is the yield paid out of the yield index
+	// account when a holder is settled.
+	BalanceDecreaseYieldSettlement BalanceChangeReason = 214
 )
 
 // GasChangeReason is used to indicate the reason for a gas change, useful
@@ -1381,7 +1381,9 @@ func (p *BlobPool) checkDelegationLimit(tx *types.Transaction) error {
 func (p *BlobPool) validateTx(tx *types.Transaction) error {
 	// Ensure the transaction adheres to the stateful pool filters (nonce, balance)
 	stateOpts := &txpool.ValidationOptionsWithState{
-		State: p.state,
+		State:  p.state,
+		Config: p.chain.Config(),
+		Head:   p.head.Load(),
 
 		FirstNonceGap: func(addr common.Address) uint64 {
 			// Nonce gaps are not permitted in the blob pool, the first gap will
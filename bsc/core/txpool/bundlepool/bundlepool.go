@@ -0,0 +1,122 @@
+// Package bundlepool implements a pool of transaction bundles submitted for
+// atomic inclusion by the local block producer.
+package bundlepool
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+)
+
+// ErrBundlePoolFull is returned if a bundle is added to a full pool.
+var ErrBundlePoolFull = errors.New("bundle pool full")
+
+var bundleGauge = metrics.NewRegisteredGauge("txpool/bundles", nil)
+
+// Config are the configuration parameters of the bundle pool.
+type Config struct {
+	Enabled       bool   // Whether bundles are accepted and merged into locally built blocks
+	MaxBundles    int    // Maximum number of bundles kept in the pool
+	MaxBlockRange uint64 // Maximum number of blocks after the head a bundle may target
+}
+
+// DefaultConfig contains the default configurations for the bundle pool.
+var DefaultConfig = Config{
+	MaxBundles:    1024,
+	MaxBlockRange: 100,
+}
+
+// BlockChain defines the minimal set of methods needed to back a bundle pool
+// with a chain.
+type BlockChain interface {
+	// CurrentBlock returns the current head of the chain.
+	CurrentBlock() *types.Header
+}
+
+// BundlePool keeps the bundles submitted to the node until the last block they
+// may be included in is imported.
+type BundlePool struct {
+	config Config
+	chain  BlockChain
+
+	bundles map[common.Hash]*types.Bundle
+	lock    sync.RWMutex
+}
+
+// New creates a bundle pool backed by the given chain.
+func New(config Config, chain BlockChain) *BundlePool {
+	return &BundlePool{
+		config:  config,
+		chain:   chain,
+		bundles: make(map[common.Hash]*types.Bundle),
+	}
+}
+
+// Add checks the block range of bundle against the current head and adds it
+// to the pool. A bundle without a maximum block number targets the next block
+// only.
+func (p *BundlePool) Add(bundle *types.Bundle) error {
+	head := p.chain.CurrentBlock().Number.Uint64()
+	if bundle.MaxBlockNumber == 0 {
+		bundle.MaxBlockNumber = head + 1
+	}
+	if bundle.MaxBlockNumber <= head || bundle.MaxBlockNumber > head+p.config.MaxBlockRange || bundle.MinBlockNumber > bundle.MaxBlockNumber {
+		return fmt.Errorf("%w: blocks %d-%d, head %d", types.ErrBundleBlockRange, bundle.MinBlockNumber, bundle.MaxBlockNumber, head)
+	}
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.prune(head + 1)
+	if _, ok := p.bundles[bundle.Hash()]; ok {
+		return txpool.ErrAlreadyKnown
+	}
+	if len(p.bundles) >= p.config.MaxBundles {
+		return ErrBundlePoolFull
+	}
+	p.bundles[bundle.Hash()] = bundle
+	bundleGauge.Update(int64(len(p.bundles)))
+
+	log.Debug("Pooled new bundle", "hash", bundle.Hash(), "txs", len(bundle.Txs), "min", bundle.MinBlockNumber, "max", bundle.MaxBlockNumber)
+	return nil
+}
+
+// Pending returns the bundles which may be included in the block with the
+// given number, dropping those which can no longer be included.
+func (p *BundlePool) Pending(number uint64) []*types.Bundle {
+	p.lock.Lock()
+	defer p.lock.Unlock()
+
+	p.prune(number)
+	bundles := make([]*types.Bundle, 0, len(p.bundles))
+	for _, bundle := range p.bundles {
+		if bundle.Eligible(number) {
+			bundles = append(bundles, bundle)
+		}
+	}
+	return bundles
+}
+
+// Len returns the number of bundles in the pool.
+func (p *BundlePool) Len() int {
+	p.lock.RLock()
+	defer p.lock.RUnlock()
+
+	return len(p.bundles)
+}
+
+// prune drops the bundles which cannot be included in the block with the given
+// number or later ones. The caller must hold the lock.
+func (p *BundlePool) prune(number uint64) {
+	for hash, bundle := range p.bundles {
+		if bundle.MaxBlockNumber < number {
+			delete(p.bundles, hash)
+		}
+	}
+	bundleGauge.Update(int64(len(p.bundles)))
+}
@@ -0,0 +1,73 @@
+package bundlepool
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+type testBlockChain struct {
+	head *types.Header
+}
+
+func (c *testBlockChain) CurrentBlock() *types.Header { return c.head }
+
+func newTestBundle(nonce uint64, min, max uint64) *types.Bundle {
+	return &types.Bundle{
+		Txs:            types.Transactions{types.NewTransaction(nonce, [20]byte{}, new(big.Int), 21000, new(big.Int), nil)},
+		MinBlockNumber: min,
+		MaxBlockNumber: max,
+	}
+}
+
+// Tests that bundles are only accepted for blocks after the head and within
+// the configured range, and dropped once their last block is passed.
+func TestBundlePool(t *testing.T) {
+	chain := &testBlockChain{head: &types.Header{Number: big.NewInt(10)}}
+	pool := New(Config{Enabled: true, MaxBundles: 3, MaxBlockRange: 5}, chain)
+
+	for i, bundle := range []*types.Bundle{
+		newTestBundle(0, 0, 10), // Past
+		newTestBundle(0, 0, 16), // Too far ahead
+		newTestBundle(0, 13, 12),
+	} {
+		if err := pool.Add(bundle); !errors.Is(err, types.ErrBundleBlockRange) {
+			t.Errorf("bundle %d: error mismatch: have %v, want %v", i, err, types.ErrBundleBlockRange)
+		}
+	}
+	next := newTestBundle(1, 0, 0)
+	if err := pool.Add(next); err != nil {
+		t.Fatalf("failed to add bundle: %v", err)
+	}
+	if next.MaxBlockNumber != 11 {
+		t.Errorf("default max block mismatch: have %d, want %d", next.MaxBlockNumber, 11)
+	}
+	if err := pool.Add(newTestBundle(1, 0, 0)); !errors.Is(err, txpool.ErrAlreadyKnown) {
+		t.Errorf("duplicate error mismatch: have %v, want %v", err, txpool.ErrAlreadyKnown)
+	}
+	later := newTestBundle(2, 13, 15)
+	if err := pool.Add(later); err != nil {
+		t.Fatalf("failed to add bundle: %v", err)
+	}
+	if err := pool.Add(newTestBundle(3, 0, 15)); err != nil {
+		t.Fatalf("failed to add bundle: %v", err)
+	}
+	if err := pool.Add(newTestBundle(4, 0, 15)); !errors.Is(err, ErrBundlePoolFull) {
+		t.Errorf("full pool error mismatch: have %v, want %v", err, ErrBundlePoolFull)
+	}
+	if pending := pool.Pending(11); len(pending) != 2 {
+		t.Errorf("pending bundle count mismatch: have %d, want %d", len(pending), 2)
+	}
+	pending := pool.Pending(13)
+	if len(pending) != 2 || pool.Len() != 2 {
+		t.Fatalf("pending bundle count mismatch: have %d/%d, want %d", len(pending), pool.Len(), 2)
+	}
+	for _, bundle := range pending {
+		if bundle == next {
+			t.Error("expired bundle still pending")
+		}
+	}
+}
@@ -643,7 +643,9 @@ func (pool *LegacyPool) validateTx(tx *types.Transaction) error {
 	}
 
 	opts := &txpool.ValidationOptionsWithState{
-		State: pool.currentState,
+		State:  pool.currentState,
+		Config: pool.chainconfig,
+		Head:   pool.currentHead.Load(),
 
 		FirstNonceGap:    nil, // Pool allows arbitrary arrival order, don't invalidate nonce gaps
 		UsedAndLeftSlots: nil, // Pool has own mechanism to limit the number of transactions
@@ -24,6 +24,7 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto/kzg4844"
 	"github.com/ethereum/go-ethereum/log"
@@ -133,6 +134,7 @@ func ValidateTransaction(tx *types.Transaction, head *types.Header, signer types
 	if err != nil {
 		return err
 	}
+	intrGas += core.YieldSettlementGas(rules, tx.Value())
 	if tx.Gas() < intrGas {
 		return fmt.Errorf("%w: gas %v, minimum needed %v", core.ErrIntrinsicGas, tx.Gas(), intrGas)
 	}
@@ -221,6 +223,9 @@ func validateBlobSidecarOsaka(sidecar *types.BlobTxSidecar, hashes []common.Hash
 type ValidationOptionsWithState struct {
 	State *state.StateDB // State database to check nonces and balances against
 
+	Config *params.ChainConfig // Chain configuration to selectively validate based on current fork rules
+	Head   *types.Header       // Current head block the state belongs to
+
 	// FirstNonceGap is an optional callback to retrieve the first nonce gap in
 	// the list of pooled transactions of a specific account. If this method is
 	// set, nonce gaps will be checked and forbidden. If this method is not set,
@@ -253,6 +258,16 @@ func ValidateTransactionWithState(tx *types.Transaction, signer types.Signer, op
 		log.Error("Transaction sender recovery failed", "err", err)
 		return err
 	}
+	// Ensure neither party is frozen by governance, following the state
+	// transition which enforces the freeze list from Mendel on
+	if opts.Config.IsMendel(opts.Head.Number, opts.Head.Time) {
+		if systemcontracts.IsFrozen(opts.State, from) {
+			return fmt.Errorf("%w: sender %v", core.ErrFrozenAccount, from)
+		}
+		if to := tx.To(); to != nil && systemcontracts.IsFrozen(opts.State, *to) {
+			return fmt.Errorf("%w: recipient %v", core.ErrFrozenAccount, *to)
+		}
+	}
 	next := opts.State.GetNonce(from)
 	if next > tx.Nonce() {
 		return fmt.Errorf("%w: next nonce %v, tx nonce %v", core.ErrNonceTooLow, next, tx.Nonce())
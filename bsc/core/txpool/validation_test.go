@@ -25,9 +25,13 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
+	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/holiman/uint256"
 )
 
 func TestValidateTransactionEIP2681(t *testing.T) {
@@ -113,3 +117,51 @@ func createTestTransaction(key *ecdsa.PrivateKey, nonce uint64) *types.Transacti
 	signedTx, _ := types.SignTx(tx, types.HomesteadSigner{}, key)
 	return signedTx
 }
+
+func TestValidateTransactionFrozenAccount(t *testing.T) {
+	var (
+		key, _    = crypto.GenerateKey()
+		sender    = crypto.PubkeyToAddress(key.PublicKey)
+		recipient = common.HexToAddress("0x0000000000000000000000000000000000000001")
+		signer    = types.HomesteadSigner{}
+		tx        = createTestTransaction(key, 0)
+	)
+	freeze := func(statedb *state.StateDB, addr common.Address) {
+		key := crypto.Keccak256Hash(common.LeftPadBytes(addr.Bytes(), 32), common.Hash{}.Bytes())
+		statedb.SetState(common.HexToAddress(systemcontracts.FreezeListContract), key, common.HexToHash("0x01"))
+	}
+	tests := []struct {
+		name    string
+		frozen  []common.Address
+		mendel  bool
+		wantErr error
+	}{
+		{name: "no frozen account", frozen: nil, mendel: true, wantErr: nil},
+		{name: "frozen sender", frozen: []common.Address{sender}, mendel: true, wantErr: core.ErrFrozenAccount},
+		{name: "frozen recipient", frozen: []common.Address{recipient}, mendel: true, wantErr: core.ErrFrozenAccount},
+		{name: "frozen sender before mendel", frozen: []common.Address{sender}, mendel: false, wantErr: nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			statedb, _ := state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+			statedb.SetBalance(sender, uint256.NewInt(params.Ether), tracing.BalanceChangeUnspecified)
+			for _, addr := range tt.frozen {
+				freeze(statedb, addr)
+			}
+			config := *params.MergedTestChainConfig
+			if tt.mendel {
+				config.MendelTime = new(uint64)
+			}
+			opts := &ValidationOptionsWithState{
+				State:               statedb,
+				Config:              &config,
+				Head:                &types.Header{Number: common.Big0},
+				ExistingExpenditure: func(common.Address) *big.Int { return new(big.Int) },
+				ExistingCost:        func(common.Address, uint64) *big.Int { return nil },
+			}
+			if err := ValidateTransactionWithState(tx, signer, opts); !errors.Is(err, tt.wantErr) {
+				t.Errorf("ValidateTransactionWithState() error = %v, wantErr %v", err, tt.wantErr)
+			}
+		})
+	}
+}
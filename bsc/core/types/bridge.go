@@ -0,0 +1,59 @@
+package types
+
+import (
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// bridgeAttestationDomain separates the signatures of bridge attestations from
+// any other message signed with the BLS vote keys.
+var bridgeAttestationDomain = []byte("BSC_BRIDGE_ATTESTATION")
+
+// BridgeAttestation is the signature of a validator's BLS vote key over the
+// digest of an event observed on a bridged chain.
+type BridgeAttestation struct {
+	Digest      common.Hash  // Digest of the attested event.
+	VoteAddress BLSPublicKey // The BLS public key of the validator.
+	Signature   BLSSignature // Validator's signature for the signing hash.
+
+	// caches
+	hash atomic.Value
+}
+
+// SigningHash returns the hash signed by the validator, which is the keccak256
+// hash of the attestation domain followed by the digest.
+func (a *BridgeAttestation) SigningHash() common.Hash {
+	return crypto.Keccak256Hash(bridgeAttestationDomain, a.Digest[:])
+}
+
+// Hash returns the attestation's hash.
+func (a *BridgeAttestation) Hash() common.Hash {
+	if hash := a.hash.Load(); hash != nil {
+		return hash.(common.Hash)
+	}
+	h := rlpHash([]interface{}{a.Digest, a.VoteAddress, a.Signature})
+	a.hash.Store(h)
+	return h
+}
+
+// Verify checks the signature of the attestation using BLS.
+func (a *BridgeAttestation) Verify() error {
+	blsPubKey, err := bls.PublicKeyFromBytes(a.VoteAddress[:])
+	if err != nil {
+		return errors.Wrap(err, "convert public key from bytes to bls failed")
+	}
+	sig, err := bls.SignatureFromBytes(a.Signature[:])
+	if err != nil {
+		return errors.Wrap(err, "invalid signature")
+	}
+	hash := a.SigningHash()
+	if !sig.Verify(blsPubKey, hash[:]) {
+		return errors.New("verify bls signature failed")
+	}
+	return nil
+}
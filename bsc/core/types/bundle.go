@@ -0,0 +1,117 @@
+package types
+
+import (
+	"errors"
+	"math/big"
+	"slices"
+	"sync/atomic"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// MaxBundleTxs is the maximum number of transactions in a bundle.
+const MaxBundleTxs = 50
+
+var (
+	ErrEmptyBundle         = errors.New("bundle has no transactions")
+	ErrBundleTooLarge      = errors.New("bundle has too many transactions")
+	ErrBundleBlobTx        = errors.New("bundle contains blob transaction")
+	ErrBundleBlockRange    = errors.New("invalid bundle block range")
+	ErrBundleRevertingHash = errors.New("reverting transaction not in bundle")
+)
+
+// SendBundleArgs represents the arguments to submit a bundle.
+type SendBundleArgs struct {
+	Txs               []hexutil.Bytes `json:"txs"`
+	MinBlockNumber    hexutil.Uint64  `json:"minBlockNumber"`
+	MaxBlockNumber    hexutil.Uint64  `json:"maxBlockNumber"`
+	RevertingTxHashes []common.Hash   `json:"revertingTxHashes"`
+}
+
+// ToBundle decodes the transactions of the arguments into a bundle.
+func (args *SendBundleArgs) ToBundle() (*Bundle, error) {
+	if len(args.Txs) == 0 {
+		return nil, ErrEmptyBundle
+	}
+	if len(args.Txs) > MaxBundleTxs {
+		return nil, ErrBundleTooLarge
+	}
+	txs := make(Transactions, len(args.Txs))
+	for i, input := range args.Txs {
+		tx := new(Transaction)
+		if err := tx.UnmarshalBinary(input); err != nil {
+			return nil, err
+		}
+		if tx.Type() == BlobTxType {
+			return nil, ErrBundleBlobTx
+		}
+		txs[i] = tx
+	}
+	bundle := &Bundle{
+		Txs:               txs,
+		MinBlockNumber:    uint64(args.MinBlockNumber),
+		MaxBlockNumber:    uint64(args.MaxBlockNumber),
+		RevertingTxHashes: args.RevertingTxHashes,
+	}
+	for _, hash := range bundle.RevertingTxHashes {
+		if !slices.ContainsFunc(txs, func(tx *Transaction) bool { return tx.Hash() == hash }) {
+			return nil, ErrBundleRevertingHash
+		}
+	}
+	return bundle, nil
+}
+
+// Bundle is a group of transactions included atomically and in order, or not
+// at all. Only the transactions listed as reverting may fail without failing
+// the bundle.
+type Bundle struct {
+	Txs               Transactions
+	MinBlockNumber    uint64 // First block the bundle may be included in, zero if unbounded
+	MaxBlockNumber    uint64 // Last block the bundle may be included in
+	RevertingTxHashes []common.Hash
+
+	hash atomic.Pointer[common.Hash]
+}
+
+// Hash returns the hash of the bundle, derived from its transactions.
+func (b *Bundle) Hash() common.Hash {
+	if hash := b.hash.Load(); hash != nil {
+		return *hash
+	}
+	data := make([]byte, 0, len(b.Txs)*common.HashLength)
+	for _, tx := range b.Txs {
+		data = append(data, tx.Hash().Bytes()...)
+	}
+	hash := crypto.Keccak256Hash(data)
+	b.hash.Store(&hash)
+	return hash
+}
+
+// AllowsRevert reports whether the transaction with the given hash may fail
+// without failing the bundle.
+func (b *Bundle) AllowsRevert(hash common.Hash) bool {
+	return slices.Contains(b.RevertingTxHashes, hash)
+}
+
+// Eligible reports whether the bundle may be included in the given block.
+func (b *Bundle) Eligible(number uint64) bool {
+	return number >= b.MinBlockNumber && number <= b.MaxBlockNumber
+}
+
+// SimulatedBundle is a bundle executed on top of a state, along with the fees
+// its transactions pay to the block producer.
+type SimulatedBundle struct {
+	Bundle  *Bundle
+	GasUsed uint64
+	Fees    *big.Int // Fees and direct payments received by the block producer
+}
+
+// Price returns the fees per gas the bundle pays to the block producer.
+func (s *SimulatedBundle) Price() *big.Int {
+	if s.GasUsed == 0 {
+		return new(big.Int)
+	}
+	return new(big.Int).Div(s.Fees, new(big.Int).SetUint64(s.GasUsed))
+}
@@ -0,0 +1,75 @@
+package types
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// NativeTransferKind classifies a movement of the native coin.
+type NativeTransferKind uint8
+
+const (
+	NativeTransferCall   NativeTransferKind = iota // Value moved by a call, creation or self-destruct
+	NativeTransferMint                             // Coins issued by the protocol
+	NativeTransferBurn                             // Coins destroyed by the protocol
+	NativeTransferFee                              // Transaction fee paid to the fee recipient
+	NativeTransferReward                           // Fees and yield distributed by the consensus engine
+)
+
+var nativeTransferKindNames = []string{"transfer", "mint", "burn", "fee", "reward"}
+
+// String implements fmt.Stringer.
+func (k NativeTransferKind) String() string {
+	if int(k) < len(nativeTransferKindNames) {
+		return nativeTransferKindNames[k]
+	}
+	return fmt.Sprintf("unknown(%d)", k)
+}
+
+// MarshalText implements encoding.TextMarshaler.
+func (k NativeTransferKind) MarshalText() ([]byte, error) {
+	return []byte(k.String()), nil
+}
+
+// UnmarshalText implements encoding.TextUnmarshaler.
+func (k *NativeTransferKind) UnmarshalText(input []byte) error {
+	for i, name := range nativeTransferKindNames {
+		if string(input) == name {
+			*k = NativeTransferKind(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("unknown native transfer kind %q", input)
+}
+
+// NativeTransfer is a movement of the native coin observed while processing a
+// block. Transfers are recorded by the node for indexers, they are not part of
+// the consensus data and carry no EVM log. Mints have no sender and burns have
+// no recipient.
+type NativeTransfer struct {
+	Kind   NativeTransferKind
+	From   common.Address
+	To     common.Address
+	Amount *big.Int
+	TxHash common.Hash // Transaction the transfer was made in, zero outside transactions
+
+	Internal bool `rlp:"optional"` // Whether the transfer was made by a call within a contract
+
+	// Derived fields. These fields are filled in by the node
+	// but not secured by consensus.
+	BlockNumber uint64      `rlp:"-"`
+	BlockHash   common.Hash `rlp:"-"`
+	Index       uint        `rlp:"-"` // Index of the transfer in the block
+}
+
+// DeriveNativeTransferFields fills the transfers of a block with their
+// derived fields.
+func DeriveNativeTransferFields(transfers []*NativeTransfer, hash common.Hash, number uint64) {
+	for i, transfer := range transfers {
+		transfer.BlockNumber = number
+		transfer.BlockHash = hash
+		transfer.Index = uint(i)
+	}
+}
@@ -0,0 +1,87 @@
+package types
+
+import (
+	"math/big"
+	"slices"
+	"sync/atomic"
+
+	"github.com/pkg/errors"
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// priceReportDomain separates the signatures of price reports from any other
+// message signed with the BLS vote keys.
+var priceReportDomain = []byte("BSC_PRICE_REPORT")
+
+// PriceReport is the signature of a validator's BLS vote key over the price it
+// observed for an oracle round.
+type PriceReport struct {
+	Round       uint64       // Oracle round the price is reported for.
+	Price       *big.Int     // Reported price, fixed point with 18 decimals.
+	VoteAddress BLSPublicKey // The BLS public key of the validator.
+	Signature   BLSSignature // Validator's signature for the round and price.
+
+	// caches
+	hash atomic.Value
+}
+
+// SigningHash returns the hash signed by the reporting validator, binding the
+// round and price to the chain they are reported on.
+func (r *PriceReport) SigningHash(chainID *big.Int) common.Hash {
+	digest := rlpHash([]interface{}{chainID, r.Round, r.Price})
+	return crypto.Keccak256Hash(priceReportDomain, digest[:])
+}
+
+// Hash returns the report's hash.
+func (r *PriceReport) Hash() common.Hash {
+	if hash := r.hash.Load(); hash != nil {
+		return hash.(common.Hash)
+	}
+	h := rlpHash([]interface{}{r.Round, r.Price, r.VoteAddress, r.Signature})
+	r.hash.Store(h)
+	return h
+}
+
+// Verify checks the signature of the report on the given chain using BLS.
+func (r *PriceReport) Verify(chainID *big.Int) error {
+	if r.Price == nil || r.Price.Sign() <= 0 {
+		return errors.New("invalid price")
+	}
+	blsPubKey, err := bls.PublicKeyFromBytes(r.VoteAddress[:])
+	if err != nil {
+		return errors.Wrap(err, "convert public key from bytes to bls failed")
+	}
+	sig, err := bls.SignatureFromBytes(r.Signature[:])
+	if err != nil {
+		return errors.Wrap(err, "invalid signature")
+	}
+	hash := r.SigningHash(chainID)
+	if !sig.Verify(blsPubKey, hash[:]) {
+		return errors.New("verify bls signature failed")
+	}
+	return nil
+}
+
+// PriceAttestation is the set of price reports of an oracle round included in
+// a block by its proposer.
+type PriceAttestation struct {
+	Round   uint64         // Oracle round of the reports.
+	Reports []*PriceReport // Reports of distinct validators, sorted by vote address.
+}
+
+// Median returns the median of the reported prices, the lower one of the two
+// middle prices for an even number of reports.
+func (a *PriceAttestation) Median() *big.Int {
+	if len(a.Reports) == 0 {
+		return nil
+	}
+	prices := make([]*big.Int, len(a.Reports))
+	for i, report := range a.Reports {
+		prices[i] = report.Price
+	}
+	slices.SortFunc(prices, func(a, b *big.Int) int { return a.Cmp(b) })
+	return new(big.Int).Set(prices[(len(prices)-1)/2])
+}
@@ -0,0 +1,71 @@
+package types
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// ReserveAsset is the amount of a backing asset held in reserve.
+type ReserveAsset struct {
+	Asset  common.Address // Asset contract on the source chain, zero for its native coin
+	Amount *big.Int       // Amount held, in units of the backed token
+}
+
+// ReserveAttestation is the statement of the reserve auditors about the assets
+// backing the bridged token, as held on the source chain at a given block.
+type ReserveAttestation struct {
+	Nonce       uint64         // Sequence number, strictly increasing
+	SourceBlock common.Hash    // Source chain block the reserves were read at
+	Assets      []ReserveAsset // Amounts per backing asset
+	Signers     []common.Address
+	Signatures  [][]byte // Signatures of the signers over the digest
+}
+
+// Digest returns the hash signed by the auditors.
+func (a *ReserveAttestation) Digest() common.Hash {
+	return rlpHash([]interface{}{a.Nonce, a.SourceBlock, a.Assets})
+}
+
+// Total returns the sum of the reserves of all backing assets.
+func (a *ReserveAttestation) Total() *big.Int {
+	total := new(big.Int)
+	for _, asset := range a.Assets {
+		total.Add(total, asset.Amount)
+	}
+	return total
+}
+
+// VerifySignatures checks that every signature is made by its signer and that
+// no signer is listed twice. Whether the signers are auditors is left to the
+// caller.
+func (a *ReserveAttestation) VerifySignatures() error {
+	if len(a.Signers) != len(a.Signatures) {
+		return fmt.Errorf("signers and signatures mismatch: %d != %d", len(a.Signers), len(a.Signatures))
+	}
+	for _, asset := range a.Assets {
+		if asset.Amount == nil || asset.Amount.Sign() < 0 {
+			return errors.New("invalid reserve amount")
+		}
+	}
+	digest := a.Digest()
+	seen := make(map[common.Address]bool, len(a.Signers))
+	for i, signer := range a.Signers {
+		if seen[signer] {
+			return fmt.Errorf("duplicate signer %v", signer)
+		}
+		seen[signer] = true
+
+		pubkey, err := crypto.SigToPub(digest[:], a.Signatures[i])
+		if err != nil {
+			return fmt.Errorf("invalid signature of %v: %v", signer, err)
+		}
+		if crypto.PubkeyToAddress(*pubkey) != signer {
+			return fmt.Errorf("signature not made by %v", signer)
+		}
+	}
+	return nil
+}
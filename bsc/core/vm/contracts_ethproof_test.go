@@ -0,0 +1,194 @@
+package vm_test
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/trienode"
+	"github.com/ethereum/go-ethereum/triedb"
+	"github.com/holiman/uint256"
+)
+
+var (
+	ethReceiptProofAddr = common.BytesToAddress([]byte{0x6c})
+	ethStorageProofAddr = common.BytesToAddress([]byte{0x6d})
+)
+
+// receiptProof and storageProof are the payloads of the ethereum proof precompiles.
+type receiptProof struct {
+	TxIndex  uint64
+	LogIndex uint64
+	Proof    [][]byte
+}
+
+type storageProof struct {
+	Address      common.Address
+	AccountProof [][]byte
+	Key          common.Hash
+	StorageProof [][]byte
+}
+
+// proofNodes returns the trie nodes proving key as raw byte slices.
+func proofNodes(t *testing.T, tr interface {
+	Prove([]byte, ethdb.KeyValueWriter) error
+}, key []byte) [][]byte {
+	var proof trienode.ProofList
+	if err := tr.Prove(key, &proof); err != nil {
+		t.Fatalf("failed to prove key %x: %v", key, err)
+	}
+	nodes := make([][]byte, len(proof))
+	for i, node := range proof {
+		nodes[i] = node
+	}
+	return nodes
+}
+
+func runEthProof(t *testing.T, addr common.Address, root common.Hash, payload interface{}) ([]byte, error) {
+	blob, err := rlp.EncodeToBytes(payload)
+	if err != nil {
+		t.Fatalf("failed to encode payload: %v", err)
+	}
+	contract := vm.PrecompiledContractsMendel[addr]
+	input := append(root.Bytes(), blob...)
+	if gas := contract.RequiredGas(input); gas == 0 {
+		t.Fatalf("%s requires no gas", contract.Name())
+	}
+	return contract.Run(input)
+}
+
+// Tests the ethereum proof precompiles against the receipts and the state of
+// a locally generated chain, in which a vault contract stores a value and
+// emits a log.
+func TestEthProofValidate(t *testing.T) {
+	var (
+		key, _ = crypto.GenerateKey()
+		sender = crypto.PubkeyToAddress(key.PublicKey)
+		vault  = common.HexToAddress("0x5a17")
+		topic  = common.Hash{31: 0x01}
+		config = params.TestChainConfig
+		signer = types.LatestSigner(config)
+	)
+	genesis := &core.Genesis{
+		Config: config,
+		Alloc: types.GenesisAlloc{
+			sender: {Balance: big.NewInt(params.Ether)},
+			vault: {
+				// sstore(0, 42); mstore(0, 0xaa); log1(0, 32, 1)
+				Code:    common.FromHex("0x602a60005560aa600052600160206000a100"),
+				Balance: common.Big0,
+			},
+		},
+	}
+	db, blocks, receipts := core.GenerateChainWithGenesis(genesis, ethash.NewFaker(), 1, func(i int, b *core.BlockGen) {
+		for nonce := uint64(0); nonce < 3; nonce++ {
+			tx := types.MustSignNewTx(key, signer, &types.LegacyTx{Nonce: nonce, To: &vault, Gas: 100000, GasPrice: b.BaseFee()})
+			b.AddTx(tx)
+		}
+	})
+	block := blocks[0]
+
+	// Rebuild the receipt trie to prove the receipts from
+	receiptTrie := trie.NewEmpty(triedb.NewDatabase(db, nil))
+	for i, receipt := range receipts[0] {
+		blob, err := receipt.MarshalBinary()
+		if err != nil {
+			t.Fatalf("failed to encode receipt: %v", err)
+		}
+		receiptTrie.MustUpdate(rlp.AppendUint64(nil, uint64(i)), blob)
+	}
+	if receiptTrie.Hash() != block.ReceiptHash() {
+		t.Fatalf("receipt trie mismatch: have %x, want %x", receiptTrie.Hash(), block.ReceiptHash())
+	}
+	result, err := runEthProof(t, ethReceiptProofAddr, block.ReceiptHash(), &receiptProof{2, 0, proofNodes(t, receiptTrie, rlp.AppendUint64(nil, 2))})
+	if err != nil {
+		t.Fatalf("failed to verify receipt proof: %v", err)
+	}
+	addressT, _ := abi.NewType("address", "", nil)
+	topicsT, _ := abi.NewType("bytes32[]", "", nil)
+	dataT, _ := abi.NewType("bytes", "", nil)
+	values, err := abi.Arguments{{Type: addressT}, {Type: topicsT}, {Type: dataT}}.Unpack(result)
+	if err != nil {
+		t.Fatalf("failed to unpack log: %v", err)
+	}
+	if have := values[0].(common.Address); have != vault {
+		t.Errorf("log address mismatch: have %v, want %v", have, vault)
+	}
+	if have := values[1].([][32]byte); len(have) != 1 || have[0] != topic {
+		t.Errorf("log topics mismatch: have %x, want [%x]", have, topic)
+	}
+	if have, want := values[2].([]byte), common.LeftPadBytes([]byte{0xaa}, 32); !bytes.Equal(have, want) {
+		t.Errorf("log data mismatch: have %x, want %x", have, want)
+	}
+	// Proofs for another key, a missing log or a wrong root must be rejected
+	for _, payload := range []interface{}{
+		&receiptProof{1, 0, proofNodes(t, receiptTrie, rlp.AppendUint64(nil, 2))},
+		&receiptProof{2, 1, proofNodes(t, receiptTrie, rlp.AppendUint64(nil, 2))},
+		&receiptProof{5, 0, proofNodes(t, receiptTrie, rlp.AppendUint64(nil, 5))},
+	} {
+		if _, err := runEthProof(t, ethReceiptProofAddr, block.ReceiptHash(), payload); err == nil {
+			t.Errorf("invalid receipt proof %+v accepted", payload)
+		}
+	}
+
+	// Prove the vault storage from the committed state
+	tdb := triedb.NewDatabase(db, triedb.HashDefaults)
+	stateTrie, err := trie.NewStateTrie(trie.StateTrieID(block.Root()), tdb)
+	if err != nil {
+		t.Fatalf("failed to open state trie: %v", err)
+	}
+	account, err := stateTrie.GetAccount(vault)
+	if err != nil || account == nil {
+		t.Fatalf("failed to read vault account: %v", err)
+	}
+	storageTrie, err := trie.NewStateTrie(trie.StorageTrieID(block.Root(), crypto.Keccak256Hash(vault[:]), account.Root), tdb)
+	if err != nil {
+		t.Fatalf("failed to open storage trie: %v", err)
+	}
+	proveStorage := func(addr common.Address, slot common.Hash) interface{} {
+		return &storageProof{addr, proofNodes(t, stateTrie, crypto.Keccak256(addr[:])), slot, proofNodes(t, storageTrie, crypto.Keccak256(slot[:]))}
+	}
+	result, err = runEthProof(t, ethStorageProofAddr, block.Root(), proveStorage(vault, common.Hash{}))
+	if err != nil {
+		t.Fatalf("failed to verify storage proof: %v", err)
+	}
+	want := make([]byte, 5*32)
+	account.Balance.WriteToSlice(want[32:64])
+	copy(want[64:96], account.Root[:])
+	copy(want[96:128], account.CodeHash)
+	want[159] = 42
+	if !bytes.Equal(result, want) {
+		t.Errorf("storage proof result mismatch:\nhave %x\nwant %x", result, want)
+	}
+	// An unset slot is proven as zero
+	result, err = runEthProof(t, ethStorageProofAddr, block.Root(), proveStorage(vault, common.Hash{31: 1}))
+	if err != nil {
+		t.Fatalf("failed to verify absent slot: %v", err)
+	}
+	if !bytes.Equal(result[128:], make([]byte, 32)) {
+		t.Errorf("absent slot value mismatch: have %x", result[128:])
+	}
+	// The sender account is proven with its nonce and balance
+	result, err = runEthProof(t, ethStorageProofAddr, block.Root(), proveStorage(sender, common.Hash{}))
+	if err != nil {
+		t.Fatalf("failed to verify sender account: %v", err)
+	}
+	if have := new(uint256.Int).SetBytes(result[:32]); have.Uint64() != 3 {
+		t.Errorf("sender nonce mismatch: have %v, want 3", have)
+	}
+	// A proof against another root must be rejected
+	if _, err := runEthProof(t, ethStorageProofAddr, blocks[0].ParentHash(), proveStorage(vault, common.Hash{})); err == nil {
+		t.Error("storage proof against wrong root accepted")
+	}
+}
@@ -12,10 +12,17 @@ import (
 	"github.com/tendermint/tendermint/crypto/secp256k1"
 	cmn "github.com/tendermint/tendermint/libs/common"
 
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm/lightclient/synccommittee"
 	//nolint:staticcheck
 	v1 "github.com/ethereum/go-ethereum/core/vm/lightclient/v1"
 	v2 "github.com/ethereum/go-ethereum/core/vm/lightclient/v2"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/trie/trienode"
 )
 
 const (
@@ -477,3 +484,257 @@ func (c *secp256k1SignatureRecover) runTMSecp256k1Signature(pubkey, signatureStr
 func (c *secp256k1SignatureRecover) Name() string {
 	return "SECP256K1_SIGNATURE_RECOVER"
 }
+
+// ------------------------------------------------------------------------------------------------------------------------------------------------
+
+// decodeSyncCommitteeInput splits the input of the ethereum light client precompiles.
+//
+// input:
+// | consensus state | rlp encoded payload |
+// | 96 bytes        |                     |
+func decodeSyncCommitteeInput(input []byte, payload interface{}) (*synccommittee.ConsensusState, error) {
+	if len(input) <= synccommittee.ConsensusStateLength {
+		return nil, errors.New("invalid input")
+	}
+	cs, err := synccommittee.DecodeConsensusState(input[:synccommittee.ConsensusStateLength])
+	if err != nil {
+		return nil, err
+	}
+	if err := rlp.DecodeBytes(input[synccommittee.ConsensusStateLength:], payload); err != nil {
+		return nil, fmt.Errorf("invalid payload: %w", err)
+	}
+	return cs, nil
+}
+
+// ethSyncCommitteeUpdate implemented as a native contract. Used to advance the
+// ethereum light client to the sync committee of the next period.
+type ethSyncCommitteeUpdate struct{}
+
+func (c *ethSyncCommitteeUpdate) RequiredGas(input []byte) uint64 {
+	return params.EthSyncCommitteeUpdateGas
+}
+
+// output:
+// | consensus state of the next period |
+// | 96 bytes                           |
+func (c *ethSyncCommitteeUpdate) Run(input []byte) (result []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("internal error: %v\n", r)
+		}
+	}()
+
+	var payload synccommittee.UpdateInput
+	cs, err := decodeSyncCommitteeInput(input, &payload)
+	if err != nil {
+		return nil, err
+	}
+	next, err := cs.ApplyUpdate(&payload)
+	if err != nil {
+		return nil, err
+	}
+	return next.Encode(), nil
+}
+
+func (c *ethSyncCommitteeUpdate) Name() string {
+	return "ETH_SYNC_COMMITTEE_UPDATE"
+}
+
+// ethFinalizedHeaderValidate implemented as a native contract. Used to verify
+// that an ethereum execution header has been finalized.
+type ethFinalizedHeaderValidate struct{}
+
+func (c *ethFinalizedHeaderValidate) RequiredGas(input []byte) uint64 {
+	return params.EthFinalizedHeaderValidateGas
+}
+
+// output:
+// | block hash | block number | state root | receipts root | timestamp | beacon slot |
+// | 32 bytes   | 32 bytes     | 32 bytes   | 32 bytes      | 32 bytes  | 32 bytes    |
+func (c *ethFinalizedHeaderValidate) Run(input []byte) (result []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("internal error: %v\n", r)
+		}
+	}()
+
+	var payload synccommittee.FinalityInput
+	cs, err := decodeSyncCommitteeInput(input, &payload)
+	if err != nil {
+		return nil, err
+	}
+	if err := cs.VerifyFinality(&payload); err != nil {
+		return nil, err
+	}
+	return synccommittee.EncodeExecutionResult(payload.ExecutionHeader, payload.FinalizedHeader.Slot), nil
+}
+
+func (c *ethFinalizedHeaderValidate) Name() string {
+	return "ETH_FINALIZED_HEADER_VALIDATE"
+}
+
+// ------------------------------------------------------------------------------------------------------------------------------------------------
+
+// ethReceiptProofInput proves a log of a receipt in an ethereum receipt trie.
+type ethReceiptProofInput struct {
+	TxIndex  uint64   // Index of the transaction in the block, the key in the receipt trie
+	LogIndex uint64   // Index of the log within the receipt
+	Proof    [][]byte // Receipt trie nodes from the root down to the receipt
+}
+
+// ethStorageProofInput proves an account and one of its storage slots in an
+// ethereum state trie, in the format of eth_getProof.
+type ethStorageProofInput struct {
+	Address      common.Address
+	AccountProof [][]byte
+	Key          common.Hash
+	StorageProof [][]byte
+}
+
+// decodeEthProofInput splits the input of the ethereum proof precompiles and
+// returns the trie root the proof is checked against.
+//
+// input:
+// | trie root | rlp encoded payload |
+// | 32 bytes  |                     |
+func decodeEthProofInput(input []byte, payload interface{}) (common.Hash, error) {
+	if len(input) <= common.HashLength {
+		return common.Hash{}, errors.New("invalid input")
+	}
+	if err := rlp.DecodeBytes(input[common.HashLength:], payload); err != nil {
+		return common.Hash{}, fmt.Errorf("invalid payload: %w", err)
+	}
+	return common.BytesToHash(input[:common.HashLength]), nil
+}
+
+// verifyEthProof returns the value stored under key in the trie with the given
+// root, or nil if the proof shows it is absent.
+func verifyEthProof(root common.Hash, key []byte, nodes [][]byte) ([]byte, error) {
+	proof := make(trienode.ProofList, len(nodes))
+	for i, node := range nodes {
+		proof[i] = node
+	}
+	return trie.VerifyProof(root, key, proof.Set())
+}
+
+// ethReceiptProofValidate implemented as a native contract. Used to prove that
+// a log was emitted in an ethereum block with a verified receipts root.
+type ethReceiptProofValidate struct{}
+
+func (c *ethReceiptProofValidate) RequiredGas(input []byte) uint64 {
+	return params.EthReceiptProofValidateBaseGas + uint64(len(input)+31)/32*params.EthProofValidatePerWordGas
+}
+
+// output is the abi encoding of (address, bytes32[], bytes):
+// | log address | topics offset | data offset | topic count | topics        | data length | data            |
+// | 32 bytes    | 32 bytes      | 32 bytes    | 32 bytes    | 32 bytes each | 32 bytes    | padded 32 bytes |
+func (c *ethReceiptProofValidate) Run(input []byte) (result []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("internal error: %v\n", r)
+		}
+	}()
+
+	var payload ethReceiptProofInput
+	root, err := decodeEthProofInput(input, &payload)
+	if err != nil {
+		return nil, err
+	}
+	value, err := verifyEthProof(root, rlp.AppendUint64(nil, payload.TxIndex), payload.Proof)
+	if err != nil {
+		return nil, fmt.Errorf("invalid receipt proof: %w", err)
+	}
+	if value == nil {
+		return nil, errors.New("receipt not found")
+	}
+	var receipt types.Receipt
+	if err := receipt.UnmarshalBinary(value); err != nil {
+		return nil, fmt.Errorf("invalid receipt: %w", err)
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return nil, errors.New("receipt of failed transaction")
+	}
+	if payload.LogIndex >= uint64(len(receipt.Logs)) {
+		return nil, fmt.Errorf("log index %d out of range, receipt has %d logs", payload.LogIndex, len(receipt.Logs))
+	}
+	entry := receipt.Logs[payload.LogIndex]
+
+	topicsOffset := uint64(3 * 32)
+	dataOffset := topicsOffset + uint64(1+len(entry.Topics))*32
+	result = make([]byte, dataOffset+32+uint64(len(entry.Data)+31)/32*32)
+	copy(result[12:32], entry.Address[:])
+	binary.BigEndian.PutUint64(result[56:64], topicsOffset)
+	binary.BigEndian.PutUint64(result[88:96], dataOffset)
+	binary.BigEndian.PutUint64(result[topicsOffset+24:topicsOffset+32], uint64(len(entry.Topics)))
+	for i, topic := range entry.Topics {
+		copy(result[topicsOffset+uint64(i+1)*32:], topic[:])
+	}
+	binary.BigEndian.PutUint64(result[dataOffset+24:dataOffset+32], uint64(len(entry.Data)))
+	copy(result[dataOffset+32:], entry.Data)
+	return result, nil
+}
+
+func (c *ethReceiptProofValidate) Name() string {
+	return "ETH_RECEIPT_PROOF_VALIDATE"
+}
+
+// ethStorageProofValidate implemented as a native contract. Used to prove an
+// account and a storage slot value in an ethereum block with a verified state
+// root. Absent accounts and slots are proven as zero.
+type ethStorageProofValidate struct{}
+
+func (c *ethStorageProofValidate) RequiredGas(input []byte) uint64 {
+	return params.EthStorageProofValidateBaseGas + uint64(len(input)+31)/32*params.EthProofValidatePerWordGas
+}
+
+// output:
+// | nonce    | balance  | storage root | code hash | storage value |
+// | 32 bytes | 32 bytes | 32 bytes     | 32 bytes  | 32 bytes      |
+func (c *ethStorageProofValidate) Run(input []byte) (result []byte, err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("internal error: %v\n", r)
+		}
+	}()
+
+	var payload ethStorageProofInput
+	root, err := decodeEthProofInput(input, &payload)
+	if err != nil {
+		return nil, err
+	}
+	value, err := verifyEthProof(root, crypto.Keccak256(payload.Address[:]), payload.AccountProof)
+	if err != nil {
+		return nil, fmt.Errorf("invalid account proof: %w", err)
+	}
+	result = make([]byte, 5*32)
+	if value == nil {
+		return result, nil
+	}
+	var account types.StateAccount
+	if err := rlp.DecodeBytes(value, &account); err != nil {
+		return nil, fmt.Errorf("invalid account: %w", err)
+	}
+	binary.BigEndian.PutUint64(result[24:32], account.Nonce)
+	account.Balance.WriteToSlice(result[32:64])
+	copy(result[64:96], account.Root[:])
+	copy(result[96:128], account.CodeHash)
+	if account.Root == types.EmptyRootHash {
+		return result, nil
+	}
+	value, err = verifyEthProof(account.Root, crypto.Keccak256(payload.Key[:]), payload.StorageProof)
+	if err != nil {
+		return nil, fmt.Errorf("invalid storage proof: %w", err)
+	}
+	if value != nil {
+		_, content, _, err := rlp.Split(value)
+		if err != nil || len(content) > 32 {
+			return nil, errors.New("invalid storage value")
+		}
+		copy(result[160-len(content):], content)
+	}
+	return result, nil
+}
+
+func (c *ethStorageProofValidate) Name() string {
+	return "ETH_STORAGE_PROOF_VALIDATE"
+}
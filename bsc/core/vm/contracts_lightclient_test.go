@@ -3,6 +3,7 @@ package vm
 import (
 	"encoding/binary"
 	"encoding/hex"
+	"math/big"
 	"testing"
 
 	"github.com/stretchr/testify/assert"
@@ -11,7 +12,12 @@ import (
 	"github.com/tendermint/tendermint/crypto/merkle"
 	cmn "github.com/tendermint/tendermint/libs/common"
 
+	beaconparams "github.com/ethereum/go-ethereum/beacon/params"
+	beacontypes "github.com/ethereum/go-ethereum/beacon/types"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm/lightclient/synccommittee"
 	v1 "github.com/ethereum/go-ethereum/core/vm/lightclient/v1"
+	"github.com/ethereum/go-ethereum/rlp"
 )
 
 const (
@@ -412,3 +418,30 @@ func TestSecp256k1SignatureRecover(t *testing.T) {
 		require.Equal(t, expectedAddr, res)
 	}
 }
+
+func TestEthLightClientInvalidInput(t *testing.T) {
+	cs := (&synccommittee.ConsensusState{
+		GenesisValidatorsRoot: beaconparams.MainnetLightConfig.GenesisValidatorsRoot,
+		Period:                1500,
+	}).Encode()
+
+	for _, contract := range []PrecompiledContract{&ethSyncCommitteeUpdate{}, &ethFinalizedHeaderValidate{}} {
+		// Consensus state without payload
+		_, err := contract.Run(cs)
+		require.Error(t, err, contract.Name())
+
+		// Malformed payload
+		_, err = contract.Run(append(cs, 0xc1, 0x80))
+		require.Error(t, err, contract.Name())
+
+		// Committee not matching the consensus state
+		payload, err := rlp.EncodeToBytes(&synccommittee.UpdateInput{Committee: new(beacontypes.SerializedSyncCommittee)})
+		require.NoError(t, err)
+		if _, ok := contract.(*ethFinalizedHeaderValidate); ok {
+			payload, err = rlp.EncodeToBytes(&synccommittee.FinalityInput{Committee: new(beacontypes.SerializedSyncCommittee), ExecutionHeader: &types.Header{Number: big.NewInt(1)}})
+			require.NoError(t, err)
+		}
+		_, err = contract.Run(append(cs, payload...))
+		require.ErrorContains(t, err, "sync committee does not match", contract.Name())
+	}
+}
@@ -0,0 +1,51 @@
+package vm
+
+import (
+	"errors"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// statefulPrecompiledContract is a precompiled contract reading the state of
+// the calling EVM, which is bound to it before every call.
+type statefulPrecompiledContract interface {
+	PrecompiledContract
+	withState(state StateDB) PrecompiledContract
+}
+
+var errPriceOracleNoState = errors.New("price oracle read without state")
+
+// priceOracle implemented as a native contract. Used to read the latest round
+// of the validator price oracle, kept in the storage of the oracle account.
+type priceOracle struct {
+	state StateDB
+}
+
+func (c *priceOracle) withState(state StateDB) PrecompiledContract {
+	return &priceOracle{state: state}
+}
+
+func (c *priceOracle) RequiredGas(input []byte) uint64 {
+	return params.PriceOracleReadGas
+}
+
+// output:
+// | median price | round    | block number | timestamp |
+// | 32 bytes     | 32 bytes | 32 bytes     | 32 bytes  |
+func (c *priceOracle) Run(input []byte) ([]byte, error) {
+	if c.state == nil {
+		return nil, errPriceOracleNoState
+	}
+	result := make([]byte, 0, 4*32)
+	for _, slot := range []int64{params.PriceOracleMedianSlot, params.PriceOracleRoundSlot, params.PriceOracleNumberSlot, params.PriceOracleTimeSlot} {
+		value := c.state.GetState(params.PriceOracleAddress, common.BigToHash(big.NewInt(slot)))
+		result = append(result, value[:]...)
+	}
+	return result, nil
+}
+
+func (c *priceOracle) Name() string {
+	return "PRICE_ORACLE"
+}
@@ -0,0 +1,45 @@
+package vm
+
+import (
+	"bytes"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// Tests that the price oracle precompile reads the latest round from the
+// state of the calling EVM.
+func TestPriceOracle(t *testing.T) {
+	var (
+		addr       = common.BytesToAddress([]byte{0x6e})
+		statedb, _ = state.New(types.EmptyRootHash, state.NewDatabaseForTesting())
+		price      = new(big.Int).Mul(big.NewInt(2650), big.NewInt(params.Ether))
+	)
+	statedb.SetState(params.PriceOracleAddress, common.BigToHash(big.NewInt(params.PriceOracleMedianSlot)), common.BigToHash(price))
+	statedb.SetState(params.PriceOracleAddress, common.BigToHash(big.NewInt(params.PriceOracleRoundSlot)), common.BigToHash(big.NewInt(7)))
+	statedb.SetState(params.PriceOracleAddress, common.BigToHash(big.NewInt(params.PriceOracleNumberSlot)), common.BigToHash(big.NewInt(1403)))
+	statedb.SetState(params.PriceOracleAddress, common.BigToHash(big.NewInt(params.PriceOracleTimeSlot)), common.BigToHash(big.NewInt(1700000000)))
+
+	evm := NewEVM(BlockContext{BlockNumber: big.NewInt(1404)}, statedb, params.TestChainConfig, Config{})
+	evm.SetPrecompiles(PrecompiledContracts{addr: PrecompiledContractsMendel[addr]})
+
+	ret, _, err := evm.StaticCall(common.Address{}, addr, nil, params.PriceOracleReadGas)
+	if err != nil {
+		t.Fatalf("failed to read price oracle: %v", err)
+	}
+	want := append(append(append(common.BigToHash(price).Bytes(), common.BigToHash(big.NewInt(7)).Bytes()...),
+		common.BigToHash(big.NewInt(1403)).Bytes()...), common.BigToHash(big.NewInt(1700000000)).Bytes()...)
+	if !bytes.Equal(ret, want) {
+		t.Fatalf("price oracle output mismatch:\nhave %x\nwant %x", ret, want)
+	}
+	if _, _, err := evm.StaticCall(common.Address{}, addr, nil, params.PriceOracleReadGas-1); err != ErrOutOfGas {
+		t.Fatalf("unexpected error with insufficient gas: have %v, want %v", err, ErrOutOfGas)
+	}
+	if _, err := PrecompiledContractsMendel[addr].Run(nil); err != errPriceOracleNoState {
+		t.Fatalf("unexpected error without state: have %v, want %v", err, errPriceOracleNoState)
+	}
+}
@@ -39,6 +39,7 @@ var (
 	ErrInvalidCode              = errors.New("invalid code: must not begin with 0xef")
 	ErrNonceUintOverflow        = errors.New("nonce uint64 overflow")
 	ErrInvalidOptimizedCode     = errors.New("cannot use optimized code when optimize config is false")
+	ErrFrozenAccount            = errors.New("value transfer of account frozen by governance")
 
 	// errStopToken is an internal token indicating interpreter loop termination,
 	// never returned to outside callers.
@@ -148,6 +149,7 @@ const (
 	VMErrorCodeStackUnderflow
 	VMErrorCodeStackOverflow
 	VMErrorCodeInvalidOpCode
+	VMErrorCodeFrozenAccount
 
 	// VMErrorCodeUnknown explicitly marks an error as unknown, this is useful when error is converted
 	// from an actual `error` in which case if the mapping is not known, we can use this value to indicate that.
@@ -182,6 +184,8 @@ func vmErrorCodeFromErr(err error) int {
 		return VMErrorCodeInvalidCode
 	case errors.Is(err, ErrNonceUintOverflow):
 		return VMErrorCodeNonceUintOverflow
+	case errors.Is(err, ErrFrozenAccount):
+		return VMErrorCodeFrozenAccount
 
 	default:
 		// Dynamic errors
@@ -37,6 +37,8 @@ type (
 	CanTransferFunc func(StateDB, common.Address, *uint256.Int) bool
 	// TransferFunc is the signature of a transfer function
 	TransferFunc func(StateDB, common.Address, common.Address, *uint256.Int)
+	// IsFrozenFunc is the signature of a freeze list lookup function
+	IsFrozenFunc func(StateDB, common.Address) bool
 	// GetHashFunc returns the n'th block hash in the blockchain
 	// and is used by the BLOCKHASH EVM op code.
 	GetHashFunc func(uint64) common.Hash
@@ -44,9 +46,26 @@ type (
 
 func (evm *EVM) precompile(addr common.Address) (PrecompiledContract, bool) {
 	p, ok := evm.precompiles[addr]
+	if sp, stateful := p.(statefulPrecompiledContract); stateful {
+		return sp.withState(evm.StateDB), true
+	}
 	return p, ok
 }
 
+// frozen reports whether any of the accounts is barred from moving ether by the
+// governance freeze list.
+func (evm *EVM) frozen(addrs ...common.Address) bool {
+	if !evm.chainRules.IsMendel || evm.Context.IsFrozen == nil {
+		return false
+	}
+	for _, addr := range addrs {
+		if evm.Context.IsFrozen(evm.StateDB, addr) {
+			return true
+		}
+	}
+	return false
+}
+
 // BlockContext provides the EVM with auxiliary information. Once provided
 // it shouldn't be modified.
 type BlockContext struct {
@@ -55,6 +74,9 @@ type BlockContext struct {
 	CanTransfer CanTransferFunc
 	// Transfer transfers ether from one account to the other
 	Transfer TransferFunc
+	// IsFrozen returns whether the account is frozen by governance, barring it
+	// from sending and receiving ether from Mendel on. Nothing is frozen if nil
+	IsFrozen IsFrozenFunc
 	// GetHash returns the hash corresponding to n
 	GetHash GetHashFunc
 
@@ -145,6 +167,10 @@ func NewEVM(blockCtx BlockContext, statedb StateDB, chainConfig *params.ChainCon
 		jumpDests:   newMapJumpDests(),
 	}
 	evm.precompiles = activePrecompiledContracts(evm.chainRules)
+	if evm.chainRules.IsMendel && statedb != nil {
+		// Balance changes settle the native holder yield from Mendel on
+		statedb.EnableYield()
+	}
 
 	evm.baseInterpreter = NewEVMInterpreter(evm)
 	evm.interpreter = evm.baseInterpreter
@@ -235,6 +261,10 @@ func (evm *EVM) Call(caller common.Address, addr common.Address, input []byte, g
 	if !value.IsZero() && !evm.Context.CanTransfer(evm.StateDB, caller, value) {
 		return nil, gas, ErrInsufficientBalance
 	}
+	// Fail if either side of the value transfer is frozen
+	if !value.IsZero() && evm.frozen(caller, addr) {
+		return nil, gas, ErrFrozenAccount
+	}
 	snapshot := evm.StateDB.Snapshot()
 	p, isPrecompile := evm.precompile(addr)
 
@@ -573,6 +603,9 @@ func (evm *EVM) create(caller common.Address, code []byte, gas uint64, value *ui
 	if !evm.Context.CanTransfer(evm.StateDB, caller, value) {
 		return nil, common.Address{}, gas, ErrInsufficientBalance
 	}
+	if !value.IsZero() && evm.frozen(caller, address) {
+		return nil, common.Address{}, gas, ErrFrozenAccount
+	}
 	nonce := evm.StateDB.GetNonce(caller)
 	if nonce+1 < nonce {
 		return nil, common.Address{}, gas, ErrNonceUintOverflow
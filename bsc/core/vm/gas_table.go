@@ -317,6 +317,10 @@ func gasCreateEip3860(evm *EVM, contract *Contract, stack *Stack, mem *Memory, m
 	}
 	// Since size <= params.MaxInitCodeSize, these multiplication cannot overflow
 	moreGas := params.InitCodeWordGas * ((size + 31) / 32)
+	if !stack.Back(0).IsZero() && evm.chainRules.IsMendel {
+		// Both the creator and the endowed contract settle their native holder yield
+		moreGas += 2 * params.YieldSettlementGas
+	}
 	if gas, overflow = math.SafeAdd(gas, moreGas); overflow {
 		return 0, ErrGasUintOverflow
 	}
@@ -336,6 +340,10 @@ func gasCreate2Eip3860(evm *EVM, contract *Contract, stack *Stack, mem *Memory,
 	}
 	// Since size <= params.MaxInitCodeSize, these multiplication cannot overflow
 	moreGas := (params.InitCodeWordGas + params.Keccak256WordGas) * ((size + 31) / 32)
+	if !stack.Back(0).IsZero() && evm.chainRules.IsMendel {
+		// Both the creator and the endowed contract settle their native holder yield
+		moreGas += 2 * params.YieldSettlementGas
+	}
 	if gas, overflow = math.SafeAdd(gas, moreGas); overflow {
 		return 0, ErrGasUintOverflow
 	}
@@ -384,6 +392,10 @@ func gasCall(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memorySize
 	if transfersValue && !evm.chainRules.IsEIP4762 {
 		gas += params.CallValueTransferGas
 	}
+	if transfersValue && evm.chainRules.IsMendel {
+		// Both parties of the transfer settle their native holder yield
+		gas += 2 * params.YieldSettlementGas
+	}
 	memoryGas, err := memoryGasCost(mem, memorySize)
 	if err != nil {
 		return 0, err
@@ -416,6 +428,10 @@ func gasCallCode(evm *EVM, contract *Contract, stack *Stack, mem *Memory, memory
 	if stack.Back(2).Sign() != 0 && !evm.chainRules.IsEIP4762 {
 		gas += params.CallValueTransferGas
 	}
+	if stack.Back(2).Sign() != 0 && evm.chainRules.IsMendel {
+		// Both parties of the transfer settle their native holder yield
+		gas += 2 * params.YieldSettlementGas
+	}
 	if gas, overflow = math.SafeAdd(gas, memoryGas); overflow {
 		return 0, ErrGasUintOverflow
 	}
@@ -37,6 +37,14 @@ type StateDB interface {
 	GetBalance(common.Address) *uint256.Int
 	SetBalance(addr common.Address, amount *uint256.Int, reason tracing.BalanceChangeReason)
 
+	// EnableYield makes balance changes settle the native holder yield.
+	EnableYield()
+	// AccrueYield pools amount for the native holders and advances their yield index.
+	AccrueYield(amount *uint256.Int)
+	// SettleYield credits the native yield accrued to the address since its
+	// last settlement. Balance changes settle implicitly.
+	SettleYield(common.Address) *uint256.Int
+
 	GetNonce(common.Address) uint64
 	SetNonce(common.Address, uint64, tracing.NonceChangeReason)
 
@@ -0,0 +1,247 @@
+// Package synccommittee verifies Ethereum beacon chain sync committee updates and
+// finalized execution headers against a light client state kept on chain.
+package synccommittee
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/beacon/merkle"
+	"github.com/ethereum/go-ethereum/beacon/params"
+	"github.com/ethereum/go-ethereum/beacon/types"
+	"github.com/ethereum/go-ethereum/common"
+	ctypes "github.com/ethereum/go-ethereum/core/types"
+)
+
+const (
+	// ConsensusStateLength is the size of the encoded consensus state.
+	ConsensusStateLength = 3 * common.HashLength
+
+	// ExecutionResultLength is the size of the encoded verified execution header.
+	ExecutionResultLength = 6 * common.HashLength
+
+	// Generalized indices of the execution block hash in the beacon block body,
+	// i.e. the block hash field of the execution payload header below the
+	// execution payload of the body.
+	bodyIndexExecBlockHashOld = params.BodyIndexExecPayload<<4 | 12 // 16 payload header fields until capella
+	bodyIndexExecBlockHash    = params.BodyIndexExecPayload<<5 | 12 // 32 payload header fields since deneb
+)
+
+var (
+	errUnknownNetwork    = errors.New("unknown beacon chain network")
+	errCommitteeMismatch = errors.New("sync committee does not match the consensus state")
+	errWrongPeriod       = errors.New("signature is not from the period of the consensus state")
+	errNotFinalized      = errors.New("update has no finalized header")
+	errSlotOrder         = errors.New("headers and signature slot out of order")
+	errLowParticipation  = errors.New("not enough sync committee participants")
+	errInvalidSignature  = errors.New("invalid sync committee signature")
+)
+
+// networks are the beacon chains the light client can follow, keyed by their
+// genesis validators root. Their fork schedule determines the signature domains
+// and the proof indices, so following a new fork takes a new release.
+var networks = map[common.Hash]*params.ChainConfig{
+	params.MainnetLightConfig.GenesisValidatorsRoot: params.MainnetLightConfig,
+	params.SepoliaLightConfig.GenesisValidatorsRoot: params.SepoliaLightConfig,
+	params.HoleskyLightConfig.GenesisValidatorsRoot: params.HoleskyLightConfig,
+	params.HoodiLightConfig.GenesisValidatorsRoot:   params.HoodiLightConfig,
+}
+
+// ConsensusState is the light client state stored by the contract, which is the
+// sync committee of the current period of a beacon chain. Only the committee
+// root is kept, the committee itself is supplied with every verification.
+type ConsensusState struct {
+	GenesisValidatorsRoot common.Hash // Identifies the beacon chain network
+	Period                uint64      // Sync committee period
+	CommitteeRoot         common.Hash // Root of the sync committee of the period
+}
+
+// DecodeConsensusState decodes the consensus state.
+//
+// input:
+// | genesis validators root | period   | committee root |
+// | 32 bytes                | 32 bytes | 32 bytes       |
+func DecodeConsensusState(input []byte) (*ConsensusState, error) {
+	if len(input) != ConsensusStateLength {
+		return nil, fmt.Errorf("invalid consensus state length %d", len(input))
+	}
+	if !isZero(input[32:56]) {
+		return nil, errors.New("sync committee period overflow")
+	}
+	return &ConsensusState{
+		GenesisValidatorsRoot: common.BytesToHash(input[:32]),
+		Period:                binary.BigEndian.Uint64(input[56:64]),
+		CommitteeRoot:         common.BytesToHash(input[64:96]),
+	}, nil
+}
+
+// Encode encodes the consensus state in the layout of DecodeConsensusState.
+func (cs *ConsensusState) Encode() []byte {
+	output := make([]byte, ConsensusStateLength)
+	copy(output[:32], cs.GenesisValidatorsRoot[:])
+	binary.BigEndian.PutUint64(output[56:64], cs.Period)
+	copy(output[64:96], cs.CommitteeRoot[:])
+	return output
+}
+
+// UpdateInput advances the consensus state to the next period. The update
+// must be finalized and signed by a supermajority of the current committee.
+type UpdateInput struct {
+	Committee *types.SerializedSyncCommittee // Sync committee of the current period
+	Update    types.LightClientUpdate        // Version is derived from the network config
+}
+
+// FinalityInput proves a finalized execution header of the current period.
+type FinalityInput struct {
+	Committee       *types.SerializedSyncCommittee // Sync committee of the current period
+	AttestedHeader  types.SignedHeader             // Header signed by the committee
+	FinalizedHeader types.Header                   // Header finalized by the attested one
+	FinalityBranch  merkle.Values                  // Proof of the finalized header in the attested state
+	ExecutionHeader *ctypes.Header                 // Execution header of the finalized block
+	ExecutionBranch merkle.Values                  // Proof of the execution block hash in the finalized body
+}
+
+// ApplyUpdate verifies the sync committee update against the consensus state
+// and returns the state of the next period.
+func (cs *ConsensusState) ApplyUpdate(input *UpdateInput) (*ConsensusState, error) {
+	config, err := cs.verifyCommittee(input.Committee)
+	if err != nil {
+		return nil, err
+	}
+	update := &input.Update
+	if update.FinalizedHeader == nil {
+		return nil, errNotFinalized
+	}
+	signed := update.AttestedHeader
+	if signed.SignatureSlot <= signed.Header.Slot || update.FinalizedHeader.Slot > signed.Header.Slot {
+		return nil, errSlotOrder
+	}
+	if types.SyncPeriod(signed.SignatureSlot) != cs.Period {
+		return nil, errWrongPeriod
+	}
+	update.Version = forkName(config, signed.Header.Epoch())
+	if err := update.Validate(); err != nil {
+		return nil, err
+	}
+	if err := verifySignature(config, input.Committee, &signed); err != nil {
+		return nil, err
+	}
+	return &ConsensusState{
+		GenesisValidatorsRoot: cs.GenesisValidatorsRoot,
+		Period:                cs.Period + 1,
+		CommitteeRoot:         update.NextSyncCommitteeRoot,
+	}, nil
+}
+
+// VerifyFinality verifies that the execution header belongs to a beacon block
+// finalized according to the current sync committee.
+func (cs *ConsensusState) VerifyFinality(input *FinalityInput) error {
+	config, err := cs.verifyCommittee(input.Committee)
+	if err != nil {
+		return err
+	}
+	signed := input.AttestedHeader
+	if signed.SignatureSlot <= signed.Header.Slot || input.FinalizedHeader.Slot > signed.Header.Slot {
+		return errSlotOrder
+	}
+	if types.SyncPeriod(signed.SignatureSlot) != cs.Period {
+		return errWrongPeriod
+	}
+	if input.ExecutionHeader == nil || input.ExecutionHeader.Number == nil {
+		return errors.New("missing execution header")
+	}
+	version := forkName(config, signed.Header.Epoch())
+	if err := merkle.VerifyProof(signed.Header.StateRoot, params.StateIndexFinalBlock(version), input.FinalityBranch, merkle.Value(input.FinalizedHeader.Hash())); err != nil {
+		return fmt.Errorf("invalid finalized header proof: %w", err)
+	}
+	index, err := execBlockHashIndex(forkName(config, input.FinalizedHeader.Epoch()))
+	if err != nil {
+		return err
+	}
+	if err := merkle.VerifyProof(input.FinalizedHeader.BodyRoot, index, input.ExecutionBranch, merkle.Value(input.ExecutionHeader.Hash())); err != nil {
+		return fmt.Errorf("invalid execution header proof: %w", err)
+	}
+	return verifySignature(config, input.Committee, &signed)
+}
+
+// EncodeExecutionResult encodes the fields of a verified execution header the
+// bridge contracts rely on.
+//
+// output:
+// | block hash | block number | state root | receipts root | timestamp | beacon slot |
+// | 32 bytes   | 32 bytes     | 32 bytes   | 32 bytes      | 32 bytes  | 32 bytes    |
+func EncodeExecutionResult(header *ctypes.Header, slot uint64) []byte {
+	output := make([]byte, ExecutionResultLength)
+	hash := header.Hash()
+	copy(output[:32], hash[:])
+	header.Number.FillBytes(output[32:64])
+	copy(output[64:96], header.Root[:])
+	copy(output[96:128], header.ReceiptHash[:])
+	binary.BigEndian.PutUint64(output[152:160], header.Time)
+	binary.BigEndian.PutUint64(output[184:192], slot)
+	return output
+}
+
+// verifyCommittee checks the supplied committee against the consensus state
+// and returns the config of its network.
+func (cs *ConsensusState) verifyCommittee(committee *types.SerializedSyncCommittee) (*params.ChainConfig, error) {
+	config, ok := networks[cs.GenesisValidatorsRoot]
+	if !ok {
+		return nil, errUnknownNetwork
+	}
+	if committee == nil || committee.Root() != cs.CommitteeRoot {
+		return nil, errCommitteeMismatch
+	}
+	return config, nil
+}
+
+// verifySignature checks that a supermajority of the committee signed the header.
+func verifySignature(config *params.ChainConfig, committee *types.SerializedSyncCommittee, signed *types.SignedHeader) error {
+	if signed.Signature.SignerCount() < params.SyncCommitteeSupermajority {
+		return errLowParticipation
+	}
+	sc, err := committee.Deserialize()
+	if err != nil {
+		return fmt.Errorf("invalid sync committee: %w", err)
+	}
+	// The fork version is the one of the slot before the signature slot
+	epoch := (max(signed.SignatureSlot, 1) - 1) / params.EpochLength
+	root, err := config.Forks.SigningRoot(epoch, signed.Header.Hash())
+	if err != nil {
+		return err
+	}
+	if !sc.VerifySignature(root, &signed.Signature) {
+		return errInvalidSignature
+	}
+	return nil
+}
+
+// forkName returns the name of the fork active at epoch in the format used by
+// the beacon proof indices.
+func forkName(config *params.ChainConfig, epoch uint64) string {
+	return strings.ToLower(config.ForkAtEpoch(epoch).Name)
+}
+
+// execBlockHashIndex returns the generalized index of the execution block hash
+// in the beacon block body of the given fork.
+func execBlockHashIndex(fork string) (uint64, error) {
+	switch fork {
+	case "bellatrix", "capella":
+		return bodyIndexExecBlockHashOld, nil
+	case "deneb", "electra", "fulu":
+		return bodyIndexExecBlockHash, nil
+	default:
+		return 0, fmt.Errorf("no execution payload in %q fork", fork)
+	}
+}
+
+func isZero(b []byte) bool {
+	for _, v := range b {
+		if v != 0 {
+			return false
+		}
+	}
+	return true
+}
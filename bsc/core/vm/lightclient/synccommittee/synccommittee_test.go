@@ -0,0 +1,288 @@
+package synccommittee
+
+import (
+	"crypto/sha256"
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/beacon/merkle"
+	"github.com/ethereum/go-ethereum/beacon/params"
+	"github.com/ethereum/go-ethereum/beacon/types"
+	"github.com/ethereum/go-ethereum/common"
+	ctypes "github.com/ethereum/go-ethereum/core/types"
+	bls "github.com/protolambda/bls12-381-util"
+)
+
+// testPeriod is a sync committee period of the mainnet electra fork.
+const testPeriod = 1500
+
+// testCommittee is a sync committee made of a few keys repeated across all seats.
+type testCommittee struct {
+	keys       []*bls.SecretKey
+	serialized *types.SerializedSyncCommittee
+}
+
+func newTestCommittee(t *testing.T, seed byte) *testCommittee {
+	c := &testCommittee{serialized: new(types.SerializedSyncCommittee)}
+	pubkeys := make([]*bls.Pubkey, params.SyncCommitteeSize)
+	for i := 0; i < 4; i++ {
+		key := new(bls.SecretKey)
+		if err := key.Deserialize(&[32]byte{30: seed, 31: byte(i + 1)}); err != nil {
+			t.Fatalf("failed to create key: %v", err)
+		}
+		c.keys = append(c.keys, key)
+	}
+	for i := range pubkeys {
+		pub, err := bls.SkToPk(c.keys[i%len(c.keys)])
+		if err != nil {
+			t.Fatalf("failed to derive pubkey: %v", err)
+		}
+		pubkeys[i] = pub
+		blob := pub.Serialize()
+		copy(c.serialized[i*params.BLSPubkeySize:], blob[:])
+	}
+	aggregate, err := bls.AggregatePubkeys(pubkeys)
+	if err != nil {
+		t.Fatalf("failed to aggregate pubkeys: %v", err)
+	}
+	blob := aggregate.Serialize()
+	copy(c.serialized[params.SyncCommitteeSize*params.BLSPubkeySize:], blob[:])
+	return c
+}
+
+// sign returns the aggregate signature of the given number of seats.
+func (c *testCommittee) sign(t *testing.T, signers int, signatureSlot uint64, header types.Header) types.SyncAggregate {
+	var (
+		sigs      []*bls.Signature
+		aggregate types.SyncAggregate
+	)
+	root, err := params.MainnetLightConfig.Forks.SigningRoot((signatureSlot-1)/params.EpochLength, header.Hash())
+	if err != nil {
+		t.Fatalf("failed to compute signing root: %v", err)
+	}
+	for i := 0; i < signers; i++ {
+		sigs = append(sigs, bls.Sign(c.keys[i%len(c.keys)], root[:]))
+		aggregate.Signers[i/8] |= 1 << (i % 8)
+	}
+	sig, err := bls.Aggregate(sigs)
+	if err != nil {
+		t.Fatalf("failed to aggregate signatures: %v", err)
+	}
+	aggregate.Signature = sig.Serialize()
+	return aggregate
+}
+
+// testTree is a sparse binary merkle tree with zero values where unset.
+type testTree map[uint64]merkle.Value
+
+func (tree testTree) node(index uint64) merkle.Value {
+	if value, ok := tree[index]; ok {
+		return value
+	}
+	for leaf := range tree {
+		for leaf > index {
+			leaf >>= 1
+		}
+		if leaf == index {
+			left, right := tree.node(index*2), tree.node(index*2+1)
+			return merkle.Value(sha256.Sum256(append(left[:], right[:]...)))
+		}
+	}
+	return merkle.Value{}
+}
+
+func (tree testTree) root() common.Hash {
+	return common.Hash(tree.node(1))
+}
+
+func (tree testTree) proof(index uint64) merkle.Values {
+	var branch merkle.Values
+	for ; index > 1; index >>= 1 {
+		branch = append(branch, tree.node(index^1))
+	}
+	return branch
+}
+
+// testChain is a finalized beacon block with an execution payload, attested
+// in a later header.
+type testChain struct {
+	exec      *ctypes.Header
+	body      testTree
+	finalized types.Header
+	state     testTree
+	attested  types.Header
+}
+
+func newTestChain(nextCommittee common.Hash) *testChain {
+	c := &testChain{
+		exec: &ctypes.Header{
+			Number:      big.NewInt(22_000_000),
+			Root:        common.Hash{0x01},
+			ReceiptHash: common.Hash{0x02},
+			Difficulty:  new(big.Int),
+			Time:        1_750_000_000,
+			BaseFee:     big.NewInt(7),
+		},
+	}
+	slot := uint64(testPeriod*params.SyncPeriodLength + 100)
+	c.body = testTree{bodyIndexExecBlockHash: merkle.Value(c.exec.Hash())}
+	c.finalized = types.Header{Slot: slot - 64, ProposerIndex: 1, BodyRoot: c.body.root()}
+	c.state = testTree{
+		params.StateIndexFinalBlockElectra:        merkle.Value(c.finalized.Hash()),
+		params.StateIndexNextSyncCommitteeElectra: merkle.Value(nextCommittee),
+	}
+	c.attested = types.Header{Slot: slot, ProposerIndex: 2, StateRoot: c.state.root()}
+	return c
+}
+
+func (c *testChain) update(t *testing.T, committee *testCommittee, signers int) *UpdateInput {
+	finalized := c.finalized
+	return &UpdateInput{
+		Committee: committee.serialized,
+		Update: types.LightClientUpdate{
+			AttestedHeader: types.SignedHeader{
+				Header:        c.attested,
+				Signature:     committee.sign(t, signers, c.attested.Slot+1, c.attested),
+				SignatureSlot: c.attested.Slot + 1,
+			},
+			NextSyncCommitteeRoot:   common.Hash(c.state[params.StateIndexNextSyncCommitteeElectra]),
+			NextSyncCommitteeBranch: c.state.proof(params.StateIndexNextSyncCommitteeElectra),
+			FinalizedHeader:         &finalized,
+			FinalityBranch:          c.state.proof(params.StateIndexFinalBlockElectra),
+		},
+	}
+}
+
+func (c *testChain) finality(t *testing.T, committee *testCommittee, signers int) *FinalityInput {
+	return &FinalityInput{
+		Committee: committee.serialized,
+		AttestedHeader: types.SignedHeader{
+			Header:        c.attested,
+			Signature:     committee.sign(t, signers, c.attested.Slot+1, c.attested),
+			SignatureSlot: c.attested.Slot + 1,
+		},
+		FinalizedHeader: c.finalized,
+		FinalityBranch:  c.state.proof(params.StateIndexFinalBlockElectra),
+		ExecutionHeader: c.exec,
+		ExecutionBranch: c.body.proof(bodyIndexExecBlockHash),
+	}
+}
+
+func TestConsensusStateEncoding(t *testing.T) {
+	cs := &ConsensusState{
+		GenesisValidatorsRoot: params.MainnetLightConfig.GenesisValidatorsRoot,
+		Period:                testPeriod,
+		CommitteeRoot:         common.Hash{0xaa},
+	}
+	dec, err := DecodeConsensusState(cs.Encode())
+	if err != nil {
+		t.Fatalf("failed to decode consensus state: %v", err)
+	}
+	if *dec != *cs {
+		t.Fatalf("consensus state mismatch: have %+v, want %+v", dec, cs)
+	}
+	overflow := cs.Encode()
+	overflow[40] = 1
+	if _, err := DecodeConsensusState(overflow); err == nil {
+		t.Fatal("decoded overflowing period")
+	}
+	if _, err := DecodeConsensusState(overflow[1:]); err == nil {
+		t.Fatal("decoded short consensus state")
+	}
+}
+
+func TestApplyUpdate(t *testing.T) {
+	var (
+		current = newTestCommittee(t, 1)
+		next    = newTestCommittee(t, 2)
+		chain   = newTestChain(next.serialized.Root())
+		cs      = &ConsensusState{
+			GenesisValidatorsRoot: params.MainnetLightConfig.GenesisValidatorsRoot,
+			Period:                testPeriod,
+			CommitteeRoot:         current.serialized.Root(),
+		}
+	)
+	updated, err := cs.ApplyUpdate(chain.update(t, current, params.SyncCommitteeSize))
+	if err != nil {
+		t.Fatalf("failed to apply update: %v", err)
+	}
+	if updated.Period != testPeriod+1 || updated.CommitteeRoot != next.serialized.Root() || updated.GenesisValidatorsRoot != cs.GenesisValidatorsRoot {
+		t.Fatalf("updated state mismatch: %+v", updated)
+	}
+
+	tests := []struct {
+		name   string
+		state  ConsensusState
+		modify func(*UpdateInput)
+	}{
+		{"unknown network", ConsensusState{GenesisValidatorsRoot: common.Hash{0x01}, Period: testPeriod, CommitteeRoot: cs.CommitteeRoot}, func(*UpdateInput) {}},
+		{"wrong committee", *cs, func(in *UpdateInput) { in.Committee = next.serialized }},
+		{"wrong period", ConsensusState{GenesisValidatorsRoot: cs.GenesisValidatorsRoot, Period: testPeriod - 1, CommitteeRoot: cs.CommitteeRoot}, func(*UpdateInput) {}},
+		{"not finalized", *cs, func(in *UpdateInput) { in.Update.FinalizedHeader = nil }},
+		{"signature before header", *cs, func(in *UpdateInput) { in.Update.AttestedHeader.SignatureSlot = chain.attested.Slot }},
+		{"wrong next committee", *cs, func(in *UpdateInput) { in.Update.NextSyncCommitteeRoot = current.serialized.Root() }},
+		{"wrong finality proof", *cs, func(in *UpdateInput) { in.Update.FinalityBranch[0][0] ^= 1 }},
+		{"low participation", *cs, func(in *UpdateInput) { in.Update.AttestedHeader.Signature.Signers[0] = 0 }},
+		{"invalid signature", *cs, func(in *UpdateInput) {
+			in.Update.AttestedHeader.Signature = next.sign(t, params.SyncCommitteeSize, chain.attested.Slot+1, chain.attested)
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := chain.update(t, current, params.SyncCommitteeSize)
+			tt.modify(input)
+			if _, err := tt.state.ApplyUpdate(input); err == nil {
+				t.Fatal("invalid update applied")
+			}
+		})
+	}
+}
+
+func TestVerifyFinality(t *testing.T) {
+	var (
+		current = newTestCommittee(t, 1)
+		chain   = newTestChain(common.Hash{})
+		cs      = &ConsensusState{
+			GenesisValidatorsRoot: params.MainnetLightConfig.GenesisValidatorsRoot,
+			Period:                testPeriod,
+			CommitteeRoot:         current.serialized.Root(),
+		}
+	)
+	if err := cs.VerifyFinality(chain.finality(t, current, params.SyncCommitteeSupermajority)); err != nil {
+		t.Fatalf("failed to verify finality: %v", err)
+	}
+	result := EncodeExecutionResult(chain.exec, chain.finalized.Slot)
+	if have, want := common.BytesToHash(result[:32]), chain.exec.Hash(); have != want {
+		t.Errorf("block hash mismatch: have %x, want %x", have, want)
+	}
+	if have := new(big.Int).SetBytes(result[32:64]); have.Cmp(chain.exec.Number) != 0 {
+		t.Errorf("block number mismatch: have %v, want %v", have, chain.exec.Number)
+	}
+	if have := new(big.Int).SetBytes(result[160:192]); have.Uint64() != chain.finalized.Slot {
+		t.Errorf("beacon slot mismatch: have %v, want %v", have, chain.finalized.Slot)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*FinalityInput)
+	}{
+		{"unfinalized header", func(in *FinalityInput) { in.FinalizedHeader.Slot = chain.attested.Slot + 1 }},
+		{"wrong finality proof", func(in *FinalityInput) { in.FinalityBranch = in.FinalityBranch[1:] }},
+		{"wrong execution header", func(in *FinalityInput) {
+			exec := ctypes.CopyHeader(in.ExecutionHeader)
+			exec.Number = big.NewInt(1)
+			in.ExecutionHeader = exec
+		}},
+		{"wrong execution proof", func(in *FinalityInput) { in.ExecutionBranch[2][5] ^= 1 }},
+		{"low participation", func(in *FinalityInput) { in.AttestedHeader.Signature.Signers[0] = 0 }},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := chain.finality(t, current, params.SyncCommitteeSupermajority)
+			tt.modify(input)
+			if err := cs.VerifyFinality(input); err == nil {
+				t.Fatal("invalid finality verified")
+			}
+		})
+	}
+}
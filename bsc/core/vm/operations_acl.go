@@ -235,6 +235,10 @@ func makeSelfdestructGasFn(refundsEnabled bool) gasFunc {
 		if evm.StateDB.Empty(address) && evm.StateDB.GetBalance(contract.Address()).Sign() != 0 {
 			gas += params.CreateBySelfdestructGas
 		}
+		// both the contract and the beneficiary settle their native holder yield
+		if evm.chainRules.IsMendel && evm.StateDB.GetBalance(contract.Address()).Sign() != 0 {
+			gas += 2 * params.YieldSettlementGas
+		}
 		if refundsEnabled && !evm.StateDB.HasSelfDestructed(contract.Address()) {
 			evm.StateDB.AddRefund(params.SelfdestructRefundGas)
 		}
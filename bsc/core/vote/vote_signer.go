@@ -2,6 +2,7 @@ package vote
 
 import (
 	"context"
+	"math/big"
 	"os"
 	"time"
 
@@ -103,3 +104,43 @@ func (signer *VoteSigner) SignVote(vote *types.VoteEnvelope) error {
 	copy(vote.Signature[:], signature.Marshal()[:])
 	return nil
 }
+
+// SignBridgeAttestation signs the domain separated digest of the attestation
+// with the validator's bls key, which is also set as its vote address.
+func (signer *VoteSigner) SignBridgeAttestation(att *types.BridgeAttestation) error {
+	pubKey := signer.PubKey
+	ctx, cancel := context.WithTimeout(context.Background(), voteSignerTimeout)
+	defer cancel()
+
+	signingHash := att.SigningHash()
+	signature, err := (*signer.km).Sign(ctx, &validatorpb.SignRequest{
+		PublicKey:   pubKey[:],
+		SigningRoot: signingHash[:],
+	})
+	if err != nil {
+		return err
+	}
+	copy(att.VoteAddress[:], pubKey[:])
+	copy(att.Signature[:], signature.Marshal()[:])
+	return nil
+}
+
+// SignPriceReport signs the round and price of the report on the given chain
+// with the validator's bls key, which is also set as its vote address.
+func (signer *VoteSigner) SignPriceReport(report *types.PriceReport, chainID *big.Int) error {
+	pubKey := signer.PubKey
+	ctx, cancel := context.WithTimeout(context.Background(), voteSignerTimeout)
+	defer cancel()
+
+	hash := report.SigningHash(chainID)
+	signature, err := (*signer.km).Sign(ctx, &validatorpb.SignRequest{
+		PublicKey:   pubKey[:],
+		SigningRoot: hash[:],
+	})
+	if err != nil {
+		return err
+	}
+	copy(report.VoteAddress[:], pubKey[:])
+	copy(report.Signature[:], signature.Marshal()[:])
+	return nil
+}
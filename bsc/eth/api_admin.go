@@ -141,3 +141,19 @@ func (api *AdminAPI) ImportChain(file string) (bool, error) {
 	}
 	return true, nil
 }
+
+// StartPrune starts pruning the state in the background while the node keeps
+// running. With the hash scheme the stale trie nodes are deleted, with the path
+// scheme the trie node ranges are compacted. Progress can be followed through
+// PruneStatus.
+func (api *AdminAPI) StartPrune() (bool, error) {
+	if err := api.eth.BlockChain().StartStatePrune(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// PruneStatus returns the status of the online state pruning.
+func (api *AdminAPI) PruneStatus() (*core.StatePruneStatus, error) {
+	return api.eth.BlockChain().StatePruneStatus()
+}
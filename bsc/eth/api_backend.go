@@ -19,6 +19,7 @@ package eth
 import (
 	"context"
 	"errors"
+	"fmt"
 	"math/big"
 	"time"
 
@@ -241,7 +242,7 @@ func (b *EthAPIBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.B
 	}
 	stateDb, err := b.eth.BlockChain().StateAt(header.Root)
 	if err != nil {
-		stateDb, err = b.eth.BlockChain().HistoricState(header.Root)
+		stateDb, err = b.historicState(header)
 		if err != nil {
 			return nil, nil, err
 		}
@@ -249,6 +250,26 @@ func (b *EthAPIBackend) StateAndHeaderByNumber(ctx context.Context, number rpc.B
 	return stateDb, header, nil
 }
 
+// historicState returns the historical state of the given block reconstructed
+// from the state histories, reporting the available window if the block is
+// beyond it.
+func (b *EthAPIBackend) historicState(header *types.Header) (*state.StateDB, error) {
+	stateDb, err := b.eth.BlockChain().HistoricState(header.Root)
+	if err == nil {
+		return stateDb, nil
+	}
+	first, _, rerr := b.eth.BlockChain().StateHistoryRange()
+	if rerr != nil {
+		return nil, err
+	}
+	// The state of the block before the first state history is the earliest
+	// one which can be reconstructed.
+	if number := header.Number.Uint64(); number+1 < first {
+		return nil, fmt.Errorf("historical state of block #%d is not available, earliest available block is #%d", number, first-1)
+	}
+	return nil, err
+}
+
 func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*state.StateDB, *types.Header, error) {
 	if blockNr, ok := blockNrOrHash.Number(); ok {
 		return b.StateAndHeaderByNumber(ctx, blockNr)
@@ -266,7 +287,7 @@ func (b *EthAPIBackend) StateAndHeaderByNumberOrHash(ctx context.Context, blockN
 		}
 		stateDb, err := b.eth.BlockChain().StateAt(header.Root)
 		if err != nil {
-			stateDb, err = b.eth.BlockChain().HistoricState(header.Root)
+			stateDb, err = b.historicState(header)
 			if err != nil {
 				return nil, nil, err
 			}
@@ -384,6 +405,17 @@ func (b *EthAPIBackend) GetPoolTransaction(hash common.Hash) *types.Transaction
 // not being finished. The caller must explicitly check the indexer progress.
 //
 // Notably, only the transaction in the canonical chain is visible.
+func (b *EthAPIBackend) SendPrivateTx(ctx context.Context, signedTx *types.Transaction, maxBlockNumber uint64) error {
+	return b.eth.handler.privateTxs.add(signedTx, maxBlockNumber)
+}
+
+func (b *EthAPIBackend) SendBundle(ctx context.Context, bundle *types.Bundle) error {
+	if b.eth.bundlePool == nil {
+		return errors.New("bundles are disabled, see --txpool.bundles")
+	}
+	return b.eth.bundlePool.Add(bundle)
+}
+
 func (b *EthAPIBackend) GetCanonicalTransaction(txHash common.Hash) (bool, *types.Transaction, common.Hash, uint64, uint64) {
 	lookup, tx := b.eth.blockchain.GetCanonicalTransaction(txHash)
 	if lookup == nil || tx == nil {
@@ -428,6 +460,39 @@ func (b *EthAPIBackend) SubscribeNewVoteEvent(ch chan<- core.NewVoteEvent) event
 	return b.eth.VotePool().SubscribeNewVoteEvent(ch)
 }
 
+// GetVoteAttestation returns the vote attestation carried in the given header.
+func (b *EthAPIBackend) GetVoteAttestation(header *types.Header) (*types.VoteAttestation, error) {
+	posa, ok := b.eth.engine.(consensus.PoSA)
+	if !ok {
+		return nil, errors.New("vote attestations require a PoSA engine")
+	}
+	return posa.GetVoteAttestation(b.eth.blockchain, header)
+}
+
+// GetFinalityAttestation returns the vote attestation which finalized the given header.
+func (b *EthAPIBackend) GetFinalityAttestation(header *types.Header) (*types.VoteAttestation, error) {
+	posa, ok := b.eth.engine.(consensus.PoSA)
+	if !ok {
+		return nil, errors.New("vote attestations require a PoSA engine")
+	}
+	return posa.GetFinalityAttestation(b.eth.blockchain, header)
+}
+
+// GetNativeTransfers returns the native transfers recorded for the given block.
+func (b *EthAPIBackend) GetNativeTransfers(ctx context.Context, hash common.Hash, number uint64) ([]*types.NativeTransfer, error) {
+	transfers, ok := b.eth.blockchain.GetNativeTransfers(hash, number)
+	if !ok {
+		return nil, fmt.Errorf("native transfers not recorded for block #%d", number)
+	}
+	return transfers, nil
+}
+
+// GetTransactionsByAddress returns a page of the transactions involving the
+// given address from the address index.
+func (b *EthAPIBackend) GetTransactionsByAddress(ctx context.Context, address common.Address, token *common.Address, start []byte, limit int) ([]rawdb.AddressTx, []byte, error) {
+	return b.eth.blockchain.AddressTransactions(address, token, start, limit)
+}
+
 func (b *EthAPIBackend) Downloader() *downloader.Downloader {
 	return b.eth.Downloader()
 }
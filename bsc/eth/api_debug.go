@@ -496,42 +496,22 @@ func (api *DebugAPI) StateSize(blockHashOrNumber *rpc.BlockNumberOrHash) (interf
 	}, nil
 }
 
+// ExecutionWitness re-executes the block with the given number and returns the
+// stateless witness of its execution, as served to peers over the bsc protocol.
 func (api *DebugAPI) ExecutionWitness(bn rpc.BlockNumber) (*stateless.ExtWitness, error) {
-	bc := api.eth.blockchain
 	block, err := api.eth.APIBackend.BlockByNumber(context.Background(), bn)
-	if err != nil {
+	if err != nil || block == nil {
 		return &stateless.ExtWitness{}, fmt.Errorf("block number %v not found", bn)
 	}
-
-	parent := bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
-	if parent == nil {
-		return &stateless.ExtWitness{}, fmt.Errorf("block number %v found, but parent missing", bn)
-	}
-
-	result, err := bc.ProcessBlock(parent.Root, block, false, true)
-	if err != nil {
-		return nil, err
-	}
-
-	return result.Witness().ToExtWitness(), nil
+	return api.ExecutionWitnessByHash(block.Hash())
 }
 
+// ExecutionWitnessByHash re-executes the block with the given hash and returns
+// the stateless witness of its execution.
 func (api *DebugAPI) ExecutionWitnessByHash(hash common.Hash) (*stateless.ExtWitness, error) {
-	bc := api.eth.blockchain
-	block := bc.GetBlockByHash(hash)
-	if block == nil {
-		return &stateless.ExtWitness{}, fmt.Errorf("block hash %x not found", hash)
-	}
-
-	parent := bc.GetHeader(block.ParentHash(), block.NumberU64()-1)
-	if parent == nil {
-		return &stateless.ExtWitness{}, fmt.Errorf("block number %x found, but parent missing", hash)
-	}
-
-	result, err := bc.ProcessBlock(parent.Root, block, false, true)
+	witness, err := api.eth.blockchain.GenerateWitness(hash)
 	if err != nil {
-		return nil, err
+		return &stateless.ExtWitness{}, err
 	}
-
-	return result.Witness().ToExtWitness(), nil
+	return witness.ToExtWitness(), nil
 }
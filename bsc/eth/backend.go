@@ -34,16 +34,20 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 
 	"github.com/ethereum/go-ethereum/accounts"
+	"github.com/ethereum/go-ethereum/bridge"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/consensus/parlia"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/filtermaps"
+	"github.com/ethereum/go-ethereum/core/history"
 	"github.com/ethereum/go-ethereum/core/monitor"
+	"github.com/ethereum/go-ethereum/core/oracle"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state/pruner"
 	"github.com/ethereum/go-ethereum/core/txpool"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/core/txpool/locals"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -123,6 +127,7 @@ type Ethereum struct {
 	config         *ethconfig.Config
 	txPool         *txpool.TxPool
 	blobTxPool     *blobpool.BlobPool
+	bundlePool     *bundlepool.BundlePool
 	localTxTracker *locals.TxTracker
 	blockchain     *core.BlockChain
 
@@ -157,6 +162,9 @@ type Ethereum struct {
 
 	votePool *vote.VotePool
 	stopCh   chan struct{}
+
+	pricePool     *oracle.Pool
+	priceReporter *oracle.Reporter
 }
 
 // New creates a new Ethereum object (including the initialisation of the common Ethereum object),
@@ -172,12 +180,31 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	if !config.HistoryMode.IsValid() {
 		return nil, fmt.Errorf("invalid history mode %d", config.HistoryMode)
 	}
+	if config.HistoryMode == history.KeepRecent && config.BlockHistory != 0 {
+		return nil, errors.New("block history cannot be combined with the recent history mode")
+	}
 	if config.Miner.GasPrice == nil || config.Miner.GasPrice.Sign() <= 0 {
 		log.Warn("Sanitizing invalid miner gas price", "provided", config.Miner.GasPrice, "updated", ethconfig.Defaults.Miner.GasPrice)
 		config.Miner.GasPrice = new(big.Int).Set(ethconfig.Defaults.Miner.GasPrice)
 	}
 
-	chainDb, err := stack.OpenAndMergeDatabase(ChainData, ChainDBNamespace, false, config)
+	var (
+		chainDb ethdb.Database
+		replica *rawdb.ReplicaDatabase
+		err     error
+	)
+	if config.Replica != "" {
+		if config.ReplicaSource == "" {
+			return nil, errors.New("read replica requires the endpoint of the writer node")
+		}
+		if config.Miner.VoteEnable {
+			return nil, errors.New("read replica can't produce votes")
+		}
+		replica, err = openReplicaDatabase(stack, config)
+		chainDb = replica
+	} else {
+		chainDb, err = stack.OpenAndMergeDatabase(ChainData, ChainDBNamespace, false, config)
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -276,10 +303,14 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 
 	// startup ancient freeze
 	freezeDb := chainDb
-	if err = freezeDb.SetupFreezerEnv(&ethdb.FreezerEnv{
+	freezerEnv := &ethdb.FreezerEnv{
 		ChainCfg:         chainConfig,
 		BlobExtraReserve: config.BlobExtraReserve,
-	}, config.BlockHistory); err != nil {
+	}
+	if config.HistoryMode == history.KeepRecent {
+		freezerEnv.HistoryRecent = config.HistoryRecent
+	}
+	if err = freezeDb.SetupFreezerEnv(freezerEnv, config.BlockHistory); err != nil {
 		return nil, err
 	}
 	// Set networkID to chainID by default.
@@ -308,7 +339,11 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		log.Info("Unprotected transactions allowed")
 	}
 	ethAPI := ethapi.NewBlockChainAPI(eth.APIBackend)
-	eth.engine, err = ethconfig.CreateConsensusEngine(chainConfig, chainDb, ethAPI, genesisHash)
+	engineDb := chainDb
+	if replica != nil {
+		engineDb = newReplicaEngineDB(chainDb)
+	}
+	eth.engine, err = ethconfig.CreateConsensusEngine(chainConfig, engineDb, ethAPI, genesisHash)
 	if err != nil {
 		return nil, err
 	}
@@ -321,7 +356,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	log.Info("Initialising Ethereum protocol", "network", networkID, "dbversion", dbVer)
 
 	// Create BlockChain object.
-	if !config.SkipBcVersionCheck {
+	if !config.SkipBcVersionCheck && replica == nil {
 		if bcVersion != nil && *bcVersion > core.BlockChainVersion {
 			return nil, fmt.Errorf("database version is v%d, Geth %s only supports v%d", *bcVersion, version.WithMeta, core.BlockChainVersion)
 		} else if bcVersion == nil || *bcVersion < core.BlockChainVersion {
@@ -350,6 +385,7 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 			TriesInMemory:         config.TriesInMemory,
 			Preimages:             config.Preimages,
 			StateHistory:          config.StateHistory,
+			HistoricalState:       config.HistoricalState,
 			StateScheme:           config.StateScheme,
 			PathSyncFlush:         config.PathSyncFlush,
 			JournalFilePath:       journalFilePath,
@@ -374,12 +410,26 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 			// - DATADIR/triedb/verkle.journal
 			TrieJournalDirectory: stack.ResolvePath("triedb"),
 			StateSizeTracking:    config.EnableStateSizeTracking,
+			NativeTransfers:      config.NativeTransfers || config.AddressIndexInternal || config.LogNativeTransfers,
+			AddressIndex:         config.AddressIndex,
+			AddressIndexInternal: config.AddressIndexInternal,
+			StatePruneIOBudget:   config.StatePruneIOBudget,
+			StatePruneBloomSize:  config.StatePruneBloomSize,
+			StatePruneInterval:   config.StatePruneInterval,
+			TrieServeReplicas:    config.ServeReplicas,
+			TrieReplica:          replica != nil,
 		}
 	)
 	if config.DisableTxIndexer {
 		log.Warn("The TxIndexer is disabled. Please note that the next time you re-enable it, it may affect the node performance because of rebuilding the tx index.")
 		options.TxLookupLimit = -1
 	}
+	if replica != nil {
+		// The indexes are maintained by the writer, including the address
+		// indexes which are served from the shared database.
+		options.TxLookupLimit = -1
+		options.StateSizeTracking = false
+	}
 
 	if config.VMTrace != "" {
 		traceConfig := json.RawMessage("{}")
@@ -411,9 +461,13 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	fmConfig := filtermaps.Config{
 		History:            config.LogHistory,
 		Disabled:           config.LogNoHistory,
+		NativeTransfers:    config.LogNativeTransfers,
 		CheckpointFileName: checkpointFile,
 		HashScheme:         config.StateScheme == rawdb.HashScheme,
 	}
+	if replica != nil {
+		fmConfig.Disabled, fmConfig.KeepIndex = true, true
+	}
 	chainView := eth.newChainView(eth.blockchain.CurrentBlock())
 	historyCutoff, _ := eth.blockchain.HistoryPruningCutoff()
 	var finalBlock uint64
@@ -443,6 +497,10 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 		return nil, err
 	}
 
+	if config.BundlePool.Enabled {
+		eth.bundlePool = bundlepool.New(config.BundlePool, eth.blockchain)
+	}
+
 	if !config.TxPool.NoLocals {
 		rejournal := config.TxPool.Rejournal
 		if rejournal < time.Second {
@@ -494,6 +552,9 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 				// if there is no VotePool in Parlia Engine, the miner can't get votes for assembling
 				parlia.VotePool = votePool
 			}
+			eth.pricePool = oracle.NewPool(eth.blockchain, parlia)
+			parlia.PriceOracle = eth.pricePool
+			eth.handler.pricepool = eth.pricePool
 		} else {
 			return nil, errors.New("Engine is not Parlia type")
 		}
@@ -514,6 +575,16 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 				return nil, err
 			}
 			log.Info("Create voteManager successfully")
+
+			if url := config.Miner.PriceOracleURL; url != "" {
+				signer, err := vote.NewVoteSigner(blsPasswordPath, blsWalletPath)
+				if err != nil {
+					return nil, err
+				}
+				source := oracle.NewHTTPSource(url, config.Miner.PriceOracleField)
+				eth.priceReporter = oracle.NewReporter(eth.blockchain, eth.pricePool, source, signer)
+				log.Info("Create price reporter successfully", "url", url)
+			}
 		}
 	}
 	eth.APIBackend.gpo = gasprice.NewOracle(eth.APIBackend, config.GPO, config.Miner.GasPrice)
@@ -521,11 +592,44 @@ func New(stack *node.Node, config *ethconfig.Config) (*Ethereum, error) {
 	// Start the RPC service
 	eth.netRPCService = ethapi.NewNetAPI(eth.p2pServer, networkID)
 
+	if config.ServeIncrSnapshots {
+		var (
+			incrPath  string
+			servePath = config.IncrSnapshotServePath
+			network   = "unknown"
+		)
+		if config.EnableIncrSnapshots {
+			incrPath = config.IncrSnapshotPath
+			if incrPath == "" {
+				incrPath = filepath.Join(stack.ResolveAncient("chaindata", config.DatabaseFreezer), rawdb.IncrementalPath)
+			}
+		}
+		if servePath == "" {
+			servePath = stack.ResolvePath("incrserve")
+		}
+		if name, ok := params.NetworkNames[eth.blockchain.Config().ChainID.String()]; ok {
+			network = name
+		}
+		server := core.NewIncrServer(incrPath, servePath, network, config.IncrSnapshotServeKeptFiles)
+		stack.RegisterHandler("Incremental snapshots", "/incr/", server)
+		stack.RegisterLifecycle(server)
+	}
+
 	// Register the backend on the node
 	stack.RegisterAPIs(eth.APIs())
-	stack.RegisterProtocols(eth.Protocols())
+	if replica == nil {
+		stack.RegisterProtocols(eth.Protocols())
+	}
 	stack.RegisterLifecycle(eth)
 
+	if replica != nil {
+		// The unclean shutdowns are tracked by the writer owning the database
+		stack.RegisterLifecycle(newReplicaFollower(config.ReplicaSource, replica, eth.blockchain))
+		return eth, nil
+	}
+	if config.ServeReplicas {
+		stack.RegisterLifecycle(newReplicaPublisher(stack.ResolvePath(replicaSnapshotsDir), config.ReplicaPublishInterval, chainDb, eth.blockchain))
+	}
 	// Successful startup; push a marker and check previous unclean shutdowns.
 	eth.shutdownTracker.MarkStartup()
 
@@ -825,6 +929,18 @@ func (s *Ethereum) StopMining() {
 	s.miner.Stop()
 }
 
+// SetBridgePool attaches the attestation pool of the bridge relayer, whose
+// attestations are then exchanged with the `bsc` peers. It must be called
+// before the node is started.
+func (s *Ethereum) SetBridgePool(pool *bridge.Pool) { s.handler.bridgepool = pool }
+
+// SetBridgeCommittee makes the node forward the bridge attestations signed by
+// the given committee to the `bsc` peers without running a relayer itself. It
+// must be called before the node is started.
+func (s *Ethereum) SetBridgeCommittee(committee []types.BLSPublicKey) {
+	s.handler.bridgeRelay = newBridgeRelay(committee)
+}
+
 func (s *Ethereum) IsMining() bool      { return s.miner.Mining() }
 func (s *Ethereum) Miner() *miner.Miner { return s.miner }
 
@@ -832,6 +948,7 @@ func (s *Ethereum) AccountManager() *accounts.Manager  { return s.accountManager
 func (s *Ethereum) BlockChain() *core.BlockChain       { return s.blockchain }
 func (s *Ethereum) TxPool() *txpool.TxPool             { return s.txPool }
 func (s *Ethereum) BlobTxPool() *blobpool.BlobPool     { return s.blobTxPool }
+func (s *Ethereum) BundlePool() *bundlepool.BundlePool { return s.bundlePool }
 func (s *Ethereum) VotePool() *vote.VotePool           { return s.votePool }
 func (s *Ethereum) EventMux() *event.TypeMux           { return s.eventMux }
 func (s *Ethereum) Engine() consensus.Engine           { return s.engine }
@@ -868,7 +985,9 @@ func (s *Ethereum) Start() error {
 	}
 
 	// Regularly update shutdown marker
-	s.shutdownTracker.Start()
+	if s.config.Replica == "" {
+		s.shutdownTracker.Start()
+	}
 
 	// Start the networking layer
 	s.handler.Start(s.p2pServer.MaxPeers, s.p2pServer.MaxPeersPerIP)
@@ -1016,6 +1135,12 @@ func (s *Ethereum) Stop() error {
 	s.discmix.Close()
 	s.dropper.Stop()
 	s.handler.Stop()
+	if s.priceReporter != nil {
+		s.priceReporter.Stop()
+	}
+	if s.pricePool != nil {
+		s.pricePool.Close()
+	}
 
 	// Then stop everything else.
 	ch := make(chan struct{})
@@ -1028,7 +1153,9 @@ func (s *Ethereum) Stop() error {
 	s.engine.Close()
 
 	// Clean shutdown marker as the last thing before closing db
-	s.shutdownTracker.Stop()
+	if s.config.Replica == "" {
+		s.shutdownTracker.Stop()
+	}
 
 	s.chainDb.Close()
 	s.eventMux.Stop()
@@ -0,0 +1,52 @@
+package eth
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+)
+
+// maxKnownBridgeAttestations is the maximum number of bridge attestations
+// remembered by a relaying node to forward every one of them only once.
+const maxKnownBridgeAttestations = 32768
+
+// bridgeRelay forwards the bridge attestations on nodes running no relayer
+// themselves, such as the sentries in front of the validators, which would cut
+// the attesters behind them off the rest of the committee otherwise. Without
+// a pool the attestations are not collected, only checked to be signed by a
+// member of the configured committee and gossiped on once.
+type bridgeRelay struct {
+	committee map[types.BLSPublicKey]struct{}
+	known     *lru.Cache[common.Hash, struct{}] // Attestations already forwarded
+}
+
+func newBridgeRelay(committee []types.BLSPublicKey) *bridgeRelay {
+	r := &bridgeRelay{
+		committee: make(map[types.BLSPublicKey]struct{}, len(committee)),
+		known:     lru.NewCache[common.Hash, struct{}](maxKnownBridgeAttestations),
+	}
+	for _, member := range committee {
+		r.committee[member] = struct{}{}
+	}
+	return r
+}
+
+// accept reports whether att is a valid attestation of a committee member which
+// was not forwarded yet, marking it as known if so.
+func (r *bridgeRelay) accept(att *types.BridgeAttestation) bool {
+	if _, ok := r.committee[att.VoteAddress]; !ok {
+		log.Debug("Dropping bridge attestation of unknown attester", "digest", att.Digest, "attester", common.Bytes2Hex(att.VoteAddress[:]))
+		return false
+	}
+	hash := att.Hash()
+	if r.known.Contains(hash) {
+		return false
+	}
+	if err := att.Verify(); err != nil {
+		log.Debug("Dropping invalid bridge attestation", "digest", att.Digest, "err", err)
+		return false
+	}
+	r.known.Add(hash, struct{}{})
+	return true
+}
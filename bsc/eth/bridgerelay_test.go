@@ -0,0 +1,48 @@
+package eth
+
+import (
+	"testing"
+
+	"github.com/prysmaticlabs/prysm/v5/crypto/bls"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// Tests that a node without a relayer forwards every valid attestation of the
+// committee once, dropping all others.
+func TestBridgeRelay(t *testing.T) {
+	var (
+		member, _   = bls.RandKey()
+		outsider, _ = bls.RandKey()
+		relay       = newBridgeRelay([]types.BLSPublicKey{types.BLSPublicKey(member.PublicKey().Marshal())})
+	)
+	attest := func(digest common.Hash, outside bool) *types.BridgeAttestation {
+		key := member
+		if outside {
+			key = outsider
+		}
+		att := &types.BridgeAttestation{Digest: digest}
+		copy(att.VoteAddress[:], key.PublicKey().Marshal())
+		hash := att.SigningHash()
+		copy(att.Signature[:], key.Sign(hash[:]).Marshal())
+		return att
+	}
+	if !relay.accept(attest(common.Hash{0x01}, false)) {
+		t.Fatal("valid attestation not forwarded")
+	}
+	if relay.accept(attest(common.Hash{0x01}, false)) {
+		t.Error("known attestation forwarded again")
+	}
+	if relay.accept(attest(common.Hash{0x02}, true)) {
+		t.Error("attestation of unknown attester forwarded")
+	}
+	corrupt := attest(common.Hash{0x03}, false)
+	corrupt.Signature[10] ^= 1
+	if relay.accept(corrupt) {
+		t.Error("attestation with invalid signature forwarded")
+	}
+	if !relay.accept(attest(common.Hash{0x03}, false)) {
+		t.Error("valid attestation not forwarded after an invalid one")
+	}
+}
@@ -30,6 +30,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/history"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/ethdb"
@@ -52,13 +53,17 @@ var FullNodeGPO = gasprice.Config{
 // Defaults contains default settings for use on the BSC main net.
 var Defaults = Config{
 	HistoryMode:            history.KeepAll,
+	HistoryRecent:          params.FullImmutabilityThreshold,
 	SyncMode:               SnapSync,
 	NetworkId:              0, // enable auto configuration of networkID == chainID
 	TxLookupLimit:          2350000,
 	TransactionHistory:     2350000,
 	BlockHistory:           0,
 	StateHistory:           params.FullImmutabilityThreshold,
+	StatePruneIOBudget:     64,
+	StatePruneBloomSize:    2048,
 	DatabaseCache:          512,
+	DatabaseColdCache:      1024,
 	TrieCleanCache:         154,
 	TrieDirtyCache:         256,
 	TrieTimeout:            10 * time.Minute,
@@ -69,6 +74,7 @@ var Defaults = Config{
 	Miner:                  minerconfig.DefaultConfig,
 	TxPool:                 legacypool.DefaultConfig,
 	BlobPool:               blobpool.DefaultConfig,
+	BundlePool:             bundlepool.DefaultConfig,
 	RPCGasCap:              50000000,
 	RPCEVMTimeout:          5 * time.Second,
 	GPO:                    FullNodeGPO,
@@ -77,6 +83,7 @@ var Defaults = Config{
 	RPCTxFeeCap:            1,                                         // 1 ether
 	BlobExtraReserve:       params.DefaultExtraReserveForBlobRequests, // Extra reserve threshold for blob, blob never expires when -1 is set, default 28800
 	EnableOpcodeOptimizing: false,
+	ReplicaPublishInterval: 3 * time.Second,
 }
 
 //go:generate go run github.com/fjl/gencodec -type Config -formats toml -out gen_config.go
@@ -102,7 +109,8 @@ type Config struct {
 	EVNNodeIDsToAdd        []enode.ID
 	EVNNodeIDsToRemove     []enode.ID
 	// HistoryMode configures chain history retention.
-	HistoryMode history.HistoryMode
+	HistoryMode   history.HistoryMode
+	HistoryRecent uint64 `toml:",omitempty"` // The number of blocks from head whose bodies and receipts are kept in the recent history mode.
 
 	// This can be set to list of enrtree:// URLs which will be queried for
 	// nodes to connect to.
@@ -122,13 +130,18 @@ type Config struct {
 	// Deprecated: use 'TransactionHistory' instead.
 	TxLookupLimit uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
 
-	TransactionHistory uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
-	BlockHistory       uint64 `toml:",omitempty"` // The maximum number of blocks from head whose block body/header/receipt/diff/hash are reserved.
-	LogHistory         uint64 `toml:",omitempty"` // The maximum number of blocks from head where a log search index is maintained.
-	LogNoHistory       bool   `toml:",omitempty"` // No log search index is maintained.
+	TransactionHistory   uint64 `toml:",omitempty"` // The maximum number of blocks from head whose tx indices are reserved.
+	BlockHistory         uint64 `toml:",omitempty"` // The maximum number of blocks from head whose block body/header/receipt/diff/hash are reserved.
+	LogHistory           uint64 `toml:",omitempty"` // The maximum number of blocks from head where a log search index is maintained.
+	LogNoHistory         bool   `toml:",omitempty"` // No log search index is maintained.
+	LogNativeTransfers   bool   `toml:",omitempty"` // Whether the native transfers are indexed as synthetic logs.
+	NativeTransfers      bool   `toml:",omitempty"` // Whether the native transfers of imported blocks are recorded.
+	AddressIndex         bool   `toml:",omitempty"` // Whether the transactions are indexed by the addresses involved.
+	AddressIndexInternal bool   `toml:",omitempty"` // Whether the native transfers of internal calls are indexed by address.
 	// Deprecated: checkpoint file is auto-enabled at datadir/geth/filtermap_checkpoints.json.
 	LogExportCheckpoints string
 	StateHistory         uint64 `toml:",omitempty"` // The maximum number of blocks from head whose state histories are reserved.
+	HistoricalState      bool   `toml:",omitempty"` // Whether the historical states and proofs within the state histories are served.
 
 	// State scheme represents the scheme used to store ethereum states and trie
 	// nodes on top. It can be 'hash', 'path', or none which means use the scheme
@@ -136,6 +149,11 @@ type Config struct {
 	StateScheme   string `toml:",omitempty"` // State scheme used to store ethereum state and merkle trie nodes on top
 	PathSyncFlush bool   `toml:",omitempty"` // State scheme used to store ethereum state and merkle trie nodes on top
 
+	// Online state pruning options
+	StatePruneIOBudget  uint64        `toml:",omitempty"` // Disk bandwidth in MB/s the online state pruning may use, 0 for unthrottled
+	StatePruneBloomSize uint64        `toml:",omitempty"` // Megabytes of memory allocated to the bloom filter of the online state pruning
+	StatePruneInterval  time.Duration `toml:",omitempty"` // Interval between the automatic online state prunings, 0 to prune on demand only
+
 	DisableTxIndexer bool `toml:",omitempty"` // Whether to enable the transaction indexer
 
 	// RequiredBlocks is a set of block number -> hash mappings which must be in the
@@ -150,6 +168,12 @@ type Config struct {
 	DatabaseFreezer    string
 	DatabaseEra        string
 
+	// DatabaseColdStore is the optional location of the blob store the completed
+	// ancient data files are offloaded to, with up to DatabaseColdCache megabytes
+	// of them cached locally once fetched back.
+	DatabaseColdStore string
+	DatabaseColdCache int
+
 	// PruneAncientData is an optional config and disabled by default, and usually you do not need it.
 	// When this flag is enabled, only keep the latest 9w blocks' data, the older blocks' data will be
 	// pruned instead of being dumped to freezerdb, the pruned data includes CanonicalHash, Header, Block,
@@ -178,8 +202,9 @@ type Config struct {
 	Miner minerconfig.Config
 
 	// Transaction pool options
-	TxPool   legacypool.Config
-	BlobPool blobpool.Config
+	TxPool     legacypool.Config
+	BlobPool   blobpool.Config
+	BundlePool bundlepool.Config
 
 	// Gas Price Oracle options
 	GPO gasprice.Config
@@ -254,6 +279,17 @@ type Config struct {
 	IncrSnapshotKeptBlocks    uint64
 	UseRemoteIncrSnapshot     bool
 	RemoteIncrSnapshotURL     string
+
+	// incremental snapshot serving config
+	ServeIncrSnapshots         bool
+	IncrSnapshotServePath      string
+	IncrSnapshotServeKeptFiles uint64
+
+	// read replica config
+	ServeReplicas          bool          // Whether the database snapshots and state layers are published for the read replicas
+	ReplicaPublishInterval time.Duration // Minimum interval between two published database snapshots
+	Replica                string        // Snapshot directory of the writer node followed as a read replica
+	ReplicaSource          string        // Websocket endpoint of the writer node announcing the new heads
 }
 
 // CreateConsensusEngine creates a consensus engine for the given chain config.
@@ -9,6 +9,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/history"
 	"github.com/ethereum/go-ethereum/core/txpool/blobpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/eth/gasprice"
 	"github.com/ethereum/go-ethereum/miner/minerconfig"
@@ -18,81 +19,100 @@ import (
 // MarshalTOML marshals as TOML.
 func (c Config) MarshalTOML() (interface{}, error) {
 	type Config struct {
-		Genesis                   *core.Genesis `toml:",omitempty"`
-		NetworkId                 uint64
-		SyncMode                  SyncMode
-		DisablePeerTxBroadcast    bool
-		EVNNodeIDsToAdd           []enode.ID
-		EVNNodeIDsToRemove        []enode.ID
-		HistoryMode               history.HistoryMode
-		EthDiscoveryURLs          []string
-		SnapDiscoveryURLs         []string
-		BscDiscoveryURLs          []string
-		NoPruning                 bool
-		NoPrefetch                bool
-		EnableBAL                 bool
-		DirectBroadcast           bool
-		DisableSnapProtocol       bool
-		RangeLimit                bool
-		TxLookupLimit             uint64 `toml:",omitempty"`
-		TransactionHistory        uint64 `toml:",omitempty"`
-		BlockHistory              uint64 `toml:",omitempty"`
-		LogHistory                uint64 `toml:",omitempty"`
-		LogNoHistory              bool   `toml:",omitempty"`
-		LogExportCheckpoints      string
-		StateHistory              uint64                 `toml:",omitempty"`
-		StateScheme               string                 `toml:",omitempty"`
-		PathSyncFlush             bool                   `toml:",omitempty"`
-		DisableTxIndexer          bool                   `toml:",omitempty"`
-		RequiredBlocks            map[uint64]common.Hash `toml:"-"`
-		SkipBcVersionCheck        bool                   `toml:"-"`
-		DatabaseHandles           int                    `toml:"-"`
-		DatabaseCache             int
-		DatabaseFreezer           string
-		DatabaseEra               string
-		PruneAncientData          bool
-		TrieCleanCache            int
-		TrieDirtyCache            int
-		TrieTimeout               time.Duration
-		SnapshotCache             int
-		TriesInMemory             uint64
-		TriesVerifyMode           core.VerifyMode
-		Preimages                 bool
-		FilterLogCacheSize        int
-		LogQueryLimit             int
-		Miner                     minerconfig.Config
-		TxPool                    legacypool.Config
-		BlobPool                  blobpool.Config
-		GPO                       gasprice.Config
-		EnablePreimageRecording   bool
-		EnableWitnessStats        bool
-		StatelessSelfValidation   bool
-		EnableStateSizeTracking   bool
-		VMTrace                   string
-		VMTraceJsonConfig         string
-		RPCGasCap                 uint64
-		RPCEVMTimeout             time.Duration
-		RPCTxFeeCap               float64
-		OverridePassedForkTime    *uint64       `toml:",omitempty"`
-		OverrideLorentz           *uint64       `toml:",omitempty"`
-		OverrideMaxwell           *uint64       `toml:",omitempty"`
-		OverrideFermi             *uint64       `toml:",omitempty"`
-		OverrideOsaka             *uint64       `toml:",omitempty"`
-		OverrideMendel            *uint64       `toml:",omitempty"`
-		OverrideBPO1              *uint64       `toml:",omitempty"`
-		OverrideBPO2              *uint64       `toml:",omitempty"`
-		OverrideVerkle            *uint64       `toml:",omitempty"`
-		TxSyncDefaultTimeout      time.Duration `toml:",omitempty"`
-		TxSyncMaxTimeout          time.Duration `toml:",omitempty"`
-		BlobExtraReserve          uint64
-		EnableOpcodeOptimizing    bool
-		EnableIncrSnapshots       bool
-		IncrSnapshotPath          string
-		IncrSnapshotBlockInterval uint64
-		IncrSnapshotStateBuffer   uint64
-		IncrSnapshotKeptBlocks    uint64
-		UseRemoteIncrSnapshot     bool
-		RemoteIncrSnapshotURL     string
+		Genesis                    *core.Genesis `toml:",omitempty"`
+		NetworkId                  uint64
+		SyncMode                   SyncMode
+		DisablePeerTxBroadcast     bool
+		EVNNodeIDsToAdd            []enode.ID
+		EVNNodeIDsToRemove         []enode.ID
+		HistoryMode                history.HistoryMode
+		HistoryRecent              uint64 `toml:",omitempty"`
+		EthDiscoveryURLs           []string
+		SnapDiscoveryURLs          []string
+		BscDiscoveryURLs           []string
+		NoPruning                  bool
+		NoPrefetch                 bool
+		EnableBAL                  bool
+		DirectBroadcast            bool
+		DisableSnapProtocol        bool
+		RangeLimit                 bool
+		TxLookupLimit              uint64 `toml:",omitempty"`
+		TransactionHistory         uint64 `toml:",omitempty"`
+		BlockHistory               uint64 `toml:",omitempty"`
+		LogHistory                 uint64 `toml:",omitempty"`
+		LogNoHistory               bool   `toml:",omitempty"`
+		LogNativeTransfers         bool   `toml:",omitempty"`
+		NativeTransfers            bool   `toml:",omitempty"`
+		AddressIndex               bool   `toml:",omitempty"`
+		AddressIndexInternal       bool   `toml:",omitempty"`
+		LogExportCheckpoints       string
+		StateHistory               uint64                 `toml:",omitempty"`
+		HistoricalState            bool                   `toml:",omitempty"`
+		StateScheme                string                 `toml:",omitempty"`
+		PathSyncFlush              bool                   `toml:",omitempty"`
+		StatePruneIOBudget         uint64                 `toml:",omitempty"`
+		StatePruneBloomSize        uint64                 `toml:",omitempty"`
+		StatePruneInterval         time.Duration          `toml:",omitempty"`
+		DisableTxIndexer           bool                   `toml:",omitempty"`
+		RequiredBlocks             map[uint64]common.Hash `toml:"-"`
+		SkipBcVersionCheck         bool                   `toml:"-"`
+		DatabaseHandles            int                    `toml:"-"`
+		DatabaseCache              int
+		DatabaseFreezer            string
+		DatabaseEra                string
+		DatabaseColdStore          string
+		DatabaseColdCache          int
+		PruneAncientData           bool
+		TrieCleanCache             int
+		TrieDirtyCache             int
+		TrieTimeout                time.Duration
+		SnapshotCache              int
+		TriesInMemory              uint64
+		TriesVerifyMode            core.VerifyMode
+		Preimages                  bool
+		FilterLogCacheSize         int
+		LogQueryLimit              int
+		Miner                      minerconfig.Config
+		TxPool                     legacypool.Config
+		BlobPool                   blobpool.Config
+		BundlePool                 bundlepool.Config
+		GPO                        gasprice.Config
+		EnablePreimageRecording    bool
+		EnableWitnessStats         bool
+		StatelessSelfValidation    bool
+		EnableStateSizeTracking    bool
+		VMTrace                    string
+		VMTraceJsonConfig          string
+		RPCGasCap                  uint64
+		RPCEVMTimeout              time.Duration
+		RPCTxFeeCap                float64
+		OverridePassedForkTime     *uint64       `toml:",omitempty"`
+		OverrideLorentz            *uint64       `toml:",omitempty"`
+		OverrideMaxwell            *uint64       `toml:",omitempty"`
+		OverrideFermi              *uint64       `toml:",omitempty"`
+		OverrideOsaka              *uint64       `toml:",omitempty"`
+		OverrideMendel             *uint64       `toml:",omitempty"`
+		OverrideBPO1               *uint64       `toml:",omitempty"`
+		OverrideBPO2               *uint64       `toml:",omitempty"`
+		OverrideVerkle             *uint64       `toml:",omitempty"`
+		TxSyncDefaultTimeout       time.Duration `toml:",omitempty"`
+		TxSyncMaxTimeout           time.Duration `toml:",omitempty"`
+		BlobExtraReserve           uint64
+		EnableOpcodeOptimizing     bool
+		EnableIncrSnapshots        bool
+		IncrSnapshotPath           string
+		IncrSnapshotBlockInterval  uint64
+		IncrSnapshotStateBuffer    uint64
+		IncrSnapshotKeptBlocks     uint64
+		UseRemoteIncrSnapshot      bool
+		RemoteIncrSnapshotURL      string
+		ServeIncrSnapshots         bool
+		IncrSnapshotServePath      string
+		IncrSnapshotServeKeptFiles uint64
+		ServeReplicas              bool
+		ReplicaPublishInterval     time.Duration
+		Replica                    string
+		ReplicaSource              string
 	}
 	var enc Config
 	enc.Genesis = c.Genesis
@@ -102,6 +122,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.EVNNodeIDsToAdd = c.EVNNodeIDsToAdd
 	enc.EVNNodeIDsToRemove = c.EVNNodeIDsToRemove
 	enc.HistoryMode = c.HistoryMode
+	enc.HistoryRecent = c.HistoryRecent
 	enc.EthDiscoveryURLs = c.EthDiscoveryURLs
 	enc.SnapDiscoveryURLs = c.SnapDiscoveryURLs
 	enc.BscDiscoveryURLs = c.BscDiscoveryURLs
@@ -116,10 +137,18 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.BlockHistory = c.BlockHistory
 	enc.LogHistory = c.LogHistory
 	enc.LogNoHistory = c.LogNoHistory
+	enc.LogNativeTransfers = c.LogNativeTransfers
+	enc.NativeTransfers = c.NativeTransfers
+	enc.AddressIndex = c.AddressIndex
+	enc.AddressIndexInternal = c.AddressIndexInternal
 	enc.LogExportCheckpoints = c.LogExportCheckpoints
 	enc.StateHistory = c.StateHistory
+	enc.HistoricalState = c.HistoricalState
 	enc.StateScheme = c.StateScheme
 	enc.PathSyncFlush = c.PathSyncFlush
+	enc.StatePruneIOBudget = c.StatePruneIOBudget
+	enc.StatePruneBloomSize = c.StatePruneBloomSize
+	enc.StatePruneInterval = c.StatePruneInterval
 	enc.DisableTxIndexer = c.DisableTxIndexer
 	enc.RequiredBlocks = c.RequiredBlocks
 	enc.SkipBcVersionCheck = c.SkipBcVersionCheck
@@ -127,6 +156,8 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.DatabaseCache = c.DatabaseCache
 	enc.DatabaseFreezer = c.DatabaseFreezer
 	enc.DatabaseEra = c.DatabaseEra
+	enc.DatabaseColdStore = c.DatabaseColdStore
+	enc.DatabaseColdCache = c.DatabaseColdCache
 	enc.PruneAncientData = c.PruneAncientData
 	enc.TrieCleanCache = c.TrieCleanCache
 	enc.TrieDirtyCache = c.TrieDirtyCache
@@ -140,6 +171,7 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.Miner = c.Miner
 	enc.TxPool = c.TxPool
 	enc.BlobPool = c.BlobPool
+	enc.BundlePool = c.BundlePool
 	enc.GPO = c.GPO
 	enc.EnablePreimageRecording = c.EnablePreimageRecording
 	enc.EnableWitnessStats = c.EnableWitnessStats
@@ -170,87 +202,113 @@ func (c Config) MarshalTOML() (interface{}, error) {
 	enc.IncrSnapshotKeptBlocks = c.IncrSnapshotKeptBlocks
 	enc.UseRemoteIncrSnapshot = c.UseRemoteIncrSnapshot
 	enc.RemoteIncrSnapshotURL = c.RemoteIncrSnapshotURL
+	enc.ServeIncrSnapshots = c.ServeIncrSnapshots
+	enc.IncrSnapshotServePath = c.IncrSnapshotServePath
+	enc.IncrSnapshotServeKeptFiles = c.IncrSnapshotServeKeptFiles
+	enc.ServeReplicas = c.ServeReplicas
+	enc.ReplicaPublishInterval = c.ReplicaPublishInterval
+	enc.Replica = c.Replica
+	enc.ReplicaSource = c.ReplicaSource
 	return &enc, nil
 }
 
 // UnmarshalTOML unmarshals from TOML.
 func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	type Config struct {
-		Genesis                   *core.Genesis `toml:",omitempty"`
-		NetworkId                 *uint64
-		SyncMode                  *SyncMode
-		DisablePeerTxBroadcast    *bool
-		EVNNodeIDsToAdd           []enode.ID
-		EVNNodeIDsToRemove        []enode.ID
-		HistoryMode               *history.HistoryMode
-		EthDiscoveryURLs          []string
-		SnapDiscoveryURLs         []string
-		BscDiscoveryURLs          []string
-		NoPruning                 *bool
-		NoPrefetch                *bool
-		EnableBAL                 *bool
-		DirectBroadcast           *bool
-		DisableSnapProtocol       *bool
-		RangeLimit                *bool
-		TxLookupLimit             *uint64 `toml:",omitempty"`
-		TransactionHistory        *uint64 `toml:",omitempty"`
-		BlockHistory              *uint64 `toml:",omitempty"`
-		LogHistory                *uint64 `toml:",omitempty"`
-		LogNoHistory              *bool   `toml:",omitempty"`
-		LogExportCheckpoints      *string
-		StateHistory              *uint64                `toml:",omitempty"`
-		StateScheme               *string                `toml:",omitempty"`
-		PathSyncFlush             *bool                  `toml:",omitempty"`
-		DisableTxIndexer          *bool                  `toml:",omitempty"`
-		RequiredBlocks            map[uint64]common.Hash `toml:"-"`
-		SkipBcVersionCheck        *bool                  `toml:"-"`
-		DatabaseHandles           *int                   `toml:"-"`
-		DatabaseCache             *int
-		DatabaseFreezer           *string
-		DatabaseEra               *string
-		PruneAncientData          *bool
-		TrieCleanCache            *int
-		TrieDirtyCache            *int
-		TrieTimeout               *time.Duration
-		SnapshotCache             *int
-		TriesInMemory             *uint64
-		TriesVerifyMode           *core.VerifyMode
-		Preimages                 *bool
-		FilterLogCacheSize        *int
-		LogQueryLimit             *int
-		Miner                     *minerconfig.Config
-		TxPool                    *legacypool.Config
-		BlobPool                  *blobpool.Config
-		GPO                       *gasprice.Config
-		EnablePreimageRecording   *bool
-		EnableWitnessStats        *bool
-		StatelessSelfValidation   *bool
-		EnableStateSizeTracking   *bool
-		VMTrace                   *string
-		VMTraceJsonConfig         *string
-		RPCGasCap                 *uint64
-		RPCEVMTimeout             *time.Duration
-		RPCTxFeeCap               *float64
-		OverridePassedForkTime    *uint64        `toml:",omitempty"`
-		OverrideLorentz           *uint64        `toml:",omitempty"`
-		OverrideMaxwell           *uint64        `toml:",omitempty"`
-		OverrideFermi             *uint64        `toml:",omitempty"`
-		OverrideOsaka             *uint64        `toml:",omitempty"`
-		OverrideMendel            *uint64        `toml:",omitempty"`
-		OverrideBPO1              *uint64        `toml:",omitempty"`
-		OverrideBPO2              *uint64        `toml:",omitempty"`
-		OverrideVerkle            *uint64        `toml:",omitempty"`
-		TxSyncDefaultTimeout      *time.Duration `toml:",omitempty"`
-		TxSyncMaxTimeout          *time.Duration `toml:",omitempty"`
-		BlobExtraReserve          *uint64
-		EnableOpcodeOptimizing    *bool
-		EnableIncrSnapshots       *bool
-		IncrSnapshotPath          *string
-		IncrSnapshotBlockInterval *uint64
-		IncrSnapshotStateBuffer   *uint64
-		IncrSnapshotKeptBlocks    *uint64
-		UseRemoteIncrSnapshot     *bool
-		RemoteIncrSnapshotURL     *string
+		Genesis                    *core.Genesis `toml:",omitempty"`
+		NetworkId                  *uint64
+		SyncMode                   *SyncMode
+		DisablePeerTxBroadcast     *bool
+		EVNNodeIDsToAdd            []enode.ID
+		EVNNodeIDsToRemove         []enode.ID
+		HistoryMode                *history.HistoryMode
+		HistoryRecent              *uint64 `toml:",omitempty"`
+		EthDiscoveryURLs           []string
+		SnapDiscoveryURLs          []string
+		BscDiscoveryURLs           []string
+		NoPruning                  *bool
+		NoPrefetch                 *bool
+		EnableBAL                  *bool
+		DirectBroadcast            *bool
+		DisableSnapProtocol        *bool
+		RangeLimit                 *bool
+		TxLookupLimit              *uint64 `toml:",omitempty"`
+		TransactionHistory         *uint64 `toml:",omitempty"`
+		BlockHistory               *uint64 `toml:",omitempty"`
+		LogHistory                 *uint64 `toml:",omitempty"`
+		LogNoHistory               *bool   `toml:",omitempty"`
+		LogNativeTransfers         *bool   `toml:",omitempty"`
+		NativeTransfers            *bool   `toml:",omitempty"`
+		AddressIndex               *bool   `toml:",omitempty"`
+		AddressIndexInternal       *bool   `toml:",omitempty"`
+		LogExportCheckpoints       *string
+		StateHistory               *uint64                `toml:",omitempty"`
+		HistoricalState            *bool                  `toml:",omitempty"`
+		StateScheme                *string                `toml:",omitempty"`
+		PathSyncFlush              *bool                  `toml:",omitempty"`
+		StatePruneIOBudget         *uint64                `toml:",omitempty"`
+		StatePruneBloomSize        *uint64                `toml:",omitempty"`
+		StatePruneInterval         *time.Duration         `toml:",omitempty"`
+		DisableTxIndexer           *bool                  `toml:",omitempty"`
+		RequiredBlocks             map[uint64]common.Hash `toml:"-"`
+		SkipBcVersionCheck         *bool                  `toml:"-"`
+		DatabaseHandles            *int                   `toml:"-"`
+		DatabaseCache              *int
+		DatabaseFreezer            *string
+		DatabaseEra                *string
+		DatabaseColdStore          *string
+		DatabaseColdCache          *int
+		PruneAncientData           *bool
+		TrieCleanCache             *int
+		TrieDirtyCache             *int
+		TrieTimeout                *time.Duration
+		SnapshotCache              *int
+		TriesInMemory              *uint64
+		TriesVerifyMode            *core.VerifyMode
+		Preimages                  *bool
+		FilterLogCacheSize         *int
+		LogQueryLimit              *int
+		Miner                      *minerconfig.Config
+		TxPool                     *legacypool.Config
+		BlobPool                   *blobpool.Config
+		BundlePool                 *bundlepool.Config
+		GPO                        *gasprice.Config
+		EnablePreimageRecording    *bool
+		EnableWitnessStats         *bool
+		StatelessSelfValidation    *bool
+		EnableStateSizeTracking    *bool
+		VMTrace                    *string
+		VMTraceJsonConfig          *string
+		RPCGasCap                  *uint64
+		RPCEVMTimeout              *time.Duration
+		RPCTxFeeCap                *float64
+		OverridePassedForkTime     *uint64        `toml:",omitempty"`
+		OverrideLorentz            *uint64        `toml:",omitempty"`
+		OverrideMaxwell            *uint64        `toml:",omitempty"`
+		OverrideFermi              *uint64        `toml:",omitempty"`
+		OverrideOsaka              *uint64        `toml:",omitempty"`
+		OverrideMendel             *uint64        `toml:",omitempty"`
+		OverrideBPO1               *uint64        `toml:",omitempty"`
+		OverrideBPO2               *uint64        `toml:",omitempty"`
+		OverrideVerkle             *uint64        `toml:",omitempty"`
+		TxSyncDefaultTimeout       *time.Duration `toml:",omitempty"`
+		TxSyncMaxTimeout           *time.Duration `toml:",omitempty"`
+		BlobExtraReserve           *uint64
+		EnableOpcodeOptimizing     *bool
+		EnableIncrSnapshots        *bool
+		IncrSnapshotPath           *string
+		IncrSnapshotBlockInterval  *uint64
+		IncrSnapshotStateBuffer    *uint64
+		IncrSnapshotKeptBlocks     *uint64
+		UseRemoteIncrSnapshot      *bool
+		RemoteIncrSnapshotURL      *string
+		ServeIncrSnapshots         *bool
+		IncrSnapshotServePath      *string
+		IncrSnapshotServeKeptFiles *uint64
+		ServeReplicas              *bool
+		ReplicaPublishInterval     *time.Duration
+		Replica                    *string
+		ReplicaSource              *string
 	}
 	var dec Config
 	if err := unmarshal(&dec); err != nil {
@@ -277,6 +335,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.HistoryMode != nil {
 		c.HistoryMode = *dec.HistoryMode
 	}
+	if dec.HistoryRecent != nil {
+		c.HistoryRecent = *dec.HistoryRecent
+	}
 	if dec.EthDiscoveryURLs != nil {
 		c.EthDiscoveryURLs = dec.EthDiscoveryURLs
 	}
@@ -319,18 +380,42 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.LogNoHistory != nil {
 		c.LogNoHistory = *dec.LogNoHistory
 	}
+	if dec.LogNativeTransfers != nil {
+		c.LogNativeTransfers = *dec.LogNativeTransfers
+	}
+	if dec.NativeTransfers != nil {
+		c.NativeTransfers = *dec.NativeTransfers
+	}
+	if dec.AddressIndex != nil {
+		c.AddressIndex = *dec.AddressIndex
+	}
+	if dec.AddressIndexInternal != nil {
+		c.AddressIndexInternal = *dec.AddressIndexInternal
+	}
 	if dec.LogExportCheckpoints != nil {
 		c.LogExportCheckpoints = *dec.LogExportCheckpoints
 	}
 	if dec.StateHistory != nil {
 		c.StateHistory = *dec.StateHistory
 	}
+	if dec.HistoricalState != nil {
+		c.HistoricalState = *dec.HistoricalState
+	}
 	if dec.StateScheme != nil {
 		c.StateScheme = *dec.StateScheme
 	}
 	if dec.PathSyncFlush != nil {
 		c.PathSyncFlush = *dec.PathSyncFlush
 	}
+	if dec.StatePruneIOBudget != nil {
+		c.StatePruneIOBudget = *dec.StatePruneIOBudget
+	}
+	if dec.StatePruneBloomSize != nil {
+		c.StatePruneBloomSize = *dec.StatePruneBloomSize
+	}
+	if dec.StatePruneInterval != nil {
+		c.StatePruneInterval = *dec.StatePruneInterval
+	}
 	if dec.DisableTxIndexer != nil {
 		c.DisableTxIndexer = *dec.DisableTxIndexer
 	}
@@ -352,6 +437,12 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.DatabaseEra != nil {
 		c.DatabaseEra = *dec.DatabaseEra
 	}
+	if dec.DatabaseColdStore != nil {
+		c.DatabaseColdStore = *dec.DatabaseColdStore
+	}
+	if dec.DatabaseColdCache != nil {
+		c.DatabaseColdCache = *dec.DatabaseColdCache
+	}
 	if dec.PruneAncientData != nil {
 		c.PruneAncientData = *dec.PruneAncientData
 	}
@@ -391,6 +482,9 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.BlobPool != nil {
 		c.BlobPool = *dec.BlobPool
 	}
+	if dec.BundlePool != nil {
+		c.BundlePool = *dec.BundlePool
+	}
 	if dec.GPO != nil {
 		c.GPO = *dec.GPO
 	}
@@ -481,5 +575,26 @@ func (c *Config) UnmarshalTOML(unmarshal func(interface{}) error) error {
 	if dec.RemoteIncrSnapshotURL != nil {
 		c.RemoteIncrSnapshotURL = *dec.RemoteIncrSnapshotURL
 	}
+	if dec.ServeIncrSnapshots != nil {
+		c.ServeIncrSnapshots = *dec.ServeIncrSnapshots
+	}
+	if dec.IncrSnapshotServePath != nil {
+		c.IncrSnapshotServePath = *dec.IncrSnapshotServePath
+	}
+	if dec.IncrSnapshotServeKeptFiles != nil {
+		c.IncrSnapshotServeKeptFiles = *dec.IncrSnapshotServeKeptFiles
+	}
+	if dec.ServeReplicas != nil {
+		c.ServeReplicas = *dec.ServeReplicas
+	}
+	if dec.ReplicaPublishInterval != nil {
+		c.ReplicaPublishInterval = *dec.ReplicaPublishInterval
+	}
+	if dec.Replica != nil {
+		c.Replica = *dec.Replica
+	}
+	if dec.ReplicaSource != nil {
+		c.ReplicaSource = *dec.ReplicaSource
+	}
 	return nil
 }
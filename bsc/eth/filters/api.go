@@ -32,6 +32,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/history"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/internal/ethapi"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/rpc"
 )
 
@@ -386,6 +387,117 @@ func (api *FilterAPI) NewFinalizedHeaders(ctx context.Context) (*rpc.Subscriptio
 	return rpcSub, nil
 }
 
+// RPCVoteAttestation is the json representation of a fast-finality vote attestation.
+type RPCVoteAttestation struct {
+	VoteAddressSet hexutil.Uint64 `json:"voteAddressSet"`
+	AggSignature   hexutil.Bytes  `json:"aggSignature"`
+	SourceNumber   hexutil.Uint64 `json:"sourceNumber"`
+	SourceHash     common.Hash    `json:"sourceHash"`
+	TargetNumber   hexutil.Uint64 `json:"targetNumber"`
+	TargetHash     common.Hash    `json:"targetHash"`
+}
+
+func newRPCVoteAttestation(attestation *types.VoteAttestation) *RPCVoteAttestation {
+	return &RPCVoteAttestation{
+		VoteAddressSet: hexutil.Uint64(attestation.VoteAddressSet),
+		AggSignature:   attestation.AggSignature[:],
+		SourceNumber:   hexutil.Uint64(attestation.Data.SourceNumber),
+		SourceHash:     attestation.Data.SourceHash,
+		TargetNumber:   hexutil.Uint64(attestation.Data.TargetNumber),
+		TargetHash:     attestation.Data.TargetHash,
+	}
+}
+
+// RPCFinalizedHead is a finalized header along with the attestation finalizing it,
+// which is nil if the attestation could not be retrieved.
+type RPCFinalizedHead struct {
+	Header      *types.Header       `json:"header"`
+	Attestation *RPCVoteAttestation `json:"attestation"`
+}
+
+// RPCBlockAttestation is the vote attestation carried in an imported block.
+type RPCBlockAttestation struct {
+	BlockNumber hexutil.Uint64 `json:"blockNumber"`
+	BlockHash   common.Hash    `json:"blockHash"`
+	*RPCVoteAttestation
+}
+
+// FinalizedHeads send a notification each time a new finalized header is reached,
+// together with the vote attestation which finalized it. The header is notified
+// without an attestation if the latter cannot be retrieved, so that subscribers
+// never miss a finalized header.
+func (api *FilterAPI) FinalizedHeads(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	gopool.Submit(func() {
+		headers := make(chan *types.Header)
+		headersSub := api.events.SubscribeNewFinalizedHeaders(headers)
+
+		for {
+			select {
+			case h := <-headers:
+				head := &RPCFinalizedHead{Header: h}
+				if attestation, err := api.sys.backend.GetFinalityAttestation(h); err != nil {
+					log.Debug("Failed to get finality attestation", "number", h.Number, "hash", h.Hash(), "err", err)
+				} else {
+					head.Attestation = newRPCVoteAttestation(attestation)
+				}
+				notifier.Notify(rpcSub.ID, head)
+			case <-rpcSub.Err():
+				headersSub.Unsubscribe()
+				return
+			}
+		}
+	})
+
+	return rpcSub, nil
+}
+
+// VoteAttestations send a notification each time a block carrying a vote
+// attestation is imported into the chain.
+func (api *FilterAPI) VoteAttestations(ctx context.Context) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	gopool.Submit(func() {
+		headers := make(chan *types.Header)
+		headersSub := api.events.SubscribeNewHeads(headers)
+		defer headersSub.Unsubscribe()
+
+		for {
+			select {
+			case h := <-headers:
+				attestation, err := api.sys.backend.GetVoteAttestation(h)
+				if err != nil {
+					log.Debug("Failed to get vote attestation", "number", h.Number, "hash", h.Hash(), "err", err)
+					continue
+				}
+				if attestation == nil || attestation.Data == nil {
+					continue
+				}
+				notifier.Notify(rpcSub.ID, &RPCBlockAttestation{
+					BlockNumber:        hexutil.Uint64(h.Number.Uint64()),
+					BlockHash:          h.Hash(),
+					RPCVoteAttestation: newRPCVoteAttestation(attestation),
+				})
+			case <-rpcSub.Err():
+				return
+			}
+		}
+	})
+
+	return rpcSub, nil
+}
+
 // Logs creates a subscription that fires for all new log that match the given filter criteria.
 func (api *FilterAPI) Logs(ctx context.Context, crit FilterCriteria) (*rpc.Subscription, error) {
 	notifier, supported := rpc.NotifierFromContext(ctx)
@@ -580,20 +692,9 @@ func (api *FilterAPI) NewFilter(crit FilterCriteria) (rpc.ID, error) {
 
 // GetLogs returns logs matching the given argument that are stored within the state.
 func (api *FilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*types.Log, error) {
-	if len(crit.Topics) > maxTopics {
-		return nil, errExceedMaxTopics
-	}
-	if api.logQueryLimit != 0 {
-		if len(crit.Addresses) > api.logQueryLimit {
-			return nil, errExceedLogQueryLimit
-		}
-		for _, topics := range crit.Topics {
-			if len(topics) > api.logQueryLimit {
-				return nil, errExceedLogQueryLimit
-			}
-		}
+	if err := api.checkQueryLimits(crit); err != nil {
+		return nil, err
 	}
-
 	var filter *Filter
 	if crit.BlockHash != nil {
 		if crit.FromBlock != nil || crit.ToBlock != nil {
@@ -631,6 +732,25 @@ func (api *FilterAPI) GetLogs(ctx context.Context, crit FilterCriteria) ([]*type
 	return returnLogs(logs), err
 }
 
+// checkQueryLimits checks the number of topics and addresses of the criteria of
+// a log query.
+func (api *FilterAPI) checkQueryLimits(crit FilterCriteria) error {
+	if len(crit.Topics) > maxTopics {
+		return errExceedMaxTopics
+	}
+	if api.logQueryLimit != 0 {
+		if len(crit.Addresses) > api.logQueryLimit {
+			return errExceedLogQueryLimit
+		}
+		for _, topics := range crit.Topics {
+			if len(topics) > api.logQueryLimit {
+				return errExceedLogQueryLimit
+			}
+		}
+	}
+	return nil
+}
+
 // UninstallFilter removes the filter with the given filter id.
 func (api *FilterAPI) UninstallFilter(id rpc.ID) bool {
 	api.filtersMu.Lock()
@@ -47,6 +47,7 @@ type Filter struct {
 	begin, end int64        // Range interval if filtering multiple blocks
 
 	rangeLimit        bool
+	nativeTransfers   bool // search the synthetic logs of the native transfers
 	rangeLogsTestHook chan rangeLogsTestEvent
 }
 
@@ -62,6 +63,15 @@ func (sys *FilterSystem) NewRangeFilter(begin, end int64, addresses []common.Add
 	return filter
 }
 
+// NewNativeTransferFilter creates a new range filter searching the synthetic
+// logs of the native transfers, as indexed with the native transfers, instead
+// of the logs of the transactions.
+func (sys *FilterSystem) NewNativeTransferFilter(begin, end int64, addresses []common.Address, topics [][]common.Hash, rangeLimit bool) *Filter {
+	filter := sys.NewRangeFilter(begin, end, addresses, topics, rangeLimit)
+	filter.nativeTransfers = true
+	return filter
+}
+
 // NewBlockFilter creates a new filter which directly inspects the contents of
 // a block to figure out whether it is interesting or not.
 func (sys *FilterSystem) NewBlockFilter(block common.Hash, addresses []common.Address, topics [][]common.Hash) *Filter {
@@ -269,7 +279,7 @@ func (s *searchSession) searchInRange(r common.Range[uint64], indexed bool) (com
 			s.filter.rangeLogsTestHook <- rangeLogsTestEvent{rangeLogsTestIndexed, r}
 		}
 		results, err := s.filter.indexedLogs(s.ctx, s.mb, r.First(), r.Last())
-		if err != nil && !errors.Is(err, filtermaps.ErrMatchAll) {
+		if err != nil && !errors.Is(err, filtermaps.ErrMatchAll) && !errors.Is(err, filtermaps.ErrNativeTransfersNotIndexed) {
 			return common.Range[uint64]{}, nil, err
 		}
 		if err == nil {
@@ -289,8 +299,9 @@ func (s *searchSession) searchInRange(r common.Range[uint64], indexed bool) (com
 			matchRange, matches := s.trimMatches(trimRange, r, results)
 			return matchRange, matches, nil
 		}
-		// "match all" filters are not supported by filtermaps; fall back to
-		// unindexed search which is the most efficient in this case
+		// "match all" filters are not supported by filtermaps, neither are the
+		// native transfers if not indexed; fall back to unindexed search which
+		// is the most efficient in this case
 		s.forceUnindexed = true
 		// fall through to unindexed case
 	}
@@ -418,7 +429,11 @@ func (f *Filter) rangeLogs(ctx context.Context, firstBlock, lastBlock uint64) ([
 
 func (f *Filter) indexedLogs(ctx context.Context, mb filtermaps.MatcherBackend, begin, end uint64) ([]*types.Log, error) {
 	start := time.Now()
-	potentialMatches, err := filtermaps.GetPotentialMatches(ctx, mb, begin, end, f.addresses, f.topics)
+	search := filtermaps.GetPotentialMatches
+	if f.nativeTransfers {
+		search = filtermaps.GetPotentialNativeTransferMatches
+	}
+	potentialMatches, err := search(ctx, mb, begin, end, f.addresses, f.topics)
 	matches := filterLogs(potentialMatches, nil, nil, f.addresses, f.topics)
 	log.Trace("Performed indexed log search", "begin", begin, "end", end, "true matches", len(matches), "false positives", len(potentialMatches)-len(matches), "elapsed", common.PrettyDuration(time.Since(start)))
 	return matches, err
@@ -445,6 +460,11 @@ func (f *Filter) unindexedLogs(ctx context.Context, chainView *filtermaps.ChainV
 		if header == nil {
 			return matches, errors.New("header not found")
 		}
+		if f.nativeTransfers {
+			// The synthetic logs are derived the same way as by the log index
+			matches = append(matches, filterLogs(filtermaps.NativeTransferLogs(chainView, blockNumber), nil, nil, f.addresses, f.topics)...)
+			continue
+		}
 		found, err := f.blockLogs(ctx, header)
 		if err != nil {
 			return matches, err
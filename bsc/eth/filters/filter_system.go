@@ -73,6 +73,9 @@ type Backend interface {
 	SubscribeRemovedLogsEvent(ch chan<- core.RemovedLogsEvent) event.Subscription
 	SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription
 	SubscribeNewVoteEvent(chan<- core.NewVoteEvent) event.Subscription
+	GetVoteAttestation(header *types.Header) (*types.VoteAttestation, error)
+	GetFinalityAttestation(header *types.Header) (*types.VoteAttestation, error)
+	GetNativeTransfers(ctx context.Context, blockHash common.Hash, number uint64) ([]*types.NativeTransfer, error)
 
 	CurrentView() *filtermaps.ChainView
 	NewMatcherBackend() filtermaps.MatcherBackend
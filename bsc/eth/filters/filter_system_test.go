@@ -51,6 +51,8 @@ type testBackend struct {
 	voteFeed            event.Feed
 	pendingBlock        *types.Block
 	pendingReceipts     types.Receipts
+	attestations        map[common.Hash]*types.VoteAttestation
+	nativeTransfers     map[common.Hash][]*types.NativeTransfer
 }
 
 func (b *testBackend) ChainConfig() *params.ChainConfig {
@@ -170,6 +172,25 @@ func (b *testBackend) SubscribeNewVoteEvent(ch chan<- core.NewVoteEvent) event.S
 	return b.voteFeed.Subscribe(ch)
 }
 
+func (b *testBackend) GetVoteAttestation(header *types.Header) (*types.VoteAttestation, error) {
+	return b.attestations[header.Hash()], nil
+}
+
+func (b *testBackend) GetFinalityAttestation(header *types.Header) (*types.VoteAttestation, error) {
+	if attestation, ok := b.attestations[header.Hash()]; ok {
+		return attestation, nil
+	}
+	return nil, errors.New("finality attestation not found")
+}
+
+func (b *testBackend) GetNativeTransfers(ctx context.Context, hash common.Hash, number uint64) ([]*types.NativeTransfer, error) {
+	transfers, ok := b.nativeTransfers[hash]
+	if !ok {
+		return nil, errors.New("native transfers not recorded")
+	}
+	return transfers, nil
+}
+
 func (b *testBackend) CurrentView() *filtermaps.ChainView {
 	head := b.CurrentBlock()
 	return filtermaps.NewChainView(b, head.Number.Uint64(), head.Hash())
@@ -1003,3 +1024,185 @@ func TestVoteSubscription(t *testing.T) {
 
 	<-sub0.Err()
 }
+
+// TestAttestationSubscriptions tests that the finalizedHeads and voteAttestations
+// subscriptions push the attestations resolved by the backend.
+func TestAttestationSubscriptions(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db           = rawdb.NewMemoryDatabase()
+		backend, sys = newTestFilterSystem(db, Config{})
+		api          = NewFilterAPI(sys, false)
+		headers      []*types.Header
+	)
+	backend.attestations = make(map[common.Hash]*types.VoteAttestation)
+	for i := 0; i < 4; i++ {
+		header := &types.Header{Number: big.NewInt(int64(i)), Difficulty: big.NewInt(2), Extra: []byte{byte(i)}}
+		headers = append(headers, header)
+		if i%2 == 1 {
+			continue // Odd headers carry no attestation
+		}
+		attestation := &types.VoteAttestation{
+			VoteAddressSet: types.ValidatorsBitSet(i + 1),
+			Data: &types.VoteData{
+				SourceNumber: uint64(i),
+				SourceHash:   header.Hash(),
+				TargetNumber: uint64(i + 1),
+				TargetHash:   common.Hash{byte(i + 1)},
+			},
+		}
+		attestation.AggSignature[0] = byte(i)
+		backend.attestations[header.Hash()] = attestation
+	}
+
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("eth", api); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	finalized := make(chan *RPCFinalizedHead)
+	finalizedSub, err := client.EthSubscribe(context.Background(), finalized, "finalizedHeads")
+	if err != nil {
+		t.Fatalf("failed to subscribe finalizedHeads: %v", err)
+	}
+	defer finalizedSub.Unsubscribe()
+	attested := make(chan *RPCBlockAttestation)
+	attestedSub, err := client.EthSubscribe(context.Background(), attested, "voteAttestations")
+	if err != nil {
+		t.Fatalf("failed to subscribe voteAttestations: %v", err)
+	}
+	defer attestedSub.Unsubscribe()
+
+	time.Sleep(time.Second)
+	for _, header := range headers {
+		backend.finalizedHeaderFeed.Send(core.FinalizedHeaderEvent{Header: header})
+		backend.chainFeed.Send(core.ChainEvent{Header: header})
+	}
+	// Every finalized head is notified, the ones without attestation as well
+	for i := 0; i < len(headers); i++ {
+		want := backend.attestations[headers[i].Hash()]
+		select {
+		case head := <-finalized:
+			if head.Header.Hash() != headers[i].Hash() {
+				t.Fatalf("finalized head %d: have %x, want %x", i, head.Header.Hash(), headers[i].Hash())
+			}
+			switch {
+			case want == nil:
+				if head.Attestation != nil {
+					t.Fatalf("finalized head %d: unexpected attestation", i)
+				}
+			case head.Attestation == nil:
+				t.Fatalf("finalized head %d: missing attestation", i)
+			case uint64(head.Attestation.SourceNumber) != want.Data.SourceNumber || head.Attestation.AggSignature[0] != byte(i):
+				t.Fatalf("finalized head %d: attestation mismatch", i)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for finalized head %d", i)
+		}
+	}
+	// Only the blocks carrying an attestation are notified
+	for i := 0; i < len(headers); i += 2 {
+		want := backend.attestations[headers[i].Hash()]
+		select {
+		case att := <-attested:
+			if att.BlockHash != headers[i].Hash() || uint64(att.BlockNumber) != uint64(i) {
+				t.Fatalf("vote attestation %d: block mismatch", i)
+			}
+			if uint64(att.VoteAddressSet) != uint64(want.VoteAddressSet) || att.TargetHash != want.Data.TargetHash {
+				t.Fatalf("vote attestation %d: attestation mismatch", i)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for vote attestation %d", i)
+		}
+	}
+}
+
+// TestNativeTransfers tests that eth_getNativeTransfers and the nativeTransfers
+// subscription return the transfers recorded by the backend, filtered by the
+// address.
+func TestNativeTransfers(t *testing.T) {
+	t.Parallel()
+
+	var (
+		db           = rawdb.NewMemoryDatabase()
+		backend, sys = newTestFilterSystem(db, Config{})
+		api          = NewFilterAPI(sys, false)
+		alice        = common.HexToAddress("0xa11ce")
+		bob          = common.HexToAddress("0xb0b")
+		headers      []*types.Header
+	)
+	backend.nativeTransfers = make(map[common.Hash][]*types.NativeTransfer)
+	for i := 0; i < 3; i++ {
+		header := &types.Header{Number: big.NewInt(int64(i)), Difficulty: big.NewInt(2)}
+		rawdb.WriteHeader(db, header)
+		rawdb.WriteCanonicalHash(db, header.Hash(), uint64(i))
+		rawdb.WriteHeadBlockHash(db, header.Hash())
+		headers = append(headers, header)
+
+		transfers := []*types.NativeTransfer{
+			{Kind: types.NativeTransferMint, To: alice, Amount: big.NewInt(int64(i + 1))},
+			{Kind: types.NativeTransferCall, From: alice, To: bob, Amount: big.NewInt(1), TxHash: common.Hash{byte(i)}},
+		}
+		types.DeriveNativeTransferFields(transfers, header.Hash(), uint64(i))
+		backend.nativeTransfers[header.Hash()] = transfers
+	}
+
+	// Query the recorded range
+	from, to := rpc.BlockNumber(1), rpc.LatestBlockNumber
+	transfers, err := api.GetNativeTransfers(context.Background(), NativeTransferRange{FromBlock: &from, ToBlock: &to}, &bob)
+	if err != nil {
+		t.Fatalf("failed to get native transfers: %v", err)
+	}
+	if len(transfers) != 2 {
+		t.Fatalf("transfer count mismatch: have %d, want 2", len(transfers))
+	}
+	for i, transfer := range transfers {
+		if uint64(transfer.BlockNumber) != uint64(i+1) || transfer.Index != 1 || transfer.Kind != types.NativeTransferCall {
+			t.Errorf("transfer %d: mismatch %+v", i, transfer)
+		}
+		if transfer.TransactionHash == nil || *transfer.TransactionHash != (common.Hash{byte(i + 1)}) {
+			t.Errorf("transfer %d: transaction hash mismatch", i)
+		}
+	}
+	if transfers, _ := api.GetNativeTransfers(context.Background(), NativeTransferRange{}, nil); len(transfers) != 2 {
+		t.Errorf("latest block transfer count mismatch: have %d, want 2", len(transfers))
+	}
+	if _, err := api.GetNativeTransfers(context.Background(), NativeTransferRange{FromBlock: &from, ToBlock: new(rpc.BlockNumber)}, nil); err != errInvalidBlockRange {
+		t.Errorf("inverted range: have %v, want %v", err, errInvalidBlockRange)
+	}
+
+	// Subscribe to the transfers of new heads
+	server := rpc.NewServer()
+	defer server.Stop()
+	if err := server.RegisterName("eth", api); err != nil {
+		t.Fatal(err)
+	}
+	client := rpc.DialInProc(server)
+	defer client.Close()
+
+	notified := make(chan *RPCNativeTransfer)
+	sub, err := client.EthSubscribe(context.Background(), notified, "nativeTransfers", bob)
+	if err != nil {
+		t.Fatalf("failed to subscribe nativeTransfers: %v", err)
+	}
+	defer sub.Unsubscribe()
+
+	time.Sleep(time.Second)
+	for _, header := range headers {
+		backend.chainFeed.Send(core.ChainEvent{Header: header})
+	}
+	for i := range headers {
+		select {
+		case transfer := <-notified:
+			if transfer.BlockHash != headers[i].Hash() || transfer.From != alice || transfer.To != bob {
+				t.Fatalf("notification %d: mismatch %+v", i, transfer)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("timeout waiting for notification %d", i)
+		}
+	}
+}
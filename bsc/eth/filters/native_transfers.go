@@ -0,0 +1,204 @@
+package filters
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/gopool"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/history"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// maxNativeTransferRange is the maximum number of blocks searched by a single
+// eth_getNativeTransfers call.
+const maxNativeTransferRange = 10000
+
+var (
+	errExceedNativeTransferRange = fmt.Errorf("exceed maximum block range of %d", maxNativeTransferRange)
+	errNativeTransferLogsByHash  = errors.New("native transfer logs can't be queried by blockHash")
+)
+
+// NativeTransferRange is the block range searched for native transfers, both
+// ends included. Missing ends default to the latest block.
+type NativeTransferRange struct {
+	FromBlock *rpc.BlockNumber `json:"fromBlock"`
+	ToBlock   *rpc.BlockNumber `json:"toBlock"`
+}
+
+// RPCNativeTransfer is a native transfer recorded by the node.
+type RPCNativeTransfer struct {
+	Kind            types.NativeTransferKind `json:"kind"`
+	From            common.Address           `json:"from"`
+	To              common.Address           `json:"to"`
+	Amount          *hexutil.Big             `json:"amount"`
+	TransactionHash *common.Hash             `json:"transactionHash"` // Nil outside transactions
+	Internal        bool                     `json:"internal,omitempty"`
+	BlockNumber     hexutil.Uint64           `json:"blockNumber"`
+	BlockHash       common.Hash              `json:"blockHash"`
+	Index           hexutil.Uint             `json:"transferIndex"`
+}
+
+func newRPCNativeTransfer(transfer *types.NativeTransfer) *RPCNativeTransfer {
+	result := &RPCNativeTransfer{
+		Kind:        transfer.Kind,
+		From:        transfer.From,
+		To:          transfer.To,
+		Amount:      (*hexutil.Big)(transfer.Amount),
+		Internal:    transfer.Internal,
+		BlockNumber: hexutil.Uint64(transfer.BlockNumber),
+		BlockHash:   transfer.BlockHash,
+		Index:       hexutil.Uint(transfer.Index),
+	}
+	if transfer.TxHash != (common.Hash{}) {
+		hash := transfer.TxHash
+		result.TransactionHash = &hash
+	}
+	return result
+}
+
+// filterNativeTransfers returns the transfers sent or received by address, or
+// all of them if address is nil.
+func filterNativeTransfers(transfers []*types.NativeTransfer, address *common.Address) []*RPCNativeTransfer {
+	var result []*RPCNativeTransfer
+	for _, transfer := range transfers {
+		if address != nil && transfer.From != *address && transfer.To != *address {
+			continue
+		}
+		result = append(result, newRPCNativeTransfer(transfer))
+	}
+	return result
+}
+
+// GetNativeTransfers returns the native transfers, mints, burns, fees and
+// rewards recorded in the given block range, optionally limited to those sent
+// or received by address. The node must record them, see the
+// --history.nativetransfers flag.
+func (api *FilterAPI) GetNativeTransfers(ctx context.Context, blockRange NativeTransferRange, address *common.Address) ([]*RPCNativeTransfer, error) {
+	resolve := func(number *rpc.BlockNumber) (uint64, error) {
+		if number == nil {
+			return api.sys.backend.CurrentHeader().Number.Uint64(), nil
+		}
+		if *number >= 0 {
+			return uint64(*number), nil
+		}
+		header, err := api.sys.backend.HeaderByNumber(ctx, *number)
+		if err != nil {
+			return 0, err
+		}
+		if header == nil {
+			return 0, errUnknownBlock
+		}
+		return header.Number.Uint64(), nil
+	}
+	begin, err := resolve(blockRange.FromBlock)
+	if err != nil {
+		return nil, err
+	}
+	end, err := resolve(blockRange.ToBlock)
+	if err != nil {
+		return nil, err
+	}
+	if begin > end {
+		return nil, errInvalidBlockRange
+	}
+	if end-begin >= maxNativeTransferRange {
+		return nil, errExceedNativeTransferRange
+	}
+	if begin < api.sys.backend.HistoryPruningCutoff() {
+		return nil, &history.PrunedHistoryError{}
+	}
+	result := []*RPCNativeTransfer{}
+	for number := begin; number <= end; number++ {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		header, err := api.sys.backend.HeaderByNumber(ctx, rpc.BlockNumber(number))
+		if err != nil {
+			return nil, err
+		}
+		if header == nil {
+			break // Beyond the head
+		}
+		transfers, err := api.sys.backend.GetNativeTransfers(ctx, header.Hash(), number)
+		if err != nil {
+			return nil, err
+		}
+		result = append(result, filterNativeTransfers(transfers, address)...)
+	}
+	return result, nil
+}
+
+// GetNativeTransferLogs returns the synthetic logs of the native transfers
+// matching the given criteria, kept out of eth_getLogs. The transfers are found
+// at filtermaps.NativeTokenAddress under NativeTransferTopic, the internal calls
+// moving value at the callee under InternalCallTopic. The node must record the
+// native transfers, the search is indexed with --history.logs.nativetransfers.
+// Only block ranges are supported.
+func (api *FilterAPI) GetNativeTransferLogs(ctx context.Context, crit FilterCriteria) ([]*types.Log, error) {
+	if err := api.checkQueryLimits(crit); err != nil {
+		return nil, err
+	}
+	if crit.BlockHash != nil {
+		return nil, errNativeTransferLogsByHash
+	}
+	begin := rpc.LatestBlockNumber.Int64()
+	if crit.FromBlock != nil {
+		begin = crit.FromBlock.Int64()
+	}
+	end := rpc.LatestBlockNumber.Int64()
+	if crit.ToBlock != nil {
+		end = crit.ToBlock.Int64()
+	}
+	if begin > 0 && end > 0 && begin > end {
+		return nil, errInvalidBlockRange
+	}
+	if begin >= 0 && begin < int64(api.sys.backend.HistoryPruningCutoff()) {
+		return nil, &history.PrunedHistoryError{}
+	}
+	logs, err := api.sys.NewNativeTransferFilter(begin, end, crit.Addresses, crit.Topics, api.rangeLimit).Logs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	return returnLogs(logs), nil
+}
+
+// NativeTransfers creates a subscription that fires for the native transfers
+// recorded in each new head block, optionally limited to those sent or
+// received by address.
+func (api *FilterAPI) NativeTransfers(ctx context.Context, address *common.Address) (*rpc.Subscription, error) {
+	notifier, supported := rpc.NotifierFromContext(ctx)
+	if !supported {
+		return &rpc.Subscription{}, rpc.ErrNotificationsUnsupported
+	}
+
+	rpcSub := notifier.CreateSubscription()
+
+	gopool.Submit(func() {
+		headers := make(chan *types.Header)
+		headersSub := api.events.SubscribeNewHeads(headers)
+		defer headersSub.Unsubscribe()
+
+		for {
+			select {
+			case h := <-headers:
+				transfers, err := api.sys.backend.GetNativeTransfers(context.Background(), h.Hash(), h.Number.Uint64())
+				if err != nil {
+					log.Debug("Failed to get native transfers", "number", h.Number, "hash", h.Hash(), "err", err)
+					continue
+				}
+				for _, transfer := range filterNativeTransfers(transfers, address) {
+					notifier.Notify(rpcSub.ID, transfer)
+				}
+			case <-rpcSub.Err():
+				return
+			}
+		}
+	})
+
+	return rpcSub, nil
+}
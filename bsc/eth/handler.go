@@ -64,6 +64,12 @@ const (
 	// voteChanSize is the size of channel listening to NewVotesEvent.
 	voteChanSize = 256
 
+	// bridgeAttestationChanSize is the size of channel listening to NewBridgeAttestationEvent.
+	bridgeAttestationChanSize = 256
+
+	// priceReportChanSize is the size of channel listening to NewPriceReportEvent.
+	priceReportChanSize = 256
+
 	// deltaTdThreshold is the threshold of TD difference for peers to broadcast votes.
 	deltaTdThreshold = 1000
 
@@ -125,6 +131,26 @@ type votePool interface {
 	SubscribeNewVoteEvent(ch chan<- core.NewVoteEvent) event.Subscription
 }
 
+// bridgePool defines the methods needed from a bridge attestation pool to
+// exchange the attestations of the bridge relayers with the `bsc` peers.
+type bridgePool interface {
+	PutAttestation(att *types.BridgeAttestation)
+
+	// SubscribeNewAttestationEvent should return an event subscription of
+	// NewBridgeAttestationEvent and send events to the given channel.
+	SubscribeNewAttestationEvent(ch chan<- core.NewBridgeAttestationEvent) event.Subscription
+}
+
+// pricePool defines the methods needed from a price report pool to exchange
+// the price reports of the validators with the `bsc` peers.
+type pricePool interface {
+	PutReport(report *types.PriceReport)
+
+	// SubscribeNewPriceReportEvent should return an event subscription of
+	// NewPriceReportEvent and send events to the given channel.
+	SubscribeNewPriceReportEvent(ch chan<- core.NewPriceReportEvent) event.Subscription
+}
+
 // handlerConfig is the collection of initialization parameters to create a full
 // node network handler.
 type handlerConfig struct {
@@ -167,6 +193,9 @@ type handler struct {
 	database             ethdb.Database
 	txpool               txPool
 	votepool             votePool
+	bridgepool           bridgePool
+	bridgeRelay          *bridgeRelay
+	pricepool            pricePool
 	maliciousVoteMonitor *monitor.MaliciousVoteMonitor
 	chain                *core.BlockChain
 	maxPeers             int
@@ -190,6 +219,13 @@ type handler struct {
 	voteCh         chan core.NewVoteEvent
 	votesSub       event.Subscription
 	voteMonitorSub event.Subscription
+	bridgeCh       chan core.NewBridgeAttestationEvent
+	bridgeSub      event.Subscription
+	priceCh        chan core.NewPriceReportEvent
+	priceSub       event.Subscription
+	privateTxs     *privateTxRelay
+	privateHeadCh  chan core.ChainHeadEvent
+	privateHeadSub event.Subscription
 
 	requiredBlocks map[uint64]common.Hash
 
@@ -237,6 +273,7 @@ func newHandler(config *handlerConfig) (*handler, error) {
 		handlerStartCh:             make(chan struct{}),
 		stopCh:                     make(chan struct{}),
 	}
+	h.privateTxs = newPrivateTxRelay(h.chain, h.peers, h.txpool, h.inTurnValidatorNodeIDs)
 	for _, nodeID := range config.EVNNodeIdsWhitelist {
 		h.evnNodeIdsWhitelistMap[nodeID] = struct{}{}
 	}
@@ -475,7 +512,7 @@ func (h *handler) runEthPeer(peer *eth.Peer, handler eth.Handler) error {
 		peer.Log().Error("Bsc extension barrier failed", "err", err)
 		return err
 	}
-	if bscExt != nil && bscExt.Version() == bsc.Bsc3 {
+	if bscExt != nil && bscExt.Version() >= bsc.Bsc3 {
 		peer.CanHandleBAL.Store(true)
 		log.Debug("runEthPeer", "bscExt.Version", bscExt.Version(), "CanHandleBAL", peer.CanHandleBAL.Load())
 	}
@@ -742,6 +779,28 @@ func (h *handler) Start(maxPeers int, maxPeersPerIP int) {
 		}
 	}
 
+	// broadcast bridge attestations
+	if h.bridgepool != nil {
+		h.wg.Add(1)
+		h.bridgeCh = make(chan core.NewBridgeAttestationEvent, bridgeAttestationChanSize)
+		h.bridgeSub = h.bridgepool.SubscribeNewAttestationEvent(h.bridgeCh)
+		go h.bridgeAttestationBroadcastLoop()
+	}
+
+	// broadcast price reports
+	if h.pricepool != nil {
+		h.wg.Add(1)
+		h.priceCh = make(chan core.NewPriceReportEvent, priceReportChanSize)
+		h.priceSub = h.pricepool.SubscribeNewPriceReportEvent(h.priceCh)
+		go h.priceReportBroadcastLoop()
+	}
+
+	// relay private transactions to the validators in turn
+	h.wg.Add(1)
+	h.privateHeadCh = make(chan core.ChainHeadEvent, chainHeadChanSize)
+	h.privateHeadSub = h.chain.SubscribeChainHeadEvent(h.privateHeadCh)
+	go h.privateTxLoop()
+
 	// announce local pending transactions again
 	h.wg.Add(1)
 	h.reannoTxsCh = make(chan core.ReannoTxsEvent, txChanSize)
@@ -787,14 +846,21 @@ func (h *handler) startMaliciousVoteMonitor() {
 func (h *handler) Stop() {
 	h.txsSub.Unsubscribe() // quits txBroadcastLoop
 	h.blockRange.stop()
-	h.reannoTxsSub.Unsubscribe()  // quits txReannounceLoop
-	h.minedBlockSub.Unsubscribe() // quits blockBroadcastLoop
+	h.reannoTxsSub.Unsubscribe()   // quits txReannounceLoop
+	h.privateHeadSub.Unsubscribe() // quits privateTxLoop
+	h.minedBlockSub.Unsubscribe()  // quits blockBroadcastLoop
 	if h.votepool != nil {
 		h.votesSub.Unsubscribe() // quits voteBroadcastLoop
 		if h.maliciousVoteMonitor != nil {
 			h.voteMonitorSub.Unsubscribe()
 		}
 	}
+	if h.bridgepool != nil {
+		h.bridgeSub.Unsubscribe() // quits bridgeAttestationBroadcastLoop
+	}
+	if h.pricepool != nil {
+		h.priceSub.Unsubscribe() // quits priceReportBroadcastLoop
+	}
 	close(h.stopCh)
 	// Quit chainSync and txsync64.
 	// After this is done, no new peers will be accepted.
@@ -948,11 +1014,36 @@ func (h *handler) queryValidatorNodeIDsMap() map[common.Address][]enode.ID {
 	return nodeIDsMap
 }
 
+// inTurnValidatorNodeIDs returns the node IDs of the validators in turn to
+// propose the blocks after head.
+func (h *handler) inTurnValidatorNodeIDs(head *types.Header) ([]enode.ID, error) {
+	parlia, ok := h.chain.Engine().(*parlia.Parlia)
+	if !ok {
+		return nil, errPrivateTxsNoParlia
+	}
+	validators, err := parlia.InTurnValidators(h.chain, head)
+	if err != nil {
+		return nil, err
+	}
+	nodeIDsMap, err := parlia.GetNodeIDsForValidators(validators)
+	if err != nil {
+		return nil, err
+	}
+	var nodeIDs []enode.ID
+	for _, validator := range validators {
+		nodeIDs = append(nodeIDs, nodeIDsMap[validator]...)
+	}
+	return nodeIDs, nil
+}
+
 // BroadcastTransactions will propagate a batch of transactions
 // - To a square root of all peers for non-blob transactions
 // - And, separately, as announcements to all peers which are not known to
 // already have the given transaction.
 func (h *handler) BroadcastTransactions(txs types.Transactions) {
+	// Transactions relayed privately are never propagated
+	txs = h.privateTxs.public(txs)
+
 	var (
 		blobTxs  int // Number of blob transactions to announce only
 		largeTxs int // Number of large transactions to announce only
@@ -1064,6 +1155,26 @@ func (h *handler) BroadcastVote(vote *types.VoteEnvelope) {
 	log.Debug("Vote broadcast", "vote packs", directPeers, "broadcast vote", directCount)
 }
 
+// BroadcastBridgeAttestation propagates a bridge attestation to all `bsc` peers
+// which are not known to already have it.
+func (h *handler) BroadcastBridgeAttestation(att *types.BridgeAttestation) {
+	peers := h.peers.peersWithoutBridgeAttestation(att.Hash())
+	for _, peer := range peers {
+		peer.bscExt.AsyncSendBridgeAttestations([]*types.BridgeAttestation{att})
+	}
+	log.Debug("Bridge attestation broadcast", "digest", att.Digest, "recipients", len(peers))
+}
+
+// BroadcastPriceReport propagates a price report to all `bsc` peers which are
+// not known to already have it.
+func (h *handler) BroadcastPriceReport(report *types.PriceReport) {
+	peers := h.peers.peersWithoutPriceReport(report.Hash())
+	for _, peer := range peers {
+		peer.bscExt.AsyncSendPriceReports([]*types.PriceReport{report})
+	}
+	log.Debug("Price report broadcast", "round", report.Round, "recipients", len(peers))
+}
+
 // minedBroadcastLoop sends mined blocks to connected peers.
 func (h *handler) minedBroadcastLoop() {
 	defer h.wg.Done()
@@ -1115,6 +1226,20 @@ func (h *handler) txReannounceLoop() {
 	}
 }
 
+// privateTxLoop relays the private transactions to the validators in turn
+// whenever the head changes.
+func (h *handler) privateTxLoop() {
+	defer h.wg.Done()
+	for {
+		select {
+		case event := <-h.privateHeadCh:
+			h.privateTxs.newHead(event.Header)
+		case <-h.privateHeadSub.Err():
+			return
+		}
+	}
+}
+
 // voteBroadcastLoop announces new vote to connected peers.
 func (h *handler) voteBroadcastLoop() {
 	defer h.wg.Done()
@@ -1130,6 +1255,32 @@ func (h *handler) voteBroadcastLoop() {
 	}
 }
 
+// bridgeAttestationBroadcastLoop announces new bridge attestations to connected peers.
+func (h *handler) bridgeAttestationBroadcastLoop() {
+	defer h.wg.Done()
+	for {
+		select {
+		case event := <-h.bridgeCh:
+			h.BroadcastBridgeAttestation(event.Attestation)
+		case <-h.bridgeSub.Err():
+			return
+		}
+	}
+}
+
+// priceReportBroadcastLoop announces new price reports to connected peers.
+func (h *handler) priceReportBroadcastLoop() {
+	defer h.wg.Done()
+	for {
+		select {
+		case event := <-h.priceCh:
+			h.BroadcastPriceReport(event.Report)
+		case <-h.priceSub.Err():
+			return
+		}
+	}
+}
+
 // enableSyncedFeatures enables the post-sync functionalities when the initial
 // sync is finished.
 func (h *handler) enableSyncedFeatures() {
@@ -9,6 +9,14 @@ import (
 	"github.com/ethereum/go-ethereum/p2p/enode"
 )
 
+// maxBridgeAttestationsPerPacket is the maximum number of bridge attestations
+// accepted in a single packet, which are broadcast one at a time.
+const maxBridgeAttestationsPerPacket = 64
+
+// maxPriceReportsPerPacket is the maximum number of price reports accepted in
+// a single packet, which are broadcast one at a time.
+const maxPriceReportsPerPacket = 64
+
 // bscHandler implements the bsc.Backend interface to handle the various network
 // packets that are sent as broadcasts.
 type bscHandler handler
@@ -42,6 +50,15 @@ func (h *bscHandler) Handle(peer *bsc.Peer, packet bsc.Packet) error {
 	case *bsc.VotesPacket:
 		return h.handleVotesBroadcast(peer, packet.Votes)
 
+	case *bsc.BridgeAttestationsPacket:
+		return h.handleBridgeAttestationsBroadcast(peer, packet.Attestations)
+
+	case *bsc.PriceReportsPacket:
+		return h.handlePriceReportsBroadcast(peer, packet.Reports)
+
+	case *bsc.PrivateTransactionsPacket:
+		return h.handlePrivateTransactions(peer, packet.Txs)
+
 	default:
 		return fmt.Errorf("unexpected bsc packet type: %T", packet)
 	}
@@ -61,3 +78,51 @@ func (h *bscHandler) handleVotesBroadcast(peer *bsc.Peer, votes []*types.VoteEnv
 
 	return nil
 }
+
+// handleBridgeAttestationsBroadcast is invoked from a peer's message handler when
+// it transmits a bridge attestations broadcast for the local node to process.
+func (h *bscHandler) handleBridgeAttestationsBroadcast(peer *bsc.Peer, atts []*types.BridgeAttestation) error {
+	if len(atts) > maxBridgeAttestationsPerPacket {
+		return fmt.Errorf("too many bridge attestations: %d", len(atts))
+	}
+	switch {
+	case h.bridgepool != nil:
+		// The pool announces the new attestations to be broadcast
+		for _, att := range atts {
+			h.bridgepool.PutAttestation(att)
+		}
+	case h.bridgeRelay != nil:
+		// Without a relayer, the attestations of the committee are passed on
+		for _, att := range atts {
+			if h.bridgeRelay.accept(att) {
+				(*handler)(h).BroadcastBridgeAttestation(att)
+			}
+		}
+	}
+	return nil
+}
+
+// handlePriceReportsBroadcast is invoked from a peer's message handler when it
+// transmits a price reports broadcast for the local node to process.
+func (h *bscHandler) handlePriceReportsBroadcast(peer *bsc.Peer, reports []*types.PriceReport) error {
+	if h.pricepool == nil {
+		return nil
+	}
+	if len(reports) > maxPriceReportsPerPacket {
+		return fmt.Errorf("too many price reports: %d", len(reports))
+	}
+	for _, report := range reports {
+		h.pricepool.PutReport(report)
+	}
+	return nil
+}
+
+// handlePrivateTransactions is invoked from a peer's message handler when it
+// relays transactions privately to the local node, which are pooled without
+// being broadcast.
+func (h *bscHandler) handlePrivateTransactions(peer *bsc.Peer, txs []*types.Transaction) error {
+	if !h.acceptTxs.Load() {
+		return nil
+	}
+	return h.privateTxs.receive(txs)
+}
@@ -20,6 +20,7 @@ import (
 	"errors"
 	"fmt"
 	"math/big"
+	"slices"
 	"sync"
 	"time"
 
@@ -461,6 +462,20 @@ func (ps *peerSet) allNonEVNPeers() []*ethPeer {
 	return nonEVNPeers
 }
 
+// peersByNodeIDs retrieves the registered peers with the given node IDs.
+func (ps *peerSet) peersByNodeIDs(nodeIDs []enode.ID) []*ethPeer {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	list := make([]*ethPeer, 0, len(nodeIDs))
+	for _, id := range nodeIDs {
+		if p, ok := ps.peers[id.String()]; ok && !slices.Contains(list, p) {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
 // peersWithoutVote retrieves a list of peers that do not have a given
 // vote in their set of known hashes.
 func (ps *peerSet) peersWithoutVote(hash common.Hash) []*ethPeer {
@@ -476,6 +491,37 @@ func (ps *peerSet) peersWithoutVote(hash common.Hash) []*ethPeer {
 	return list
 }
 
+// peersWithoutBridgeAttestation retrieves a list of `bsc` peers supporting
+// bridge attestations that do not have the given one in their set of known
+// hashes.
+func (ps *peerSet) peersWithoutBridgeAttestation(hash common.Hash) []*ethPeer {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	list := make([]*ethPeer, 0, len(ps.peers))
+	for _, p := range ps.peers {
+		if p.bscExt != nil && p.bscExt.Version() >= bsc.Bsc4 && !p.bscExt.KnownBridgeAttestation(hash) {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
+// peersWithoutPriceReport retrieves a list of `bsc` peers supporting price
+// reports that do not have the given one in their set of known hashes.
+func (ps *peerSet) peersWithoutPriceReport(hash common.Hash) []*ethPeer {
+	ps.lock.RLock()
+	defer ps.lock.RUnlock()
+
+	list := make([]*ethPeer, 0, len(ps.peers))
+	for _, p := range ps.peers {
+		if p.bscExt != nil && p.bscExt.Version() >= bsc.Bsc5 && !p.bscExt.KnownPriceReport(hash) {
+			list = append(list, p)
+		}
+	}
+	return list
+}
+
 // len returns if the current number of `eth` peers in the set. Since the `snap`
 // peers are tied to the existence of an `eth` connection, that will always be a
 // subset of `eth`.
@@ -0,0 +1,236 @@
+package eth
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/protocols/bsc"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+)
+
+const (
+	// maxPrivateTxs is the maximum number of private transactions kept out of
+	// the public transaction pool at any time.
+	maxPrivateTxs = 1024
+
+	// maxPrivateTxBlocks is the maximum number of blocks a transaction can be
+	// kept private for.
+	maxPrivateTxBlocks = 100
+
+	// maxReceivedPrivateTxs is the maximum number of privately received
+	// transactions remembered to keep them out of the broadcast.
+	maxReceivedPrivateTxs = 4096
+)
+
+var (
+	errPrivateTxsFull     = errors.New("too many private transactions")
+	errPrivateTxDeadline  = errors.New("invalid private transaction deadline")
+	errPrivateBlobTx      = errors.New("blob transactions cannot be sent privately")
+	errPrivateTxsNoParlia = errors.New("private transactions require the parlia engine")
+	errPrivateTxsTooMany  = errors.New("too many private transactions in packet")
+)
+
+// privateTx is a transaction kept out of the public transaction pool.
+type privateTx struct {
+	tx       *types.Transaction
+	from     common.Address
+	deadline uint64 // Last block the transaction is kept private for
+}
+
+// privateTxRelay keeps privately submitted transactions out of the public
+// transaction pool, forwarding them over direct connections to the validators
+// in turn to propose the next blocks only. Transactions not included by their
+// deadline are added to the transaction pool and broadcast publicly.
+//
+// On the receiving side, the transactions relayed privately are added to the
+// transaction pool, but kept out of the broadcast.
+type privateTxRelay struct {
+	chain   *core.BlockChain
+	peers   *peerSet
+	txpool  txPool
+	resolve func(head *types.Header) ([]enode.ID, error) // Node IDs of the validators in turn after head
+
+	txs  map[common.Hash]*privateTx
+	lock sync.Mutex
+
+	received *lru.Cache[common.Hash, struct{}] // Transactions received privately
+}
+
+func newPrivateTxRelay(chain *core.BlockChain, peers *peerSet, txpool txPool, resolve func(head *types.Header) ([]enode.ID, error)) *privateTxRelay {
+	return &privateTxRelay{
+		chain:   chain,
+		peers:   peers,
+		txpool:  txpool,
+		resolve: resolve,
+		txs:     make(map[common.Hash]*privateTx),
+
+		received: lru.NewCache[common.Hash, struct{}](maxReceivedPrivateTxs),
+	}
+}
+
+// add keeps tx private until the deadline block and forwards it to the
+// validators in turn.
+func (r *privateTxRelay) add(tx *types.Transaction, deadline uint64) error {
+	head := r.chain.CurrentBlock()
+	if number := head.Number.Uint64(); deadline <= number || deadline > number+maxPrivateTxBlocks {
+		return fmt.Errorf("%w: block %d, head %d", errPrivateTxDeadline, deadline, number)
+	}
+	if tx.Type() == types.BlobTxType {
+		return errPrivateBlobTx
+	}
+	from, err := types.Sender(types.MakeSigner(r.chain.Config(), head.Number, head.Time), tx)
+	if err != nil {
+		return err
+	}
+	statedb, err := r.chain.StateAt(head.Root)
+	if err != nil {
+		return err
+	}
+	if nonce := statedb.GetNonce(from); tx.Nonce() < nonce {
+		return fmt.Errorf("%w: address %v, tx: %d state: %d", core.ErrNonceTooLow, from, tx.Nonce(), nonce)
+	}
+	if balance := statedb.GetBalance(from).ToBig(); balance.Cmp(tx.Cost()) < 0 {
+		return fmt.Errorf("%w: address %v have %v want %v", core.ErrInsufficientFunds, from, balance, tx.Cost())
+	}
+	if r.txpool.Has(tx.Hash()) {
+		return txpool.ErrAlreadyKnown
+	}
+	r.lock.Lock()
+	if _, ok := r.txs[tx.Hash()]; ok {
+		r.lock.Unlock()
+		return txpool.ErrAlreadyKnown
+	}
+	if len(r.txs) >= maxPrivateTxs {
+		r.lock.Unlock()
+		return errPrivateTxsFull
+	}
+	r.txs[tx.Hash()] = &privateTx{tx: tx, from: from, deadline: deadline}
+	r.lock.Unlock()
+
+	r.relay(head, types.Transactions{tx})
+	return nil
+}
+
+// newHead drops the private transactions included up to head, publishes the
+// ones past their deadline and forwards the rest to the validators in turn.
+func (r *privateTxRelay) newHead(head *types.Header) {
+	r.lock.Lock()
+	if len(r.txs) == 0 {
+		r.lock.Unlock()
+		return
+	}
+	statedb, err := r.chain.StateAt(head.Root)
+	if err != nil {
+		r.lock.Unlock()
+		log.Debug("Failed to open state for private transactions", "number", head.Number, "err", err)
+		return
+	}
+	var pending, expired types.Transactions
+	for hash, ptx := range r.txs {
+		switch {
+		case ptx.tx.Nonce() < statedb.GetNonce(ptx.from):
+			delete(r.txs, hash) // Included or replaced
+		case head.Number.Uint64() >= ptx.deadline:
+			delete(r.txs, hash)
+			expired = append(expired, ptx.tx)
+		default:
+			pending = append(pending, ptx.tx)
+		}
+	}
+	r.lock.Unlock()
+
+	if len(expired) > 0 {
+		for i, err := range r.txpool.Add(expired, false) {
+			if err != nil {
+				log.Debug("Failed to publish private transaction", "hash", expired[i].Hash(), "err", err)
+			}
+		}
+		log.Info("Published private transactions past deadline", "number", head.Number, "count", len(expired))
+	}
+	if len(pending) > 0 {
+		// Keep the transactions of a sender in nonce order
+		slices.SortFunc(pending, func(a, b *types.Transaction) int {
+			return cmp.Compare(a.Nonce(), b.Nonce())
+		})
+		r.relay(head, pending)
+	}
+}
+
+// relay sends txs directly to the connected validators in turn after head
+// which do not know them yet.
+func (r *privateTxRelay) relay(head *types.Header, txs types.Transactions) {
+	nodeIDs, err := r.resolve(head)
+	if err != nil {
+		log.Debug("Failed to resolve validators for private transactions", "number", head.Number, "err", err)
+		return
+	}
+	var peers []*ethPeer
+	for _, peer := range r.peers.peersByNodeIDs(nodeIDs) {
+		if peer.bscExt != nil && peer.bscExt.Version() >= bsc.Bsc6 {
+			peers = append(peers, peer)
+		}
+	}
+	if len(peers) == 0 {
+		log.Debug("No validator connected for private transactions", "number", head.Number, "txs", len(txs))
+		return
+	}
+	for _, peer := range peers {
+		var unknown types.Transactions
+		for _, tx := range txs {
+			if !peer.KnownTransaction(tx.Hash()) && !peer.bscExt.KnownPrivateTransaction(tx.Hash()) {
+				unknown = append(unknown, tx)
+			}
+		}
+		if len(unknown) == 0 {
+			continue
+		}
+		if err := peer.bscExt.SendPrivateTransactions(unknown); err != nil {
+			log.Debug("Failed to send private transactions", "peer", peer.ID(), "err", err)
+		}
+	}
+	log.Debug("Relayed private transactions", "number", head.Number, "txs", len(txs), "peers", len(peers))
+}
+
+// receive adds the transactions relayed privately by a peer to the transaction
+// pool, remembering them to keep them out of the broadcast.
+func (r *privateTxRelay) receive(txs types.Transactions) error {
+	if len(txs) > maxPrivateTxs {
+		return fmt.Errorf("%w: %d", errPrivateTxsTooMany, len(txs))
+	}
+	for _, tx := range txs {
+		if tx.Type() == types.BlobTxType {
+			return errPrivateBlobTx
+		}
+	}
+	// Remember the transactions before adding them, as the broadcast is
+	// triggered by the pool asynchronously
+	for _, tx := range txs {
+		r.received.Add(tx.Hash(), struct{}{})
+	}
+	for i, err := range r.txpool.Add(txs, false) {
+		if err != nil {
+			log.Debug("Failed to add private transaction", "hash", txs[i].Hash(), "err", err)
+		}
+	}
+	return nil
+}
+
+// public returns the transactions of txs which were not received privately.
+func (r *privateTxRelay) public(txs types.Transactions) types.Transactions {
+	var public types.Transactions
+	for _, tx := range txs {
+		if !r.received.Contains(tx.Hash()) {
+			public = append(public, tx)
+		}
+	}
+	return public
+}
@@ -0,0 +1,160 @@
+package eth
+
+import (
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/protocols/bsc"
+	"github.com/ethereum/go-ethereum/eth/protocols/eth"
+	"github.com/ethereum/go-ethereum/p2p"
+	"github.com/ethereum/go-ethereum/p2p/enode"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// connectPrivateTxPeers links two handlers over the `eth` and `bsc` protocols.
+func connectPrivateTxPeers(t *testing.T, a, b *testHandler, aID, bID enode.ID) {
+	var (
+		protos = []p2p.Protocol{{Name: eth.ProtocolName, Version: eth.ETH68}, {Name: bsc.ProtocolName, Version: bsc.Bsc6}}
+		caps   = []p2p.Cap{{Name: eth.ProtocolName, Version: eth.ETH68}, {Name: bsc.ProtocolName, Version: bsc.Bsc6}}
+	)
+	ethA, ethB := p2p.MsgPipe()
+	bscA, bscB := p2p.MsgPipe()
+	t.Cleanup(func() {
+		ethA.Close()
+		ethB.Close()
+		bscA.Close()
+		bscB.Close()
+	})
+	run := func(local *testHandler, remoteID enode.ID, ethRW, bscRW p2p.MsgReadWriter) {
+		ethPeer := eth.NewPeer(eth.ETH68, p2p.NewPeerWithProtocols(remoteID, protos, "", caps), ethRW, local.txpool)
+		bscPeer := bsc.NewPeer(bsc.Bsc6, p2p.NewPeerWithProtocols(remoteID, protos, "", caps), bscRW)
+		t.Cleanup(ethPeer.Close)
+		t.Cleanup(bscPeer.Close)
+
+		go local.handler.runBscExtension(bscPeer, func(peer *bsc.Peer) error {
+			return bsc.Handle((*bscHandler)(local.handler), peer)
+		})
+		go local.handler.runEthPeer(ethPeer, func(peer *eth.Peer) error {
+			return eth.Handle((*ethHandler)(local.handler), peer)
+		})
+	}
+	run(a, bID, ethA, bscA)
+	run(b, aID, ethB, bscB)
+}
+
+// Tests that private transactions are only sent to the validators in turn,
+// which keep them out of the broadcast, and broadcast publicly once their
+// deadline passes.
+func TestPrivateTransactions(t *testing.T) {
+	t.Parallel()
+
+	source := newTestHandler()
+	source.handler.snapSync.Store(false)
+	t.Cleanup(source.close)
+
+	var (
+		sourceID    = enode.ID{0}
+		validatorID = enode.ID{1}
+		regularID   = enode.ID{2}
+	)
+	source.handler.privateTxs.resolve = func(head *types.Header) ([]enode.ID, error) {
+		return []enode.ID{validatorID}, nil
+	}
+	// Connect the validator and a regular node to the source and each other
+	var (
+		validator = newTestHandler()
+		regular   = newTestHandler()
+		sinks     = []*testHandler{validator, regular}
+		txChs     = make([]chan core.NewTxsEvent, len(sinks))
+	)
+	for i, sink := range sinks {
+		t.Cleanup(sink.close)
+		sink.handler.acceptTxs.Store(true) // mark synced to accept transactions
+
+		txChs[i] = make(chan core.NewTxsEvent, 1)
+		sub := sink.txpool.SubscribeTransactions(txChs[i], false)
+		defer sub.Unsubscribe()
+	}
+	connectPrivateTxPeers(t, source, validator, sourceID, validatorID)
+	connectPrivateTxPeers(t, source, regular, sourceID, regularID)
+	connectPrivateTxPeers(t, validator, regular, validatorID, regularID)
+
+	for source.handler.peers.len() < 2 || validator.handler.peers.len() < 2 || regular.handler.peers.len() < 2 {
+		time.Sleep(10 * time.Millisecond)
+	}
+	tx, _ := types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(0), params.TxGas, big.NewInt(1), nil), types.HomesteadSigner{}, testKey)
+
+	head := source.chain.CurrentBlock()
+	deadline := head.Number.Uint64() + 2
+	if err := source.handler.privateTxs.add(tx, head.Number.Uint64()); !errors.Is(err, errPrivateTxDeadline) {
+		t.Fatalf("past deadline error mismatch: have %v, want %v", err, errPrivateTxDeadline)
+	}
+	if err := source.handler.privateTxs.add(tx, deadline); err != nil {
+		t.Fatalf("failed to add private transaction: %v", err)
+	}
+	select {
+	case event := <-txChs[0]:
+		if len(event.Txs) != 1 || event.Txs[0].Hash() != tx.Hash() {
+			t.Fatalf("validator received unexpected transactions: %v", event.Txs)
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("validator did not receive private transaction")
+	}
+	// Neither the source nor the validator may propagate it
+	select {
+	case <-txChs[1]:
+		t.Fatal("private transaction leaked to regular node")
+	case <-time.After(250 * time.Millisecond):
+	}
+	if source.txpool.Has(tx.Hash()) {
+		t.Fatal("private transaction added to public pool")
+	}
+	// Pass the deadline without inclusion and ensure the transaction is broadcast
+	expired := types.CopyHeader(head)
+	expired.Number = new(big.Int).SetUint64(deadline)
+	source.handler.privateTxs.newHead(expired)
+
+	if !source.txpool.Has(tx.Hash()) {
+		t.Fatal("expired private transaction not added to public pool")
+	}
+	select {
+	case <-txChs[1]:
+	case <-time.After(2 * time.Second):
+		t.Fatal("expired private transaction not broadcast")
+	}
+}
+
+// Tests that transactions received privately are pooled, but never broadcast,
+// unlike the same transactions received regularly.
+func TestReceivePrivateTransactions(t *testing.T) {
+	t.Parallel()
+
+	validator := newTestHandler()
+	defer validator.close()
+	validator.handler.acceptTxs.Store(true)
+
+	var (
+		private, _ = types.SignTx(types.NewTransaction(0, common.Address{}, big.NewInt(0), params.TxGas, big.NewInt(1), nil), types.HomesteadSigner{}, testKey)
+		public, _  = types.SignTx(types.NewTransaction(1, common.Address{}, big.NewInt(0), params.TxGas, big.NewInt(1), nil), types.HomesteadSigner{}, testKey)
+	)
+	if err := validator.handler.privateTxs.receive(types.Transactions{private}); err != nil {
+		t.Fatalf("failed to receive private transaction: %v", err)
+	}
+	if !validator.txpool.Has(private.Hash()) {
+		t.Fatal("private transaction not pooled")
+	}
+	have := validator.handler.privateTxs.public(types.Transactions{private, public})
+	if len(have) != 1 || have[0].Hash() != public.Hash() {
+		t.Fatalf("broadcast transactions mismatch: have %v, want %v", have, public.Hash())
+	}
+	// Blob transactions are never relayed privately
+	blob := types.NewTx(&types.BlobTx{})
+	if err := validator.handler.privateTxs.receive(types.Transactions{blob}); !errors.Is(err, errPrivateBlobTx) {
+		t.Fatalf("blob transaction error mismatch: have %v, want %v", err, errPrivateBlobTx)
+	}
+}
@@ -17,6 +17,18 @@ import (
 
 const MaxRequestRangeBlocksCount = 64
 
+// maxConcurrentWitnesses is the maximum number of witnesses generated at the
+// same time for the remote peers, each requiring the block to be re-executed.
+const maxConcurrentWitnesses = 2
+
+// maxWitnessBlockAge is the maximum distance from the chain head of the blocks
+// the witnesses are generated for, older ones are not served.
+const maxWitnessBlockAge = 128
+
+// witnessSlots limits the witness generation across all the peers, requests
+// beyond the limit are replied without a witness.
+var witnessSlots = make(chan struct{}, maxConcurrentWitnesses)
+
 // Handler is a callback to invoke from an outside runner after the boilerplate
 // exchanges have passed.
 type Handler func(peer *Peer) error
@@ -96,6 +108,45 @@ var bsc2 = map[uint64]msgHandler{
 	BlocksByRangeMsg:    handleBlocksByRange,
 }
 
+var bsc4 = map[uint64]msgHandler{
+	BscCapMsg:             handleBscCap, // ignore capability message for backward compatibility
+	VotesMsg:              handleVotes,
+	GetBlocksByRangeMsg:   handleGetBlocksByRange,
+	BlocksByRangeMsg:      handleBlocksByRange,
+	BridgeAttestationsMsg: handleBridgeAttestations,
+}
+
+var bsc5 = map[uint64]msgHandler{
+	BscCapMsg:             handleBscCap, // ignore capability message for backward compatibility
+	VotesMsg:              handleVotes,
+	GetBlocksByRangeMsg:   handleGetBlocksByRange,
+	BlocksByRangeMsg:      handleBlocksByRange,
+	BridgeAttestationsMsg: handleBridgeAttestations,
+	PriceReportsMsg:       handlePriceReports,
+}
+
+var bsc6 = map[uint64]msgHandler{
+	BscCapMsg:              handleBscCap, // ignore capability message for backward compatibility
+	VotesMsg:               handleVotes,
+	GetBlocksByRangeMsg:    handleGetBlocksByRange,
+	BlocksByRangeMsg:       handleBlocksByRange,
+	BridgeAttestationsMsg:  handleBridgeAttestations,
+	PriceReportsMsg:        handlePriceReports,
+	PrivateTransactionsMsg: handlePrivateTransactions,
+}
+
+var bsc7 = map[uint64]msgHandler{
+	BscCapMsg:              handleBscCap, // ignore capability message for backward compatibility
+	VotesMsg:               handleVotes,
+	GetBlocksByRangeMsg:    handleGetBlocksByRange,
+	BlocksByRangeMsg:       handleBlocksByRange,
+	BridgeAttestationsMsg:  handleBridgeAttestations,
+	PriceReportsMsg:        handlePriceReports,
+	PrivateTransactionsMsg: handlePrivateTransactions,
+	GetWitnessMsg:          handleGetWitness,
+	WitnessMsg:             handleWitness,
+}
+
 // handleBscCap ignores the capability message for backward compatibility.
 // Old nodes send BscCapMsg as part of their handshake, we just ignore it
 // since P2P layer already negotiated the protocol version.
@@ -123,7 +174,15 @@ func handleMessage(backend Backend, peer *Peer) error {
 	defer msg.Discard()
 
 	var handlers = bsc1
-	if peer.Version() >= Bsc2 {
+	if peer.Version() >= Bsc7 {
+		handlers = bsc7
+	} else if peer.Version() >= Bsc6 {
+		handlers = bsc6
+	} else if peer.Version() >= Bsc5 {
+		handlers = bsc5
+	} else if peer.Version() >= Bsc4 {
+		handlers = bsc4
+	} else if peer.Version() >= Bsc2 {
 		handlers = bsc2
 	}
 
@@ -155,6 +214,33 @@ func handleVotes(backend Backend, msg Decoder, peer *Peer) error {
 	return backend.Handle(peer, ann)
 }
 
+func handleBridgeAttestations(backend Backend, msg Decoder, peer *Peer) error {
+	ann := new(BridgeAttestationsPacket)
+	if err := msg.Decode(ann); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	peer.markBridgeAttestations(ann.Attestations)
+	return backend.Handle(peer, ann)
+}
+
+func handlePriceReports(backend Backend, msg Decoder, peer *Peer) error {
+	ann := new(PriceReportsPacket)
+	if err := msg.Decode(ann); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	peer.markPriceReports(ann.Reports)
+	return backend.Handle(peer, ann)
+}
+
+func handlePrivateTransactions(backend Backend, msg Decoder, peer *Peer) error {
+	ann := new(PrivateTransactionsPacket)
+	if err := msg.Decode(ann); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	peer.markPrivateTransactions(ann.Txs)
+	return backend.Handle(peer, ann)
+}
+
 func handleGetBlocksByRange(backend Backend, msg Decoder, peer *Peer) error {
 	req := new(GetBlocksByRangePacket)
 	if err := msg.Decode(req); err != nil {
@@ -212,6 +298,49 @@ func handleBlocksByRange(backend Backend, msg Decoder, peer *Peer) error {
 	return nil
 }
 
+func handleGetWitness(backend Backend, msg Decoder, peer *Peer) error {
+	req := new(GetWitnessPacket)
+	if err := msg.Decode(req); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	res := &WitnessPacket{RequestId: req.RequestId}
+	if peer.isWitnessOverLimit() {
+		log.Debug("Witness requests over limit", "from", peer.id, "hash", req.BlockHash)
+		return p2p.Send(peer.rw, WitnessMsg, res)
+	}
+	header := backend.Chain().GetHeaderByHash(req.BlockHash)
+	if header == nil || header.Number.Uint64()+maxWitnessBlockAge < backend.Chain().CurrentBlock().Number.Uint64() {
+		log.Debug("Witness unavailable for the block", "from", peer.id, "hash", req.BlockHash)
+		return p2p.Send(peer.rw, WitnessMsg, res)
+	}
+	select {
+	case witnessSlots <- struct{}{}:
+		witness, err := backend.Chain().GenerateWitness(req.BlockHash)
+		<-witnessSlots
+		if err != nil {
+			log.Debug("Failed to generate witness", "from", peer.id, "hash", req.BlockHash, "err", err)
+		}
+		res.Witness = witness
+	default:
+		log.Debug("Witness generation busy", "from", peer.id, "hash", req.BlockHash)
+	}
+	return p2p.Send(peer.rw, WitnessMsg, res)
+}
+
+func handleWitness(backend Backend, msg Decoder, peer *Peer) error {
+	res := new(WitnessPacket)
+	if err := msg.Decode(res); err != nil {
+		return fmt.Errorf("%w: message %v: %v", errDecode, msg, err)
+	}
+	err := peer.dispatcher.DispatchResponse(&Response{
+		requestID: res.RequestId,
+		data:      res,
+		code:      WitnessMsg,
+	})
+	log.Debug("receive Witness response", "from", peer.id, "requestId", res.RequestId, "found", res.Witness != nil, "err", err)
+	return nil
+}
+
 // NodeInfo represents a short summary of the `bsc` sub-protocol metadata
 // known about the host peer.
 type NodeInfo struct{}
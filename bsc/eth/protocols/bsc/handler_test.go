@@ -3,6 +3,7 @@ package bsc
 import (
 	"math/big"
 	"testing"
+	"time"
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core"
@@ -247,3 +248,22 @@ func TestHandleBlocksByRange(t *testing.T) {
 		})
 	}
 }
+
+func TestWitnessRequestLimit(t *testing.T) {
+	peer := newMockPeer().Peer
+	peer.witnessPeriodBegin = time.Now()
+
+	for i := 0; i < witnessRequestsPerPeriod; i++ {
+		if peer.isWitnessOverLimit() {
+			t.Fatalf("request %d over limit", i)
+		}
+	}
+	if !peer.isWitnessOverLimit() {
+		t.Fatal("request beyond the limit served")
+	}
+	// The limit is reset in the next period
+	peer.witnessPeriodBegin = time.Now().Add(-time.Duration(secondsPerPeriod) * time.Second)
+	if peer.isWitnessOverLimit() {
+		t.Fatal("request over limit in the next period")
+	}
+}
@@ -7,6 +7,7 @@ import (
 
 	mapset "github.com/deckarep/golang-set/v2"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/stateless"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/p2p"
@@ -20,6 +21,26 @@ const (
 	// voteBufferSize is the maximum number of batch votes can be hold before sending
 	voteBufferSize = 21 * 2
 
+	// maxKnownAttestations is the maximum bridge attestation hashes to keep in
+	// the known list before starting to randomly evict them.
+	maxKnownAttestations = 4096
+
+	// attestationBufferSize is the maximum number of batch bridge attestations
+	// can be hold before sending
+	attestationBufferSize = 64
+
+	// maxKnownPriceReports is the maximum price report hashes to keep in the
+	// known list before starting to randomly evict them.
+	maxKnownPriceReports = 1024
+
+	// priceReportBufferSize is the maximum number of batch price reports can
+	// be hold before sending
+	priceReportBufferSize = 64
+
+	// maxKnownPrivateTxs is the maximum private transaction hashes to keep in
+	// the known list before starting to randomly evict them.
+	maxKnownPrivateTxs = 1024
+
 	// used to avoid of DDOS attack
 	// It's the max number of received votes per second from one peer
 	// 21 validators exist now, so 21 votes will be produced every one block interval
@@ -28,6 +49,14 @@ const (
 
 	// the time span of one period
 	secondsPerPeriod = float64(30)
+
+	// witnessRequestTimeout is the time allowance for the remote peer to
+	// re-execute a block and reply its witness.
+	witnessRequestTimeout = 5 * time.Second
+
+	// witnessRequestsPerPeriod is the max number of witnesses generated for one
+	// peer within secondsPerPeriod, each requiring a block to be re-executed.
+	witnessRequestsPerPeriod = 16
 )
 
 // Peer is a collection of relevant information we have about a `bsc` peer.
@@ -39,6 +68,17 @@ type Peer struct {
 	periodCounter uint                       // Votes number in the latest period
 	dispatcher    *Dispatcher                // Message request-response dispatcher
 
+	knownAttestations    *knownCache                     // Set of bridge attestation hashes known to be known by this peer
+	attestationBroadcast chan []*types.BridgeAttestation // Channel used to queue bridge attestations propagation requests
+
+	knownPriceReports    *knownCache               // Set of price report hashes known to be known by this peer
+	priceReportBroadcast chan []*types.PriceReport // Channel used to queue price reports propagation requests
+
+	knownPrivateTxs *knownCache // Set of private transaction hashes known to be known by this peer
+
+	witnessPeriodBegin   time.Time // Begin time of the latest period for witness requests counting
+	witnessPeriodCounter uint      // Witness requests number in the latest period
+
 	*p2p.Peer                   // The embedded P2P package peer
 	rw        p2p.MsgReadWriter // Input/output streams for bsc
 	version   uint              // Protocol version negotiated
@@ -61,9 +101,25 @@ func NewPeer(version uint, p *p2p.Peer, rw p2p.MsgReadWriter) *Peer {
 		version:       version,
 		logger:        log.New("peer", id[:8]),
 		term:          make(chan struct{}),
+
+		knownAttestations:    newKnownCache(maxKnownAttestations),
+		attestationBroadcast: make(chan []*types.BridgeAttestation, attestationBufferSize),
+
+		knownPriceReports:    newKnownCache(maxKnownPriceReports),
+		priceReportBroadcast: make(chan []*types.PriceReport, priceReportBufferSize),
+
+		knownPrivateTxs: newKnownCache(maxKnownPrivateTxs),
+
+		witnessPeriodBegin: time.Now(),
 	}
 	peer.dispatcher = NewDispatcher(peer)
 	go peer.broadcastVotes()
+	if version >= Bsc4 {
+		go peer.broadcastBridgeAttestations()
+	}
+	if version >= Bsc5 {
+		go peer.broadcastPriceReports()
+	}
 	return peer
 }
 
@@ -124,6 +180,103 @@ func (p *Peer) AsyncSendVotes(votes []*types.VoteEnvelope) {
 	}
 }
 
+// KnownBridgeAttestation returns whether peer is known to already have a bridge
+// attestation.
+func (p *Peer) KnownBridgeAttestation(hash common.Hash) bool {
+	return p.knownAttestations.contains(hash)
+}
+
+// markBridgeAttestations marks bridge attestations as known for the peer,
+// ensuring that they will never be repropagated to this particular peer.
+func (p *Peer) markBridgeAttestations(atts []*types.BridgeAttestation) {
+	for _, att := range atts {
+		p.knownAttestations.add(att.Hash())
+	}
+}
+
+// sendBridgeAttestations propagates a batch of bridge attestations to the
+// remote peer.
+func (p *Peer) sendBridgeAttestations(atts []*types.BridgeAttestation) error {
+	p.markBridgeAttestations(atts)
+	return p2p.Send(p.rw, BridgeAttestationsMsg, &BridgeAttestationsPacket{atts})
+}
+
+// AsyncSendBridgeAttestations queues a batch of bridge attestations for
+// propagation to a remote peer. If the peer's broadcast queue is full or the
+// peer does not support them, the event is silently dropped.
+func (p *Peer) AsyncSendBridgeAttestations(atts []*types.BridgeAttestation) {
+	if p.version < Bsc4 {
+		return
+	}
+	select {
+	case p.attestationBroadcast <- atts:
+	case <-p.term:
+		p.Log().Debug("Dropping bridge attestation propagation for closed peer", "count", len(atts))
+	default:
+		p.Log().Debug("Dropping bridge attestation propagation for abnormal peer", "count", len(atts))
+	}
+}
+
+// KnownPriceReport returns whether peer is known to already have a price report.
+func (p *Peer) KnownPriceReport(hash common.Hash) bool {
+	return p.knownPriceReports.contains(hash)
+}
+
+// markPriceReports marks price reports as known for the peer, ensuring that
+// they will never be repropagated to this particular peer.
+func (p *Peer) markPriceReports(reports []*types.PriceReport) {
+	for _, report := range reports {
+		p.knownPriceReports.add(report.Hash())
+	}
+}
+
+// sendPriceReports propagates a batch of price reports to the remote peer.
+func (p *Peer) sendPriceReports(reports []*types.PriceReport) error {
+	p.markPriceReports(reports)
+	return p2p.Send(p.rw, PriceReportsMsg, &PriceReportsPacket{reports})
+}
+
+// AsyncSendPriceReports queues a batch of price reports for propagation to a
+// remote peer. If the peer's broadcast queue is full or the peer does not
+// support them, the event is silently dropped.
+func (p *Peer) AsyncSendPriceReports(reports []*types.PriceReport) {
+	if p.version < Bsc5 {
+		return
+	}
+	select {
+	case p.priceReportBroadcast <- reports:
+	case <-p.term:
+		p.Log().Debug("Dropping price report propagation for closed peer", "count", len(reports))
+	default:
+		p.Log().Debug("Dropping price report propagation for abnormal peer", "count", len(reports))
+	}
+}
+
+// KnownPrivateTransaction returns whether peer is known to already have a
+// private transaction.
+func (p *Peer) KnownPrivateTransaction(hash common.Hash) bool {
+	return p.knownPrivateTxs.contains(hash)
+}
+
+// markPrivateTransactions marks private transactions as known for the peer,
+// ensuring that they will never be resent to this particular peer.
+func (p *Peer) markPrivateTransactions(txs []*types.Transaction) {
+	for _, tx := range txs {
+		p.knownPrivateTxs.add(tx.Hash())
+	}
+}
+
+// SendPrivateTransactions sends a batch of transactions to the remote peer to
+// be kept out of its broadcast. It fails if the peer does not support them, so
+// the transactions are never sent as regular ones instead.
+func (p *Peer) SendPrivateTransactions(txs types.Transactions) error {
+	if p.version < Bsc6 {
+		return errors.New("private transactions not supported")
+	}
+	p.markPrivateTransactions(txs)
+	return p2p.Send(p.rw, PrivateTransactionsMsg, &PrivateTransactionsPacket{txs})
+}
+
 // Step into the next period when secondsPerPeriod seconds passed,
 // Otherwise, check whether the number of received votes extra (secondsPerPeriod * receiveRateLimitPerSecond)
 func (p *Peer) IsOverLimitAfterReceiving() bool {
@@ -139,6 +292,18 @@ func (p *Peer) IsOverLimitAfterReceiving() bool {
 	return p.periodCounter > uint(secondsPerPeriod*receiveRateLimitPerSecond)
 }
 
+// isWitnessOverLimit steps into the next period when secondsPerPeriod seconds
+// passed, otherwise it checks whether the peer requested more than
+// witnessRequestsPerPeriod witnesses within the period.
+func (p *Peer) isWitnessOverLimit() bool {
+	if time.Since(p.witnessPeriodBegin).Seconds() >= secondsPerPeriod {
+		p.witnessPeriodBegin = time.Now()
+		p.witnessPeriodCounter = 0
+	}
+	p.witnessPeriodCounter += 1
+	return p.witnessPeriodCounter > witnessRequestsPerPeriod
+}
+
 // broadcastVotes is a write loop that schedules votes broadcasts
 // to the remote peer. The goal is to have an async writer that does not lock up
 // node internals and at the same time rate limits queued data.
@@ -157,6 +322,40 @@ func (p *Peer) broadcastVotes() {
 	}
 }
 
+// broadcastBridgeAttestations is a write loop that schedules bridge attestation
+// broadcasts to the remote peer.
+func (p *Peer) broadcastBridgeAttestations() {
+	for {
+		select {
+		case atts := <-p.attestationBroadcast:
+			if err := p.sendBridgeAttestations(atts); err != nil {
+				return
+			}
+			p.Log().Trace("Sent bridge attestations", "count", len(atts))
+
+		case <-p.term:
+			return
+		}
+	}
+}
+
+// broadcastPriceReports is a write loop that schedules price report broadcasts
+// to the remote peer.
+func (p *Peer) broadcastPriceReports() {
+	for {
+		select {
+		case reports := <-p.priceReportBroadcast:
+			if err := p.sendPriceReports(reports); err != nil {
+				return
+			}
+			p.Log().Trace("Sent price reports", "count", len(reports))
+
+		case <-p.term:
+			return
+		}
+	}
+}
+
 // knownCache is a cache for known hashes.
 type knownCache struct {
 	hashes mapset.Set[common.Hash]
@@ -214,3 +413,33 @@ func (p *Peer) RequestBlocksByRange(startHeight uint64, startHash common.Hash, c
 
 	return ret.Blocks, nil
 }
+
+// RequestWitness sends GetWitnessMsg for the block with the given hash and
+// waits for the execution witness regenerated by the remote peer.
+func (p *Peer) RequestWitness(hash common.Hash) (*stateless.Witness, error) {
+	if p.version < Bsc7 {
+		return nil, errors.New("witness requests not supported")
+	}
+	requestID := p.dispatcher.GenRequestID()
+	res, err := p.dispatcher.DispatchRequest(&Request{
+		code:      GetWitnessMsg,
+		want:      WitnessMsg,
+		requestID: requestID,
+		data: &GetWitnessPacket{
+			RequestId: requestID,
+			BlockHash: hash,
+		},
+		timeout: witnessRequestTimeout,
+	})
+	if err != nil {
+		return nil, err
+	}
+	ret, ok := res.(*WitnessPacket)
+	if !ok {
+		return nil, errors.New("unexpected response type")
+	}
+	if ret.Witness == nil {
+		return nil, errors.New("witness unavailable")
+	}
+	return ret.Witness, nil
+}
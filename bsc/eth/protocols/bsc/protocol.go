@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/stateless"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
 )
@@ -13,6 +14,10 @@ const (
 	Bsc1 = 1
 	Bsc2 = 2
 	Bsc3 = 3 // to BAL process
+	Bsc4 = 4 // to bridge attestations
+	Bsc5 = 5 // to price reports
+	Bsc6 = 6 // to private transactions
+	Bsc7 = 7 // to execution witnesses
 )
 
 // ProtocolName is the official short name of the `bsc` protocol used during
@@ -21,11 +26,11 @@ const ProtocolName = "bsc"
 
 // ProtocolVersions are the supported versions of the `bsc` protocol (first
 // is primary).
-var ProtocolVersions = []uint{Bsc1, Bsc2, Bsc3}
+var ProtocolVersions = []uint{Bsc1, Bsc2, Bsc3, Bsc4, Bsc5, Bsc6, Bsc7}
 
 // protocolLengths are the number of implemented message corresponding to
 // different protocol versions.
-var protocolLengths = map[uint]uint64{Bsc1: 2, Bsc2: 4, Bsc3: 4}
+var protocolLengths = map[uint]uint64{Bsc1: 2, Bsc2: 4, Bsc3: 4, Bsc4: 5, Bsc5: 6, Bsc6: 7, Bsc7: 9}
 
 // maxMessageSize is the maximum cap on the size of a protocol message.
 const maxMessageSize = 10 * 1024 * 1024
@@ -35,6 +40,13 @@ const (
 	VotesMsg            = 0x01
 	GetBlocksByRangeMsg = 0x02 // it can request (StartBlockHeight-Count, StartBlockHeight] range blocks from remote peer
 	BlocksByRangeMsg    = 0x03 // the replied blocks from remote peer
+
+	BridgeAttestationsMsg = 0x04 // validator attestations of bridged chain events
+	PriceReportsMsg       = 0x05 // validator reports of the oracle price
+
+	PrivateTransactionsMsg = 0x06 // transactions sent privately to a validator, never to be broadcast
+	GetWitnessMsg          = 0x07 // it can request the execution witness of a block from remote peer
+	WitnessMsg             = 0x08 // the replied execution witness from remote peer
 )
 
 var defaultExtra = []byte{0x00}
@@ -62,12 +74,37 @@ type VotesPacket struct {
 	Votes []*types.VoteEnvelope
 }
 
+// BridgeAttestationsPacket is the network packet for bridge attestations.
+type BridgeAttestationsPacket struct {
+	Attestations []*types.BridgeAttestation
+}
+
+// PriceReportsPacket is the network packet for price reports.
+type PriceReportsPacket struct {
+	Reports []*types.PriceReport
+}
+
+// PrivateTransactionsPacket is the network packet for transactions relayed
+// privately to the validators in turn, which keep them out of the broadcast.
+type PrivateTransactionsPacket struct {
+	Txs []*types.Transaction
+}
+
 func (*BscCapPacket) Name() string { return "BscCap" }
 func (*BscCapPacket) Kind() byte   { return BscCapMsg }
 
 func (*VotesPacket) Name() string { return "Votes" }
 func (*VotesPacket) Kind() byte   { return VotesMsg }
 
+func (*BridgeAttestationsPacket) Name() string { return "BridgeAttestations" }
+func (*BridgeAttestationsPacket) Kind() byte   { return BridgeAttestationsMsg }
+
+func (*PriceReportsPacket) Name() string { return "PriceReports" }
+func (*PriceReportsPacket) Kind() byte   { return PriceReportsMsg }
+
+func (*PrivateTransactionsPacket) Name() string { return "PrivateTransactions" }
+func (*PrivateTransactionsPacket) Kind() byte   { return PrivateTransactionsMsg }
+
 type GetBlocksByRangePacket struct {
 	RequestId        uint64
 	StartBlockHeight uint64      // The start block height expected to be obtained from
@@ -107,3 +144,24 @@ type BlocksByRangePacket struct {
 
 func (*BlocksByRangePacket) Name() string { return "BlocksByRange" }
 func (*BlocksByRangePacket) Kind() byte   { return BlocksByRangeMsg }
+
+// GetWitnessPacket requests the execution witness of the block with the given
+// hash, which the remote peer regenerates by executing the block.
+type GetWitnessPacket struct {
+	RequestId uint64
+	BlockHash common.Hash
+}
+
+func (*GetWitnessPacket) Name() string { return "GetWitness" }
+func (*GetWitnessPacket) Kind() byte   { return GetWitnessMsg }
+
+// WitnessPacket is the reply to GetWitnessPacket, carrying the stateless witness
+// of the requested block. The witness is nil if the remote peer is unable to
+// produce it, e.g. because the block is unknown or the parent state is pruned.
+type WitnessPacket struct {
+	RequestId uint64
+	Witness   *stateless.Witness `rlp:"nil"`
+}
+
+func (*WitnessPacket) Name() string { return "Witness" }
+func (*WitnessPacket) Kind() byte   { return WitnessMsg }
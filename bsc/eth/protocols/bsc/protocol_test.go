@@ -3,9 +3,11 @@ package bsc
 import (
 	"bytes"
 	"encoding/hex"
+	"math/big"
 	"testing"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/stateless"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
 )
@@ -130,3 +132,41 @@ func TestBsc1Messages(t *testing.T) {
 		}
 	}
 }
+
+// TestWitnessPacketRoundTrip tests the encoding of the witness reply, with and
+// without a witness.
+func TestWitnessPacketRoundTrip(t *testing.T) {
+	witness := &stateless.Witness{
+		Headers: []*types.Header{{Number: big.NewInt(1), Difficulty: big.NewInt(2), Extra: []byte{}}},
+		Codes:   map[string]struct{}{"\x60\x00": {}},
+		State:   map[string]struct{}{"\xc2\x80\x80": {}},
+	}
+	for i, packet := range []*WitnessPacket{
+		{RequestId: 1},
+		{RequestId: 2, Witness: witness},
+	} {
+		blob, err := rlp.EncodeToBytes(packet)
+		if err != nil {
+			t.Fatalf("test %d: failed to encode: %v", i, err)
+		}
+		have := new(WitnessPacket)
+		if err := rlp.DecodeBytes(blob, have); err != nil {
+			t.Fatalf("test %d: failed to decode: %v", i, err)
+		}
+		if have.RequestId != packet.RequestId {
+			t.Errorf("test %d: request id mismatch: have %d, want %d", i, have.RequestId, packet.RequestId)
+		}
+		if (have.Witness == nil) != (packet.Witness == nil) {
+			t.Fatalf("test %d: witness presence mismatch", i)
+		}
+		if packet.Witness == nil {
+			continue
+		}
+		if have.Witness.Root() != packet.Witness.Root() {
+			t.Errorf("test %d: pre-state root mismatch", i)
+		}
+		if len(have.Witness.Codes) != 1 || len(have.Witness.State) != 1 {
+			t.Errorf("test %d: witness content mismatch", i)
+		}
+	}
+}
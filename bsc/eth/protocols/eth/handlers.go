@@ -241,6 +241,9 @@ func ServiceGetBlockBodiesQuery(chain *core.BlockChain, query GetBlockBodiesRequ
 		}
 		body := chain.GetBody(hash)
 		if body == nil {
+			if prunedHistory(chain, hash) {
+				break
+			}
 			continue
 		}
 		sidecars := chain.GetSidecarsByHash(hash)
@@ -261,6 +264,19 @@ func ServiceGetBlockBodiesQuery(chain *core.BlockChain, query GetBlockBodiesRequ
 	return bodies
 }
 
+// prunedHistory reports whether the body and receipts of the block with the
+// given hash were pruned from the database. The responses stop at such blocks,
+// so that pruned ranges are answered with empty responses instead of leaving
+// gaps in them.
+func prunedHistory(chain *core.BlockChain, hash common.Hash) bool {
+	number := chain.GetBlockNumber(hash)
+	if number == nil {
+		return false
+	}
+	cutoff, _ := chain.HistoryPruningCutoff()
+	return *number < cutoff
+}
+
 func handleGetReceipts68(backend Backend, msg Decoder, peer *Peer) error {
 	// Decode the block receipts retrieval message
 	var query GetReceiptsPacket
@@ -297,6 +313,9 @@ func ServiceGetReceiptsQuery68(chain *core.BlockChain, query GetReceiptsRequest)
 		// Retrieve the requested block's receipts
 		results := chain.GetReceiptsRLP(hash)
 		if results == nil {
+			if prunedHistory(chain, hash) {
+				break
+			}
 			if header := chain.GetHeaderByHash(hash); header == nil || header.ReceiptHash != types.EmptyRootHash {
 				continue
 			}
@@ -334,6 +353,9 @@ func serviceGetReceiptsQuery69(chain *core.BlockChain, query GetReceiptsRequest)
 		// Retrieve the requested block's receipts
 		results := chain.GetReceiptsRLP(hash)
 		if results == nil {
+			if prunedHistory(chain, hash) {
+				break
+			}
 			if header := chain.GetHeaderByHash(hash); header == nil || header.ReceiptHash != types.EmptyRootHash {
 				continue
 			}
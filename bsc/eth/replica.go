@@ -0,0 +1,308 @@
+package eth
+
+import (
+	"context"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/eth/ethconfig"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/metrics"
+	"github.com/ethereum/go-ethereum/node"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const (
+	// replicaRetryInterval is the delay between the attempts to observe a head
+	// announced by the writer, which is only visible once the writer published
+	// its next snapshot.
+	replicaRetryInterval = 500 * time.Millisecond
+
+	// replicaRetries is the number of attempts to observe an announced head,
+	// spanning the default publish interval of the writer.
+	replicaRetries = 10
+
+	// replicaResubscribeDelay is the delay before resubscribing to the writer
+	// after the subscription failed.
+	replicaResubscribeDelay = 5 * time.Second
+
+	// replicaRefreshInterval is the interval of the refreshes without head
+	// announcements, catching up with the writes missed meanwhile.
+	replicaRefreshInterval = 30 * time.Second
+
+	// replicaSnapshotsDir is the directory within the instance directory of the
+	// writer node, the snapshots for the read replicas are published in.
+	replicaSnapshotsDir = "replicas"
+
+	// replicaViewsDir is the directory within the instance directory of the
+	// read replica, the published snapshots are linked into.
+	replicaViewsDir = "replica"
+)
+
+// replicaPublishTimer measures the time taken to publish a database snapshot.
+var replicaPublishTimer = metrics.NewRegisteredTimer("eth/replica/publish", nil)
+
+// openReplicaDatabase opens the latest snapshot of the chain database published
+// by the writer node as a read replica, reopening the subsequent snapshots to
+// observe the latest writes.
+func openReplicaDatabase(stack *node.Node, config *ethconfig.Config) (*rawdb.ReplicaDatabase, error) {
+	// Delete the snapshots linked by the previous run
+	views := stack.ResolvePath(replicaViewsDir)
+	if err := os.RemoveAll(views); err != nil {
+		return nil, err
+	}
+	return rawdb.NewReplicaDatabase(func() (ethdb.Database, error) {
+		return rawdb.OpenReplicaSnapshot(config.Replica, views, func(path string) (ethdb.Database, error) {
+			return stack.OpenDatabaseWithOptions(path, node.DatabaseOptions{
+				MetricsNamespace: ChainDBNamespace,
+				Cache:            config.DatabaseCache,
+				Handles:          config.DatabaseHandles,
+				Replica:          true,
+			})
+		})
+	})
+}
+
+// replicaPublisher publishes a snapshot of the chain database for the read
+// replicas whenever the chain head changed since the last one, at most once per
+// publish interval. Every snapshot checkpoints the key-value store and links all
+// the freezer files, so the heads imported meanwhile are covered by the next one.
+type replicaPublisher struct {
+	dir      string
+	interval time.Duration
+	db       ethdb.Database
+	chain    *core.BlockChain
+
+	closeCh chan struct{}
+	wg      sync.WaitGroup
+}
+
+func newReplicaPublisher(dir string, interval time.Duration, db ethdb.Database, chain *core.BlockChain) *replicaPublisher {
+	if interval <= 0 {
+		log.Warn("Sanitizing invalid replica publish interval", "provided", interval, "updated", ethconfig.Defaults.ReplicaPublishInterval)
+		interval = ethconfig.Defaults.ReplicaPublishInterval
+	}
+	return &replicaPublisher{
+		dir:      dir,
+		interval: interval,
+		db:       db,
+		chain:    chain,
+		closeCh:  make(chan struct{}),
+	}
+}
+
+// Start implements node.Lifecycle, starting the snapshot publishing.
+func (p *replicaPublisher) Start() error {
+	// The snapshots of the previous run are outdated
+	if err := os.RemoveAll(p.dir); err != nil {
+		return err
+	}
+	p.wg.Add(1)
+	go p.loop()
+	log.Info("Publishing read replica snapshots", "dir", p.dir, "interval", p.interval)
+	return nil
+}
+
+// Stop implements node.Lifecycle, terminating the snapshot publishing.
+func (p *replicaPublisher) Stop() error {
+	close(p.closeCh)
+	p.wg.Wait()
+	return nil
+}
+
+// loop publishes a snapshot on every tick of the publish interval, if the chain
+// head changed since the last published one.
+func (p *replicaPublisher) loop() {
+	defer p.wg.Done()
+
+	ticker := time.NewTicker(p.interval)
+	defer ticker.Stop()
+
+	var published common.Hash
+	for {
+		if head := p.chain.CurrentBlock(); head.Hash() != published && p.publish(head) {
+			published = head.Hash()
+		}
+		select {
+		case <-ticker.C:
+		case <-p.closeCh:
+			return
+		}
+	}
+}
+
+// publish writes a snapshot of the chain database, reporting whether it has
+// been published.
+func (p *replicaPublisher) publish(head *types.Header) bool {
+	start := time.Now()
+	if err := rawdb.CreateReplicaSnapshot(p.db, p.dir); err != nil {
+		log.Warn("Failed to publish read replica snapshot", "err", err)
+		return false
+	}
+	replicaPublishTimer.UpdateSince(start)
+	log.Debug("Published read replica snapshot", "head", head.Number, "elapsed", common.PrettyDuration(time.Since(start)))
+	return true
+}
+
+// replicaEngineDB is the database of the consensus engine of a read replica.
+// The snapshots stored by the engine are kept in memory, while the ones stored
+// by the writer are read from the snapshot.
+type replicaEngineDB struct {
+	ethdb.Database
+	mem *memorydb.Database
+}
+
+func newReplicaEngineDB(db ethdb.Database) *replicaEngineDB {
+	return &replicaEngineDB{Database: db, mem: memorydb.New()}
+}
+
+func (db *replicaEngineDB) Has(key []byte) (bool, error) {
+	if ok, _ := db.mem.Has(key); ok {
+		return true, nil
+	}
+	return db.Database.Has(key)
+}
+
+func (db *replicaEngineDB) Get(key []byte) ([]byte, error) {
+	if blob, err := db.mem.Get(key); err == nil {
+		return blob, nil
+	}
+	return db.Database.Get(key)
+}
+
+func (db *replicaEngineDB) Put(key []byte, value []byte) error {
+	return db.mem.Put(key, value)
+}
+
+func (db *replicaEngineDB) Delete(key []byte) error {
+	return db.mem.Delete(key)
+}
+
+// replicaFollower follows the chain head of the writer node publishing the
+// database snapshots, refreshing the read replica whenever a new head is
+// announced.
+type replicaFollower struct {
+	source string
+	db     *rawdb.ReplicaDatabase
+	chain  *core.BlockChain
+
+	closeCh chan struct{}
+	wg      sync.WaitGroup
+}
+
+func newReplicaFollower(source string, db *rawdb.ReplicaDatabase, chain *core.BlockChain) *replicaFollower {
+	return &replicaFollower{
+		source:  source,
+		db:      db,
+		chain:   chain,
+		closeCh: make(chan struct{}),
+	}
+}
+
+// Start implements node.Lifecycle, starting the head subscription.
+func (f *replicaFollower) Start() error {
+	f.wg.Add(1)
+	go f.loop()
+	log.Info("Started read replica", "source", f.source)
+	return nil
+}
+
+// Stop implements node.Lifecycle, terminating the head subscription.
+func (f *replicaFollower) Stop() error {
+	close(f.closeCh)
+	f.wg.Wait()
+	log.Info("Stopped read replica")
+	return nil
+}
+
+// loop subscribes to the heads announced by the writer and refreshes the read
+// replica, resubscribing if the connection is lost.
+func (f *replicaFollower) loop() {
+	defer f.wg.Done()
+
+	for {
+		if err := f.follow(); err != nil {
+			log.Warn("Read replica subscription failed", "source", f.source, "err", err)
+		}
+		select {
+		case <-time.After(replicaResubscribeDelay):
+		case <-f.closeCh:
+			return
+		}
+	}
+}
+
+// follow refreshes the read replica on the heads announced through a single
+// subscription, until it fails or the follower is stopped.
+func (f *replicaFollower) follow() error {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	client, err := rpc.DialContext(ctx, f.source)
+	if err != nil {
+		return err
+	}
+	defer client.Close()
+
+	heads := make(chan *types.Header, 16)
+	sub, err := client.EthSubscribe(ctx, heads, "newHeads")
+	if err != nil {
+		return err
+	}
+	defer sub.Unsubscribe()
+
+	// Catch up with the writes happened while not subscribed
+	f.refresh(0)
+
+	ticker := time.NewTicker(replicaRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case head := <-heads:
+			// Skip the heads superseded by the pending announcements
+			for len(heads) > 0 {
+				head = <-heads
+			}
+			f.refresh(head.Number.Uint64())
+
+		case <-ticker.C:
+			f.refresh(0)
+
+		case err := <-sub.Err():
+			return err
+
+		case <-f.closeCh:
+			return nil
+		}
+	}
+}
+
+// refresh reopens the latest database snapshot and reloads the chain of the read
+// replica, retrying until the given announced head is observed.
+func (f *replicaFollower) refresh(number uint64) {
+	for i := 0; i < replicaRetries; i++ {
+		err := f.db.Reopen(f.chain.PrepareReplicaRefresh)
+		if err == nil {
+			err = f.chain.RefreshReplica()
+		}
+		if err != nil {
+			log.Debug("Failed to refresh read replica", "number", number, "err", err)
+		} else if f.chain.CurrentBlock().Number.Uint64() >= number {
+			return
+		}
+		select {
+		case <-time.After(replicaRetryInterval):
+		case <-f.closeCh:
+			return
+		}
+	}
+	log.Warn("Read replica is lagging behind the writer", "announced", number, "head", f.chain.CurrentBlock().Number)
+}
@@ -65,9 +65,23 @@ type supplyInfoBurnMarshaling struct {
 	Misc    *hexutil.Big
 }
 
+// supplyInfoYield tracks the holder yield, which moves existing supply rather
+// than issuing new coins.
+type supplyInfoYield struct {
+	Accrued *big.Int `json:"accrued,omitempty"`
+	Settled *big.Int `json:"settled,omitempty"`
+}
+
+//go:generate go run github.com/fjl/gencodec -type supplyInfoYield -field-override supplyInfoYieldMarshaling -out gen_supplyinfoyield.go
+type supplyInfoYieldMarshaling struct {
+	Accrued *hexutil.Big
+	Settled *hexutil.Big
+}
+
 type supplyInfo struct {
 	Issuance *supplyInfoIssuance `json:"issuance,omitempty"`
 	Burn     *supplyInfoBurn     `json:"burn,omitempty"`
+	Yield    *supplyInfoYield    `json:"yield,omitempty"`
 
 	// Block info
 	Number     uint64      `json:"blockNumber"`
@@ -138,6 +152,10 @@ func newSupplyInfo() supplyInfo {
 			Blob:    big.NewInt(0),
 			Misc:    big.NewInt(0),
 		},
+		Yield: &supplyInfoYield{
+			Accrued: big.NewInt(0),
+			Settled: big.NewInt(0),
+		},
 
 		Number:     0,
 		Hash:       common.Hash{},
@@ -207,6 +225,10 @@ func (s *supplyTracer) onBalanceChange(a common.Address, prevBalance, newBalance
 		// BalanceDecreaseSelfdestructBurn is non-reversible as it happens
 		// at the end of the transaction.
 		s.delta.Burn.Misc.Sub(s.delta.Burn.Misc, diff)
+	case tracing.BalanceIncreaseYieldAccrual:
+		s.delta.Yield.Accrued.Add(s.delta.Yield.Accrued, diff)
+	case tracing.BalanceIncreaseYieldSettlement:
+		s.delta.Yield.Settled.Add(s.delta.Yield.Settled, diff)
 	default:
 		return
 	}
@@ -317,6 +339,18 @@ func (s *supplyTracer) write(data any) {
 		supply.Burn = nil
 	}
 
+	if supply.Yield.Accrued.Sign() == 0 {
+		supply.Yield.Accrued = nil
+	}
+
+	if supply.Yield.Settled.Sign() == 0 {
+		supply.Yield.Settled = nil
+	}
+
+	if supply.Yield.Accrued == nil && supply.Yield.Settled == nil {
+		supply.Yield = nil
+	}
+
 	out, _ := json.Marshal(supply)
 	if _, err := s.logger.Write(out); err != nil {
 		log.Warn("failed to write to supply tracer log file", "error", err)
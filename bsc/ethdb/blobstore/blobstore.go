@@ -0,0 +1,63 @@
+// Package blobstore implements the blob stores the ancient data files can be
+// offloaded to: a local directory tree, e.g. a mounted network volume, and any
+// object store speaking the S3 API.
+package blobstore
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	"github.com/ethereum/go-ethereum/ethdb"
+)
+
+// ErrNotFound is returned if the requested blob doesn't exist.
+var ErrNotFound = errors.New("blob not found")
+
+// Open opens the blob store at the given location:
+//
+//   - s3://bucket/prefix?endpoint=http://host:9000&region=us-east-1 for an S3
+//     compatible object store, authenticating with the credentials from the
+//     AWS_ACCESS_KEY_ID and AWS_SECRET_ACCESS_KEY environment variables
+//   - file:///path or a plain path for a directory tree
+func Open(location string) (ethdb.BlobStore, error) {
+	if !strings.Contains(location, "://") {
+		return NewDirectory(location)
+	}
+	u, err := url.Parse(location)
+	if err != nil {
+		return nil, err
+	}
+	switch u.Scheme {
+	case "file":
+		return NewDirectory(u.Path)
+	case "s3":
+		query := u.Query()
+		return NewS3(S3Config{
+			Endpoint:  query.Get("endpoint"),
+			Region:    query.Get("region"),
+			Bucket:    u.Host,
+			Prefix:    strings.Trim(u.Path, "/"),
+			AccessKey: os.Getenv("AWS_ACCESS_KEY_ID"),
+			SecretKey: os.Getenv("AWS_SECRET_ACCESS_KEY"),
+		})
+	default:
+		return nil, fmt.Errorf("unsupported blob store scheme %q", u.Scheme)
+	}
+}
+
+// validateName checks that the blob name is a relative slash separated path,
+// not escaping the root of the store.
+func validateName(name string) error {
+	if name == "" || strings.HasPrefix(name, "/") {
+		return fmt.Errorf("invalid blob name %q", name)
+	}
+	for _, segment := range strings.Split(name, "/") {
+		if segment == "" || segment == "." || segment == ".." {
+			return fmt.Errorf("invalid blob name %q", name)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,113 @@
+package blobstore
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+)
+
+// Directory is a blob store keeping the blobs as files in a directory tree, the
+// slashes of the blob names separating the subdirectories.
+type Directory struct {
+	root string
+}
+
+// NewDirectory opens the directory tree at the given path as a blob store,
+// creating it if non-existent.
+func NewDirectory(root string) (*Directory, error) {
+	if root == "" {
+		return nil, errors.New("empty blob store directory")
+	}
+	if err := os.MkdirAll(root, 0755); err != nil {
+		return nil, err
+	}
+	return &Directory{root: root}, nil
+}
+
+// path returns the file path of the blob with the given name.
+func (d *Directory) path(name string) (string, error) {
+	if err := validateName(name); err != nil {
+		return "", err
+	}
+	return filepath.Join(d.root, filepath.FromSlash(name)), nil
+}
+
+// Put stores the content of the reader under the given name. The content is
+// written to a temporary file first, so that a blob is never left incomplete.
+func (d *Directory) Put(name string, r io.Reader, size int64) error {
+	path, err := d.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	f, err := os.CreateTemp(filepath.Dir(path), filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(f.Name())
+
+	n, err := io.Copy(f, r)
+	if err == nil && n != size {
+		err = io.ErrUnexpectedEOF
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+	return os.Rename(f.Name(), path)
+}
+
+// Get retrieves the content of the blob with the given name.
+func (d *Directory) Get(name string) (io.ReadCloser, error) {
+	path, err := d.path(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrNotFound
+	}
+	return f, err
+}
+
+// Size returns the size of the blob with the given name.
+func (d *Directory) Size(name string) (int64, error) {
+	path, err := d.path(name)
+	if err != nil {
+		return 0, err
+	}
+	stat, err := os.Stat(path)
+	if errors.Is(err, fs.ErrNotExist) {
+		return 0, ErrNotFound
+	}
+	if err != nil {
+		return 0, err
+	}
+	return stat.Size(), nil
+}
+
+// Delete removes the blob with the given name if it exists.
+func (d *Directory) Delete(name string) error {
+	path, err := d.path(name)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return err
+	}
+	return nil
+}
+
+// Close implements io.Closer, there is nothing to release.
+func (d *Directory) Close() error {
+	return nil
+}
@@ -0,0 +1,306 @@
+package blobstore
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"slices"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	// s3DefaultRegion is the region signed into the requests if none is given,
+	// which most S3 compatible stores accept regardless of their location.
+	s3DefaultRegion = "us-east-1"
+
+	// s3UnsignedPayload is the payload hash of requests whose content is not
+	// signed, sparing to hash the multi-gigabyte data files before uploading.
+	s3UnsignedPayload = "UNSIGNED-PAYLOAD"
+
+	// s3EmptyPayload is the payload hash of requests without content.
+	s3EmptyPayload = "e3b0c44298fc1c149afbf4c8996fb92427ae41e4649b934ca495991b7852b855"
+
+	// s3TimeFormat is the format of the request timestamps.
+	s3TimeFormat = "20060102T150405Z"
+)
+
+// S3Config contains the settings of an S3 compatible object store.
+type S3Config struct {
+	Endpoint  string // Base URL of the API, defaults to the AWS endpoint of the region
+	Region    string // Region signed into the requests, defaults to us-east-1
+	Bucket    string // Bucket holding the blobs
+	Prefix    string // Prefix of the object keys, separated from the blob names by a slash
+	AccessKey string // Access key id of the credentials
+	SecretKey string // Secret access key of the credentials
+}
+
+// S3 is a blob store keeping the blobs as the objects of a bucket in an object
+// store speaking the S3 API, e.g. AWS S3 or MinIO. The objects are addressed
+// path-style and the requests are signed with AWS signature version 4.
+type S3 struct {
+	config   S3Config
+	endpoint *url.URL
+	signer   *s3Signer
+	client   *http.Client
+}
+
+// NewS3 creates a blob store on top of the bucket of an S3 compatible object
+// store.
+func NewS3(config S3Config) (*S3, error) {
+	if config.Bucket == "" {
+		return nil, errors.New("missing s3 bucket")
+	}
+	if config.AccessKey == "" || config.SecretKey == "" {
+		return nil, errors.New("missing s3 credentials")
+	}
+	if config.Region == "" {
+		config.Region = s3DefaultRegion
+	}
+	if config.Endpoint == "" {
+		config.Endpoint = fmt.Sprintf("https://s3.%s.amazonaws.com", config.Region)
+	}
+	endpoint, err := url.Parse(config.Endpoint)
+	if err != nil {
+		return nil, fmt.Errorf("invalid s3 endpoint: %v", err)
+	}
+	if endpoint.Scheme != "http" && endpoint.Scheme != "https" {
+		return nil, fmt.Errorf("invalid s3 endpoint scheme %q", endpoint.Scheme)
+	}
+	config.Prefix = strings.Trim(config.Prefix, "/")
+
+	return &S3{
+		config:   config,
+		endpoint: endpoint,
+		signer: &s3Signer{
+			region:    config.Region,
+			accessKey: config.AccessKey,
+			secretKey: config.SecretKey,
+		},
+		client: new(http.Client),
+	}, nil
+}
+
+// do sends a signed request for the object of the given blob.
+func (s *S3) do(method string, name string, body io.Reader, size int64) (*http.Response, error) {
+	if err := validateName(name); err != nil {
+		return nil, err
+	}
+	key := name
+	if s.config.Prefix != "" {
+		key = s.config.Prefix + "/" + name
+	}
+	u := *s.endpoint
+	u.Path = strings.TrimSuffix(u.Path, "/") + "/" + s.config.Bucket + "/" + key
+	u.RawPath = s3Escape(u.Path, false)
+
+	req, err := http.NewRequest(method, u.String(), nil)
+	if err != nil {
+		return nil, err
+	}
+	payload := s3EmptyPayload
+	if body != nil {
+		payload = s3UnsignedPayload
+		req.ContentLength = size
+		req.Body = http.NoBody
+		if size > 0 {
+			req.Body = io.NopCloser(body)
+		}
+	}
+	s.signer.sign(req, payload, time.Now())
+	return s.client.Do(req)
+}
+
+// Put stores the content of the reader as the object of the given blob.
+func (s *S3) Put(name string, r io.Reader, size int64) error {
+	resp, err := s.do(http.MethodPut, name, r, size)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return s3Error(http.MethodPut, name, resp)
+	}
+	return nil
+}
+
+// Get retrieves the content of the object of the given blob.
+func (s *S3) Get(name string) (io.ReadCloser, error) {
+	resp, err := s.do(http.MethodGet, name, nil, 0)
+	if err != nil {
+		return nil, err
+	}
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return resp.Body, nil
+	case http.StatusNotFound:
+		resp.Body.Close()
+		return nil, ErrNotFound
+	default:
+		defer resp.Body.Close()
+		return nil, s3Error(http.MethodGet, name, resp)
+	}
+}
+
+// Size returns the size of the object of the given blob.
+func (s *S3) Size(name string) (int64, error) {
+	resp, err := s.do(http.MethodHead, name, nil, 0)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+		return strconv.ParseInt(resp.Header.Get("Content-Length"), 10, 64)
+	case http.StatusNotFound:
+		return 0, ErrNotFound
+	default:
+		return 0, s3Error(http.MethodHead, name, resp)
+	}
+}
+
+// Delete removes the object of the given blob if it exists.
+func (s *S3) Delete(name string) error {
+	resp, err := s.do(http.MethodDelete, name, nil, 0)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return s3Error(http.MethodDelete, name, resp)
+	}
+}
+
+// Close releases the idle connections to the object store.
+func (s *S3) Close() error {
+	s.client.CloseIdleConnections()
+	return nil
+}
+
+// s3Error converts an unexpected response into an error, including the error
+// message returned by the object store.
+func s3Error(method string, name string, resp *http.Response) error {
+	msg, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+	return fmt.Errorf("s3 %s %s failed: %s %s", method, name, resp.Status, strings.TrimSpace(string(msg)))
+}
+
+// s3Signer signs the requests with AWS signature version 4.
+type s3Signer struct {
+	region    string
+	accessKey string
+	secretKey string
+}
+
+// sign adds the timestamp, the payload hash and the authorization headers to
+// the request.
+func (s *s3Signer) sign(req *http.Request, payload string, now time.Time) {
+	date := now.UTC().Format(s3TimeFormat)
+	req.Header.Set("X-Amz-Date", date)
+	req.Header.Set("X-Amz-Content-Sha256", payload)
+
+	host := req.Host
+	if host == "" {
+		host = req.URL.Host
+	}
+	signed := []string{"host"}
+	for key := range req.Header {
+		key = strings.ToLower(key)
+		if key == "range" || key == "content-md5" || key == "content-type" || strings.HasPrefix(key, "x-amz-") {
+			signed = append(signed, key)
+		}
+	}
+	sort.Strings(signed)
+
+	signature := s.signature(req.Method, req.URL.Path, req.URL.Query(), host, req.Header, signed, payload, date)
+	req.Header.Set("Authorization", fmt.Sprintf("AWS4-HMAC-SHA256 Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		s.accessKey, s.scope(date), strings.Join(signed, ";"), signature))
+}
+
+// scope returns the credential scope of the requests made at the given time.
+func (s *s3Signer) scope(date string) string {
+	return date[:8] + "/" + s.region + "/s3/aws4_request"
+}
+
+// signature computes the signature of a request over the given sorted list of
+// signed headers.
+func (s *s3Signer) signature(method string, path string, query url.Values, host string, header http.Header, signed []string, payload string, date string) string {
+	// Assemble the canonical form of the request
+	var canonical strings.Builder
+	canonical.WriteString(method + "\n")
+	canonical.WriteString(s3Escape(path, false) + "\n")
+
+	keys := make([]string, 0, len(query))
+	for key := range query {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	var params []string
+	for _, key := range keys {
+		values := query[key]
+		sort.Strings(values)
+		for _, value := range values {
+			params = append(params, s3Escape(key, true)+"="+s3Escape(value, true))
+		}
+	}
+	canonical.WriteString(strings.Join(params, "&") + "\n")
+
+	for _, key := range signed {
+		value := host
+		if key != "host" {
+			values := slices.Clone(header.Values(key))
+			for i := range values {
+				values[i] = strings.Join(strings.Fields(values[i]), " ")
+			}
+			value = strings.Join(values, ",")
+		}
+		canonical.WriteString(key + ":" + value + "\n")
+	}
+	canonical.WriteString("\n" + strings.Join(signed, ";") + "\n")
+	canonical.WriteString(payload)
+
+	// Sign the digest of the canonical request with the key derived for the scope
+	digest := sha256.Sum256([]byte(canonical.String()))
+	toSign := "AWS4-HMAC-SHA256\n" + date + "\n" + s.scope(date) + "\n" + hex.EncodeToString(digest[:])
+
+	key := s3HMAC([]byte("AWS4"+s.secretKey), date[:8])
+	key = s3HMAC(key, s.region)
+	key = s3HMAC(key, "s3")
+	key = s3HMAC(key, "aws4_request")
+	return hex.EncodeToString(s3HMAC(key, toSign))
+}
+
+func s3HMAC(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+// s3Escape percent-encodes all but the unreserved characters of the string, and
+// the slashes too if requested.
+func s3Escape(s string, slash bool) string {
+	var b strings.Builder
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case 'A' <= c && c <= 'Z', 'a' <= c && c <= 'z', '0' <= c && c <= '9',
+			c == '-', c == '_', c == '.', c == '~', c == '/' && !slash:
+			b.WriteByte(c)
+		default:
+			fmt.Fprintf(&b, "%%%02X", c)
+		}
+	}
+	return b.String()
+}
@@ -182,6 +182,7 @@ type AncientWriter interface {
 type FreezerEnv struct {
 	ChainCfg         *params.ChainConfig
 	BlobExtraReserve uint64
+	HistoryRecent    uint64 // Number of recent blocks whose bodies and receipts are kept, zero to keep all
 }
 
 // AncientFreezer defines the help functions for freezing ancient data
@@ -251,6 +252,25 @@ type ResettableAncientStore interface {
 	Reset() error
 }
 
+// BlobStore contains the methods required to keep immutable named blobs in an
+// external object store, e.g. the ancient data files offloaded to cold storage.
+type BlobStore interface {
+	// Put stores the content of the reader under the given name, replacing any
+	// previous blob. The size is the exact length of the content.
+	Put(name string, r io.Reader, size int64) error
+
+	// Get retrieves the content of the blob with the given name.
+	Get(name string) (io.ReadCloser, error)
+
+	// Size returns the size of the blob with the given name.
+	Size(name string) (int64, error)
+
+	// Delete removes the blob with the given name if it exists.
+	Delete(name string) error
+
+	io.Closer
+}
+
 // Database contains all the methods required by the high level database to not
 // only access the key-value data store but also the ancient chain store.
 type Database interface {
@@ -521,6 +521,18 @@ func (d *Database) SyncKeyValue() error {
 	return d.db.Apply(b, pebble.Sync)
 }
 
+// Checkpoint writes a point-in-time snapshot of the database into the given
+// directory, which must not exist yet. The sstables are hard-linked, while the
+// write-ahead-log, the manifest and the options are copied.
+func (d *Database) Checkpoint(dir string) error {
+	d.quitLock.RLock()
+	defer d.quitLock.RUnlock()
+	if d.closed {
+		return pebble.ErrClosed
+	}
+	return d.db.Checkpoint(dir, pebble.WithFlushedWAL())
+}
+
 // meter periodically retrieves internal pebble counters and reports them to
 // the metrics subsystem.
 func (d *Database) meter(refresh time.Duration, namespace string) {
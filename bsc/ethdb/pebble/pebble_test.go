@@ -18,6 +18,7 @@ package pebble
 
 import (
 	"errors"
+	"path/filepath"
 	"testing"
 
 	"github.com/cockroachdb/pebble"
@@ -78,3 +79,37 @@ func TestPebbleLogData(t *testing.T) {
 		t.Fatal("Unknown database entry")
 	}
 }
+
+func TestPebbleCheckpoint(t *testing.T) {
+	db, err := New(t.TempDir(), 16, 16, "", false)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	if err := db.Put([]byte("a"), []byte("1")); err != nil {
+		t.Fatal(err)
+	}
+	dir := filepath.Join(t.TempDir(), "checkpoint")
+	if err := db.Checkpoint(dir); err != nil {
+		t.Fatalf("failed to create checkpoint: %v", err)
+	}
+	// The checkpoint is opened independently, without the later writes
+	if err := db.Put([]byte("b"), []byte("2")); err != nil {
+		t.Fatal(err)
+	}
+	snap, err := New(dir, 16, 16, "", true)
+	if err != nil {
+		t.Fatalf("failed to open checkpoint: %v", err)
+	}
+	defer snap.Close()
+	if val, err := snap.Get([]byte("a")); err != nil || string(val) != "1" {
+		t.Fatalf("unexpected value: %q, %v", val, err)
+	}
+	if ok, _ := snap.Has([]byte("b")); ok {
+		t.Fatal("checkpoint observed a later write")
+	}
+	if err := db.Checkpoint(dir); err == nil {
+		t.Fatal("checkpoint overwrote an existing directory")
+	}
+}
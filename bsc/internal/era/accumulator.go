@@ -24,6 +24,7 @@ import (
 	ssz "github.com/bnb-chain/fastssz"
 
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
 // ComputeAccumulator calculates the SSZ hash tree root of the Era1
@@ -48,6 +49,67 @@ func ComputeAccumulator(hashes []common.Hash, tds []*big.Int) (common.Hash, erro
 	return hh.HashRoot()
 }
 
+// ComputeParliaAccumulator calculates the SSZ hash tree root of the accumulator
+// of Parlia records.
+func ComputeParliaAccumulator(records []ParliaRecord) (common.Hash, error) {
+	if len(records) > MaxEra1Size {
+		return common.Hash{}, fmt.Errorf("too many records: have %d, max %d", len(records), MaxEra1Size)
+	}
+	hh := ssz.NewHasher()
+	for i := range records {
+		root, err := records[i].HashTreeRoot()
+		if err != nil {
+			return common.Hash{}, err
+		}
+		hh.Append(root[:])
+	}
+	hh.MerkleizeWithMixin(0, uint64(len(records)), uint64(MaxEra1Size))
+	return hh.HashRoot()
+}
+
+// ParliaRecord is an individual record for a block in the Parlia variant of
+// the Era1 archives. It commits to all the block data stored in the archive.
+type ParliaRecord struct {
+	Hash            common.Hash
+	BodyHash        common.Hash
+	ReceiptsHash    common.Hash
+	AttestationHash common.Hash
+}
+
+// NewParliaRecord creates the record of a block from its hash and the RLP
+// encoded data stored in the archive.
+func NewParliaRecord(hash common.Hash, body, receipts, attestation []byte) ParliaRecord {
+	rec := ParliaRecord{
+		Hash:         hash,
+		BodyHash:     crypto.Keccak256Hash(body),
+		ReceiptsHash: crypto.Keccak256Hash(receipts),
+	}
+	if len(attestation) > 0 {
+		rec.AttestationHash = crypto.Keccak256Hash(attestation)
+	}
+	return rec
+}
+
+// GetTree completes the ssz.HashRoot interface, but is unused.
+func (r *ParliaRecord) GetTree() (*ssz.Node, error) {
+	return nil, nil
+}
+
+// HashTreeRoot ssz hashes the ParliaRecord object.
+func (r *ParliaRecord) HashTreeRoot() ([32]byte, error) {
+	return ssz.HashWithDefaultHasher(r)
+}
+
+// HashTreeRootWith ssz hashes the ParliaRecord object with a hasher.
+func (r *ParliaRecord) HashTreeRootWith(hh ssz.HashWalker) (err error) {
+	hh.PutBytes(r.Hash[:])
+	hh.PutBytes(r.BodyHash[:])
+	hh.PutBytes(r.ReceiptsHash[:])
+	hh.PutBytes(r.AttestationHash[:])
+	hh.Merkleize(0)
+	return
+}
+
 // headerRecord is an individual record for a historical header.
 //
 // See https://github.com/ethereum/portal-network-specs/blob/master/history/history-network.md#the-historical-hashes-accumulator
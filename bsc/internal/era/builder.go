@@ -71,13 +71,31 @@ import (
 //
 // Due to the accumulator size limit of 8192, the maximum number of blocks in
 // an Era1 batch is also 8192.
+//
+// Chains finalized by Parlia have no meaningful total difficulty, so the Parlia
+// variant of the archive replaces it by the vote attestation carried in the
+// header, and commits to the full block data instead:
+//
+//	block-tuple := CompressedHeader | CompressedBody | CompressedReceipts | VoteAttestation
+//
+//	VoteAttestation   = { type: [0x08, 0x00], data: rlp(attestation) or empty }
+//	ParliaAccumulator = { type: [0x09, 0x00], data: parlia-accumulator-root }
+//
+//	parlia-record      := { block-hash: Bytes32, body-hash: Bytes32, receipts-hash: Bytes32, attestation-hash: Bytes32 }
+//	parlia-accumulator := hash_tree_root([]parlia-record, 8192)
+//
+// The body, receipts and attestation hashes are the keccak256 hashes of the
+// RLP data stored in the archive, the attestation hash is zero for blocks not
+// carrying one.
 type Builder struct {
 	w        *e2store.Writer
+	parlia   bool
 	startNum *uint64
 	startTd  *big.Int
 	indexes  []uint64
 	hashes   []common.Hash
 	tds      []*big.Int
+	records  []ParliaRecord
 	written  int
 
 	buf    *bytes.Buffer
@@ -94,6 +112,14 @@ func NewBuilder(w io.Writer) *Builder {
 	}
 }
 
+// NewParliaBuilder returns a new Builder instance creating the Parlia variant
+// of Era1 archives.
+func NewParliaBuilder(w io.Writer) *Builder {
+	b := NewBuilder(w)
+	b.parlia = true
+	return b
+}
+
 // Add writes a compressed block entry and compressed receipts entry to the
 // underlying e2store file.
 func (b *Builder) Add(block *types.Block, receipts types.Receipts, td *big.Int) error {
@@ -115,6 +141,76 @@ func (b *Builder) Add(block *types.Block, receipts types.Receipts, td *big.Int)
 // AddRLP writes a compressed block entry and compressed receipts entry to the
 // underlying e2store file.
 func (b *Builder) AddRLP(header, body, receipts []byte, number uint64, hash common.Hash, td, difficulty *big.Int) error {
+	if b.parlia {
+		return errors.New("total difficulty not supported in parlia era")
+	}
+	if b.startNum == nil {
+		b.startTd = new(big.Int).Sub(td, difficulty)
+	}
+	if err := b.addBlock(header, body, receipts, number); err != nil {
+		return err
+	}
+	b.hashes = append(b.hashes, hash)
+	b.tds = append(b.tds, td)
+
+	// Also write total difficulty, but don't snappy encode.
+	btd := bigToBytes32(td)
+	n, err := b.w.Write(TypeTotalDifficulty, btd[:])
+	b.written += n
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// AddParlia writes the block, its receipts and the vote attestation carried in
+// its header to the underlying e2store file. The attestation may be nil.
+func (b *Builder) AddParlia(block *types.Block, receipts types.Receipts, attestation *types.VoteAttestation) error {
+	eh, err := rlp.EncodeToBytes(block.Header())
+	if err != nil {
+		return err
+	}
+	eb, err := rlp.EncodeToBytes(block.Body())
+	if err != nil {
+		return err
+	}
+	er, err := rlp.EncodeToBytes(receipts)
+	if err != nil {
+		return err
+	}
+	var ea []byte
+	if attestation != nil {
+		if ea, err = rlp.EncodeToBytes(attestation); err != nil {
+			return err
+		}
+	}
+	return b.AddParliaRLP(eh, eb, er, ea, block.NumberU64(), block.Hash())
+}
+
+// AddParliaRLP writes the RLP encoded block data and vote attestation to the
+// underlying e2store file. The attestation is empty for blocks not carrying one.
+func (b *Builder) AddParliaRLP(header, body, receipts, attestation []byte, number uint64, hash common.Hash) error {
+	if !b.parlia {
+		return errors.New("vote attestation not supported in era1")
+	}
+	if err := b.addBlock(header, body, receipts, number); err != nil {
+		return err
+	}
+	b.records = append(b.records, NewParliaRecord(hash, body, receipts, attestation))
+
+	// The attestation is small, don't snappy encode.
+	n, err := b.w.Write(TypeVoteAttestation, attestation)
+	b.written += n
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// addBlock writes the compressed header, body and receipts entries of a block,
+// preceded by the version entry if it's the first block of the archive.
+func (b *Builder) addBlock(header, body, receipts []byte, number uint64) error {
 	// Write Era1 version entry before first block.
 	if b.startNum == nil {
 		n, err := b.w.Write(TypeVersion, nil)
@@ -123,7 +219,6 @@ func (b *Builder) AddRLP(header, body, receipts []byte, number uint64, hash comm
 		}
 		startNum := number
 		b.startNum = &startNum
-		b.startTd = new(big.Int).Sub(td, difficulty)
 		b.written += n
 	}
 	if len(b.indexes) >= MaxEra1Size {
@@ -131,8 +226,6 @@ func (b *Builder) AddRLP(header, body, receipts []byte, number uint64, hash comm
 	}
 
 	b.indexes = append(b.indexes, uint64(b.written))
-	b.hashes = append(b.hashes, hash)
-	b.tds = append(b.tds, td)
 
 	// Write block data.
 	if err := b.snappyWrite(TypeCompressedHeader, header); err != nil {
@@ -144,15 +237,6 @@ func (b *Builder) AddRLP(header, body, receipts []byte, number uint64, hash comm
 	if err := b.snappyWrite(TypeCompressedReceipts, receipts); err != nil {
 		return err
 	}
-
-	// Also write total difficulty, but don't snappy encode.
-	btd := bigToBytes32(td)
-	n, err := b.w.Write(TypeTotalDifficulty, btd[:])
-	b.written += n
-	if err != nil {
-		return err
-	}
-
 	return nil
 }
 
@@ -163,11 +247,21 @@ func (b *Builder) Finalize() (common.Hash, error) {
 		return common.Hash{}, errors.New("finalize called on empty builder")
 	}
 	// Compute accumulator root and write entry.
-	root, err := ComputeAccumulator(b.hashes, b.tds)
+	var (
+		root common.Hash
+		typ  = TypeAccumulator
+		err  error
+	)
+	if b.parlia {
+		root, err = ComputeParliaAccumulator(b.records)
+		typ = TypeParliaAccumulator
+	} else {
+		root, err = ComputeAccumulator(b.hashes, b.tds)
+	}
 	if err != nil {
 		return common.Hash{}, fmt.Errorf("error calculating accumulator root: %w", err)
 	}
-	n, err := b.w.Write(TypeAccumulator, root[:])
+	n, err := b.w.Write(typ, root[:])
 	b.written += n
 	if err != nil {
 		return common.Hash{}, fmt.Errorf("error writing accumulator: %w", err)
@@ -18,6 +18,7 @@ package era
 
 import (
 	"encoding/binary"
+	"errors"
 	"fmt"
 	"io"
 	"math/big"
@@ -41,6 +42,8 @@ var (
 	TypeCompressedReceipts uint16 = 0x05
 	TypeTotalDifficulty    uint16 = 0x06
 	TypeAccumulator        uint16 = 0x07
+	TypeVoteAttestation    uint16 = 0x08
+	TypeParliaAccumulator  uint16 = 0x09
 	TypeBlockIndex         uint16 = 0x3266
 
 	MaxEra1Size = 8192
@@ -197,9 +200,14 @@ func (e *Era) GetRawReceiptsByNumber(num uint64) ([]byte, error) {
 	return io.ReadAll(r)
 }
 
-// Accumulator reads the accumulator entry in the Era1 file.
+// Accumulator reads the accumulator entry in the Era1 file. For the Parlia
+// variant this is the root of the Parlia records.
 func (e *Era) Accumulator() (common.Hash, error) {
-	entry, err := e.s.Find(TypeAccumulator)
+	typ := TypeAccumulator
+	if e.m.parlia {
+		typ = TypeParliaAccumulator
+	}
+	entry, err := e.s.Find(typ)
 	if err != nil {
 		return common.Hash{}, err
 	}
@@ -209,6 +217,9 @@ func (e *Era) Accumulator() (common.Hash, error) {
 // InitialTD returns initial total difficulty before the difficulty of the
 // first block of the Era1 is applied.
 func (e *Era) InitialTD() (*big.Int, error) {
+	if e.m.parlia {
+		return nil, errors.New("total difficulty not available in parlia era")
+	}
 	var (
 		r      io.Reader
 		header types.Header
@@ -248,6 +259,12 @@ func (e *Era) InitialTD() (*big.Int, error) {
 	return td.Sub(td, header.Difficulty), nil
 }
 
+// IsParlia reports whether the archive is the Parlia variant, storing vote
+// attestations instead of total difficulties.
+func (e *Era) IsParlia() bool {
+	return e.m.parlia
+}
+
 // Start returns the listed start block.
 func (e *Era) Start() uint64 {
 	return e.m.start
@@ -293,6 +310,7 @@ type metadata struct {
 	start  uint64
 	count  uint64
 	length int64
+	parlia bool
 }
 
 // readMetadata reads the metadata stored in an Era1 file's block index.
@@ -313,5 +331,11 @@ func readMetadata(f ReadAtSeekCloser) (m metadata, err error) {
 		return
 	}
 	m.start = binary.LittleEndian.Uint64(b[8:])
+	// The accumulator entry directly precedes the block index, its type tells
+	// the variant of the archive.
+	if _, err = f.ReadAt(b[:2], m.length-24-int64(m.count*8)-40); err != nil {
+		return
+	}
+	m.parlia = binary.LittleEndian.Uint16(b[:2]) == TypeParliaAccumulator
 	return
 }
@@ -82,6 +82,9 @@ func TestEra1Builder(t *testing.T) {
 		t.Fatalf("failed to open era: %v", err)
 	}
 	defer e.Close()
+	if e.IsParlia() {
+		t.Fatalf("era1 detected as parlia era")
+	}
 	it, err := NewRawIterator(e)
 	if err != nil {
 		t.Fatalf("failed to make iterator: %s", err)
@@ -143,6 +146,107 @@ func TestEra1Builder(t *testing.T) {
 	}
 }
 
+func TestParliaEra1Builder(t *testing.T) {
+	t.Parallel()
+
+	f, err := os.CreateTemp(t.TempDir(), "era1-parlia-test")
+	if err != nil {
+		t.Fatalf("error creating temp file: %v", err)
+	}
+	defer f.Close()
+
+	var (
+		builder      = NewParliaBuilder(f)
+		blocks       []*types.Block
+		attestations []*types.VoteAttestation
+		records      []ParliaRecord
+	)
+	for i := 0; i < 128; i++ {
+		block := types.NewBlockWithHeader(&types.Header{Number: big.NewInt(int64(i))}).WithBody(types.Body{
+			Transactions: []*types.Transaction{types.NewTransaction(0, common.Address{byte(i)}, nil, 0, nil, nil)},
+		})
+		receipts := types.Receipts{{CumulativeGasUsed: uint64(i)}}
+
+		// Only every other block carries an attestation.
+		var attestation *types.VoteAttestation
+		if i%2 == 1 {
+			attestation = &types.VoteAttestation{
+				VoteAddressSet: types.ValidatorsBitSet(i),
+				Data:           &types.VoteData{SourceNumber: uint64(i - 1), TargetNumber: uint64(i)},
+			}
+		}
+		if err := builder.AddParlia(block, receipts, attestation); err != nil {
+			t.Fatalf("error adding entry: %v", err)
+		}
+		var encAttestation []byte
+		if attestation != nil {
+			encAttestation = mustEncode(attestation)
+		}
+		blocks = append(blocks, block)
+		attestations = append(attestations, attestation)
+		records = append(records, NewParliaRecord(block.Hash(), mustEncode(block.Body()), mustEncode(receipts), encAttestation))
+	}
+	if err := builder.AddRLP(nil, nil, nil, 128, common.Hash{}, big.NewInt(1), big.NewInt(1)); err == nil {
+		t.Fatalf("expected total difficulty to be rejected")
+	}
+	root, err := builder.Finalize()
+	if err != nil {
+		t.Fatalf("error finalizing era1: %v", err)
+	}
+	if want, _ := ComputeParliaAccumulator(records); root != want {
+		t.Fatalf("accumulator mismatch: have %x, want %x", root, want)
+	}
+
+	// Verify the archive contents.
+	e, err := Open(f.Name())
+	if err != nil {
+		t.Fatalf("failed to open era: %v", err)
+	}
+	defer e.Close()
+
+	if !e.IsParlia() {
+		t.Fatalf("parlia era not detected")
+	}
+	if have, err := e.Accumulator(); err != nil || have != root {
+		t.Fatalf("stored accumulator mismatch: have %x (%v), want %x", have, err, root)
+	}
+	if _, err := e.InitialTD(); err == nil {
+		t.Fatalf("expected no total difficulty in parlia era")
+	}
+	it, err := NewIterator(e)
+	if err != nil {
+		t.Fatalf("failed to make iterator: %s", err)
+	}
+	for i := 0; i < len(blocks); i++ {
+		if !it.Next() {
+			t.Fatalf("expected more entries")
+		}
+		if it.Error() != nil {
+			t.Fatalf("unexpected error %v", it.Error())
+		}
+		block, err := it.Block()
+		if err != nil {
+			t.Fatalf("error reading block: %v", err)
+		}
+		if block.Hash() != blocks[i].Hash() {
+			t.Fatalf("block %d mismatch: have %x, want %x", i, block.Hash(), blocks[i].Hash())
+		}
+		attestation, err := it.VoteAttestation()
+		if err != nil {
+			t.Fatalf("error reading attestation: %v", err)
+		}
+		if (attestation == nil) != (attestations[i] == nil) {
+			t.Fatalf("attestation %d presence mismatch: have %v, want %v", i, attestation, attestations[i])
+		}
+		if attestation != nil && attestation.Data.Hash() != attestations[i].Data.Hash() {
+			t.Fatalf("attestation %d mismatch", i)
+		}
+		if _, err := it.TotalDifficulty(); err == nil {
+			t.Fatalf("expected no total difficulty in parlia era")
+		}
+	}
+}
+
 func TestEraFilename(t *testing.T) {
 	t.Parallel()
 
@@ -52,7 +52,14 @@ func New(baseURL string, network string) (*Loader, error) {
 	default:
 		return nil, fmt.Errorf("missing era1 checksum definitions for network %q", network)
 	}
+	return NewWithChecksums(baseURL, network, checksums)
+}
 
+// NewWithChecksums creates an era1 loader for the given server URL and network
+// name, downloading the files listed in the given checksum manifest. This is
+// used for networks without built-in checksum definitions, with the manifest
+// written by export-history.
+func NewWithChecksums(baseURL string, network string, checksums []byte) (*Loader, error) {
 	csdb, err := download.ParseChecksums(checksums)
 	if err != nil {
 		return nil, fmt.Errorf("invalid checksums: %v", err)
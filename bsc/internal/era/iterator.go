@@ -103,6 +103,9 @@ func (it *Iterator) BlockAndReceipts() (*types.Block, types.Receipts, error) {
 // TotalDifficulty returns the total difficulty for the iterator's current
 // position.
 func (it *Iterator) TotalDifficulty() (*big.Int, error) {
+	if it.inner.TotalDifficulty == nil {
+		return nil, errors.New("total difficulty must be non-nil")
+	}
 	td, err := io.ReadAll(it.inner.TotalDifficulty)
 	if err != nil {
 		return nil, err
@@ -110,6 +113,23 @@ func (it *Iterator) TotalDifficulty() (*big.Int, error) {
 	return new(big.Int).SetBytes(reverseOrder(td)), nil
 }
 
+// VoteAttestation returns the vote attestation for the iterator's current
+// position in a Parlia era, or nil if the block doesn't carry one.
+func (it *Iterator) VoteAttestation() (*types.VoteAttestation, error) {
+	if it.inner.VoteAttestation == nil {
+		return nil, errors.New("vote attestation must be non-nil")
+	}
+	b, err := io.ReadAll(it.inner.VoteAttestation)
+	if err != nil || len(b) == 0 {
+		return nil, err
+	}
+	attestation := new(types.VoteAttestation)
+	if err := rlp.DecodeBytes(b, attestation); err != nil {
+		return nil, err
+	}
+	return attestation, nil
+}
+
 // RawIterator reads an RLP-encode Era1 entries.
 type RawIterator struct {
 	e    *Era   // backing Era1
@@ -119,7 +139,8 @@ type RawIterator struct {
 	Header          io.Reader
 	Body            io.Reader
 	Receipts        io.Reader
-	TotalDifficulty io.Reader
+	TotalDifficulty io.Reader // nil in Parlia eras
+	VoteAttestation io.Reader // only set in Parlia eras
 }
 
 // NewRawIterator returns a new RawIterator instance. Next must be immediately
@@ -133,7 +154,7 @@ func NewRawIterator(e *Era) (*RawIterator, error) {
 
 // Next moves the iterator to the next block entry. It returns false when all
 // items have been read or an error has halted its progress. Header, Body,
-// Receipts, TotalDifficulty will be set to nil in the case returning false or
+// Receipts, TotalDifficulty and VoteAttestation will be set to nil in the case returning false or
 // finding an error and should therefore no longer be read from.
 func (it *RawIterator) Next() bool {
 	// Clear old errors.
@@ -166,7 +187,12 @@ func (it *RawIterator) Next() bool {
 		return true
 	}
 	off += n
-	if it.TotalDifficulty, _, it.err = it.e.s.ReaderAt(TypeTotalDifficulty, off); it.err != nil {
+	if it.e.m.parlia {
+		if it.VoteAttestation, _, it.err = it.e.s.ReaderAt(TypeVoteAttestation, off); it.err != nil {
+			it.clear()
+			return true
+		}
+	} else if it.TotalDifficulty, _, it.err = it.e.s.ReaderAt(TypeTotalDifficulty, off); it.err != nil {
 		it.clear()
 		return true
 	}
@@ -194,4 +220,5 @@ func (it *RawIterator) clear() {
 	it.Body = nil
 	it.Receipts = nil
 	it.TotalDifficulty = nil
+	it.VoteAttestation = nil
 }
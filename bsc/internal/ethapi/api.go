@@ -40,6 +40,7 @@ import (
 	"github.com/ethereum/go-ethereum/core/forkid"
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
+	"github.com/ethereum/go-ethereum/core/systemcontracts"
 	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -52,7 +53,6 @@ import (
 	"github.com/ethereum/go-ethereum/params"
 	"github.com/ethereum/go-ethereum/rlp"
 	"github.com/ethereum/go-ethereum/rpc"
-	"github.com/ethereum/go-ethereum/trie"
 	"github.com/holiman/uint256"
 )
 
@@ -62,6 +62,14 @@ const UnHealthyTimeout = 5 * time.Second
 // allowed to produce in order to speed up calculations.
 const estimateGasErrorRatio = 0.015
 
+// defaultPrivateTxBlocks is the number of blocks a transaction submitted with
+// eth_sendPrivateRawTransaction is kept private for by default.
+const defaultPrivateTxBlocks = 20
+
+// maxAddressTransactions is the maximum number of transactions returned by a
+// single eth_getTransactionsByAddress call, also used if no limit is given.
+const maxAddressTransactions = 1000
+
 var errBlobTxNotSupported = errors.New("signing blob transactions not supported")
 var errSubClosed = errors.New("chain subscription closed")
 
@@ -330,6 +338,12 @@ func (api *BlockChainAPI) BlockNumber() hexutil.Uint64 {
 	return hexutil.Uint64(header.Number.Uint64())
 }
 
+// EarliestAvailableBlock returns the number of the oldest block whose body and
+// receipts are available, older ones having been pruned.
+func (api *BlockChainAPI) EarliestAvailableBlock() hexutil.Uint64 {
+	return hexutil.Uint64(api.b.HistoryPruningCutoff())
+}
+
 // GetBalance returns the amount of wei for the given address in the state of the
 // given block number. The rpc.LatestBlockNumber and rpc.PendingBlockNumber meta
 // block numbers are also allowed.
@@ -391,14 +405,19 @@ func (api *BlockChainAPI) GetProof(ctx context.Context, address common.Address,
 	if statedb == nil || err != nil {
 		return nil, err
 	}
+	// The tries are opened through the state database, which resolves the
+	// trie nodes of historical states from the trienode history.
+	db := statedb.Database()
+	if db.NoTries() {
+		return nil, errors.New("proofs are not available without tries")
+	}
 	codeHash := statedb.GetCodeHash(address)
 	storageRoot := statedb.GetStorageRoot(address)
 
 	if len(keys) > 0 {
 		var storageTrie state.Trie
 		if storageRoot != types.EmptyRootHash && storageRoot != (common.Hash{}) {
-			id := trie.StorageTrieID(header.Root, crypto.Keccak256Hash(address.Bytes()), storageRoot)
-			st, err := trie.NewStateTrie(id, statedb.Database().TrieDB())
+			st, err := db.OpenStorageTrie(header.Root, address, storageRoot, nil)
 			if err != nil {
 				return nil, err
 			}
@@ -429,7 +448,7 @@ func (api *BlockChainAPI) GetProof(ctx context.Context, address common.Address,
 		}
 	}
 	// Create the accountProof.
-	tr, err := trie.NewStateTrie(trie.StateTrieID(header.Root), statedb.Database().TrieDB())
+	tr, err := db.OpenTrie(header.Root)
 	if err != nil {
 		return nil, err
 	}
@@ -695,6 +714,244 @@ func (api *BlockChainAPI) GetStorageAt(ctx context.Context, address common.Addre
 	return res[:], state.Error()
 }
 
+// FreezeStatus is the governance freeze list record of an account.
+type FreezeStatus struct {
+	Address    common.Address `json:"address"`
+	Frozen     bool           `json:"frozen"`
+	ProposalID *hexutil.Big   `json:"proposalId,omitempty"` // Governance proposal which froze the account
+	Enforced   bool           `json:"enforced"`             // Whether the freeze list is enforced at the block
+}
+
+// GetFreezeStatus returns whether the given address is on the governance freeze
+// list at the given block, together with the proposal which froze it.
+func (api *BlockChainAPI) GetFreezeStatus(ctx context.Context, address common.Address, blockNrOrHash rpc.BlockNumberOrHash) (*FreezeStatus, error) {
+	state, header, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	status := &FreezeStatus{
+		Address:  address,
+		Enforced: api.b.ChainConfig().IsMendel(header.Number, header.Time),
+	}
+	if id := systemcontracts.FrozenBy(state, address); id != nil {
+		status.Frozen = true
+		status.ProposalID = (*hexutil.Big)(id)
+	}
+	return status, state.Error()
+}
+
+// GetAccruedYield returns the holder yield accrued to the given address in the
+// given token which has not been settled into its balance yet. The zero address
+// denotes the native coin. If no block is given, the latest one is used.
+func (api *BlockChainAPI) GetAccruedYield(ctx context.Context, address common.Address, token common.Address, blockNrOrHash *rpc.BlockNumberOrHash) (*hexutil.Big, error) {
+	bNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.LatestBlockNumber)
+	if blockNrOrHash != nil {
+		bNrOrHash = *blockNrOrHash
+	}
+	state, header, err := api.b.StateAndHeaderByNumberOrHash(ctx, bNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	// The yield index is only consulted from Mendel on, as when executing blocks
+	if api.b.ChainConfig().IsMendel(header.Number, header.Time) {
+		state.EnableYield()
+	}
+	return (*hexutil.Big)(state.AccruedYield(address, token).ToBig()), state.Error()
+}
+
+// PriceReport is a validator's signed report of the oracle price.
+type PriceReport struct {
+	Round       hexutil.Uint64 `json:"round"`
+	Price       *hexutil.Big   `json:"price"`
+	VoteAddress hexutil.Bytes  `json:"voteAddress"`
+	Signature   hexutil.Bytes  `json:"signature"`
+}
+
+// PriceOracleRound is the latest round of the validator price oracle.
+type PriceOracleRound struct {
+	Price       *hexutil.Big   `json:"price"` // Median of the reported prices, 18 decimals
+	Round       hexutil.Uint64 `json:"round"`
+	BlockNumber hexutil.Uint64 `json:"blockNumber"` // Block the round was included in
+	Timestamp   hexutil.Uint64 `json:"timestamp"`
+	Reports     []*PriceReport `json:"reports"`
+}
+
+// GetPriceOracle returns the latest round of the validator price oracle at the
+// given block, together with the signed reports its median was taken from. It
+// returns nil if no round has been included yet.
+func (api *BlockChainAPI) GetPriceOracle(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*PriceOracleRound, error) {
+	state, _, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	slot := func(index int64) common.Hash {
+		return state.GetState(params.PriceOracleAddress, common.BigToHash(big.NewInt(index)))
+	}
+	number := slot(params.PriceOracleNumberSlot).Big().Uint64()
+	if number == 0 {
+		return nil, state.Error()
+	}
+	result := &PriceOracleRound{
+		Price:       (*hexutil.Big)(slot(params.PriceOracleMedianSlot).Big()),
+		Round:       hexutil.Uint64(slot(params.PriceOracleRoundSlot).Big().Uint64()),
+		BlockNumber: hexutil.Uint64(number),
+		Timestamp:   hexutil.Uint64(slot(params.PriceOracleTimeSlot).Big().Uint64()),
+		Reports:     []*PriceReport{},
+	}
+	if err := state.Error(); err != nil {
+		return nil, err
+	}
+	// Retrieve the reports from the system transaction including the round
+	block, err := api.b.BlockByNumber(ctx, rpc.BlockNumber(number))
+	if block == nil || err != nil {
+		return nil, fmt.Errorf("block #%d including the price oracle round not found", number)
+	}
+	for _, tx := range block.Transactions() {
+		if tx.To() == nil || *tx.To() != params.PriceOracleAddress {
+			continue
+		}
+		var att types.PriceAttestation
+		if err := rlp.DecodeBytes(tx.Data(), &att); err != nil || att.Round != uint64(result.Round) {
+			continue
+		}
+		for _, report := range att.Reports {
+			result.Reports = append(result.Reports, &PriceReport{
+				Round:       hexutil.Uint64(report.Round),
+				Price:       (*hexutil.Big)(report.Price),
+				VoteAddress: report.VoteAddress[:],
+				Signature:   report.Signature[:],
+			})
+		}
+	}
+	return result, nil
+}
+
+// ReserveAsset is the attested reserve of a backing asset.
+type ReserveAsset struct {
+	Asset  common.Address `json:"asset"`
+	Amount *hexutil.Big   `json:"amount"`
+}
+
+// ReserveStatus compares the live supply of the reserve backed token to the
+// last attested reserve.
+type ReserveStatus struct {
+	Token          common.Address   `json:"token"`
+	Supply         *hexutil.Big     `json:"supply"`
+	Reserve        *hexutil.Big     `json:"reserve"` // Attested reserve of all assets, nil if none attested
+	Collateralized bool             `json:"collateralized"`
+	Nonce          hexutil.Uint64   `json:"nonce"`
+	SourceBlock    common.Hash      `json:"sourceBlock"`
+	AttestedAt     hexutil.Uint64   `json:"attestedAt"` // Block the attestation was included in
+	Assets         []ReserveAsset   `json:"assets"`
+	Signers        []common.Address `json:"signers"`
+}
+
+// GetReserveStatus returns the total supply of the reserve backed token at the
+// given block together with the last reserve attestation, with the amounts per
+// backing asset and the auditors who signed it.
+func (api *BlockChainAPI) GetReserveStatus(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*ReserveStatus, error) {
+	state, _, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	token, supplySlot := systemcontracts.ReserveToken(state)
+	if token == (common.Address{}) {
+		return nil, errors.New("no reserve backed token configured")
+	}
+	supply := state.GetState(token, supplySlot).Big()
+	status := &ReserveStatus{
+		Token:   token,
+		Supply:  (*hexutil.Big)(supply),
+		Assets:  []ReserveAsset{},
+		Signers: []common.Address{},
+	}
+	reserve := systemcontracts.GetReserve(state)
+	if err := state.Error(); err != nil || reserve == nil {
+		return status, err
+	}
+	status.Reserve = (*hexutil.Big)(reserve.Total)
+	status.Collateralized = supply.Cmp(reserve.Total) <= 0
+	status.Nonce = hexutil.Uint64(reserve.Nonce)
+	status.SourceBlock = reserve.SourceBlock
+	status.AttestedAt = hexutil.Uint64(reserve.Number)
+
+	// Retrieve the amounts and signers from the system transaction including it
+	block, err := api.b.BlockByNumber(ctx, rpc.BlockNumber(reserve.Number))
+	if block == nil || err != nil {
+		return nil, fmt.Errorf("block #%d including the reserve attestation not found", reserve.Number)
+	}
+	for _, tx := range block.Transactions() {
+		if tx.To() == nil || *tx.To() != systemcontracts.ReserveRegistryAddr {
+			continue
+		}
+		att, err := systemcontracts.UnpackReserveAttestation(tx.Data())
+		if err != nil || att.Nonce != reserve.Nonce {
+			continue
+		}
+		for _, asset := range att.Assets {
+			status.Assets = append(status.Assets, ReserveAsset{Asset: asset.Asset, Amount: (*hexutil.Big)(asset.Amount)})
+		}
+		status.Signers = att.Signers
+	}
+	return status, nil
+}
+
+// MintStatus is the utilisation of the mint limit of the reserve backed token.
+type MintStatus struct {
+	Token       common.Address `json:"token"`
+	Window      hexutil.Uint64 `json:"window"` // Length of the mint window in seconds, zero if unlimited
+	Limit       *hexutil.Big   `json:"limit"`
+	WindowStart hexutil.Uint64 `json:"windowStart"`
+	Minted      *hexutil.Big   `json:"minted"` // Amount minted in the window of the block
+	Remaining   *hexutil.Big   `json:"remaining"`
+	Paused      bool           `json:"paused"`
+	PauseReason string         `json:"pauseReason,omitempty"`
+}
+
+// GetMintStatus returns how much of the mint limit of the reserve backed token
+// is used in the mint window of the given block, and whether minting is
+// paused.
+func (api *BlockChainAPI) GetMintStatus(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) (*MintStatus, error) {
+	state, header, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	token, _ := systemcontracts.ReserveToken(state)
+	if token == (common.Address{}) {
+		return nil, errors.New("no reserve backed token configured")
+	}
+	limit := systemcontracts.GetMintLimit(state)
+	if err := state.Error(); err != nil {
+		return nil, err
+	}
+	status := &MintStatus{
+		Token:  token,
+		Window: hexutil.Uint64(limit.Window),
+		Paused: limit.Pause != 0,
+	}
+	if limit.Window != 0 {
+		minted := limit.Used(header.Time)
+		remaining := new(big.Int).Sub(limit.Limit, minted)
+		if remaining.Sign() < 0 {
+			remaining.SetUint64(0)
+		}
+		status.Limit = (*hexutil.Big)(limit.Limit)
+		status.WindowStart = hexutil.Uint64(header.Time - header.Time%limit.Window)
+		status.Minted = (*hexutil.Big)(minted)
+		status.Remaining = (*hexutil.Big)(remaining)
+	}
+	switch limit.Pause {
+	case 0:
+	case systemcontracts.MintPauseLimitExceeded:
+		status.PauseReason = "mint limit exceeded"
+	case systemcontracts.MintPauseReserveExceeded:
+		status.PauseReason = "reserve exceeded"
+	default:
+		status.PauseReason = "governance"
+	}
+	return status, nil
+}
+
 // GetBlockReceipts returns the block receipts for the given block hash or number or tag.
 func (api *BlockChainAPI) GetBlockReceipts(ctx context.Context, blockNrOrHash rpc.BlockNumberOrHash) ([]map[string]interface{}, error) {
 	var (
@@ -1975,6 +2232,45 @@ func (api *TransactionAPI) GetTransactionByBlockHashAndIndex(ctx context.Context
 	return nil, err
 }
 
+// AddressTransactions is a page of the transactions involving an address, along
+// with the position of the following page.
+type AddressTransactions struct {
+	Transactions []*RPCTransaction `json:"transactions"`
+	Next         hexutil.Bytes     `json:"next,omitempty"`
+}
+
+// GetTransactionsByAddress returns the transactions involving the given address
+// from the most recent, starting at the given position of the address index.
+// If token is specified, only the transactions transferring the token are
+// returned, the zero address denoting the native coin.
+func (api *TransactionAPI) GetTransactionsByAddress(ctx context.Context, address common.Address, start hexutil.Bytes, limit hexutil.Uint, token *common.Address) (*AddressTransactions, error) {
+	if limit == 0 || limit > maxAddressTransactions {
+		limit = maxAddressTransactions
+	}
+	txs, next, err := api.b.GetTransactionsByAddress(ctx, address, token, start, int(limit))
+	if err != nil {
+		return nil, err
+	}
+	var (
+		result = &AddressTransactions{Transactions: make([]*RPCTransaction, 0, len(txs)), Next: next}
+		block  *types.Block
+	)
+	for _, tx := range txs {
+		if block == nil || block.NumberU64() != tx.Number {
+			if block, err = api.b.BlockByNumber(ctx, rpc.BlockNumber(tx.Number)); err != nil {
+				return nil, err
+			}
+		}
+		// Skip the transactions of the blocks reorged since they were read
+		// from the index.
+		if block == nil || tx.Index >= uint32(len(block.Transactions())) || block.Transactions()[tx.Index].Hash() != tx.Hash {
+			continue
+		}
+		result.Transactions = append(result.Transactions, newRPCTransactionFromBlockIndex(block, uint64(tx.Index), api.b.ChainConfig()))
+	}
+	return result, nil
+}
+
 // GetRawTransactionByBlockNumberAndIndex returns the bytes of the transaction for the given block number and index.
 func (api *TransactionAPI) GetRawTransactionByBlockNumberAndIndex(ctx context.Context, blockNr rpc.BlockNumber, index hexutil.Uint) hexutil.Bytes {
 	if block, _ := api.b.BlockByNumber(ctx, blockNr); block != nil {
@@ -2505,6 +2801,33 @@ func (api *TransactionAPI) SendRawTransactionConditional(ctx context.Context, in
 	return SubmitTransaction(ctx, api.b, tx)
 }
 
+// SendPrivateRawTransaction will forward the signed transaction only to the
+// validators in turn to propose the next blocks, over direct connections,
+// instead of gossiping it to all peers. The transaction is kept private until
+// maxBlockNumber, which defaults to defaultPrivateTxBlocks after the current
+// block. If it is not included by then, it is broadcast publicly.
+func (api *TransactionAPI) SendPrivateRawTransaction(ctx context.Context, input hexutil.Bytes, maxBlockNumber *hexutil.Uint64) (common.Hash, error) {
+	tx := new(types.Transaction)
+	if err := tx.UnmarshalBinary(input); err != nil {
+		return common.Hash{}, err
+	}
+	if err := checkTxFee(tx.GasPrice(), tx.Gas(), api.b.RPCTxFeeCap()); err != nil {
+		return common.Hash{}, err
+	}
+	if !api.b.UnprotectedAllowed() && !tx.Protected() {
+		return common.Hash{}, errors.New("only replay-protected (EIP-155) transactions allowed over RPC")
+	}
+	deadline := api.b.CurrentHeader().Number.Uint64() + defaultPrivateTxBlocks
+	if maxBlockNumber != nil {
+		deadline = uint64(*maxBlockNumber)
+	}
+	if err := api.b.SendPrivateTx(ctx, tx, deadline); err != nil {
+		return common.Hash{}, err
+	}
+	log.Info("Submitted private transaction", "hash", tx.Hash().Hex(), "nonce", tx.Nonce(), "maxBlockNumber", deadline, "x-forward-ip", ctx.Value("X-Forwarded-For"))
+	return tx.Hash(), nil
+}
+
 // Sign calculates an ECDSA signature for:
 // keccak256("\x19Ethereum Signed Message:\n" + len(message) + message).
 //
@@ -0,0 +1,173 @@
+package ethapi
+
+import (
+	"context"
+	"errors"
+	gomath "math"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus/misc/eip1559"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BundleAPI offers the methods to submit and simulate transaction bundles.
+type BundleAPI struct {
+	b Backend
+}
+
+// NewBundleAPI creates a new BundleAPI.
+func NewBundleAPI(b Backend) *BundleAPI {
+	return &BundleAPI{b}
+}
+
+// checkBundleTxs ensures the transactions of a bundle are acceptable over RPC.
+func (api *BundleAPI) checkBundleTxs(bundle *types.Bundle) error {
+	for _, tx := range bundle.Txs {
+		if err := checkTxFee(tx.GasPrice(), tx.Gas(), api.b.RPCTxFeeCap()); err != nil {
+			return err
+		}
+		if !api.b.UnprotectedAllowed() && !tx.Protected() {
+			return errors.New("only replay-protected (EIP-155) transactions allowed over RPC")
+		}
+	}
+	return nil
+}
+
+// SendBundle adds a bundle to the bundle pool of the node, to be included
+// atomically and in order in the blocks it builds between the minimum and the
+// maximum block number. The maximum defaults to the next block. Transactions
+// listed as reverting may fail without failing the bundle.
+func (api *BundleAPI) SendBundle(ctx context.Context, args types.SendBundleArgs) (common.Hash, error) {
+	bundle, err := args.ToBundle()
+	if err != nil {
+		return common.Hash{}, err
+	}
+	if err := api.checkBundleTxs(bundle); err != nil {
+		return common.Hash{}, err
+	}
+	if err := api.b.SendBundle(ctx, bundle); err != nil {
+		return common.Hash{}, err
+	}
+	log.Info("Submitted bundle", "hash", bundle.Hash(), "txs", len(bundle.Txs), "min", bundle.MinBlockNumber, "max", bundle.MaxBlockNumber, "x-forward-ip", ctx.Value("X-Forwarded-For"))
+	return bundle.Hash(), nil
+}
+
+// CallBundleArgs represents the arguments to simulate a bundle.
+type CallBundleArgs struct {
+	Txs               []hexutil.Bytes        `json:"txs"`
+	RevertingTxHashes []common.Hash          `json:"revertingTxHashes"`
+	StateBlockNumber  *rpc.BlockNumberOrHash `json:"stateBlockNumber"` // Block to build on, defaults to pending
+	Timestamp         *hexutil.Uint64        `json:"timestamp"`        // Timestamp of the simulated block, defaults to the parent's
+}
+
+// CallBundleTxResult is the outcome of a transaction of a simulated bundle.
+type CallBundleTxResult struct {
+	TxHash  common.Hash     `json:"txHash"`
+	From    common.Address  `json:"from"`
+	To      *common.Address `json:"to"`
+	GasUsed hexutil.Uint64  `json:"gasUsed"`
+	Status  hexutil.Uint64  `json:"status"`
+	Logs    []*types.Log    `json:"logs"`
+}
+
+// CallBundleResult is the outcome of a simulated bundle.
+type CallBundleResult struct {
+	BundleHash       common.Hash           `json:"bundleHash"`
+	StateBlockNumber hexutil.Uint64        `json:"stateBlockNumber"`
+	Results          []*CallBundleTxResult `json:"results"`
+	GasUsed          hexutil.Uint64        `json:"gasUsed"`
+	Fees             *hexutil.Big          `json:"fees"`           // Fees and direct payments to the block producer
+	BundleGasPrice   *hexutil.Big          `json:"bundleGasPrice"` // Fees per gas
+}
+
+// CallBundle simulates a bundle in a block built on top of the given state,
+// the pending one by default. The simulation fails like the inclusion of the
+// bundle would, if a transaction is invalid or fails without being listed as
+// reverting.
+func (api *BundleAPI) CallBundle(ctx context.Context, args CallBundleArgs) (*CallBundleResult, error) {
+	bundle, err := (&types.SendBundleArgs{Txs: args.Txs, RevertingTxHashes: args.RevertingTxHashes}).ToBundle()
+	if err != nil {
+		return nil, err
+	}
+	blockNrOrHash := rpc.BlockNumberOrHashWithNumber(rpc.PendingBlockNumber)
+	if args.StateBlockNumber != nil {
+		blockNrOrHash = *args.StateBlockNumber
+	}
+	state, parent, err := api.b.StateAndHeaderByNumberOrHash(ctx, blockNrOrHash)
+	if state == nil || err != nil {
+		return nil, err
+	}
+	config := api.b.ChainConfig()
+	header := &types.Header{
+		ParentHash: parent.Hash(),
+		Coinbase:   parent.Coinbase,
+		Difficulty: parent.Difficulty,
+		Number:     new(big.Int).Add(parent.Number, common.Big1),
+		GasLimit:   parent.GasLimit,
+		Time:       parent.Time,
+	}
+	if args.Timestamp != nil {
+		header.Time = uint64(*args.Timestamp)
+	}
+	if config.IsLondon(header.Number) {
+		header.BaseFee = eip1559.CalcBaseFee(config, parent)
+	}
+	// Setup context so it may be cancelled when the call has completed
+	// or, in case of unmetered gas, setup a context with a timeout.
+	var cancel context.CancelFunc
+	if timeout := api.b.RPCEVMTimeout(); timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+	} else {
+		ctx, cancel = context.WithCancel(ctx)
+	}
+	defer cancel()
+
+	blockCtx := core.NewEVMBlockContext(header, NewChainContext(ctx, api.b), nil)
+	evm := vm.NewEVM(blockCtx, state, config, vm.Config{})
+	go func() {
+		<-ctx.Done()
+		evm.Cancel()
+	}()
+	var (
+		usedGas uint64
+		gp      = new(core.GasPool).AddGas(gomath.MaxUint64)
+	)
+	if gasCap := api.b.RPCGasCap(); gasCap != 0 {
+		gp.SetGas(gasCap)
+	}
+	receipts, sim, err := core.ApplyBundle(evm, gp, state, header, bundle, &usedGas, 0)
+	if evm.Cancelled() {
+		return nil, errors.New("execution aborted (timeout)")
+	}
+	if err != nil {
+		return nil, err
+	}
+	signer := types.MakeSigner(config, header.Number, header.Time)
+	results := make([]*CallBundleTxResult, len(receipts))
+	for i, receipt := range receipts {
+		tx := bundle.Txs[i]
+		from, _ := types.Sender(signer, tx)
+		results[i] = &CallBundleTxResult{
+			TxHash:  tx.Hash(),
+			From:    from,
+			To:      tx.To(),
+			GasUsed: hexutil.Uint64(receipt.GasUsed),
+			Status:  hexutil.Uint64(receipt.Status),
+			Logs:    receipt.Logs,
+		}
+	}
+	return &CallBundleResult{
+		BundleHash:       bundle.Hash(),
+		StateBlockNumber: hexutil.Uint64(parent.Number.Uint64()),
+		Results:          results,
+		GasUsed:          hexutil.Uint64(sim.GasUsed),
+		Fees:             (*hexutil.Big)(sim.Fees),
+		BundleGasPrice:   (*hexutil.Big)(sim.Price()),
+	}, nil
+}
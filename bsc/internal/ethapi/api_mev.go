@@ -7,6 +7,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/log"
 	"github.com/ethereum/go-ethereum/params"
 )
 
@@ -87,6 +88,13 @@ func (m *MevAPI) SendBid(ctx context.Context, args types.BidArgs) (common.Hash,
 	return m.b.SendBid(ctx, &args)
 }
 
+// ReportIssue receives the issues validators found with the bids submitted by
+// the local builder.
+func (m *MevAPI) ReportIssue(ctx context.Context, issue types.BidIssue) error {
+	log.Info("Bid issue reported", "validator", issue.Validator, "builder", issue.Builder, "bidHash", issue.BidHash, "msg", issue.Message)
+	return nil
+}
+
 func (m *MevAPI) Params() *types.MevParams {
 	return m.b.MevParams()
 }
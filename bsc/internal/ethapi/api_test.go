@@ -634,6 +634,24 @@ func (b testBackend) SubscribeFinalizedHeaderEvent(ch chan<- core.FinalizedHeade
 func (b testBackend) SubscribeNewVoteEvent(ch chan<- core.NewVoteEvent) event.Subscription {
 	panic("implement me")
 }
+func (b testBackend) GetVoteAttestation(header *types.Header) (*types.VoteAttestation, error) {
+	panic("implement me")
+}
+func (b testBackend) GetFinalityAttestation(header *types.Header) (*types.VoteAttestation, error) {
+	panic("implement me")
+}
+func (b testBackend) GetNativeTransfers(ctx context.Context, blockHash common.Hash, number uint64) ([]*types.NativeTransfer, error) {
+	panic("implement me")
+}
+func (b testBackend) GetTransactionsByAddress(ctx context.Context, address common.Address, token *common.Address, start []byte, limit int) ([]rawdb.AddressTx, []byte, error) {
+	panic("implement me")
+}
+func (b *testBackend) SendPrivateTx(ctx context.Context, tx *types.Transaction, maxBlockNumber uint64) error {
+	panic("implement me")
+}
+func (b *testBackend) SendBundle(ctx context.Context, bundle *types.Bundle) error {
+	panic("implement me")
+}
 func (b *testBackend) SendTx(ctx context.Context, tx *types.Transaction) error {
 	b.sentTx = tx
 	b.sentTxHash = tx.Hash()
@@ -4392,3 +4410,68 @@ func TestSendRawTransactionSync_Timeout(t *testing.T) {
 		t.Fatalf("expected ErrorData=%s, got %v", want, got)
 	}
 }
+
+// Tests that the accrued holder yield is reported from the Mendel fork on, as
+// the yield index is only consulted by blocks executed under it.
+func TestGetAccruedYield(t *testing.T) {
+	t.Parallel()
+
+	var (
+		mendel = uint64(15)
+		config = *params.TestChainConfig
+		holder = common.Address{0xaa}
+		scale  = new(big.Int).Exp(big.NewInt(10), big.NewInt(27), nil)
+
+		// Storage keys of the native token mappings in the yield index account
+		tokenKey = func(slot byte) common.Hash {
+			return crypto.Keccak256Hash(common.Hash{}.Bytes(), common.Hash{31: slot}.Bytes())
+		}
+		holderKey = func(slot byte) common.Hash {
+			return crypto.Keccak256Hash(common.LeftPadBytes(holder.Bytes(), common.HashLength), tokenKey(slot).Bytes())
+		}
+	)
+	config.MendelTime = &mendel
+
+	// Two units of yield accrued per unit of the holder's recorded balance
+	genesis := &core.Genesis{
+		Config: &config,
+		Alloc: types.GenesisAlloc{
+			holder: {Balance: big.NewInt(100)},
+			params.YieldIndexAddress: {
+				Nonce:   1,
+				Balance: big.NewInt(1000),
+				Storage: map[common.Hash]common.Hash{
+					tokenKey(0):  common.BigToHash(new(big.Int).Mul(scale, big.NewInt(2))),
+					tokenKey(1):  common.BigToHash(big.NewInt(100)),
+					holderKey(3): common.BigToHash(big.NewInt(100)),
+				},
+			},
+		},
+	}
+	api := NewBlockChainAPI(newTestBackend(t, 2, genesis, ethash.NewFaker(), nil))
+
+	for _, tt := range []struct {
+		number rpc.BlockNumber
+		want   int64
+	}{
+		{1, 0},   // before Mendel
+		{2, 200}, // from Mendel on
+	} {
+		block := rpc.BlockNumberOrHashWithNumber(tt.number)
+		have, err := api.GetAccruedYield(context.Background(), holder, state.NativeYieldToken, &block)
+		if err != nil {
+			t.Fatalf("block %d: failed to get accrued yield: %v", tt.number, err)
+		}
+		if have.ToInt().Int64() != tt.want {
+			t.Errorf("block %d: accrued yield mismatch: have %v, want %d", tt.number, have.ToInt(), tt.want)
+		}
+	}
+	// Untracked tokens never accrue anything
+	have, err := api.GetAccruedYield(context.Background(), holder, common.Address{0x01}, nil)
+	if err != nil {
+		t.Fatalf("failed to get accrued yield: %v", err)
+	}
+	if have.ToInt().Sign() != 0 {
+		t.Errorf("accrued yield of untracked token: %v", have.ToInt())
+	}
+}
@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/filtermaps"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -81,6 +82,8 @@ type Backend interface {
 
 	// Transaction pool API
 	SendTx(ctx context.Context, signedTx *types.Transaction) error
+	SendPrivateTx(ctx context.Context, signedTx *types.Transaction, maxBlockNumber uint64) error
+	SendBundle(ctx context.Context, bundle *types.Bundle) error
 	GetCanonicalTransaction(txHash common.Hash) (bool, *types.Transaction, common.Hash, uint64, uint64)
 	TxIndexDone() bool
 	GetPoolTransactions() (types.Transactions, error)
@@ -106,6 +109,10 @@ type Backend interface {
 	SubscribeLogsEvent(ch chan<- []*types.Log) event.Subscription
 	SubscribeFinalizedHeaderEvent(ch chan<- core.FinalizedHeaderEvent) event.Subscription
 	SubscribeNewVoteEvent(chan<- core.NewVoteEvent) event.Subscription
+	GetVoteAttestation(header *types.Header) (*types.VoteAttestation, error)
+	GetFinalityAttestation(header *types.Header) (*types.VoteAttestation, error)
+	GetNativeTransfers(ctx context.Context, blockHash common.Hash, number uint64) ([]*types.NativeTransfer, error)
+	GetTransactionsByAddress(ctx context.Context, address common.Address, token *common.Address, start []byte, limit int) ([]rawdb.AddressTx, []byte, error)
 
 	// MevRunning return true if mev is running
 	MevRunning() bool
@@ -151,6 +158,9 @@ func GetAPIs(apiBackend Backend) []rpc.API {
 		}, {
 			Namespace: "eth",
 			Service:   NewEthereumAccountAPI(apiBackend.AccountManager()),
+		}, {
+			Namespace: "eth",
+			Service:   NewBundleAPI(apiBackend),
 		}, {
 			Namespace: "mev",
 			Service:   NewMevAPI(apiBackend),
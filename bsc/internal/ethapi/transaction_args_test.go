@@ -31,6 +31,7 @@ import (
 	"github.com/ethereum/go-ethereum/consensus"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/filtermaps"
+	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/core/vm"
@@ -394,7 +395,23 @@ func (b *backendMock) SubscribeFinalizedHeaderEvent(ch chan<- core.FinalizedHead
 func (b *backendMock) SubscribeNewVoteEvent(ch chan<- core.NewVoteEvent) event.Subscription {
 	return nil
 }
+func (b *backendMock) GetVoteAttestation(header *types.Header) (*types.VoteAttestation, error) {
+	return nil, nil
+}
+func (b *backendMock) GetFinalityAttestation(header *types.Header) (*types.VoteAttestation, error) {
+	return nil, nil
+}
+func (b *backendMock) GetNativeTransfers(ctx context.Context, blockHash common.Hash, number uint64) ([]*types.NativeTransfer, error) {
+	return nil, nil
+}
+func (b *backendMock) GetTransactionsByAddress(ctx context.Context, address common.Address, token *common.Address, start []byte, limit int) ([]rawdb.AddressTx, []byte, error) {
+	return nil, nil, nil
+}
 func (b *backendMock) SendTx(ctx context.Context, signedTx *types.Transaction) error { return nil }
+func (b *backendMock) SendBundle(ctx context.Context, bundle *types.Bundle) error    { return nil }
+func (b *backendMock) SendPrivateTx(ctx context.Context, signedTx *types.Transaction, maxBlockNumber uint64) error {
+	return nil
+}
 func (b *backendMock) GetCanonicalTransaction(txHash common.Hash) (bool, *types.Transaction, common.Hash, uint64, uint64) {
 	return false, nil, [32]byte{}, 0, 0
 }
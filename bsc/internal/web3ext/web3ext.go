@@ -67,6 +67,11 @@ web3._extend({
 			params: 1,
 			inputFormatter: [web3._extend.formatters.inputBlockNumberFormatter]
 		}),
+		new web3._extend.Method({
+			name: 'submitReserveAttestation',
+			call: 'parlia_submitReserveAttestation',
+			params: 1
+		}),
 	],
 	properties: []
 });
@@ -144,6 +149,10 @@ web3._extend({
 			name: 'stopWS',
 			call: 'admin_stopWS'
 		}),
+		new web3._extend.Method({
+			name: 'startPrune',
+			call: 'admin_startPrune'
+		}),
 	],
 	properties: [
 		new web3._extend.Property({
@@ -158,6 +167,10 @@ web3._extend({
 			name: 'datadir',
 			getter: 'admin_datadir'
 		}),
+		new web3._extend.Property({
+			name: 'pruneStatus',
+			getter: 'admin_pruneStatus'
+		}),
 	]
 });
 `
@@ -591,6 +604,69 @@ web3._extend({
 			call: 'eth_getBlobSidecarByTxHash',
 			params: 2,
 		}),
+		new web3._extend.Method({
+			name: 'getFreezeStatus',
+			call: 'eth_getFreezeStatus',
+			params: 2,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter],
+		}),
+		new web3._extend.Method({
+			name: 'getPriceOracle',
+			call: 'eth_getPriceOracle',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter],
+		}),
+		new web3._extend.Method({
+			name: 'getReserveStatus',
+			call: 'eth_getReserveStatus',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter],
+		}),
+		new web3._extend.Method({
+			name: 'getMintStatus',
+			call: 'eth_getMintStatus',
+			params: 1,
+			inputFormatter: [web3._extend.formatters.inputDefaultBlockNumberFormatter],
+		}),
+		new web3._extend.Method({
+			name: 'getNativeTransfers',
+			call: 'eth_getNativeTransfers',
+			params: 2,
+			inputFormatter: [null, web3._extend.formatters.inputAddressFormatter],
+		}),
+		new web3._extend.Method({
+			name: 'getNativeTransferLogs',
+			call: 'eth_getNativeTransferLogs',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'getTransactionsByAddress',
+			call: 'eth_getTransactionsByAddress',
+			params: 4,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, null, null, null],
+		}),
+		new web3._extend.Method({
+			name: 'sendBundle',
+			call: 'eth_sendBundle',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'callBundle',
+			call: 'eth_callBundle',
+			params: 1,
+		}),
+		new web3._extend.Method({
+			name: 'sendPrivateRawTransaction',
+			call: 'eth_sendPrivateRawTransaction',
+			params: 2,
+			inputFormatter: [null, web3._extend.utils.fromDecimal],
+		}),
+		new web3._extend.Method({
+			name: 'getAccruedYield',
+			call: 'eth_getAccruedYield',
+			params: 3,
+			inputFormatter: [web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputAddressFormatter, web3._extend.formatters.inputDefaultBlockNumberFormatter],
+		}),
 		new web3._extend.Method({
 			name: 'config',
 			call: 'eth_config',
@@ -615,6 +691,11 @@ web3._extend({
 			getter: 'eth_maxPriorityFeePerGas',
 			outputFormatter: web3._extend.utils.toBigNumber
 		}),
+		new web3._extend.Property({
+			name: 'earliestAvailableBlock',
+			getter: 'eth_earliestAvailableBlock',
+			outputFormatter: web3._extend.utils.toDecimal
+		}),
 	]
 });
 `
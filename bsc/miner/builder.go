@@ -0,0 +1,241 @@
+package miner
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/consensus"
+	"github.com/ethereum/go-ethereum/core"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/event"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/miner/minerconfig"
+	"github.com/ethereum/go-ethereum/params"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+const bidSubmitTimeout = time.Second
+
+var errNoBidFees = errors.New("no fees to bid")
+
+// bidSender submits bids to a validator.
+type bidSender interface {
+	SendBid(ctx context.Context, args types.BidArgs) (common.Hash, error)
+}
+
+type bidValidator struct {
+	url    string
+	sender bidSender
+}
+
+// localBuilder builds blocks from the local transaction pool on top of every
+// new head and submits them as bids to the configured validators. It is a
+// reference builder to run proposer-builder flows without external builders.
+type localBuilder struct {
+	config     *minerconfig.LocalBuilderConfig
+	key        *ecdsa.PrivateKey
+	address    common.Address
+	worker     *worker
+	validators []*bidValidator
+
+	chainHeadCh  chan core.ChainHeadEvent
+	chainHeadSub event.Subscription
+	exitCh       chan struct{}
+	wg           sync.WaitGroup
+}
+
+func newLocalBuilder(config *minerconfig.LocalBuilderConfig, worker *worker) (*localBuilder, error) {
+	key, err := crypto.LoadECDSA(config.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load builder key: %v", err)
+	}
+	b := &localBuilder{
+		config:      config,
+		key:         key,
+		address:     crypto.PubkeyToAddress(key.PublicKey),
+		worker:      worker,
+		chainHeadCh: make(chan core.ChainHeadEvent, chainHeadChanSize),
+		exitCh:      make(chan struct{}),
+	}
+	for _, url := range config.Validators {
+		c, err := rpc.DialOptions(context.Background(), url, rpc.WithHTTPClient(client))
+		if err != nil {
+			return nil, fmt.Errorf("failed to dial validator %s: %v", url, err)
+		}
+		b.validators = append(b.validators, &bidValidator{url: url, sender: ethclient.NewClient(c)})
+	}
+	return b, nil
+}
+
+func (b *localBuilder) start() {
+	b.chainHeadSub = b.worker.chain.SubscribeChainHeadEvent(b.chainHeadCh)
+
+	b.wg.Add(1)
+	go b.loop()
+
+	log.Info("LocalBuilder: started", "builder", b.address, "validators", len(b.validators))
+}
+
+func (b *localBuilder) close() {
+	close(b.exitCh)
+	b.wg.Wait()
+}
+
+// loop builds a bid every bid interval after a new head, and submits it if it
+// pays more than the previous one, until the maximum number of bids for the
+// block is reached.
+func (b *localBuilder) loop() {
+	defer b.wg.Done()
+	defer b.chainHeadSub.Unsubscribe()
+
+	var (
+		timer = time.NewTimer(0)
+		head  *types.Header
+		bids  uint32
+		best  *big.Int
+	)
+	defer timer.Stop()
+	<-timer.C // discard the initial tick
+
+	for {
+		select {
+		case ev := <-b.chainHeadCh:
+			head, bids, best = ev.Header, 0, nil
+			timer.Reset(*b.config.BidInterval)
+
+		case <-timer.C:
+			if head == nil || b.worker.syncing.Load() {
+				continue
+			}
+			args, err := b.buildBid(head)
+			switch {
+			case errors.Is(err, errNoBidFees):
+			case err != nil:
+				log.Warn("LocalBuilder: failed to build bid", "number", head.Number.Uint64()+1, "err", err)
+			case best == nil || args.RawBid.GasFee.Cmp(best) > 0:
+				b.submit(args)
+				bids, best = bids+1, args.RawBid.GasFee
+			}
+			if bids < *b.config.MaxBids {
+				timer.Reset(*b.config.BidInterval)
+			}
+
+		case <-b.chainHeadSub.Err():
+			return
+		case <-b.exitCh:
+			return
+		}
+	}
+}
+
+// buildBid builds a block on top of parent from the local transaction pool and
+// returns it as a signed bid. The bid pays no builder fee, its payment
+// transaction is a zero-value transfer of the builder to itself.
+func (b *localBuilder) buildBid(parent *types.Header) (*types.BidArgs, error) {
+	env, err := b.worker.prepareWork(&generateParams{
+		parentHash: parent.Hash(),
+		coinbase:   b.address,
+	}, false)
+	if err != nil {
+		return nil, err
+	}
+	defer env.discard()
+
+	// The fees are credited to the system address on BSC, which the validators
+	// measure the bids with.
+	recipient := env.header.Coinbase
+	if b.worker.chainConfig.IsInBSC() {
+		recipient = consensus.SystemAddress
+	}
+	balance := env.state.GetBalance(recipient).ToBig()
+
+	// Leave room for the payment transaction appended by the validator
+	b.worker.initGasPool(env)
+	env.gasPool.SubGas(params.PayBidTxGasLimit)
+
+	stopTimer := time.NewTimer(*b.config.BidInterval)
+	defer stopTimer.Stop()
+	err = b.worker.fillTransactions(nil, env, stopTimer, nil)
+	if err != nil && !errors.Is(err, errBlockInterruptedByTimeout) && !errors.Is(err, errBlockInterruptedByOutOfGas) {
+		return nil, err
+	}
+	fee := new(big.Int).Sub(env.state.GetBalance(recipient).ToBig(), balance)
+	if len(env.txs) == 0 || fee.Sign() <= 0 {
+		return nil, errNoBidFees
+	}
+	txs := make([]hexutil.Bytes, len(env.txs))
+	for i, tx := range env.txs {
+		if txs[i], err = tx.MarshalBinary(); err != nil {
+			return nil, err
+		}
+	}
+	gasPrice := new(big.Int)
+	if env.header.BaseFee != nil {
+		gasPrice.Set(env.header.BaseFee)
+	}
+	payBidTx, err := types.SignNewTx(b.key, env.signer, &types.LegacyTx{
+		Nonce:    env.state.GetNonce(b.address),
+		To:       &b.address,
+		Value:    new(big.Int),
+		Gas:      params.TxGas,
+		GasPrice: gasPrice,
+	})
+	if err != nil {
+		return nil, err
+	}
+	payBidTxBytes, err := payBidTx.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	rawBid := &types.RawBid{
+		BlockNumber: env.header.Number.Uint64(),
+		ParentHash:  parent.Hash(),
+		Txs:         txs,
+		GasUsed:     env.header.GasUsed,
+		GasFee:      fee,
+		BuilderFee:  new(big.Int),
+	}
+	signature, err := crypto.Sign(rawBid.Hash().Bytes(), b.key)
+	if err != nil {
+		return nil, err
+	}
+	return &types.BidArgs{
+		RawBid:          rawBid,
+		Signature:       signature,
+		PayBidTx:        payBidTxBytes,
+		PayBidTxGasUsed: params.TxGas,
+	}, nil
+}
+
+// submit sends the bid to all the validators. Only the in-turn one accepts it,
+// so the rejections of the others are logged at debug level.
+func (b *localBuilder) submit(args *types.BidArgs) {
+	var wg sync.WaitGroup
+	for _, validator := range b.validators {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			ctx, cancel := context.WithTimeout(context.Background(), bidSubmitTimeout)
+			defer cancel()
+
+			hash, err := validator.sender.SendBid(ctx, *args)
+			if err != nil {
+				log.Debug("LocalBuilder: bid rejected", "validator", validator.url, "number", args.RawBid.BlockNumber, "err", err)
+				return
+			}
+			log.Info("LocalBuilder: bid submitted", "validator", validator.url, "number", args.RawBid.BlockNumber,
+				"hash", hash, "txs", len(args.RawBid.Txs), "gasUsed", args.RawBid.GasUsed, "gasFee", weiToEtherStringF6(args.RawBid.GasFee))
+		}()
+	}
+	wg.Wait()
+}
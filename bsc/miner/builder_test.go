@@ -0,0 +1,91 @@
+package miner
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/consensus/ethash"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/miner/minerconfig"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+type testBidSender struct {
+	err  error
+	bids []types.BidArgs
+	lock sync.Mutex
+}
+
+func (s *testBidSender) SendBid(ctx context.Context, args types.BidArgs) (common.Hash, error) {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	s.bids = append(s.bids, args)
+	return args.RawBid.Hash(), s.err
+}
+
+// Tests that the local builder turns the pending transactions into a bid signed
+// by the builder, and submits it to every validator.
+func TestLocalBuilderBid(t *testing.T) {
+	t.Parallel()
+
+	engine := ethash.NewFaker()
+	defer engine.Close()
+
+	w, b := newTestWorker(t, ethashChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+	defer w.close()
+
+	key, _ := crypto.GenerateKey()
+	interval := time.Second
+	var (
+		accepting = new(testBidSender)
+		rejecting = &testBidSender{err: errors.New("not in turn")}
+		builder   = &localBuilder{
+			config:  &minerconfig.LocalBuilderConfig{BidInterval: &interval},
+			key:     key,
+			address: crypto.PubkeyToAddress(key.PublicKey),
+			worker:  w,
+			validators: []*bidValidator{
+				{url: "accepting", sender: accepting},
+				{url: "rejecting", sender: rejecting},
+			},
+		}
+	)
+	head := b.chain.CurrentBlock()
+	args, err := builder.buildBid(head)
+	if err != nil {
+		t.Fatalf("failed to build bid: %v", err)
+	}
+	if sender, err := args.EcrecoverSender(); err != nil || sender != builder.address {
+		t.Fatalf("bid signer mismatch: have %v (%v), want %v", sender, err, builder.address)
+	}
+	bid, err := args.ToBid(builder.address, types.LatestSigner(ethashChainConfig))
+	if err != nil {
+		t.Fatalf("failed to decode bid: %v", err)
+	}
+	// The payment transaction is appended to the decoded transactions
+	if len(bid.Txs) != len(pendingTxs)+1 {
+		t.Fatalf("bid transaction count mismatch: have %d, want %d", len(bid.Txs), len(pendingTxs)+1)
+	}
+	for i, tx := range pendingTxs {
+		if bid.Txs[i].Hash() != tx.Hash() {
+			t.Errorf("bid transaction %d mismatch: have %x, want %x", i, bid.Txs[i].Hash(), tx.Hash())
+		}
+	}
+	if bid.BlockNumber != head.Number.Uint64()+1 || bid.ParentHash != head.Hash() {
+		t.Errorf("bid block mismatch: have %d/%x, want %d/%x", bid.BlockNumber, bid.ParentHash, head.Number.Uint64()+1, head.Hash())
+	}
+	if bid.GasUsed != params.TxGas*uint64(len(pendingTxs)) || bid.GasFee.Sign() <= 0 {
+		t.Errorf("bid gas mismatch: have %d used, %v fee", bid.GasUsed, bid.GasFee)
+	}
+	builder.submit(args)
+	if len(accepting.bids) != 1 || len(rejecting.bids) != 1 {
+		t.Errorf("submitted bid count mismatch: have %d/%d, want 1/1", len(accepting.bids), len(rejecting.bids))
+	}
+}
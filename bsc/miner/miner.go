@@ -28,6 +28,7 @@ import (
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/state"
 	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/eth/downloader"
 	"github.com/ethereum/go-ethereum/event"
@@ -41,6 +42,7 @@ import (
 type Backend interface {
 	BlockChain() *core.BlockChain
 	TxPool() *txpool.TxPool
+	BundlePool() *bundlepool.BundlePool // Nil if bundles are disabled
 }
 
 // Miner is the main object which takes care of submitting new work to consensus
@@ -55,6 +57,7 @@ type Miner struct {
 	worker  *worker
 
 	bidSimulator *bidSimulator
+	localBuilder *localBuilder // Nil unless the node builds bids for other validators
 
 	wg sync.WaitGroup
 }
@@ -73,6 +76,16 @@ func New(eth Backend, config *minerconfig.Config, mux *event.TypeMux, engine con
 	miner.bidSimulator = newBidSimulator(&config.Mev, config.DelayLeftOver, config.GasPrice, config.TxGasLimit, eth, eth.BlockChain().Config(), engine, miner.worker)
 	miner.worker.setBestBidFetcher(miner.bidSimulator)
 
+	if config.Mev.LocalBuilder.Enabled {
+		builder, err := newLocalBuilder(&config.Mev.LocalBuilder, miner.worker)
+		if err != nil {
+			log.Error("Failed to create local builder", "err", err)
+		} else {
+			miner.localBuilder = builder
+			miner.localBuilder.start()
+		}
+	}
+
 	miner.wg.Add(1)
 	go miner.update()
 	return miner
@@ -148,6 +161,9 @@ func (miner *Miner) update() {
 		case <-miner.exitCh:
 			miner.worker.close()
 			miner.bidSimulator.close()
+			if miner.localBuilder != nil {
+				miner.localBuilder.close()
+			}
 			return
 		}
 	}
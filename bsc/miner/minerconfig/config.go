@@ -54,6 +54,7 @@ var (
 	defaultBuilderFeeCeil      = "0"
 	defaultValidatorCommission = uint64(100)
 	defaultMaxBidsPerBuilder   = uint32(2) // Simple strategy: send one bid early, another near deadline
+	defaultBidInterval         = 300 * time.Millisecond
 )
 
 // Config is the configuration parameters of mining.
@@ -69,6 +70,8 @@ type Config struct {
 	MaxWaitProposalInSecs  *uint64        `toml:",omitempty"` // The maximum time to wait for the proposal to be done, it's aimed to prevent validator being slashed when restarting
 	DisableVoteAttestation bool           // Whether to skip assembling vote attestation
 	TxGasLimit             uint64         // Maximum gas for per transaction
+	PriceOracleURL         string         `toml:",omitempty"` // URL of the price reported to the price oracle when voting
+	PriceOracleField       string         `toml:",omitempty"` // Field of the price in the JSON served by the price URL
 
 	Mev MevConfig // Mev configuration
 }
@@ -106,6 +109,19 @@ type MevConfig struct {
 	BidSimulationLeftOver *time.Duration  `toml:",omitempty"`
 	NoInterruptLeftOver   *time.Duration  `toml:",omitempty"`
 	MaxBidsPerBuilder     *uint32         `toml:",omitempty"` // Maximum number of bids allowed per builder per block
+
+	LocalBuilder LocalBuilderConfig // In-process builder bidding to other validators
+}
+
+// LocalBuilderConfig is the configuration of the in-process builder, which
+// builds blocks from the local transaction pool and submits them as bids to
+// the configured validators.
+type LocalBuilderConfig struct {
+	Enabled     bool           // Whether to build and submit bids
+	KeyFile     string         // File holding the hex encoded key the bids are signed with
+	Validators  []string       // RPC URLs of the validators the bids are submitted to
+	BidInterval *time.Duration `toml:",omitempty"` // Time between the bids for a block
+	MaxBids     *uint32        `toml:",omitempty"` // Maximum number of bids submitted per block
 }
 
 var DefaultMevConfig = MevConfig{
@@ -118,6 +134,10 @@ var DefaultMevConfig = MevConfig{
 	BidSimulationLeftOver: &defaultBidSimulationLeftOver,
 	NoInterruptLeftOver:   getDefaultNoInterruptLeftOver(),
 	MaxBidsPerBuilder:     &defaultMaxBidsPerBuilder,
+	LocalBuilder: LocalBuilderConfig{
+		BidInterval: &defaultBidInterval,
+		MaxBids:     &defaultMaxBidsPerBuilder,
+	},
 }
 
 func ApplyDefaultMinerConfig(cfg *Config) {
@@ -169,4 +189,12 @@ func ApplyDefaultMinerConfig(cfg *Config) {
 		cfg.Mev.MaxBidsPerBuilder = &defaultMaxBidsPerBuilder
 		log.Info("ApplyDefaultMinerConfig", "Mev.MaxBidsPerBuilder", *cfg.Mev.MaxBidsPerBuilder)
 	}
+	if cfg.Mev.LocalBuilder.BidInterval == nil {
+		cfg.Mev.LocalBuilder.BidInterval = &defaultBidInterval
+		log.Info("ApplyDefaultMinerConfig", "Mev.LocalBuilder.BidInterval", *cfg.Mev.LocalBuilder.BidInterval)
+	}
+	if cfg.Mev.LocalBuilder.MaxBids == nil {
+		cfg.Mev.LocalBuilder.MaxBids = &defaultMaxBidsPerBuilder
+		log.Info("ApplyDefaultMinerConfig", "Mev.LocalBuilder.MaxBids", *cfg.Mev.LocalBuilder.MaxBids)
+	}
 }
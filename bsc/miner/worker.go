@@ -101,6 +101,8 @@ type environment struct {
 	coinbase common.Address
 	evm      *vm.EVM
 
+	transfers *core.NativeTransferRecorder // Native transfers of the block, if recorded
+
 	header   *types.Header
 	txs      []*types.Transaction
 	receipts []*types.Receipt
@@ -122,11 +124,48 @@ func (env *environment) discard() {
 	env.state.StopPrefetcher()
 }
 
+// transfersSnapshot returns an identifier of the native transfers recorded so
+// far, to be reverted along with the state.
+func (env *environment) transfersSnapshot() int {
+	if env.transfers == nil {
+		return 0
+	}
+	return env.transfers.Snapshot()
+}
+
+// recordedTransfers returns the native transfers recorded for the block, or nil
+// if they are not recorded.
+func (env *environment) recordedTransfers() []*types.NativeTransfer {
+	if env.transfers == nil {
+		return nil
+	}
+	return env.transfers.Transfers()
+}
+
+// revertTransfers drops the native transfers recorded since the given snapshot.
+func (env *environment) revertTransfers(id int) {
+	if env.transfers != nil {
+		env.transfers.RevertToSnapshot(id)
+	}
+}
+
+// restoreState replaces the state of env with a copy taken earlier, dropping the
+// changes made since then. The state journal is reset on every transaction, so
+// the changes of several transactions cannot be reverted through a snapshot.
+func (env *environment) restoreState(statedb *state.StateDB) {
+	statedb.TransferPrefetcher(env.state)
+	statedb.TransferBlockAccessList(env.state)
+	env.state = statedb
+	env.witness = statedb.Witness()
+	env.evm.StateDB = consensus.TracingState(statedb, env.evm.Config.Tracer)
+}
+
 // task contains all information for consensus engine sealing and result submitting.
 type task struct {
-	receipts []*types.Receipt
-	state    *state.StateDB
-	block    *types.Block
+	receipts  []*types.Receipt
+	transfers []*types.NativeTransfer
+	state     *state.StateDB
+	block     *types.Block
 
 	createdAt     time.Time
 	miningStartAt time.Time
@@ -637,7 +676,7 @@ func (w *worker) resultLoop() {
 
 			// Commit block and state to database.
 			start := time.Now()
-			status, err := w.chain.WriteBlockAndSetHead(block, receipts, logs, task.state, w.mux)
+			status, err := w.chain.WriteBlockAndSetHead(block, receipts, logs, task.transfers, task.state, w.mux)
 			if status != core.CanonStatTy {
 				if err != nil {
 					log.Error("Failed writing block to chain", "err", err, "status", status)
@@ -687,8 +726,19 @@ func (w *worker) makeEnv(parent *types.Header, header *types.Header, coinbase co
 		coinbase: coinbase,
 		header:   header,
 		witness:  state.Witness(),
-		evm:      vm.NewEVM(core.NewEVMBlockContext(header, w.chain, &coinbase), state, w.chainConfig, vm.Config{}),
 	}
+	// Record the native transfers while building the block, so that it does not
+	// need to be traced again once sealed
+	var (
+		tracingState = vm.StateDB(state)
+		vmConfig     vm.Config
+	)
+	if w.chain.RecordsNativeTransfers() {
+		env.transfers = core.NewNativeTransferRecorder()
+		vmConfig.Tracer = env.transfers.Hooks(nil)
+		tracingState = consensus.TracingState(state, vmConfig.Tracer)
+	}
+	env.evm = vm.NewEVM(core.NewEVMBlockContext(header, w.chain, &coinbase), tracingState, w.chainConfig, vmConfig)
 	// Keep track of transactions which return errors so they can be removed
 	env.tcount = 0
 	return env, nil
@@ -742,32 +792,120 @@ func (w *worker) commitBlobTransaction(env *environment, tx *types.Transaction,
 // applyTransaction runs the transaction. If execution fails, state and gas pool are reverted.
 func (w *worker) applyTransaction(env *environment, tx *types.Transaction, receiptProcessors ...core.ReceiptProcessor) (*types.Receipt, error) {
 	var (
-		snap = env.state.Snapshot()
-		gp   = env.gasPool.Gas()
+		snap      = env.state.Snapshot()
+		gp        = env.gasPool.Gas()
+		transfers = env.transfersSnapshot()
 	)
 
 	receipt, err := core.ApplyTransaction(env.evm, env.gasPool, env.state, env.header, tx, &env.header.GasUsed, receiptProcessors...)
 	if err != nil {
 		env.state.RevertToSnapshot(snap)
 		env.gasPool.SetGas(gp)
+		env.revertTransfers(transfers)
 	}
 	return receipt, err
 }
 
-func (w *worker) commitTransactions(env *environment, plainTxs, blobTxs *transactionsByPriceAndNonce,
-	interruptCh chan int32, stopTimer *time.Timer) error {
-	var (
-		isCancun = w.chainConfig.IsCancun(env.header.Number, env.header.Time)
-		gasLimit = env.header.GasLimit
-	)
-	if env.gasPool == nil {
-		env.gasPool = new(core.GasPool).AddGas(gasLimit)
-		if p, ok := w.engine.(*parlia.Parlia); ok {
-			gasReserved := p.EstimateGasReservedForSystemTxs(w.chain, env.header)
-			env.gasPool.SubGas(gasReserved)
-			log.Debug("commitTransactions", "number", env.header.Number.Uint64(), "time", env.header.Time, "EstimateGasReservedForSystemTxs", gasReserved)
+// initGasPool creates the gas pool of env, if not yet done, holding back the
+// gas reserved for the system transactions.
+func (w *worker) initGasPool(env *environment) {
+	if env.gasPool != nil {
+		return
+	}
+	env.gasPool = new(core.GasPool).AddGas(env.header.GasLimit)
+	if p, ok := w.engine.(*parlia.Parlia); ok {
+		gasReserved := p.EstimateGasReservedForSystemTxs(w.chain, env.header)
+		env.gasPool.SubGas(gasReserved)
+		log.Debug("commitTransactions", "number", env.header.Number.Uint64(), "time", env.header.Time, "EstimateGasReservedForSystemTxs", gasReserved)
+	}
+}
+
+// commitBundles simulates the pending bundles on top of env and merges them
+// into the block by decreasing fees per gas. Every bundle is committed all or
+// none, bundles invalidated by the ones merged before are skipped.
+func (w *worker) commitBundles(env *environment, interruptCh chan int32) error {
+	pool := w.eth.BundlePool()
+	if pool == nil {
+		return nil
+	}
+	bundles := pool.Pending(env.header.Number.Uint64())
+	if len(bundles) == 0 {
+		return nil
+	}
+	w.initGasPool(env)
+
+	simulated := make([]*types.SimulatedBundle, 0, len(bundles))
+	for _, bundle := range bundles {
+		sim, err := w.simulateBundle(env, bundle)
+		if err != nil {
+			log.Trace("Bundle simulation failed", "hash", bundle.Hash(), "err", err)
+			continue
 		}
+		simulated = append(simulated, sim)
 	}
+	slices.SortStableFunc(simulated, func(a, b *types.SimulatedBundle) int {
+		return b.Price().Cmp(a.Price())
+	})
+
+	bloomProcessor := core.NewReceiptBloomGenerator()
+	for _, sim := range simulated {
+		if interruptCh != nil {
+			select {
+			case signal := <-interruptCh:
+				return signalToErr(signal)
+			default:
+			}
+		}
+		size := env.size
+		for _, tx := range sim.Bundle.Txs {
+			size += tx.Size()
+		}
+		if size >= params.MaxBlockSize-maxBlockSizeBufferZone {
+			continue
+		}
+		// The bundles merged before may invalidate the simulated one, in which
+		// case the partially applied bundle is reverted and skipped
+		var (
+			prev      = env.state.Copy()
+			gp        = env.gasPool.Gas()
+			gasUsed   = env.header.GasUsed
+			transfers = env.transfersSnapshot()
+		)
+		receipts, _, err := core.ApplyBundle(env.evm, env.gasPool, env.state, env.header, sim.Bundle, &env.header.GasUsed, env.tcount, bloomProcessor)
+		if err != nil {
+			env.restoreState(prev)
+			env.gasPool.SetGas(gp)
+			env.header.GasUsed = gasUsed
+			env.revertTransfers(transfers)
+			log.Debug("Bundle skipped", "hash", sim.Bundle.Hash(), "err", err)
+			continue
+		}
+		env.txs = append(env.txs, sim.Bundle.Txs...)
+		env.receipts = append(env.receipts, receipts...)
+		env.size = size
+		env.tcount += len(sim.Bundle.Txs)
+		log.Debug("Committed bundle", "hash", sim.Bundle.Hash(), "txs", len(sim.Bundle.Txs), "gas", sim.GasUsed, "fees", sim.Fees)
+	}
+	return nil
+}
+
+// simulateBundle executes bundle on a copy of the state of env, returning the
+// gas it uses and the fees it pays.
+func (w *worker) simulateBundle(env *environment, bundle *types.Bundle) (*types.SimulatedBundle, error) {
+	var (
+		statedb = env.state.Copy()
+		gasPool = new(core.GasPool).AddGas(env.gasPool.Gas())
+		gasUsed = env.header.GasUsed
+		evm     = vm.NewEVM(env.evm.Context, statedb, w.chainConfig, vm.Config{})
+	)
+	_, sim, err := core.ApplyBundle(evm, gasPool, statedb, env.header, bundle, &gasUsed, env.tcount)
+	return sim, err
+}
+
+func (w *worker) commitTransactions(env *environment, plainTxs, blobTxs *transactionsByPriceAndNonce,
+	interruptCh chan int32, stopTimer *time.Timer) error {
+	isCancun := w.chainConfig.IsCancun(env.header.Number, env.header.Time)
+	w.initGasPool(env)
 
 	// initialize bloom processors
 	processorCapacity := 100
@@ -1033,9 +1171,9 @@ func (w *worker) prepareWork(genParams *generateParams, witness bool) (*environm
 	return env, nil
 }
 
-// fillTransactions retrieves the pending transactions from the txpool and fills them
-// into the given sealing block. The transaction selection and ordering strategy can
-// be customized with the plugin in the future.
+// fillTransactions retrieves the pending bundles and transactions from the pools and
+// fills them into the given sealing block. The transaction selection and ordering
+// strategy can be customized with the plugin in the future.
 func (w *worker) fillTransactions(interruptCh chan int32, env *environment, stopTimer *time.Timer, bidTxs mapset.Set[common.Hash]) (err error) {
 	w.confMu.RLock()
 	tip := w.tip
@@ -1107,6 +1245,13 @@ func (w *worker) fillTransactions(interruptCh chan int32, env *environment, stop
 		}
 	}
 
+	// Merge the bundles at the top of the block, unless filling up a builder bid
+	if bidTxs == nil {
+		if err := w.commitBundles(env, interruptCh); err != nil {
+			return err
+		}
+	}
+
 	// Fill the block with all available pending transactions.
 	if len(prioPlainTxs) > 0 || len(prioBlobTxs) > 0 {
 		plainTxs := newTransactionsByPriceAndNonce(env.signer, prioPlainTxs, env.header.BaseFee)
@@ -1485,7 +1630,7 @@ func (w *worker) commit(env *environment, interval func(), start time.Time) erro
 		if env.header.EmptyWithdrawalsHash() {
 			body.Withdrawals = make([]*types.Withdrawal, 0)
 		}
-		block, receipts, err := w.engine.FinalizeAndAssemble(w.chain, types.CopyHeader(env.header), env.state, &body, env.receipts, nil)
+		block, receipts, err := w.engine.FinalizeAndAssemble(w.chain, types.CopyHeader(env.header), env.state, &body, env.receipts, env.evm.Config.Tracer)
 		env.committed = true
 		if err != nil {
 			return err
@@ -1501,7 +1646,7 @@ func (w *worker) commit(env *environment, interval func(), start time.Time) erro
 		block = block.WithSidecars(env.sidecars)
 
 		select {
-		case w.taskCh <- &task{receipts: receipts, state: env.state, block: block, createdAt: time.Now(), miningStartAt: start}:
+		case w.taskCh <- &task{receipts: receipts, transfers: env.recordedTransfers(), state: env.state, block: block, createdAt: time.Now(), miningStartAt: start}:
 			log.Info("Commit new sealing work", "number", block.Number(), "sealhash", w.engine.SealHash(block.Header()),
 				"txs", len(env.txs), "blobs", env.blobs, "gas", block.GasUsed(), "fees", feesInEther, "elapsed", common.PrettyDuration(time.Since(start)))
 
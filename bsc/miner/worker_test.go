@@ -17,6 +17,7 @@
 package miner // TOFIX
 
 import (
+	"crypto/ecdsa"
 	"math/big"
 	"testing"
 	"time"
@@ -28,7 +29,9 @@ import (
 	"github.com/ethereum/go-ethereum/consensus/ethash"
 	"github.com/ethereum/go-ethereum/core"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/tracing"
 	"github.com/ethereum/go-ethereum/core/txpool"
+	"github.com/ethereum/go-ethereum/core/txpool/bundlepool"
 	"github.com/ethereum/go-ethereum/core/txpool/legacypool"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
@@ -107,13 +110,18 @@ func init() {
 
 // testWorkerBackend implements worker.Backend interfaces and wraps all information needed during the testing.
 type testWorkerBackend struct {
-	db      ethdb.Database
-	txPool  *txpool.TxPool
-	chain   *core.BlockChain
-	genesis *core.Genesis
+	db         ethdb.Database
+	txPool     *txpool.TxPool
+	bundlePool *bundlepool.BundlePool
+	chain      *core.BlockChain
+	genesis    *core.Genesis
 }
 
 func newTestWorkerBackend(t *testing.T, chainConfig *params.ChainConfig, engine consensus.Engine, db ethdb.Database, n int) *testWorkerBackend {
+	return newTestWorkerBackendWithConfig(t, chainConfig, engine, db, &core.BlockChainConfig{ArchiveMode: true})
+}
+
+func newTestWorkerBackendWithConfig(t *testing.T, chainConfig *params.ChainConfig, engine consensus.Engine, db ethdb.Database, cfg *core.BlockChainConfig) *testWorkerBackend {
 	var gspec = &core.Genesis{
 		Config: chainConfig,
 		Alloc:  types.GenesisAlloc{testBankAddress: {Balance: testBankFunds}},
@@ -129,7 +137,7 @@ func newTestWorkerBackend(t *testing.T, chainConfig *params.ChainConfig, engine
 	default:
 		t.Fatalf("unexpected consensus engine type: %T", engine)
 	}
-	chain, err := core.NewBlockChain(db, gspec, engine, &core.BlockChainConfig{ArchiveMode: true})
+	chain, err := core.NewBlockChain(db, gspec, engine, cfg)
 	if err != nil {
 		t.Fatalf("core.NewBlockChain failed: %v", err)
 	}
@@ -144,8 +152,9 @@ func newTestWorkerBackend(t *testing.T, chainConfig *params.ChainConfig, engine
 	}
 }
 
-func (b *testWorkerBackend) BlockChain() *core.BlockChain { return b.chain }
-func (b *testWorkerBackend) TxPool() *txpool.TxPool       { return b.txPool }
+func (b *testWorkerBackend) BlockChain() *core.BlockChain       { return b.chain }
+func (b *testWorkerBackend) TxPool() *txpool.TxPool             { return b.txPool }
+func (b *testWorkerBackend) BundlePool() *bundlepool.BundlePool { return b.bundlePool }
 
 func (b *testWorkerBackend) newRandomTx(creation bool) *types.Transaction {
 	var tx *types.Transaction
@@ -210,6 +219,58 @@ func TestGenerateAndImportBlock(t *testing.T) {
 	}
 }
 
+// Tests that the native transfers of the sealed blocks are recorded while they
+// are built and written along with them.
+func TestSealedNativeTransfers(t *testing.T) {
+	t.Parallel()
+	var (
+		db     = rawdb.NewMemoryDatabase()
+		config = *params.AllCliqueProtocolChanges
+		cfg    = core.DefaultConfig().WithArchive(true)
+	)
+	config.Clique = &params.CliqueConfig{Period: 1, Epoch: 30000}
+	engine := clique.New(config.Clique, db)
+
+	cfg.NativeTransfers = true
+	b := newTestWorkerBackendWithConfig(t, &config, engine, db, cfg)
+	w := newWorker(testConfig, engine, b, new(event.TypeMux))
+	w.setEtherbase(testBankAddress)
+	defer w.close()
+
+	w.skipSealHook = func(task *task) bool {
+		return len(task.receipts) == 0
+	}
+	sub := w.mux.Subscribe(core.NewMinedBlockEvent{})
+	defer sub.Unsubscribe()
+
+	w.start()
+	tx := b.newRandomTx(false)
+	b.txPool.Add([]*types.Transaction{tx}, true)
+
+	select {
+	case ev := <-sub.Chan():
+		block := ev.Data.(core.NewMinedBlockEvent).Block
+		transfers, ok := b.chain.GetNativeTransfers(block.Hash(), block.NumberU64())
+		if !ok {
+			t.Fatal("native transfers of sealed block not recorded")
+		}
+		var found bool
+		for _, transfer := range transfers {
+			if transfer.Kind == types.NativeTransferCall && transfer.TxHash == tx.Hash() {
+				if transfer.From != testBankAddress || transfer.To != testUserAddress || transfer.Amount.Cmp(big.NewInt(1000)) != 0 {
+					t.Errorf("transfer mismatch: have %+v", transfer)
+				}
+				found = true
+			}
+		}
+		if !found {
+			t.Errorf("transfer of %x not recorded: %+v", tx.Hash(), transfers)
+		}
+	case <-time.After(3 * time.Second):
+		t.Fatalf("timeout")
+	}
+}
+
 func TestEmptyWorkEthash(t *testing.T) {
 	t.Parallel()
 	testEmptyWork(t, ethashChainConfig, ethash.NewFaker())
@@ -411,3 +472,133 @@ func testGetSealingWork(t *testing.T, chainConfig *params.ChainConfig, engine co
 		}
 	}
 }
+
+// Tests that the bundles are merged atomically at the top of the built block,
+// by decreasing fees per gas.
+func TestCommitBundles(t *testing.T) {
+	t.Parallel()
+
+	engine := ethash.NewFaker()
+	defer engine.Close()
+
+	w, b := newTestWorker(t, ethashChainConfig, engine, rawdb.NewMemoryDatabase(), 0)
+	defer w.close()
+
+	b.bundlePool = bundlepool.New(bundlepool.DefaultConfig, b.chain)
+	signer := types.LatestSigner(ethashChainConfig)
+	transfer := func(nonce uint64, price int64) *types.Transaction {
+		return types.MustSignNewTx(testBankKey, signer, &types.LegacyTx{
+			Nonce:    nonce,
+			To:       &testUserAddress,
+			Value:    big.NewInt(1),
+			Gas:      params.TxGas,
+			GasPrice: big.NewInt(price * params.InitialBaseFee),
+		})
+	}
+	var (
+		cheap = &types.Bundle{Txs: types.Transactions{transfer(0, 2), transfer(1, 2)}}
+		rich  = &types.Bundle{Txs: types.Transactions{transfer(0, 5), transfer(1, 5)}}
+		gap   = &types.Bundle{Txs: types.Transactions{transfer(0, 10), transfer(2, 10)}}
+	)
+	for _, bundle := range []*types.Bundle{cheap, rich, gap} {
+		if err := b.bundlePool.Add(bundle); err != nil {
+			t.Fatalf("failed to add bundle: %v", err)
+		}
+	}
+	r := w.getSealingBlock(&generateParams{
+		parentHash: b.chain.CurrentBlock().Hash(),
+		timestamp:  uint64(time.Now().Unix()),
+		coinbase:   common.HexToAddress("0xdeadbeef"),
+		forceTime:  true,
+	})
+	if r.err != nil {
+		t.Fatalf("failed to build block: %v", r.err)
+	}
+	// The richer bundle wins the nonces, the others and the pending transactions
+	// with the same nonces are left out. The valid prefix of the gapped bundle
+	// must not be included either.
+	txs := r.block.Transactions()
+	if len(txs) != len(rich.Txs) {
+		t.Fatalf("transaction count mismatch: have %d, want %d", len(txs), len(rich.Txs))
+	}
+	for i, tx := range rich.Txs {
+		if txs[i].Hash() != tx.Hash() {
+			t.Errorf("transaction %d mismatch: have %x, want %x", i, txs[i].Hash(), tx.Hash())
+		}
+	}
+}
+
+// Tests that a bundle invalidated by the bundles merged before it is reverted
+// as a whole, along with the gas and native transfers of its applied prefix.
+func TestCommitBundlesRevert(t *testing.T) {
+	t.Parallel()
+
+	engine := ethash.NewFaker()
+	defer engine.Close()
+
+	var (
+		db  = rawdb.NewMemoryDatabase()
+		cfg = core.DefaultConfig().WithArchive(true)
+	)
+	cfg.NativeTransfers = true
+	b := newTestWorkerBackendWithConfig(t, ethashChainConfig, engine, db, cfg)
+	w := newWorker(testConfig, engine, b, new(event.TypeMux))
+	defer w.close()
+
+	b.bundlePool = bundlepool.New(bundlepool.DefaultConfig, b.chain)
+	signer := types.LatestSigner(ethashChainConfig)
+	transfer := func(key *ecdsa.PrivateKey, nonce uint64, price int64) *types.Transaction {
+		return types.MustSignNewTx(key, signer, &types.LegacyTx{
+			Nonce:    nonce,
+			To:       &common.Address{0xaa},
+			Value:    big.NewInt(1),
+			Gas:      params.TxGas,
+			GasPrice: big.NewInt(price * params.InitialBaseFee),
+		})
+	}
+	var (
+		rich    = &types.Bundle{Txs: types.Transactions{transfer(testBankKey, 0, 5), transfer(testBankKey, 1, 5)}}
+		partial = &types.Bundle{Txs: types.Transactions{transfer(testUserKey, 0, 3), transfer(testBankKey, 0, 3)}}
+	)
+	for _, bundle := range []*types.Bundle{rich, partial} {
+		if err := b.bundlePool.Add(bundle); err != nil {
+			t.Fatalf("failed to add bundle: %v", err)
+		}
+	}
+	env, err := w.prepareWork(&generateParams{
+		parentHash: b.chain.CurrentBlock().Hash(),
+		timestamp:  uint64(time.Now().Unix()),
+		coinbase:   common.HexToAddress("0xdeadbeef"),
+		forceTime:  true,
+	}, false)
+	if err != nil {
+		t.Fatalf("failed to prepare work: %v", err)
+	}
+	defer env.discard()
+
+	// Both bundles are valid on their own, the second one fails halfway on top
+	// of the first one
+	env.state.AddBalance(testUserAddress, uint256.NewInt(params.Ether), tracing.BalanceChangeUnspecified)
+	w.initGasPool(env)
+	gas := env.gasPool.Gas()
+	if err := w.commitBundles(env, nil); err != nil {
+		t.Fatalf("failed to commit bundles: %v", err)
+	}
+	if len(env.txs) != len(rich.Txs) || env.txs[0].Hash() != rich.Txs[0].Hash() {
+		t.Fatalf("committed transactions mismatch: have %d", len(env.txs))
+	}
+	if nonce := env.state.GetNonce(testUserAddress); nonce != 0 {
+		t.Errorf("applied prefix of the reverted bundle kept, nonce %d", nonce)
+	}
+	if want := params.TxGas * uint64(len(rich.Txs)); env.header.GasUsed != want {
+		t.Errorf("gas used mismatch: have %d, want %d", env.header.GasUsed, want)
+	}
+	if want := gas - env.header.GasUsed; env.gasPool.Gas() != want {
+		t.Errorf("gas pool mismatch: have %d, want %d", env.gasPool.Gas(), want)
+	}
+	for _, transfer := range env.recordedTransfers() {
+		if transfer.TxHash == partial.Txs[0].Hash() {
+			t.Errorf("transfer of the reverted bundle kept: %+v", transfer)
+		}
+	}
+}
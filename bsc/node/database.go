@@ -21,6 +21,7 @@ import (
 
 	"github.com/ethereum/go-ethereum/core/rawdb"
 	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/ethdb/blobstore"
 	"github.com/ethereum/go-ethereum/ethdb/leveldb"
 	"github.com/ethereum/go-ethereum/ethdb/pebble"
 	"github.com/ethereum/go-ethereum/log"
@@ -39,6 +40,12 @@ type DatabaseOptions struct {
 	Cache            int    // the capacity(in megabytes) of the data caching
 	Handles          int    // number of files to be open simultaneously
 	ReadOnly         bool   // if true, no writes can be performed
+	Replica          bool   // if true, the database is opened read-only from a snapshot published by another writer process
+
+	// Optional location of the blob store the completed ancient data files are
+	// offloaded to, see blobstore.Open for the supported ones.
+	ColdStore string
+	ColdCache int // the capacity(in megabytes) of the offloaded data files cached locally
 }
 
 type internalOpenOptions struct {
@@ -62,6 +69,16 @@ func openDatabase(o internalOpenOptions) (ethdb.Database, error) {
 		Era:              o.EraDirectory,
 		MetricsNamespace: o.MetricsNamespace,
 		ReadOnly:         o.ReadOnly,
+		Replica:          o.Replica,
+		ColdCache:        o.ColdCache,
+	}
+	if o.ColdStore != "" && o.AncientsDirectory != "" {
+		store, err := blobstore.Open(o.ColdStore)
+		if err != nil {
+			kvdb.Close()
+			return nil, fmt.Errorf("failed to open ancient cold store: %v", err)
+		}
+		opts.ColdStore = store
 	}
 	frdb, err := rawdb.Open(kvdb, opts)
 	if err != nil {
@@ -88,6 +105,13 @@ func openKeyValueDatabase(o internalOpenOptions) (ethdb.KeyValueStore, error) {
 	if len(existingDb) != 0 && len(o.dbEngine) != 0 && o.dbEngine != existingDb {
 		return nil, fmt.Errorf("db.engine choice was %v but found pre-existing %v database in specified data directory", o.dbEngine, existingDb)
 	}
+	if o.Replica {
+		// Replica snapshots are only published by pebble
+		if existingDb != rawdb.DBPebble {
+			return nil, fmt.Errorf("read replica requires a pebble snapshot, found %q", existingDb)
+		}
+		return newPebbleDBDatabase(o.directory, o.Cache, o.Handles, o.MetricsNamespace, true)
+	}
 	if o.dbEngine == rawdb.DBPebble || existingDb == rawdb.DBPebble {
 		log.Info("Using pebble as the backing database")
 		return newPebbleDBDatabase(o.directory, o.Cache, o.Handles, o.MetricsNamespace, o.ReadOnly)
@@ -804,7 +804,15 @@ func (n *Node) OpenAndMergeDatabase(name string, namespace string, readonly bool
 		stateDbHandles = config.DatabaseHandles - chainDataHandles
 	}
 
-	chainDB, err := n.OpenDatabaseWithFreezer(name, chainDbCache, chainDataHandles, config.DatabaseFreezer, namespace, readonly)
+	chainDB, err := n.OpenDatabaseWithOptions(name, DatabaseOptions{
+		AncientsDirectory: n.ResolveAncient(name, config.DatabaseFreezer),
+		MetricsNamespace:  namespace,
+		Cache:             chainDbCache,
+		Handles:           chainDataHandles,
+		ReadOnly:          readonly,
+		ColdStore:         config.DatabaseColdStore,
+		ColdCache:         config.DatabaseColdCache,
+	})
 	if err != nil {
 		return nil, err
 	}
@@ -881,6 +889,11 @@ func (db *closeTrackingDB) Close() error {
 	return db.Database.Close()
 }
 
+// Unwrap returns the tracked database.
+func (db *closeTrackingDB) Unwrap() ethdb.Database {
+	return db.Database
+}
+
 // wrapDatabase ensures the database will be auto-closed when Node is closed.
 func (n *Node) wrapDatabase(db ethdb.Database) ethdb.Database {
 	wrapper := &closeTrackingDB{db, n}
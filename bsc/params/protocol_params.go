@@ -133,6 +133,11 @@ const (
 	// Introduced in Tangerine Whistle (Eip 150)
 	CreateBySelfdestructGas uint64 = 25000
 
+	// YieldSettlementGas is paid from Mendel per account whose balance change
+	// settles its native holder yield, covering the update of the balance and
+	// the index recorded for it.
+	YieldSettlementGas uint64 = 2 * SstoreResetGasEIP2200
+
 	DefaultBaseFeeChangeDenominator = 8          // Bounds the amount the base fee can change between blocks.
 	DefaultElasticityMultiplier     = 2          // Bounds the maximum gas limit an EIP-1559 block may have.
 	InitialBaseFee                  = 1000000000 // Initial base fee for EIP-1559 blocks.
@@ -147,6 +152,13 @@ const (
 	IAVLMerkleProofValidateGas    uint64 = 3000 // Gas for validate merkle proof
 	CometBFTLightBlockValidateGas uint64 = 3000 // Gas for validate cometBFT light block
 
+	EthSyncCommitteeUpdateGas      uint64 = 2000000 // Gas for verify an ethereum sync committee update
+	EthFinalizedHeaderValidateGas  uint64 = 2000000 // Gas for verify an ethereum finalized execution header
+	EthReceiptProofValidateBaseGas uint64 = 20000   // Base price for verify an ethereum receipt proof
+	EthStorageProofValidateBaseGas uint64 = 30000   // Base price for verify an ethereum account and storage proof
+	EthProofValidatePerWordGas     uint64 = 6       // Per-word price for verify an ethereum trie proof
+	PriceOracleReadGas             uint64 = 2100    // Price for reading the latest price oracle round
+
 	EcrecoverGas                uint64 = 3000  // Elliptic curve sender recovery gas price
 	Sha256BaseGas               uint64 = 60    // Base price for a SHA256 operation
 	Sha256PerWordGas            uint64 = 12    // Per-word price for a SHA256 operation
@@ -244,4 +256,22 @@ var (
 	// EIP-7251 - Increase the MAX_EFFECTIVE_BALANCE
 	ConsolidationQueueAddress = common.HexToAddress("0x0000BBdDc7CE488642fb579F8B00f3a590007251")
 	ConsolidationQueueCode    = common.FromHex("3373fffffffffffffffffffffffffffffffffffffffe1460d35760115f54807fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff1461019a57600182026001905f5b5f82111560685781019083028483029004916001019190604d565b9093900492505050366060146088573661019a573461019a575f5260205ff35b341061019a57600154600101600155600354806004026004013381556001015f358155600101602035815560010160403590553360601b5f5260605f60143760745fa0600101600355005b6003546002548082038060021160e7575060025b5f5b8181146101295782810160040260040181607402815460601b815260140181600101548152602001816002015481526020019060030154905260010160e9565b910180921461013b5790600255610146565b90505f6002555f6003555b5f54807fffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffffff141561017357505f5b6001546001828201116101885750505f61018e565b01600190035b5f555f6001556074025ff35b5f5ffd")
+
+	// YieldIndexAddress is the account pooling the block fees allotted to native
+	// holders, whose storage keeps the yield index they are settled against.
+	YieldIndexAddress = common.HexToAddress("0x0000000000000000000000000000000000003003")
+
+	// PriceOracleAddress is the account whose storage keeps the median of the
+	// latest round of price reports signed by the validators.
+	PriceOracleAddress = common.HexToAddress("0x0000000000000000000000000000000000003004")
+)
+
+// Storage layout of the price oracle account.
+const (
+	PriceOracleMedianSlot = 0 // Median price of the latest round, 18 decimals
+	PriceOracleRoundSlot  = 1 // Number of the latest round
+	PriceOracleNumberSlot = 2 // Block number the latest round was included in
+	PriceOracleTimeSlot   = 3 // Timestamp of the block including the latest round
+
+	PriceOracleInterval uint64 = 200 // Number of blocks between two price oracle rounds
 )
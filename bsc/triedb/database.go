@@ -181,6 +181,18 @@ func (db *Database) HistoricReader(root common.Hash) (*pathdb.HistoricalStateRea
 	return pdb.HistoricReader(root)
 }
 
+// HistoricNodeReader constructs a reader for accessing the trie nodes of the
+// requested historic state.
+//
+// This function is only supported by path mode database.
+func (db *Database) HistoricNodeReader(root common.Hash) (*pathdb.HistoricalNodeReader, error) {
+	pdb, ok := db.backend.(*pathdb.Database)
+	if !ok {
+		return nil, errors.New("not supported")
+	}
+	return pdb.HistoricNodeReader(root)
+}
+
 // Update performs a state transition by committing dirty nodes contained in the
 // given set in order to update state from the specified parent to the specified
 // root. The held pre-images accumulated up to this point will be flushed in case
@@ -357,6 +369,43 @@ func (db *Database) Enable(root common.Hash) error {
 	return pdb.Enable(root)
 }
 
+// SetWriteHook installs a callback invoked with the hash of every trie node
+// before it's flushed to disk, or removes it if nil.
+//
+// It's only supported by hash-based database and will return an error for others.
+func (db *Database) SetWriteHook(hook func(hash common.Hash)) error {
+	hdb, ok := db.backend.(*hashdb.Database)
+	if !ok {
+		return errors.New("not supported")
+	}
+	hdb.SetWriteHook(hook)
+	return nil
+}
+
+// PrepareRefresh is invoked on a read replica with the reopened database before
+// it replaces the one in use.
+//
+// It's only supported by path-based database and will return an error for others.
+func (db *Database) PrepareRefresh(next ethdb.KeyValueReader) error {
+	pdb, ok := db.backend.(*pathdb.Database)
+	if !ok {
+		return errors.New("not supported")
+	}
+	return pdb.PrepareRefresh(next)
+}
+
+// Refresh reloads the state layers of a read replica once the database has
+// been reopened to observe the latest writes of the writer.
+//
+// It's only supported by path-based database and will return an error for others.
+func (db *Database) Refresh() error {
+	pdb, ok := db.backend.(*pathdb.Database)
+	if !ok {
+		return errors.New("not supported")
+	}
+	return pdb.Refresh()
+}
+
 // Journal commits an entire diff hierarchy to disk into a single journal entry.
 // This is meant to be used during shutdown to persist the snapshot without
 // flattening everything down (bad for reorgs). It's only supported by path-based
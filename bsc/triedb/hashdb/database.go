@@ -95,6 +95,8 @@ type Database struct {
 	dirtiesSize  common.StorageSize // Storage size of the dirty node cache (exc. metadata)
 	childrenSize common.StorageSize // Storage size of the external children tracking
 
+	writeHook func(hash common.Hash) // Callback invoked before a node is flushed to disk
+
 	lock sync.RWMutex
 }
 
@@ -318,6 +320,16 @@ func (db *Database) dereference(hash common.Hash) {
 	}
 }
 
+// SetWriteHook installs a callback invoked with the hash of every trie node
+// before it's flushed to disk, or removes it if nil. It allows a concurrent
+// pruner to learn about the nodes becoming live while it's running.
+func (db *Database) SetWriteHook(hook func(hash common.Hash)) {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	db.writeHook = hook
+}
+
 // Cap iteratively flushes old but still referenced trie nodes until the total
 // memory usage goes below the given threshold.
 func (db *Database) Cap(limit common.StorageSize) error {
@@ -342,6 +354,9 @@ func (db *Database) Cap(limit common.StorageSize) error {
 	for size > limit && oldest != (common.Hash{}) {
 		// Fetch the oldest referenced node and push into the batch
 		node := db.dirties[oldest]
+		if db.writeHook != nil {
+			db.writeHook(oldest)
+		}
 		rawdb.WriteLegacyTrieNode(batch, oldest, node.node)
 
 		// If we exceeded the ideal batch size, commit and reset
@@ -465,6 +480,9 @@ func (db *Database) commit(hash common.Hash, batch ethdb.Batch, uncacher *cleane
 		return err
 	}
 	// If we've reached an optimal batch size, commit and start over
+	if db.writeHook != nil {
+		db.writeHook(hash)
+	}
 	rawdb.WriteLegacyTrieNode(batch, hash, node.node)
 	if batch.ValueSize() >= ethdb.IdealBatchSize {
 		if err := batch.Write(); err != nil {
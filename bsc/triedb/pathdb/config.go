@@ -70,11 +70,14 @@ var ReadOnly = &Config{
 type Config struct {
 	StateHistory        uint64 // Number of recent blocks to maintain state history for, 0: full chain
 	EnableStateIndexing bool   // Whether to enable state history indexing for external state access
+	TrienodeHistory     bool   // Whether to maintain trie node history for historical trie access, indexing is required
 	TrieCleanSize       int    // Maximum memory allowance (in bytes) for caching clean trie data
 	StateCleanSize      int    // Maximum memory allowance (in bytes) for caching clean state data
 	WriteBufferSize     int    // Maximum memory allowance (in bytes) for write buffer
 	ReadOnly            bool   // Flag whether the database is opened in read only mode
 	JournalDirectory    string // Absolute path of journal directory (null means the journal data is persisted in key-value store)
+	ServeReplicas       bool   // Flag whether the diff layers are persisted for the read replicas following the database snapshots
+	Replica             bool   // Flag whether the database is a read replica of the database written by another process
 
 	// Testing configurations
 	SnapshotNoBuild   bool // Flag Whether the state generation is disabled
@@ -99,6 +102,17 @@ func (c *Config) sanitize() *Config {
 		log.Warn("Sanitizing invalid node buffer size", "provided", common.StorageSize(conf.WriteBufferSize), "updated", common.StorageSize(maxBufferSize))
 		conf.WriteBufferSize = maxBufferSize
 	}
+	if conf.Replica {
+		conf.ReadOnly = true
+	}
+	if conf.TrienodeHistory && !conf.EnableStateIndexing {
+		log.Warn("Sanitizing trienode history without state history indexing")
+		conf.TrienodeHistory = false
+	}
+	if conf.TrienodeHistory && (conf.EnableIncr || conf.MergeIncr) {
+		log.Warn("Sanitizing trienode history along with incremental snapshot")
+		conf.TrienodeHistory = false
+	}
 	return &conf
 }
 
@@ -120,8 +134,17 @@ func (c *Config) fields() []interface{} {
 	if c.EnableStateIndexing {
 		list = append(list, "index-history", true)
 	}
+	if c.TrienodeHistory {
+		list = append(list, "trienode-history", true)
+	}
 	if c.JournalDirectory != "" {
 		list = append(list, "journal-dir", c.JournalDirectory)
 	}
+	if c.ServeReplicas {
+		list = append(list, "serve-replicas", true)
+	}
+	if c.Replica {
+		list = append(list, "replica", true)
+	}
 	return list
 }
@@ -151,9 +151,15 @@ type Database struct {
 	stateFreezer ethdb.ResettableAncientStore // Freezer for storing state histories, nil possible in tests
 	stateIndexer *historyIndexer              // History indexer historical state data, nil possible
 
+	trienodeFreezer ethdb.ResettableAncientStore // Freezer for storing trienode histories, nil if not enabled
+	trienodeIndexer *historyIndexer              // History indexer for historical trie node data, nil possible
+
 	lock sync.RWMutex // Lock to prevent mutations from happening at the same time
 
 	incr *incrManager // used to store incremental data: block, state and contract codes
+
+	replicaPruned uint64 // State id up to which the diff layers for read replicas are pruned
+	replicaHead   uint64 // Highest state id of the diff layers loaded by the read replica
 }
 
 // New attempts to load an already existing layer from a persistent key-value
@@ -181,14 +187,28 @@ func New(diskdb ethdb.Database, config *Config, isVerkle bool) *Database {
 		db.diskdb = rawdb.NewTable(diskdb, string(rawdb.VerklePrefix))
 		db.hasher = verkleNodeHasher
 	}
-	// Construct the layer tree by resolving the in-disk singleton state
-	// and in-memory layer journal.
-	db.tree = newLayerTree(db.loadLayers())
-
-	// Repair the state history, which might not be aligned with the state
-	// in the key-value store due to an unclean shutdown.
-	if err := db.repairHistory(); err != nil {
-		log.Crit("Failed to repair state history", "err", err)
+	if config.Replica {
+		// Construct the layer tree of the read replica with the persistent
+		// state and the diff layers persisted by the writer. The state history
+		// is locked by the writer and not available to the replica.
+		base, err := db.newReplicaBase(nil, nil)
+		if err != nil {
+			log.Crit("Failed to compute node hash", "err", err)
+		}
+		db.tree = newLayerTree(base)
+		if err := db.loadReplicaLayers(db.tree); err != nil {
+			log.Crit("Failed to load replica layers", "err", err)
+		}
+	} else {
+		// Construct the layer tree by resolving the in-disk singleton state
+		// and in-memory layer journal.
+		db.tree = newLayerTree(db.loadLayers())
+
+		// Repair the state history, which might not be aligned with the state
+		// in the key-value store due to an unclean shutdown.
+		if err := db.repairHistory(); err != nil {
+			log.Crit("Failed to repair state history", "err", err)
+		}
 	}
 
 	if db.config.EnableIncr {
@@ -220,6 +240,10 @@ func New(diskdb ethdb.Database, config *Config, isVerkle bool) *Database {
 		db.stateIndexer = newHistoryIndexer(db.diskdb, db.stateFreezer, db.tree.bottom().stateID(), typeStateHistory)
 		log.Info("Enabled state history indexing")
 	}
+	if db.trienodeFreezer != nil {
+		db.trienodeIndexer = newHistoryIndexer(db.diskdb, db.trienodeFreezer, db.tree.bottom().stateID(), typeTrienodeHistory)
+		log.Info("Enabled trienode history indexing")
+	}
 	fields := config.fields()
 	if db.isVerkle {
 		fields = append(fields, "verkle", true)
@@ -276,7 +300,7 @@ func (db *Database) repairHistory() error {
 			}
 			log.Info("Truncated extraneous state history")
 		}
-		return nil
+		return db.repairTrienodeHistory(ancient, id)
 	}
 	// Truncate the extra state histories above in freezer in case it's not
 	// aligned with the disk layer. It might happen after a unclean shutdown.
@@ -287,6 +311,62 @@ func (db *Database) repairHistory() error {
 	if pruned != 0 {
 		log.Warn("Truncated extra state histories", "number", pruned)
 	}
+	return db.repairTrienodeHistory(ancient, id)
+}
+
+// repairTrienodeHistory opens the freezer for trienode history if it's enabled,
+// aligning the stored histories with the disk layer.
+func (db *Database) repairTrienodeHistory(ancient string, id uint64) error {
+	if !db.config.TrienodeHistory {
+		return nil
+	}
+	freezer, err := rawdb.NewTrienodeFreezer(ancient, db.isVerkle, db.readOnly)
+	if err != nil {
+		log.Crit("Failed to open trienode history freezer", "err", err)
+	}
+	db.trienodeFreezer = freezer
+
+	head, err := freezer.Ancients()
+	if err != nil {
+		return err
+	}
+	tail, err := freezer.Tail()
+	if err != nil {
+		return err
+	}
+	if head == id {
+		return nil
+	}
+	// Truncate the extra trienode histories above the disk layer, it might
+	// happen after a unclean shutdown.
+	if head > id && tail <= id {
+		pruned, err := truncateFromHead(freezer, typeTrienodeHistory, id)
+		if err != nil {
+			return err
+		}
+		log.Warn("Truncated extra trienode histories", "number", pruned)
+		return nil
+	}
+	// The trienode histories are not aligned with the disk layer, either it's
+	// the first time they are recorded or they fell behind while disabled.
+	// Drop the stale ones and start recording from the disk layer.
+	if db.readOnly {
+		db.trienodeFreezer = nil
+		return freezer.Close()
+	}
+	batch := db.diskdb.NewBatch()
+	rawdb.DeleteTrienodeHistoryIndexMetadata(batch)
+	rawdb.DeleteTrienodeHistoryIndexes(batch)
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	if id == 0 {
+		return freezer.Reset()
+	}
+	if err := rawdb.ResetTrienodeHistory(freezer, id); err != nil {
+		return err
+	}
+	log.Info("Started recording trienode history", "id", id)
 	return nil
 }
 
@@ -454,16 +534,36 @@ func (db *Database) Update(root common.Hash, parentRoot common.Hash, block uint6
 	if err := db.modifyAllowed(); err != nil {
 		return err
 	}
-	// TODO(rjl493456442) tracking the origins in the following PRs.
-	if err := db.tree.add(root, parentRoot, block, NewNodeSetWithOrigin(nodes.Nodes(), nil), states); err != nil {
+	// The original values of trie nodes are only tracked if the trienode
+	// history is maintained.
+	var set *nodeSetWithOrigin
+	if db.trienodeFreezer != nil {
+		set = NewNodeSetWithOrigin(nodes.NodeAndOrigins())
+	} else {
+		set = NewNodeSetWithOrigin(nodes.Nodes(), nil)
+	}
+	if err := db.tree.add(root, parentRoot, block, set, states); err != nil {
 		return err
 	}
+	// Persist the new layer for the read replicas before it's referenced by
+	// the chain head.
+	if db.config.ServeReplicas {
+		if err := db.writeReplicaLayer(root); err != nil {
+			return err
+		}
+	}
 	// Keep 128 diff layers in the memory, persistent layer is 129th.
 	// - head layer is paired with HEAD state
 	// - head-1 layer is paired with HEAD-1 state
 	// - head-127 layer(bottom-most diff layer) is paired with HEAD-127 state
 	// - head-128 layer(disk layer) is paired with HEAD-128 state
-	return db.tree.cap(root, maxDiffLayers)
+	if err := db.tree.cap(root, maxDiffLayers); err != nil {
+		return err
+	}
+	if db.config.ServeReplicas {
+		db.pruneReplicaLayers()
+	}
+	return nil
 }
 
 // Commit traverses downwards the layer tree from a specified layer with the
@@ -555,6 +655,17 @@ func (db *Database) Enable(root common.Hash) error {
 			return err
 		}
 	}
+	if db.trienodeFreezer != nil {
+		batch.Reset()
+		rawdb.DeleteTrienodeHistoryIndexMetadata(batch)
+		rawdb.DeleteTrienodeHistoryIndexes(batch)
+		if err := batch.Write(); err != nil {
+			return err
+		}
+		if err := db.trienodeFreezer.Reset(); err != nil {
+			return err
+		}
+	}
 	// Re-enable the database as the final step.
 	db.waitSync = false
 	rawdb.WriteSnapSyncStatusFlag(db.diskdb, rawdb.StateSyncFinished)
@@ -572,6 +683,11 @@ func (db *Database) Enable(root common.Hash) error {
 		db.stateIndexer = newHistoryIndexer(db.diskdb, db.stateFreezer, db.tree.bottom().stateID(), typeStateHistory)
 		log.Info("Re-enabled state history indexing")
 	}
+	if db.trienodeIndexer != nil {
+		db.trienodeIndexer.close()
+		db.trienodeIndexer = newHistoryIndexer(db.diskdb, db.trienodeFreezer, db.tree.bottom().stateID(), typeTrienodeHistory)
+		log.Info("Re-enabled trienode history indexing")
+	}
 	log.Info("Rebuilt trie database", "root", root)
 	return nil
 }
@@ -627,10 +743,45 @@ func (db *Database) Recover(root common.Hash) error {
 	if err != nil {
 		return err
 	}
+	if err := db.recoverTrienodeHistory(dl.stateID()); err != nil {
+		return err
+	}
 	log.Debug("Recovered state", "root", root, "elapsed", common.PrettyDuration(time.Since(start)))
 	return nil
 }
 
+// recoverTrienodeHistory truncates the trienode histories above the recovered
+// disk layer. If the disk layer is rolled back below the first trienode history,
+// the histories are reset and recorded from the disk layer again.
+func (db *Database) recoverTrienodeHistory(id uint64) error {
+	if db.trienodeFreezer == nil {
+		return nil
+	}
+	tail, err := db.trienodeFreezer.Tail()
+	if err != nil {
+		return err
+	}
+	if tail <= id {
+		_, err := truncateFromHead(db.trienodeFreezer, typeTrienodeHistory, id)
+		return err
+	}
+	if db.trienodeIndexer != nil {
+		db.trienodeIndexer.close()
+	}
+	batch := db.diskdb.NewBatch()
+	rawdb.DeleteTrienodeHistoryIndexMetadata(batch)
+	rawdb.DeleteTrienodeHistoryIndexes(batch)
+	if err := batch.Write(); err != nil {
+		return err
+	}
+	if err := rawdb.ResetTrienodeHistory(db.trienodeFreezer, id); err != nil {
+		return err
+	}
+	db.trienodeIndexer = newHistoryIndexer(db.diskdb, db.trienodeFreezer, id, typeTrienodeHistory)
+	log.Info("Reset trienode history", "id", id)
+	return nil
+}
+
 // Recoverable returns the indicator if the specified state is recoverable.
 //
 // The supplied root must be a valid trie hash value.
@@ -687,7 +838,15 @@ func (db *Database) Close() error {
 	if db.stateIndexer != nil {
 		db.stateIndexer.close()
 	}
-	// Close the attached state history freezer.
+	if db.trienodeIndexer != nil {
+		db.trienodeIndexer.close()
+	}
+	// Close the attached trienode and state history freezers.
+	if db.trienodeFreezer != nil {
+		if err := db.trienodeFreezer.Close(); err != nil {
+			return err
+		}
+	}
 	if db.stateFreezer == nil {
 		return nil
 	}
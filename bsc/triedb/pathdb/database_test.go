@@ -144,6 +144,8 @@ type testerConfig struct {
 	enableIndex  bool   // Enable state history indexing or not
 	journalDir   string // Directory path for persisting journal files
 	isVerkle     bool   // Enables Verkle trie mode if true
+	serveReplica bool   // Persist the diff layers for read replicas if true
+	trienodeHist bool   // Maintain the trienode history if true
 
 	writeBuffer *int // Optional, the size of memory allocated for write buffer
 	trieCache   *int // Optional, the size of memory allocated for trie cache
@@ -182,6 +184,8 @@ func newTester(t *testing.T, config *testerConfig) *tester {
 			WriteBufferSize:     config.writeBufferSize(),
 			NoAsyncFlush:        true,
 			JournalDirectory:    config.journalDir,
+			ServeReplicas:       config.serveReplica,
+			TrienodeHistory:     config.trienodeHist,
 		}, config.isVerkle)
 
 		obj = &tester{
@@ -348,6 +348,9 @@ func (dl *diskLayer) writeStateHistory(diff *diffLayer) (bool, error) {
 			return false, err
 		}
 	}
+	if err := dl.writeTrienodeHistory(diff); err != nil {
+		return false, err
+	}
 	// Determine if the persisted history object has exceeded the
 	// configured limitation.
 	limit := dl.db.config.StateHistory
@@ -385,9 +388,42 @@ func (dl *diskLayer) writeStateHistory(diff *diffLayer) (bool, error) {
 		return false, err
 	}
 	log.Debug("Pruned state history", "items", pruned, "tailid", newFirst)
+
+	// Prune the trienode histories along with the state histories, the ones
+	// recorded since a later point are left untouched.
+	if dl.db.trienodeFreezer != nil {
+		tail, err := dl.db.trienodeFreezer.Tail()
+		if err != nil {
+			return false, err
+		}
+		if tail < newFirst-1 {
+			pruned, err := truncateFromTail(dl.db.trienodeFreezer, typeTrienodeHistory, newFirst-1)
+			if err != nil {
+				return false, err
+			}
+			log.Debug("Pruned trienode history", "items", pruned, "tailid", newFirst)
+		}
+	}
 	return false, nil
 }
 
+// writeTrienodeHistory stores the trienode history of the given diff layer if
+// it's enabled and notifies the associated indexer.
+func (dl *diskLayer) writeTrienodeHistory(diff *diffLayer) error {
+	if dl.db.trienodeFreezer == nil {
+		return nil
+	}
+	if err := writeTrienodeHistory(dl.db.trienodeFreezer, diff); err != nil {
+		return err
+	}
+	if dl.db.trienodeIndexer != nil {
+		if err := dl.db.trienodeIndexer.extend(diff.stateID()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
 // commit merges the given bottom-most diff layer into the node buffer
 // and returns a newly constructed disk layer. Note the current disk
 // layer must be tagged as stale first to prevent re-access.
@@ -521,6 +557,19 @@ func (dl *diskLayer) revert(h *stateHistory) (*diskLayer, error) {
 			return nil, err
 		}
 	}
+	// Unindex the corresponding trienode history if it's existent, the ones
+	// below the first trienode history are reset after the recovery.
+	if dl.db.trienodeIndexer != nil {
+		tail, err := dl.db.trienodeFreezer.Tail()
+		if err != nil {
+			return nil, err
+		}
+		if tail < dl.id {
+			if err := dl.db.trienodeIndexer.shorten(dl.id); err != nil {
+				return nil, err
+			}
+		}
+	}
 	// State change may be applied to node buffer, or the persistent
 	// state, depends on if node buffer is empty or not. If the node
 	// buffer is not empty, it means that the state transition that
@@ -548,14 +548,15 @@ func (i *indexIniter) index(done chan struct{}, interrupt *atomic.Int32, lastID
 	// when the state is reverted manually (chain.SetHead) or the deep reorg is
 	// encountered. In such cases, no indexing should be scheduled.
 	if beginID > lastID {
-		if lastID == 0 && beginID == 1 {
+		if (lastID == 0 && beginID == 1) || (beginID == lastID+1 && loadIndexMetadata(i.disk, i.typ) == nil) {
 			// Initialize the indexing flag if the state history is empty by
-			// using zero as the disk layer ID. This is a common case that
-			// can occur after snap sync.
+			// using the disk layer ID. This is a common case that can occur
+			// after snap sync, or once the history recording is started on
+			// an existing database.
 			//
 			// This step is essential to avoid spinning up indexing thread
 			// endlessly until a history object is produced.
-			storeIndexMetadata(i.disk, i.typ, 0)
+			storeIndexMetadata(i.disk, i.typ, lastID)
 			i.log.Info("Initialized history indexing flag")
 		} else {
 			i.log.Debug("History is fully indexed", "last", lastID)
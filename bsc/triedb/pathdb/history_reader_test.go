@@ -24,7 +24,11 @@ import (
 
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/ethdb/memorydb"
 	"github.com/ethereum/go-ethereum/internal/testrand"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/ethereum/go-ethereum/triedb/database"
 )
 
 func waitIndexing(db *Database) {
@@ -215,3 +219,112 @@ func TestHistoricalStateReader(t *testing.T) {
 		t.Fatalf("Unexpected error: %v", err)
 	}
 }
+
+// historicNodeDB resolves the trie nodes of historical states for tries.
+type historicNodeDB struct {
+	db *Database
+}
+
+func (db *historicNodeDB) NodeReader(root common.Hash) (database.NodeReader, error) {
+	return db.db.HistoricNodeReader(root)
+}
+
+func waitTrienodeIndexing(db *Database) {
+	for {
+		metadata := loadIndexMetadata(db.diskdb, typeTrienodeHistory)
+		if metadata != nil && metadata.Last >= db.tree.bottom().stateID() {
+			return
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
+
+func checkHistoricalTrie(env *tester, root common.Hash) error {
+	nodedb := &historicNodeDB{db: env.db}
+	tr, err := trie.New(trie.StateTrieID(root), nodedb)
+	if err != nil {
+		return err
+	}
+	for addrHash, accountData := range env.snapAccounts[root] {
+		blob, err := tr.Get(addrHash.Bytes())
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(accountData, blob) {
+			return fmt.Errorf("wrong account data, expected %x, got %x", accountData, blob)
+		}
+		proof := memorydb.New()
+		if err := tr.Prove(addrHash.Bytes(), proof); err != nil {
+			return err
+		}
+		if _, err := trie.VerifyProof(root, addrHash.Bytes(), proof); err != nil {
+			return fmt.Errorf("invalid account proof, %v", err)
+		}
+		account, err := types.FullAccount(accountData)
+		if err != nil {
+			return err
+		}
+		if account.Root == types.EmptyRootHash {
+			continue
+		}
+		st, err := trie.New(trie.StorageTrieID(root, addrHash, account.Root), nodedb)
+		if err != nil {
+			return err
+		}
+		for slotHash, slotData := range env.snapStorages[root][addrHash] {
+			blob, err := st.Get(slotHash.Bytes())
+			if err != nil {
+				return err
+			}
+			if !bytes.Equal(slotData, blob) {
+				return fmt.Errorf("wrong storage data, expected %x, got %x", slotData, blob)
+			}
+		}
+	}
+	return nil
+}
+
+func TestHistoricalNodeReader(t *testing.T) {
+	maxDiffLayers = 4
+	defer func() {
+		maxDiffLayers = 128
+	}()
+
+	config := &testerConfig{
+		stateHistory: 0,
+		layers:       32,
+		enableIndex:  true,
+		trienodeHist: true,
+	}
+	env := newTester(t, config)
+	defer env.release()
+	waitTrienodeIndexing(env.db)
+
+	dl := env.db.tree.bottom()
+	for _, root := range env.roots {
+		if root == dl.rootHash() {
+			break
+		}
+		if err := checkHistoricalTrie(env, root); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Pile up more histories on top, ensuring the historic reader is not affected
+	env.extend(4)
+	waitTrienodeIndexing(env.db)
+
+	for _, root := range env.roots {
+		if root == dl.rootHash() {
+			break
+		}
+		if err := checkHistoricalTrie(env, root); err != nil {
+			t.Fatal(err)
+		}
+	}
+	// Non-canonical state is not accessible
+	fakeRoot := testrand.Hash()
+	rawdb.WriteStateID(env.db.diskdb, fakeRoot, 10)
+	if _, err := env.db.HistoricNodeReader(fakeRoot); err == nil {
+		t.Fatal("expected error")
+	}
+}
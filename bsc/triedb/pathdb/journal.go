@@ -333,6 +333,16 @@ func (dl *diffLayer) journal(w io.Writer) error {
 		return err
 	}
 	// Everything below was journaled, persist this layer too
+	if err := dl.encode(w); err != nil {
+		return err
+	}
+	log.Debug("Journaled pathdb diff layer", "root", dl.root, "parent", dl.parent.rootHash(), "id", dl.stateID(), "block", dl.block)
+	return nil
+}
+
+// encode writes the content of the diff layer, excluding its parents, into
+// the provided writer.
+func (dl *diffLayer) encode(w io.Writer) error {
 	if err := rlp.Encode(w, dl.root); err != nil {
 		return err
 	}
@@ -344,11 +354,7 @@ func (dl *diffLayer) journal(w io.Writer) error {
 		return err
 	}
 	// Write the associated flat state set into buffer
-	if err := dl.states.encode(w); err != nil {
-		return err
-	}
-	log.Debug("Journaled pathdb diff layer", "root", dl.root, "parent", dl.parent.rootHash(), "id", dl.stateID(), "block", dl.block)
-	return nil
+	return dl.states.encode(w)
 }
 
 // Journal commits an entire diff hierarchy to disk into a single journal entry.
@@ -394,6 +400,11 @@ func (db *Database) Journal(root common.Hash) error {
 			return err
 		}
 	}
+	if db.trienodeFreezer != nil {
+		if err := db.trienodeFreezer.SyncAncient(); err != nil {
+			return err
+		}
+	}
 	// Store the journal into the database and return
 	var (
 		file        *os.File
@@ -77,6 +77,19 @@ func (tree *layerTree) init(head layer) {
 	tree.lookup = newLookup(head, tree.isDescendant)
 }
 
+// replace swaps the layers of the tree with the ones of the given tree, which
+// must not be used afterwards.
+func (tree *layerTree) replace(other *layerTree) {
+	tree.lock.Lock()
+	defer tree.lock.Unlock()
+
+	tree.base = other.base
+	tree.layers = other.layers
+	tree.descendants = other.descendants
+	tree.lookup = other.lookup
+	tree.lookup.descendant = tree.isDescendant
+}
+
 // get retrieves a layer belonging to the given state root.
 func (tree *layerTree) get(root common.Hash) layer {
 	tree.lock.RLock()
@@ -318,3 +318,76 @@ func (r *HistoricalStateReader) Storage(address common.Address, key common.Hash)
 	}
 	return r.reader.read(newStorageIdentQuery(address, addrHash, key, keyHash), r.id, dl.stateID(), latest)
 }
+
+// HistoricalNodeReader is a wrapper over history reader, providing access to
+// the trie nodes of historical state.
+type HistoricalNodeReader struct {
+	db     *Database
+	reader *historyReader
+	id     uint64
+}
+
+// HistoricNodeReader constructs a reader for accessing the trie nodes of the
+// requested historic state.
+func (db *Database) HistoricNodeReader(root common.Hash) (*HistoricalNodeReader, error) {
+	// Bail out if the trienode history hasn't been fully indexed
+	if db.trienodeIndexer == nil || db.trienodeFreezer == nil {
+		return nil, fmt.Errorf("historical trie nodes of %x are not available", root)
+	}
+	if !db.trienodeIndexer.inited() {
+		return nil, errors.New("trienode histories haven't been fully indexed yet")
+	}
+	id := rawdb.ReadStateID(db.diskdb, root)
+	if id == nil {
+		return nil, fmt.Errorf("state %#x is not available", root)
+	}
+	// Ensure the requested state is canonical and the trie nodes since then
+	// are all recorded.
+	tail, err := db.trienodeFreezer.Tail()
+	if err != nil {
+		return nil, err
+	}
+	if *id < tail {
+		return nil, fmt.Errorf("historical trie nodes of %#x have been pruned, first: %d, state: %d", root, tail+1, *id)
+	}
+	meta, err := readTrienodeMetadata(db.trienodeFreezer, *id+1)
+	if err != nil {
+		return nil, err
+	}
+	if meta.parent != root {
+		return nil, fmt.Errorf("state %#x is not canonincal", root)
+	}
+	return &HistoricalNodeReader{
+		id:     *id,
+		db:     db,
+		reader: newHistoryReader(db.diskdb, db.trienodeFreezer),
+	}, nil
+}
+
+// Node implements database.NodeReader interface, retrieving the node with
+// specified node info in the historical state.
+func (r *HistoricalNodeReader) Node(owner common.Hash, path []byte, hash common.Hash) ([]byte, error) {
+	defer func(start time.Time) {
+		historicalTrienodeReadTimer.UpdateSince(start)
+	}(time.Now())
+
+	// TODO(rjl493456442): Theoretically, the obtained disk layer could become stale
+	// within a very short time window, the same as the historical state reader.
+	dl := r.db.tree.bottom()
+	latest, _, _, err := dl.node(owner, path, 0)
+	if err != nil {
+		return nil, err
+	}
+	blob, err := r.reader.read(newTrienodeIdentQuery(owner, path), r.id, dl.stateID(), latest)
+	if err != nil {
+		return nil, err
+	}
+	got, err := r.db.hasher(blob)
+	if err != nil {
+		return nil, err
+	}
+	if got != hash {
+		return nil, fmt.Errorf("unexpected historical node: (%x %v), %x!=%x, state: %d", owner, path, hash, got, r.id)
+	}
+	return blob, nil
+}
@@ -0,0 +1,210 @@
+package pathdb
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"sort"
+
+	"github.com/VictoriaMetrics/fastcache"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+	"github.com/ethereum/go-ethereum/ethdb"
+	"github.com/ethereum/go-ethereum/log"
+	"github.com/ethereum/go-ethereum/rlp"
+)
+
+// errNotReplica is returned if a replica operation is requested on a database
+// which isn't a read replica.
+var errNotReplica = errors.New("not a read replica")
+
+// writeReplicaLayer persists the diff layer with the given root for the read
+// replicas following the database snapshots. It's encoded as the parent root
+// followed by the layer content in the journal format.
+func (db *Database) writeReplicaLayer(root common.Hash) error {
+	dl, ok := db.tree.get(root).(*diffLayer)
+	if !ok {
+		return fmt.Errorf("triedb diff layer [%#x] missing", root)
+	}
+	var buf bytes.Buffer
+	if err := rlp.Encode(&buf, dl.parentLayer().rootHash()); err != nil {
+		return err
+	}
+	if err := dl.encode(&buf); err != nil {
+		return err
+	}
+	rawdb.WriteReplicaLayer(db.diskdb, dl.stateID(), root, buf.Bytes())
+	return nil
+}
+
+// pruneReplicaLayers deletes the diff layers persisted for the read replicas
+// which have been flushed into the persistent state.
+func (db *Database) pruneReplicaLayers() {
+	persisted := rawdb.ReadPersistentStateID(db.diskdb)
+	if persisted <= db.replicaPruned {
+		return
+	}
+	rawdb.DeleteReplicaLayers(db.diskdb, persisted)
+	db.replicaPruned = persisted
+}
+
+// newReplicaBase creates the disk layer of a read replica with the persistent
+// state, reusing the given clean caches.
+func (db *Database) newReplicaBase(nodes, states *fastcache.Cache) (*diskLayer, error) {
+	root, err := db.hasher(rawdb.ReadAccountTrieNode(db.diskdb, nil))
+	if err != nil {
+		return nil, err
+	}
+	id := rawdb.ReadPersistentStateID(db.diskdb)
+	return newDiskLayer(root, id, db, nodes, states, newBuffer(db.config.WriteBufferSize, nil, nil, 0), nil), nil
+}
+
+// loadReplicaLayers loads the diff layers persisted by the writer into the
+// given tree. The layers already known and the ones which can't be linked to
+// the tree are skipped.
+func (db *Database) loadReplicaLayers(tree *layerTree) error {
+	// Reorgs deeper than the in-memory layers of the writer are not expected,
+	// start at the deepest possible sibling of the known layers.
+	from := tree.bottom().stateID() + 1
+	if db.replicaHead > from+uint64(maxDiffLayers) {
+		from = db.replicaHead - uint64(maxDiffLayers)
+	}
+	it := rawdb.IterateReplicaLayers(db.diskdb, from)
+	defer it.Release()
+
+	for it.Next() {
+		id, root, ok := rawdb.ParseReplicaLayerKey(it.Key())
+		if !ok || tree.get(root) != nil {
+			continue
+		}
+		r := rlp.NewStream(bytes.NewReader(it.Value()), 0)
+
+		var parent common.Hash
+		if err := r.Decode(&parent); err != nil {
+			return fmt.Errorf("load replica layer parent: %v", err)
+		}
+		if tree.get(parent) == nil {
+			continue
+		}
+		var (
+			stored common.Hash
+			block  uint64
+			nodes  nodeSetWithOrigin
+			states StateSetWithOrigin
+		)
+		if err := r.Decode(&stored); err != nil {
+			return fmt.Errorf("load replica layer root: %v", err)
+		}
+		if stored != root {
+			return fmt.Errorf("unmatched replica layer root: want %x got %x", root, stored)
+		}
+		if err := r.Decode(&block); err != nil {
+			return fmt.Errorf("load replica layer block number: %v", err)
+		}
+		if err := nodes.decode(r); err != nil {
+			return err
+		}
+		if err := states.decode(r); err != nil {
+			return err
+		}
+		if err := tree.add(root, parent, block, &nodes, &states); err != nil {
+			return err
+		}
+		if id > db.replicaHead {
+			db.replicaHead = id
+		}
+	}
+	return it.Error()
+}
+
+// PrepareRefresh is invoked on a read replica with the reopened database before
+// it replaces the one in use. If the writer persisted a new state meanwhile, the
+// disk layer is marked as stale, so that the state being replaced is no longer
+// read through it.
+func (db *Database) PrepareRefresh(next ethdb.KeyValueReader) error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if !db.config.Replica {
+		return errNotReplica
+	}
+	base := db.tree.bottom()
+	if rawdb.ReadPersistentStateID(next) != base.stateID() {
+		db.invalidateReplicaBase(base)
+	}
+	return nil
+}
+
+// invalidateReplicaBase marks the disk layer of the read replica as stale and
+// drops the content of the clean caches inherited by the next one.
+func (db *Database) invalidateReplicaBase(base *diskLayer) {
+	base.lock.Lock()
+	defer base.lock.Unlock()
+
+	if base.stale {
+		return
+	}
+	base.stale = true
+	if base.nodes != nil {
+		base.nodes.Reset()
+	}
+	if base.states != nil {
+		base.states.Reset()
+	}
+}
+
+// Refresh reloads the layer tree of a read replica after the database has been
+// reopened to observe the latest writes. The diff layers persisted by the
+// writer are loaded on top of the tree, and the disk layer is replaced if the
+// writer persisted a new state meanwhile, keeping the diff layers above it.
+func (db *Database) Refresh() error {
+	db.lock.Lock()
+	defer db.lock.Unlock()
+
+	if !db.config.Replica {
+		return errNotReplica
+	}
+	base := db.tree.bottom()
+	base.lock.RLock()
+	stale := base.stale
+	base.lock.RUnlock()
+	if !stale && rawdb.ReadPersistentStateID(db.diskdb) == base.stateID() {
+		return db.loadReplicaLayers(db.tree)
+	}
+	db.invalidateReplicaBase(base)
+
+	disk, err := db.newReplicaBase(base.nodes, base.states)
+	if err != nil {
+		return err
+	}
+	// Relink the retained diff layers above the new persistent state, sharing
+	// their content with the ones being replaced.
+	var retained []*diffLayer
+	db.tree.forEach(func(l layer) {
+		if dl, ok := l.(*diffLayer); ok && dl.stateID() > disk.stateID() {
+			retained = append(retained, dl)
+		}
+	})
+	sort.Slice(retained, func(i, j int) bool {
+		return retained[i].stateID() < retained[j].stateID()
+	})
+	tree := newLayerTree(disk)
+	for _, dl := range retained {
+		if tree.get(dl.parentLayer().rootHash()) == nil {
+			continue
+		}
+		if err := tree.add(dl.root, dl.parentLayer().rootHash(), dl.block, dl.nodes, dl.states); err != nil {
+			return err
+		}
+	}
+	if err := db.loadReplicaLayers(tree); err != nil {
+		return err
+	}
+	db.tree.replace(tree)
+
+	if err := db.setStateGenerator(); err != nil {
+		return err
+	}
+	log.Debug("Refreshed replica disk layer", "root", disk.rootHash(), "id", disk.stateID(), "layers", db.tree.len())
+	return nil
+}
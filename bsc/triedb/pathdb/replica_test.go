@@ -0,0 +1,78 @@
+package pathdb
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/rawdb"
+)
+
+func TestReplicaRefresh(t *testing.T) {
+	writer := newTester(t, &testerConfig{layers: 8, serveReplica: true})
+	defer writer.release()
+
+	open := func() *tester {
+		replica := *writer
+		replica.db = New(writer.db.diskdb, &Config{Replica: true, TrieCleanSize: 256 * 1024, StateCleanSize: 256 * 1024}, false)
+		return &replica
+	}
+	verify := func(replica *tester, roots []common.Hash) {
+		t.Helper()
+		for _, root := range roots {
+			if err := replica.verifyState(root); err != nil {
+				t.Fatalf("failed to verify state %x: %v", root, err)
+			}
+		}
+	}
+	// The replica loads the diff layers persisted by the writer
+	replica := open()
+	defer replica.db.Close()
+	verify(replica, writer.roots)
+
+	// The new layers are loaded on refresh
+	writer.extend(4)
+	if replica.db.tree.get(writer.lastHash()) != nil {
+		t.Fatal("replica observed the new layers before refresh")
+	}
+	if err := replica.db.PrepareRefresh(writer.db.diskdb); err != nil {
+		t.Fatalf("failed to prepare refresh: %v", err)
+	}
+	if err := replica.db.Refresh(); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+	verify(replica, writer.roots)
+
+	// Persist the state, the replica switches to the new disk layer and keeps
+	// the layers on top of it
+	persisted := writer.lastHash()
+	if err := writer.db.Commit(persisted, false); err != nil {
+		t.Fatalf("failed to commit: %v", err)
+	}
+	writer.extend(2)
+
+	stale := replica.db.tree.bottom()
+	if err := replica.db.PrepareRefresh(writer.db.diskdb); err != nil {
+		t.Fatalf("failed to prepare refresh: %v", err)
+	}
+	if _, _, _, err := stale.node(common.Hash{}, nil, 0); err != errSnapshotStale {
+		t.Fatalf("replaced disk layer is not stale: %v", err)
+	}
+	if err := replica.db.Refresh(); err != nil {
+		t.Fatalf("failed to refresh: %v", err)
+	}
+	if root := replica.db.tree.bottom().rootHash(); root != persisted {
+		t.Fatalf("unexpected disk layer root: have %x, want %x", root, persisted)
+	}
+	verify(replica, writer.roots[len(writer.roots)-3:])
+	if replica.db.tree.get(writer.roots[0]) != nil {
+		t.Fatal("flattened layer is still present")
+	}
+	// The writer prunes the layers which were persisted
+	it := rawdb.IterateReplicaLayers(writer.db.diskdb, 0)
+	defer it.Release()
+	for it.Next() {
+		if id, _, _ := rawdb.ParseReplicaLayerKey(it.Key()); id <= rawdb.ReadPersistentStateID(writer.db.diskdb) {
+			t.Fatalf("persisted layer %d is not pruned", id)
+		}
+	}
+}